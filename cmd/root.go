@@ -6,9 +6,12 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"os"
 	"os/signal"
 	"path/filepath"
+	"time"
 
 	cliCommit "github.com/MagdielCAS/magi-cli/internal/cli/commit"
 	"github.com/MagdielCAS/magi-cli/internal/cli/config"
@@ -21,6 +24,7 @@ import (
 	"github.com/MagdielCAS/magi-cli/internal/cli/push"
 	"github.com/MagdielCAS/magi-cli/internal/cli/ssh"
 	"github.com/MagdielCAS/magi-cli/internal/cli/update"
+	"github.com/MagdielCAS/magi-cli/pkg/shared"
 	"github.com/MagdielCAS/magi-cli/pkg/utils"
 	"github.com/MagdielCAS/pcli"
 	"github.com/pterm/pterm"
@@ -30,10 +34,17 @@ import (
 
 var (
 	cfgFile string
+	// closeLog closes the structured log file opened by setupLogging.
+	closeLog = func() error { return nil }
+	// cancelCommand cancels the command context with a cause; --timeout uses it.
+	cancelCommand context.CancelCauseFunc = func(error) {}
+	// stopTimeout stops the --timeout timer started by setupTimeout.
+	stopTimeout = func() bool { return false }
 	// These variables are set at build time using ldflags
 	version = "v0.8.1" // <---VERSION---> Updating this version, will also create a new GitHub tag.
 	commit  = "none"
 	date    = "unknown"
+	builtBy = ""
 
 	rootCmd = &cobra.Command{
 		Use:   "magi",
@@ -65,27 +76,90 @@ Run 'magi [command] --help' for more information on a specific command.`,
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
+// Failures exit with the code selected by shared.ExitCode.
 func Execute() {
-	setupSignalHandler()
+	signalCtx, stop := setupSignalHandler()
+	ctx, cancel := context.WithCancelCause(signalCtx)
+	cancelCommand = cancel
+	shared.SetBaseContext(ctx)
 	setupCompletionCmd()
 
-	if err := rootCmd.Execute(); err != nil {
-		utils.CheckForUpdates(rootCmd)
-		os.Exit(1)
+	started := time.Now()
+	cmd, err := rootCmd.ExecuteContextC(ctx)
+	var timeoutErr *shared.TimeoutError
+	timedOut := errors.As(context.Cause(ctx), &timeoutErr)
+	interrupted := signalCtx.Err() != nil
+	stopTimeout()
+	cancel(nil)
+	stop()
+
+	code := 0
+	switch {
+	case timedOut:
+		pterm.Error.Println(timeoutErr)
+		err = errors.Join(timeoutErr, err)
+		code = shared.ExitCodeTimeout
+	case err != nil:
+		code = shared.ExitCode(err)
+		if interrupted {
+			code = shared.ExitCodeInterrupted
+		}
 	}
+	logCommandFinished(cmd, err, code, time.Since(started))
+	_ = closeLog()
 
 	utils.CheckForUpdates(rootCmd)
+	if code != 0 {
+		os.Exit(code)
+	}
+}
+
+// logCommandStarted records the start of a command in the structured log. Arguments are
+// left out because they may hold secrets (as in 'magi config set api.key ...').
+func logCommandStarted(cmd *cobra.Command, args []string) {
+	shared.Logger().Info("command started", "command", cmd.CommandPath(), "version", version)
+}
+
+// logCommandFinished records the outcome of a command in the structured log.
+func logCommandFinished(cmd *cobra.Command, err error, code int, elapsed time.Duration) {
+	if cmd == nil {
+		cmd = rootCmd
+	}
+	attrs := []any{"command", cmd.CommandPath(), "exit_code", code, "duration_ms", elapsed.Milliseconds()}
+	if err != nil {
+		shared.Logger().Error("command failed", append(attrs, "error", err)...)
+		return
+	}
+	shared.Logger().Info("command finished", attrs...)
 }
 
-func setupSignalHandler() {
-	c := make(chan os.Signal, 1)
+// setupSignalHandler returns a context that is cancelled on the first interrupt so running
+// LLM requests and external commands stop and deferred cleanup runs. A second interrupt
+// exits immediately.
+func setupSignalHandler() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+	c := make(chan os.Signal, 2)
 	signal.Notify(c, os.Interrupt)
 	go func() {
-		<-c
-		pterm.Warning.Println("user interrupt")
+		if _, ok := <-c; !ok {
+			return
+		}
+		pterm.Warning.Println("user interrupt, stopping... press Ctrl-C again to force exit")
+		cancel()
+
+		if _, ok := <-c; !ok {
+			return
+		}
+		pterm.Warning.Println("user interrupt, forcing exit")
 		pcli.CheckForUpdates()
-		os.Exit(0)
+		os.Exit(shared.ExitCodeInterrupted)
 	}()
+
+	return ctx, func() {
+		signal.Stop(c)
+		close(c)
+		cancel()
+	}
 }
 
 func setupCompletionCmd() {
@@ -131,6 +205,33 @@ Fish:
 func initConfig() {
 	setupPTermFlags()
 	loadConfiguration()
+	setupOutputMode()
+	setupConfirmDefault()
+	setupProgress()
+	setupLogging()
+	setupTimeout()
+}
+
+// timeoutGracePeriod is how long a command has to stop and clean up after --timeout
+// cancelled it before magi exits anyway.
+const timeoutGracePeriod = 10 * time.Second
+
+// setupTimeout cancels the command context when --timeout expires. Running LLM requests
+// and external commands stop as on Ctrl-C, so temporary files are cleaned up; a command
+// that does not return within timeoutGracePeriod is stopped with ExitCodeTimeout.
+func setupTimeout() {
+	timeout, _ := rootCmd.PersistentFlags().GetDuration("timeout")
+	if timeout <= 0 {
+		return
+	}
+	stopTimeout = shared.StartTimeout(timeout, func(cause error) {
+		shared.Logger().Error("command timed out", "error", cause)
+		cancelCommand(cause)
+		time.AfterFunc(timeoutGracePeriod, func() {
+			pterm.Error.Printf("%v; forcing exit\n", cause)
+			os.Exit(shared.ExitCodeTimeout)
+		})
+	})
 }
 
 func setupPTermFlags() {
@@ -138,6 +239,47 @@ func setupPTermFlags() {
 	rootCmd.PersistentFlags().BoolVarP(&pterm.RawOutput, "raw", "", false, "print unstyled raw output")
 	rootCmd.PersistentFlags().BoolVarP(&pcli.DisableUpdateChecking, "disable-update-checks", "", false, "disables update checks")
 }
+
+func setupOutputMode() {
+	if jsonOutput, _ := rootCmd.PersistentFlags().GetBool("json"); jsonOutput {
+		shared.SetOutputMode(shared.OutputJSON)
+	} else if quiet, _ := rootCmd.PersistentFlags().GetBool("quiet"); quiet {
+		shared.SetOutputMode(shared.OutputQuiet)
+	}
+	shared.ConfigureOutput(shared.CurrentOutputMode())
+}
+
+func setupConfirmDefault() {
+	if assumeYes, _ := rootCmd.PersistentFlags().GetBool("assume-yes"); assumeYes {
+		shared.SetConfirmDefault(true)
+	} else if assumeNo, _ := rootCmd.PersistentFlags().GetBool("assume-no"); assumeNo {
+		shared.SetConfirmDefault(false)
+	}
+}
+
+func setupProgress() {
+	if progress, _ := rootCmd.PersistentFlags().GetBool("progress"); progress {
+		shared.SetProgress(true)
+	} else if noProgress, _ := rootCmd.PersistentFlags().GetBool("no-progress"); noProgress {
+		shared.SetProgress(false)
+	}
+}
+
+func setupLogging() {
+	if format, _ := rootCmd.PersistentFlags().GetString("log-format"); format != "" {
+		shared.SetLogFormat(format)
+	}
+	if path, _ := rootCmd.PersistentFlags().GetString("log-file"); path != "" {
+		shared.SetLogFile(path)
+	}
+	stop, err := shared.StartLogging()
+	if err != nil {
+		pterm.Error.Printf("Error starting the structured log: %v\n", err)
+		os.Exit(shared.ExitCode(err))
+	}
+	closeLog = stop
+}
+
 func loadConfiguration() {
 	viper.AutomaticEnv()
 
@@ -208,6 +350,18 @@ func init() {
 
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.magi/config.yaml)")
 	rootCmd.PersistentFlags().StringP("author", "", "Magdiel Campelo <github.com/MagdielCAS>", "author name for copyright attribution")
+	rootCmd.PersistentFlags().Bool("quiet", false, "suppress informational and spinner output (errors still go to stderr)")
+	rootCmd.PersistentFlags().Bool("json", false, "print structured JSON results to stdout where supported (defaults to output.format)")
+	rootCmd.PersistentFlags().Bool("assume-yes", false, "preselect \"yes\" in every confirmation prompt (defaults to ui.confirm_default)")
+	rootCmd.PersistentFlags().Bool("assume-no", false, "preselect \"no\" in every confirmation prompt (defaults to ui.confirm_default)")
+	rootCmd.MarkFlagsMutuallyExclusive("assume-yes", "assume-no")
+	rootCmd.PersistentFlags().Bool("progress", false, "show which AI agent is running in multi-agent commands (defaults to ui.progress)")
+	rootCmd.PersistentFlags().Bool("no-progress", false, "show a single spinner instead of the per-agent progress list")
+	rootCmd.MarkFlagsMutuallyExclusive("progress", "no-progress")
+	rootCmd.PersistentFlags().Duration("timeout", 0, "stop the command when it runs longer than this duration, e.g. 10m (0 means no limit)")
+	rootCmd.PersistentFlags().String("log-format", "", "write a structured log of command, agent and LLM events to stderr: json or text (defaults to MAGI_LOG, then log.format)")
+	rootCmd.PersistentFlags().String("log-file", "", "append the structured log to this file instead of stderr (defaults to log.file)")
+	rootCmd.PersistentPreRun = logCommandStarted
 
 	viper.BindPFlag("author", rootCmd.PersistentFlags().Lookup("author"))
 	viper.SetDefault("license", "bsd-2")
@@ -236,4 +390,18 @@ func init() {
 	pcli.SetRepo("MagdielCAS/magi-cli")
 	pcli.SetRootCmd(rootCmd)
 	pcli.Setup()
+	rootCmd.SetVersionTemplate(versionTemplate(currentBuildInfo()))
+	rootCmd.SetFlagErrorFunc(flagErrorFunc(pcli.FlagErrorFunc()))
+}
+
+// flagErrorFunc keeps pcli's styled flag error output but still returns the error, so
+// invalid flags exit with a non-zero code instead of pcli's nil.
+func flagErrorFunc(print func(*cobra.Command, error) error) func(*cobra.Command, error) error {
+	return func(cmd *cobra.Command, err error) error {
+		_ = print(cmd, err)
+		// pcli already printed the usage and the error.
+		cmd.SilenceUsage = true
+		cmd.SilenceErrors = true
+		return err
+	}
 }
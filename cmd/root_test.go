@@ -0,0 +1,32 @@
+/**
+ * Copyright © 2025 Magdiel Campelo <github.com/MagdielCAS/magi-cli>
+ * This file is part of the magi-cli
+**/
+package cmd
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestFlagErrorFuncReturnsError(t *testing.T) {
+	printed := false
+	handler := flagErrorFunc(func(*cobra.Command, error) error {
+		printed = true
+		return nil
+	})
+
+	cmd := &cobra.Command{Use: "test"}
+	flagErr := errors.New("unknown flag: --bogus")
+	if err := handler(cmd, flagErr); !errors.Is(err, flagErr) {
+		t.Fatalf("expected the flag error to be returned, got %v", err)
+	}
+	if !printed {
+		t.Fatal("expected the styled output to be printed")
+	}
+	if !cmd.SilenceErrors || !cmd.SilenceUsage {
+		t.Fatal("expected cobra's own error and usage output to be silenced")
+	}
+}
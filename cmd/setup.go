@@ -5,8 +5,15 @@
 package cmd
 
 import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
 	"strings"
+	"time"
 
+	"github.com/MagdielCAS/magi-cli/pkg/llm"
+	"github.com/MagdielCAS/magi-cli/pkg/shared"
 	"github.com/pterm/pterm"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -20,6 +27,21 @@ func init() {
 It will guide you through setting up your API key and other preferences.
 This command can also be run non-interactively by providing the required flags.
 
+The providers openai, openrouter, groq, together, deepseek, mistral and ollama come with
+their base URL, so only the API key is needed (--base-url still overrides it). Pick custom
+for any other OpenAI-compatible endpoint and enter its base URL.
+
+Available subcommands:
+  export    Export the current configuration with API keys redacted or encrypted
+  import    Merge a shared configuration file into the current configuration
+
+Outside CI mode, setup sends a minimal test prompt to the configured heavy model to
+confirm the API key works, and checks that custom base URLs are well-formed and reachable.
+A failed check only produces a warning: the configuration is still saved.
+
+The response cache (cache.enabled), which keeps the last generated commit message per diff
+in ~/.magi/cache, is off unless you accept it in the wizard or pass --cache.
+
 Usage:
   magi setup [flags]
 
@@ -30,20 +52,34 @@ Examples:
   # Run setup non-interactively with OpenAI
   magi setup --api-provider openai --api-key YOUR_API_KEY --heavy-model gpt-4
 
+  # Also turn on the commit message cache
+  magi setup --cache
+
+  # Run setup non-interactively with OpenRouter
+  magi setup --api-provider openrouter --api-key YOUR_API_KEY --heavy-model openai/gpt-4o
+
   # Run setup non-interactively with a custom provider
-  magi setup --api-provider custom --base-url http://localhost:8080 --api-key YOUR_API_KEY --heavy-model custom-model`,
+  magi setup --api-provider custom --base-url http://localhost:8080 --api-key YOUR_API_KEY --heavy-model custom-model
+
+  # Share your configuration with a teammate (API keys redacted)
+  magi setup export team-magi.yaml
+  magi setup import team-magi.yaml`,
 		Run: runSetup,
 	}
 
-	setupCmd.Flags().String("api-provider", "", "API provider (e.g., openai, custom)")
-	setupCmd.Flags().String("base-url", "", "Base URL for custom OpenAI compatible API")
+	setupCmd.Flags().String("api-provider", "", "API provider (openai, openrouter, groq, together, deepseek, mistral, ollama or custom)")
+	setupCmd.Flags().String("base-url", "", "Base URL for custom OpenAI compatible API (overrides the provider default)")
 	setupCmd.Flags().String("api-key", "", "Your OpenAI API key")
 	setupCmd.Flags().String("light-model", "", "Model for light tasks (e.g., gpt-3.5-turbo)")
 	setupCmd.Flags().String("heavy-model", "", "Model for heavy tasks (e.g., gpt-4)")
 	setupCmd.Flags().String("fallback-model", "", "Fallback model (e.g., gpt-3.5-turbo)")
 	setupCmd.Flags().String("format", "", "Default output format (e.g., text, json, yaml)")
+	setupCmd.Flags().Bool("cache", false, "Cache generated commit messages per diff (asked interactively when not set)")
 	setupCmd.Flags().Bool("ci", false, "Run setup in CI mode (non-interactive, uses defaults)")
 
+	setupCmd.AddCommand(newSetupExportCmd())
+	setupCmd.AddCommand(newSetupImportCmd())
+
 	rootCmd.AddCommand(setupCmd)
 }
 
@@ -57,7 +93,7 @@ func validateOption(option string, validOptions []string) bool {
 }
 
 func runSetup(cmd *cobra.Command, args []string) {
-	pterm.Info.Println("Starting magi setup...")
+	pterm.Info.Println(shared.T("setup.start"))
 
 	isCI, _ := cmd.Flags().GetBool("ci")
 	apiProvider, _ := cmd.Flags().GetString("api-provider")
@@ -67,10 +103,11 @@ func runSetup(cmd *cobra.Command, args []string) {
 	heavyModel, _ := cmd.Flags().GetString("heavy-model")
 	fallbackModel, _ := cmd.Flags().GetString("fallback-model")
 	format, _ := cmd.Flags().GetString("format")
+	cacheEnabled, _ := cmd.Flags().GetBool("cache")
 	var err error
 
 	if isCI {
-		pterm.Info.Println("Running in CI mode...")
+		pterm.Info.Println(shared.T("setup.ci_mode"))
 		if apiProvider == "" {
 			apiProvider = "openai"
 		}
@@ -92,47 +129,52 @@ func runSetup(cmd *cobra.Command, args []string) {
 	}
 
 	// Select API provider
-	validProviders := []string{"openai", "custom"}
+	validProviders := append(llm.KnownProviders(), "custom")
 	if apiProvider == "" {
 		apiProvider, err = pterm.DefaultInteractiveSelect.
 			WithOptions(validProviders).
-			WithDefaultText("Select your API provider").
+			WithDefaultText(shared.T("setup.select_provider")).
 			Show()
 		if err != nil {
-			pterm.Error.Printf("Failed to select API provider: %v\n", err)
+			pterm.Error.Println(shared.T("setup.select_provider_failed", err))
 			return
 		}
 	} else if !validateOption(apiProvider, validProviders) {
-		pterm.Error.Printf("Invalid API provider: %s. Valid providers are: %s\n", apiProvider, strings.Join(validProviders, ", "))
+		pterm.Error.Println(shared.T("setup.invalid_provider", apiProvider, strings.Join(validProviders, ", ")))
 		return
 	}
 
-	// Get Base URL for custom provider
-	if apiProvider == "custom" {
-		if baseURL == "" {
-			if isCI {
-				// Should have been provided via flag if needed, or we can set a default
-				pterm.Warning.Println("Base URL not provided in CI mode for custom provider")
-			} else {
-				baseURL, err = pterm.DefaultInteractiveTextInput.
-					WithMultiLine(false).
-					Show("Enter the base URL for the custom API")
-				if err != nil {
-					pterm.Error.Printf("Failed to get base URL: %v\n", err)
-					return
-				}
+	// Get Base URL for custom provider; known providers use their default unless --base-url is set
+	if apiProvider == "custom" && baseURL == "" {
+		if isCI {
+			// Should have been provided via flag if needed, or we can set a default
+			pterm.Warning.Println(shared.T("setup.base_url_missing_ci"))
+		} else {
+			baseURL, err = pterm.DefaultInteractiveTextInput.
+				WithMultiLine(false).
+				Show(shared.T("setup.enter_base_url"))
+			if err != nil {
+				pterm.Error.Println(shared.T("setup.base_url_failed", err))
+				return
 			}
 		}
 	}
+	if baseURL != "" {
+		baseURL, err = confirmBaseURL(baseURL, isCI)
+		if err != nil {
+			pterm.Error.Println(shared.T("setup.base_url_failed", err))
+			return
+		}
+	}
 
 	// Get API Key
 	if apiKey == "" {
 		apiKey, err = pterm.DefaultInteractiveTextInput.
 			WithMultiLine(false).
 			WithMask("*").
-			Show("Enter your API key")
+			Show(shared.T("setup.enter_api_key"))
 		if err != nil {
-			pterm.Error.Printf("Failed to get API key: %v\n", err)
+			pterm.Error.Println(shared.T("setup.api_key_failed", err))
 			return
 		}
 	}
@@ -141,27 +183,36 @@ func runSetup(cmd *cobra.Command, args []string) {
 	if lightModel == "" {
 		lightModel, err = pterm.DefaultInteractiveTextInput.
 			WithDefaultValue("gpt-3.5-turbo").
-			Show("Enter the model for light tasks")
+			Show(shared.T("setup.enter_light_model"))
 		if err != nil {
-			pterm.Error.Printf("Failed to get light model: %v\n", err)
+			pterm.Error.Println(shared.T("setup.light_model_failed", err))
 			return
 		}
 	}
 	if heavyModel == "" {
 		heavyModel, err = pterm.DefaultInteractiveTextInput.
 			WithDefaultValue("gpt-4").
-			Show("Enter the model for heavy tasks")
+			Show(shared.T("setup.enter_heavy_model"))
 		if err != nil {
-			pterm.Error.Printf("Failed to get heavy model: %v\n", err)
+			pterm.Error.Println(shared.T("setup.heavy_model_failed", err))
 			return
 		}
 	}
 	if fallbackModel == "" {
 		fallbackModel, err = pterm.DefaultInteractiveTextInput.
 			WithDefaultValue("gpt-3.5-turbo").
-			Show("Enter the fallback model")
+			Show(shared.T("setup.enter_fallback_model"))
 		if err != nil {
-			pterm.Error.Printf("Failed to get fallback model: %v\n", err)
+			pterm.Error.Println(shared.T("setup.fallback_model_failed", err))
+			return
+		}
+	}
+
+	// Verify the key against the heavy model before saving
+	if !isCI {
+		apiKey, err = confirmAPIKey(apiProvider, baseURL, apiKey, heavyModel)
+		if err != nil {
+			pterm.Error.Println(shared.T("setup.api_key_failed", err))
 			return
 		}
 	}
@@ -171,36 +222,185 @@ func runSetup(cmd *cobra.Command, args []string) {
 	if format == "" {
 		format, err = pterm.DefaultInteractiveSelect.
 			WithOptions(validFormats).
-			WithDefaultText("Select default output format").
+			WithDefaultText(shared.T("setup.select_format")).
 			Show()
 		if err != nil {
-			pterm.Error.Printf("Failed to select output format: %v\n", err)
+			pterm.Error.Println(shared.T("setup.select_format_failed", err))
 			return
 		}
 	} else if !validateOption(format, validFormats) {
-		pterm.Error.Printf("Invalid format: %s. Valid formats are: %s\n", format, strings.Join(validFormats, ", "))
+		pterm.Error.Println(shared.T("setup.invalid_format", format, strings.Join(validFormats, ", ")))
 		return
 	}
 
+	// The response cache writes AI output to disk, so it stays off unless asked for
+	if !isCI && !cmd.Flags().Changed("cache") {
+		cacheEnabled, err = shared.Confirm(shared.T("setup.enable_cache"), false)
+		if err != nil {
+			pterm.Error.Println(shared.T("setup.cache_failed", err))
+			return
+		}
+	}
+
 	// Save configuration
 	viper.Set("api.provider", apiProvider)
-	if apiProvider == "custom" {
-		viper.Set("api.base_url", baseURL)
-	}
+	// An empty base URL lets known providers use their default instead of a stale custom URL.
+	viper.Set("api.base_url", baseURL)
 	viper.Set("api.key", apiKey)
 	viper.Set("api.light_model", lightModel)
 	viper.Set("api.heavy_model", heavyModel)
 	viper.Set("api.fallback_model", fallbackModel)
 	viper.Set("output.format", format)
 	viper.Set("output.color", true)
-	viper.Set("cache.enabled", true)
+	viper.Set("cache.enabled", cacheEnabled)
 	viper.Set("cache.ttl", 3600)
 
 	if err := viper.WriteConfig(); err != nil {
-		pterm.Error.Printf("Failed to save configuration: %v\n", err)
+		pterm.Error.Println(shared.T("setup.save_failed", err))
 		return
 	}
 
-	pterm.Success.Println("Setup completed successfully!")
-	pterm.Info.Println("You can modify these settings anytime using 'magi config' commands")
+	pterm.Success.Println(shared.T("setup.completed"))
+	pterm.Info.Println(shared.T("setup.modify_hint"))
+}
+
+const setupCheckTimeout = 20 * time.Second
+
+// confirmBaseURL validates a custom base URL and, when running interactively, offers to
+// re-enter it until it is well-formed and reachable or the user accepts it as-is.
+func confirmBaseURL(baseURL string, isCI bool) (string, error) {
+	for {
+		checkErr := checkBaseURL(context.Background(), baseURL, isCI, &http.Client{Timeout: setupCheckTimeout})
+		if checkErr == nil {
+			return baseURL, nil
+		}
+
+		pterm.Warning.Println(shared.T("setup.base_url_check_failed", checkErr))
+		pterm.Warning.Println(shared.T("setup.base_url_check_hint"))
+		if isCI {
+			return baseURL, nil
+		}
+
+		retry, err := shared.Confirm(shared.T("setup.reenter_base_url"), true)
+		if err != nil {
+			return "", err
+		}
+		if !retry {
+			return baseURL, nil
+		}
+
+		baseURL, err = pterm.DefaultInteractiveTextInput.
+			WithMultiLine(false).
+			Show(shared.T("setup.enter_base_url"))
+		if err != nil {
+			return "", err
+		}
+	}
+}
+
+// confirmAPIKey sends a test completion with the collected settings and offers to re-enter
+// the API key when it fails. The last key entered is returned even when the check never passes.
+func confirmAPIKey(provider, baseURL, apiKey, model string) (string, error) {
+	for {
+		spinner, _ := pterm.DefaultSpinner.Start(shared.T("setup.verifying_key"))
+		ctx, cancel := context.WithTimeout(context.Background(), setupCheckTimeout)
+		checkErr := verifyAPIConnection(ctx, provider, baseURL, apiKey, model, nil)
+		cancel()
+		if checkErr == nil {
+			spinner.Success(shared.T("setup.key_verified"))
+			return apiKey, nil
+		}
+		spinner.Warning(shared.T("setup.key_verification_failed"))
+
+		pterm.Warning.Println(shared.T("setup.test_request_failed", model, checkErr))
+		pterm.Warning.Println(shared.T("setup.test_request_hint"))
+
+		retry, err := shared.Confirm(shared.T("setup.reenter_api_key"), true)
+		if err != nil {
+			return "", err
+		}
+		if !retry {
+			return apiKey, nil
+		}
+
+		apiKey, err = pterm.DefaultInteractiveTextInput.
+			WithMultiLine(false).
+			WithMask("*").
+			Show(shared.T("setup.enter_api_key"))
+		if err != nil {
+			return "", err
+		}
+	}
+}
+
+// validateBaseURL ensures the base URL is an absolute http(s) URL with a host.
+func validateBaseURL(raw string) error {
+	parsed, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return fmt.Errorf("invalid URL: %w", err)
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return fmt.Errorf("URL must start with http:// or https://")
+	}
+	if parsed.Host == "" {
+		return fmt.Errorf("URL must include a host")
+	}
+	return nil
+}
+
+// checkBaseURL validates baseURL and checks it against security.allowed_hosts before
+// probing it, so setup never sends a request to a host the policy rejects. The probe is
+// skipped in CI.
+func checkBaseURL(ctx context.Context, baseURL string, isCI bool, client *http.Client) error {
+	if err := validateBaseURL(baseURL); err != nil {
+		return err
+	}
+	if err := shared.CheckHostAllowed(baseURL, shared.AllowedHosts()); err != nil {
+		return err
+	}
+	if isCI {
+		return nil
+	}
+	return checkBaseURLReachable(ctx, baseURL, client)
+}
+
+// checkBaseURLReachable performs a lightweight request to confirm something is listening at
+// the base URL. Any HTTP response counts as reachable; only transport errors fail the check.
+func checkBaseURLReachable(ctx context.Context, baseURL string, client *http.Client) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSpace(baseURL), nil)
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("endpoint is not reachable: %w", err)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// verifyAPIConnection issues a minimal chat completion using the provided settings.
+func verifyAPIConnection(ctx context.Context, provider, baseURL, apiKey, model string, client *http.Client) error {
+	if client == nil {
+		client = shared.DefaultHTTPClient()
+	}
+	runtime := &shared.RuntimeContext{
+		Provider:     provider,
+		BaseURL:      baseURL,
+		APIKey:       apiKey,
+		HeavyModel:   model,
+		HTTPClient:   client,
+		AllowedHosts: shared.AllowedHosts(),
+	}
+
+	service, err := llm.NewServiceBuilder(runtime).UseHeavyModel().Build()
+	if err != nil {
+		return err
+	}
+
+	_, err = service.ChatCompletion(ctx, llm.ChatCompletionRequest{
+		Messages:  []llm.ChatMessage{{Role: "user", Content: "Reply with OK."}},
+		MaxTokens: 16,
+	})
+	return err
 }
@@ -0,0 +1,218 @@
+/**
+ * Copyright © 2025 Magdiel Campelo <github.com/MagdielCAS/magi-cli>
+ * This file is part of the magi-cli
+**/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/MagdielCAS/magi-cli/pkg/shared"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+func newSetupExportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export <file>",
+		Short: "Exports the current configuration so it can be shared",
+		Long: `Exports the current configuration (model tiers, output, agent and PR settings) to a YAML file
+that teammates can load with 'magi setup import'.
+
+API keys are replaced with a redaction marker by default. Use --encrypt to keep them in the
+file encrypted with a passphrase (PBKDF2 + AES-GCM) that you share through a separate channel.
+The exported file is written with 0600 permissions.
+
+Usage:
+  magi setup export <file> [flags]
+
+Examples:
+  # Export the configuration with API keys redacted
+  magi setup export team-magi.yaml
+
+  # Export the configuration with API keys encrypted
+  magi setup export team-magi.yaml --encrypt`,
+		Args: cobra.ExactArgs(1),
+		RunE: runSetupExport,
+	}
+	cmd.Flags().Bool("encrypt", false, "Encrypt API keys with a passphrase instead of redacting them")
+	return cmd
+}
+
+func newSetupImportCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "import <file>",
+		Short: "Imports a shared configuration file",
+		Long: `Imports a configuration file created by 'magi setup export' and merges it into the
+global configuration (~/.magi/config.yaml, or the file passed with --config). Keys present in
+the file override the global values; everything else is kept. A project .magi.yaml is never
+written, so imported API keys cannot end up in a committed file.
+
+Redacted API keys are prompted for (leave empty to keep your current key). Encrypted API keys
+are decrypted with the passphrase used during export.
+
+Usage:
+  magi setup import <file>
+
+Examples:
+  # Import a configuration shared by a teammate
+  magi setup import team-magi.yaml`,
+		Args: cobra.ExactArgs(1),
+		RunE: runSetupImport,
+	}
+}
+
+func runSetupExport(cmd *cobra.Command, args []string) error {
+	encrypt, _ := cmd.Flags().GetBool("encrypt")
+
+	var passphrase string
+	if encrypt {
+		var err error
+		passphrase, err = pterm.DefaultInteractiveTextInput.
+			WithMask("*").
+			Show("Enter a passphrase to encrypt the API keys")
+		if err != nil {
+			return fmt.Errorf("failed to read passphrase: %w", err)
+		}
+		if passphrase == "" {
+			return fmt.Errorf("passphrase cannot be empty")
+		}
+	}
+
+	settings, err := exportSettings(viper.AllSettings(), passphrase)
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(settings)
+	if err != nil {
+		return fmt.Errorf("failed to encode configuration: %w", err)
+	}
+
+	if err := os.WriteFile(args[0], data, 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", args[0], err)
+	}
+
+	if encrypt {
+		pterm.Success.Printf("Configuration exported to %s with encrypted API keys\n", args[0])
+	} else {
+		pterm.Success.Printf("Configuration exported to %s with API keys redacted\n", args[0])
+	}
+	return nil
+}
+
+func runSetupImport(cmd *cobra.Command, args []string) error {
+	imported := viper.New()
+	imported.SetConfigFile(args[0])
+	if err := imported.ReadInConfig(); err != nil {
+		return fmt.Errorf("failed to read %s: %w", args[0], err)
+	}
+
+	var passphrase string
+	settings := imported.AllSettings()
+	err := shared.TransformSecrets(settings, func(key, value string) (string, error) {
+		switch {
+		case value == shared.RedactedValue:
+			return pterm.DefaultInteractiveTextInput.
+				WithMask("*").
+				Show(fmt.Sprintf("Enter a value for %s (leave empty to keep the current one)", key))
+		case shared.IsEncryptedSecret(value):
+			if passphrase == "" {
+				var err error
+				passphrase, err = pterm.DefaultInteractiveTextInput.
+					WithMask("*").
+					Show("Enter the passphrase used to encrypt the API keys")
+				if err != nil {
+					return "", err
+				}
+			}
+			return shared.DecryptSecret(value, passphrase)
+		default:
+			return value, nil
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	path, err := globalConfigFile()
+	if err != nil {
+		return fmt.Errorf("failed to locate the global config file: %w", err)
+	}
+	if err := importSettings(path, settings); err != nil {
+		return err
+	}
+
+	pterm.Success.Printf("Configuration imported from %s into %s\n", args[0], path)
+	return nil
+}
+
+// globalConfigFile returns the file passed with --config, or ~/.magi/config.yaml.
+func globalConfigFile() (string, error) {
+	if cfgFile != "" {
+		return cfgFile, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".magi", "config.yaml"), nil
+}
+
+// importSettings merges settings into the config file at path and writes it back. The file
+// is read into a fresh viper instance, so a project .magi.yaml, which is often committed,
+// never receives the imported API keys. A missing file is an empty config.
+func importSettings(path string, settings map[string]any) error {
+	v := viper.New()
+	v.SetConfigFile(path)
+	v.SetConfigType("yaml")
+	if err := v.ReadInConfig(); err != nil {
+		var notFound viper.ConfigFileNotFoundError
+		if !os.IsNotExist(err) && !errors.As(err, &notFound) {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+	}
+	if err := v.MergeConfigMap(settings); err != nil {
+		return fmt.Errorf("failed to merge configuration: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	if err := v.WriteConfigAs(path); err != nil {
+		return fmt.Errorf("failed to save configuration: %w", err)
+	}
+	return nil
+}
+
+// exportSettings returns a copy of settings whose secrets are encrypted with passphrase,
+// or redacted when passphrase is empty.
+func exportSettings(settings map[string]any, passphrase string) (map[string]any, error) {
+	exported := copySettings(settings)
+	err := shared.TransformSecrets(exported, func(_, value string) (string, error) {
+		if passphrase == "" {
+			return shared.RedactedValue, nil
+		}
+		return shared.EncryptSecret(value, passphrase)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return exported, nil
+}
+
+func copySettings(settings map[string]any) map[string]any {
+	clone := make(map[string]any, len(settings))
+	for k, v := range settings {
+		if nested, ok := v.(map[string]any); ok {
+			clone[k] = copySettings(nested)
+			continue
+		}
+		clone[k] = v
+	}
+	return clone
+}
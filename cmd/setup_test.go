@@ -0,0 +1,174 @@
+/**
+ * Copyright © 2025 Magdiel Campelo <github.com/MagdielCAS/magi-cli>
+ * This file is part of the magi-cli
+**/
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/MagdielCAS/magi-cli/pkg/shared"
+	"github.com/spf13/viper"
+)
+
+func TestValidateBaseURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		url     string
+		wantErr bool
+	}{
+		{"https", "https://api.example.com/v1", false},
+		{"http with port", "http://localhost:8080", false},
+		{"missing scheme", "localhost:8080", true},
+		{"unsupported scheme", "ftp://example.com", true},
+		{"missing host", "https://", true},
+		{"empty", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateBaseURL(tt.url)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("validateBaseURL(%q) error = %v, wantErr %v", tt.url, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckBaseURLReachable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	url := server.URL
+
+	if err := checkBaseURLReachable(context.Background(), url, server.Client()); err != nil {
+		t.Fatalf("expected reachable server, got %v", err)
+	}
+
+	server.Close()
+	if err := checkBaseURLReachable(context.Background(), url, &http.Client{}); err == nil {
+		t.Fatal("expected error for closed server")
+	}
+}
+
+func TestCheckBaseURLSkipsProbeForDisallowedHost(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+	}))
+	defer server.Close()
+
+	viper.Set("security.allowed_hosts", []string{"api.openai.com"})
+	defer viper.Set("security.allowed_hosts", nil)
+
+	if err := checkBaseURL(context.Background(), server.URL, false, server.Client()); err == nil {
+		t.Fatal("expected the allowed hosts check to reject the server")
+	}
+	if got := requests.Load(); got != 0 {
+		t.Fatalf("expected no request to a disallowed host, got %d", got)
+	}
+
+	viper.Set("security.allowed_hosts", []string{"127.0.0.1"})
+	if err := checkBaseURL(context.Background(), server.URL, false, server.Client()); err != nil {
+		t.Fatalf("expected allowed host to pass, got %v", err)
+	}
+	if got := requests.Load(); got != 1 {
+		t.Fatalf("expected one probe request, got %d", got)
+	}
+}
+
+func TestVerifyAPIConnection(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer good-key" {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error":{"message":"invalid api key","type":"invalid_request_error"}}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"x","object":"chat.completion","created":1,"model":"m","choices":[{"index":0,"finish_reason":"stop","message":{"role":"assistant","content":"OK"}}]}`))
+	}))
+	defer server.Close()
+
+	if err := verifyAPIConnection(context.Background(), "custom", server.URL, "good-key", "m", server.Client()); err != nil {
+		t.Fatalf("expected verification to pass, got %v", err)
+	}
+	if err := verifyAPIConnection(context.Background(), "custom", server.URL, "bad-key", "m", server.Client()); err == nil {
+		t.Fatal("expected verification to fail for invalid key")
+	}
+}
+
+func TestExportSettingsRedactsSecrets(t *testing.T) {
+	settings := map[string]any{
+		"api": map[string]any{
+			"key":         "sk-secret",
+			"heavy_model": "gpt-4",
+		},
+	}
+
+	exported, err := exportSettings(settings, "")
+	if err != nil {
+		t.Fatalf("exportSettings failed: %v", err)
+	}
+
+	if got := exported["api"].(map[string]any)["key"]; got != shared.RedactedValue {
+		t.Fatalf("expected redacted key, got %v", got)
+	}
+	if got := settings["api"].(map[string]any)["key"]; got != "sk-secret" {
+		t.Fatalf("expected original settings untouched, got %v", got)
+	}
+}
+
+func TestSetupImportWritesGlobalConfig(t *testing.T) {
+	t.Cleanup(viper.Reset)
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	project := t.TempDir()
+	t.Chdir(project)
+
+	globalPath := filepath.Join(home, ".magi", "config.yaml")
+	if err := os.MkdirAll(filepath.Dir(globalPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(globalPath, []byte("output:\n  format: text\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	localPath := filepath.Join(project, ".magi.yaml")
+	if err := os.WriteFile(localPath, []byte("commit:\n  use_heavy: true\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// loadConfiguration points viper at the project file when one exists.
+	viper.SetConfigFile(localPath)
+
+	teamFile := filepath.Join(t.TempDir(), "team.yaml")
+	if err := os.WriteFile(teamFile, []byte("api:\n  key: sk-imported\n  heavy_model: gpt-4o\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := runSetupImport(nil, []string{teamFile}); err != nil {
+		t.Fatalf("runSetupImport failed: %v", err)
+	}
+
+	global, err := os.ReadFile(globalPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"sk-imported", "gpt-4o", "format: text"} {
+		if !strings.Contains(string(global), want) {
+			t.Fatalf("expected the global config to contain %q, got:\n%s", want, global)
+		}
+	}
+	local, err := os.ReadFile(localPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(local) != "commit:\n  use_heavy: true\n" {
+		t.Fatalf("expected the project config to be left alone, got:\n%s", local)
+	}
+}
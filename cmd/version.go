@@ -7,7 +7,9 @@ package cmd
 import (
 	"fmt"
 	"runtime"
+	"runtime/debug"
 
+	"github.com/MagdielCAS/magi-cli/pkg/shared"
 	"github.com/pterm/pterm"
 	"github.com/spf13/cobra"
 )
@@ -15,7 +17,11 @@ import (
 var versionCmd = &cobra.Command{
 	Use:   "version",
 	Short: "Shows the version of magi",
-	Long: `Shows the current version of magi, build date, and commit hash.
+	Long: `Shows the current version of magi, commit hash, build date, and the Go runtime it was
+built with. Include this output in bug reports.
+
+Release builds get the commit and date from the build flags; binaries installed with
+'go install' fall back to the version control information embedded by the Go toolchain.
 
 Usage:
   magi version
@@ -28,47 +34,89 @@ Examples:
   magi version --json
 
 Run 'magi version --help' for more information on a specific command.`,
-	Run: runVersion,
+	RunE: runVersion,
+}
+
+// buildInfo describes the running binary.
+type buildInfo struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildDate string `json:"buildDate"`
+	BuiltBy   string `json:"builtBy,omitempty"`
+	GoVersion string `json:"goVersion"`
+	OSArch    string `json:"osArch"`
 }
 
 func init() {
 	rootCmd.AddCommand(versionCmd)
 }
 
-func runVersion(cmd *cobra.Command, args []string) {
-	version, _ := cmd.Flags().GetBool("json")
-	if version {
-		printVersionJSON()
-	} else {
-		printVersion()
+// versionTemplate is the output of 'magi --version'. It keeps the pcli wording, which the
+// pcli output writer relies on to let the line through.
+func versionTemplate(info buildInfo) string {
+	return pterm.Info.Sprintfln("%s is on version: %s (commit %s, built %s, %s)",
+		rootCmd.Name(), pterm.Magenta(info.Version), info.Commit, info.BuildDate, info.GoVersion)
+}
+
+func runVersion(cmd *cobra.Command, args []string) error {
+	info := currentBuildInfo()
+	if shared.IsJSONOutput() {
+		return shared.PrintJSON(info)
 	}
+	printVersion(info)
+	return nil
 }
 
-func printVersion() {
-	pterm.DefaultSection.Printf("magi CLI Version: %s", version)
+// currentBuildInfo combines the values set with ldflags and, for the ones left at their
+// defaults, the version control settings the Go toolchain embeds in the binary.
+func currentBuildInfo() buildInfo {
+	info := buildInfo{
+		Version:   version,
+		Commit:    commit,
+		BuildDate: date,
+		BuiltBy:   builtBy,
+		GoVersion: runtime.Version(),
+		OSArch:    fmt.Sprintf("%s/%s", runtime.GOOS, runtime.GOARCH),
+	}
+	if embedded, ok := debug.ReadBuildInfo(); ok {
+		applyVCSSettings(&info, embedded.Settings)
+	}
+	return info
+}
 
-	// Create a table for detailed version info
-	tableData := pterm.TableData{
-		{"Version", version},
-		{"Git Commit", commit},
-		{"Build Date", date},
-		{"Go Version", runtime.Version()},
-		{"OS/Arch", fmt.Sprintf("%s/%s", runtime.GOOS, runtime.GOARCH)},
+// applyVCSSettings fills the commit and build date from the vcs.* build settings when
+// they were not set with ldflags. Builds from a modified tree get a "-dirty" commit.
+func applyVCSSettings(info *buildInfo, settings []debug.BuildSetting) {
+	values := make(map[string]string, len(settings))
+	for _, setting := range settings {
+		values[setting.Key] = setting.Value
 	}
 
-	pterm.DefaultTable.WithHasHeader().WithData(tableData).Render()
+	if info.Commit == "none" && values["vcs.revision"] != "" {
+		info.Commit = values["vcs.revision"]
+		if values["vcs.modified"] == "true" {
+			info.Commit += "-dirty"
+		}
+	}
+	if info.BuildDate == "unknown" && values["vcs.time"] != "" {
+		info.BuildDate = values["vcs.time"]
+	}
 }
 
-func printVersionJSON() {
-	msg := fmt.Sprintf("magi CLI Version: %s", version)
-	data := map[string]any{
-		"version":   version,
-		"commit":    commit,
-		"buildDate": date,
-		"goVersion": runtime.Version(),
-		"osArch":    fmt.Sprintf("%s/%s", runtime.GOOS, runtime.GOARCH),
+func printVersion(info buildInfo) {
+	pterm.DefaultSection.Printf("magi CLI Version: %s", info.Version)
+
+	// Create a table for detailed version info
+	tableData := pterm.TableData{
+		{"Version", info.Version},
+		{"Git Commit", info.Commit},
+		{"Build Date", info.BuildDate},
+		{"Go Version", info.GoVersion},
+		{"OS/Arch", info.OSArch},
+	}
+	if info.BuiltBy != "" {
+		tableData = append(tableData, []string{"Built By", info.BuiltBy})
 	}
-	logger := pterm.DefaultLogger.WithLevel(pterm.LogLevelInfo).WithFormatter(pterm.LogFormatterJSON)
 
-	logger.Info(msg, logger.ArgsFromMap(data))
+	pterm.DefaultTable.WithHasHeader().WithData(tableData).Render()
 }
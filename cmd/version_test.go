@@ -7,6 +7,7 @@ package cmd
 import (
 	"bytes"
 	"os"
+	"runtime/debug"
 	"strings"
 	"testing"
 
@@ -34,3 +35,34 @@ func TestVersionCmd(t *testing.T) {
 		t.Errorf("expected output to contain version header, but got '%s'", output)
 	}
 }
+
+func TestApplyVCSSettings(t *testing.T) {
+	settings := []debug.BuildSetting{
+		{Key: "vcs.revision", Value: "abc123"},
+		{Key: "vcs.time", Value: "2025-01-02T03:04:05Z"},
+		{Key: "vcs.modified", Value: "true"},
+	}
+
+	info := buildInfo{Commit: "none", BuildDate: "unknown"}
+	applyVCSSettings(&info, settings)
+	if info.Commit != "abc123-dirty" || info.BuildDate != "2025-01-02T03:04:05Z" {
+		t.Fatalf("expected the embedded VCS settings to be used, got %+v", info)
+	}
+
+	// Values set with ldflags win.
+	info = buildInfo{Commit: "def456", BuildDate: "2025-02-03"}
+	applyVCSSettings(&info, settings)
+	if info.Commit != "def456" || info.BuildDate != "2025-02-03" {
+		t.Fatalf("expected the ldflags values to be kept, got %+v", info)
+	}
+}
+
+func TestVersionFlagMatchesBuildInfo(t *testing.T) {
+	info := currentBuildInfo()
+	if rootCmd.Version != info.Version {
+		t.Fatalf("expected --version to report %s, got %s", info.Version, rootCmd.Version)
+	}
+	if !strings.Contains(rootCmd.VersionTemplate(), info.Commit) {
+		t.Fatalf("expected the --version template to include the commit, got %q", rootCmd.VersionTemplate())
+	}
+}
@@ -0,0 +1,71 @@
+package commit
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	commitAuthor string
+	coAuthors    []string
+)
+
+// identityPattern matches the "Name <email>" form git and GitHub expect for authors and
+// Co-authored-by trailers.
+var identityPattern = regexp.MustCompile(`^[^<>]+ <[^<>\s@]+@[^<>\s]+>$`)
+
+// validateAttribution checks --author and every --co-authored-by value before any AI call.
+func validateAttribution() error {
+	if commitAuthor != "" {
+		if err := validateIdentity("--author", commitAuthor); err != nil {
+			return err
+		}
+	}
+	for _, coAuthor := range coAuthors {
+		if err := validateIdentity("--co-authored-by", coAuthor); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateIdentity(flag, value string) error {
+	if !identityPattern.MatchString(strings.TrimSpace(value)) {
+		return fmt.Errorf("invalid %s %q: expected \"Name <email>\"", flag, value)
+	}
+	return nil
+}
+
+// withCoAuthorTrailers appends one Co-authored-by trailer per co-author, separated from the
+// summary by a blank line so git treats them as the message body. Duplicates are dropped.
+func withCoAuthorTrailers(message string, coAuthors []string) string {
+	if len(coAuthors) == 0 {
+		return message
+	}
+
+	seen := make(map[string]bool, len(coAuthors))
+	var trailers []string
+	for _, coAuthor := range coAuthors {
+		coAuthor = strings.TrimSpace(coAuthor)
+		if coAuthor == "" || seen[strings.ToLower(coAuthor)] {
+			continue
+		}
+		seen[strings.ToLower(coAuthor)] = true
+		trailers = append(trailers, "Co-authored-by: "+coAuthor)
+	}
+	if len(trailers) == 0 {
+		return message
+	}
+	return strings.TrimRight(message, "\n") + "\n\n" + strings.Join(trailers, "\n")
+}
+
+// commitArgs builds the git commit arguments for message, applying --author and
+// --co-authored-by.
+func commitArgs(message string) []string {
+	args := []string{"commit", "-m", withCoAuthorTrailers(message, coAuthors)}
+	if author := strings.TrimSpace(commitAuthor); author != "" {
+		args = append(args, "--author", author)
+	}
+	return args
+}
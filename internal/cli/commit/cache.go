@@ -0,0 +1,123 @@
+/**
+ * Copyright © 2025 Magdiel Campelo <github.com/MagdielCAS/magi-cli>
+ * This file is part of the magi-cli
+**/
+package commit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+const defaultCacheTTL = time.Hour
+
+// messageCache stores the last commit message generated for each diff so retries
+// (for example after a pre-commit hook failure) do not need a new LLM call.
+type messageCache struct {
+	path string
+	ttl  time.Duration
+	now  func() time.Time
+}
+
+type cacheEntry struct {
+	Message   string    `json:"message"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// newMessageCache returns the commit message cache, or nil when cache.enabled is false.
+func newMessageCache() *messageCache {
+	if !viper.GetBool("cache.enabled") {
+		return nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil
+	}
+
+	ttl := time.Duration(viper.GetInt("cache.ttl")) * time.Second
+	if ttl <= 0 {
+		ttl = defaultCacheTTL
+	}
+
+	return &messageCache{
+		path: filepath.Join(home, ".magi", "cache", "commit_messages.json"),
+		ttl:  ttl,
+		now:  time.Now,
+	}
+}
+
+// Get returns the cached message for diff if it has not expired.
+func (c *messageCache) Get(diff string) (string, bool) {
+	if c == nil {
+		return "", false
+	}
+
+	entries, err := c.load()
+	if err != nil {
+		return "", false
+	}
+
+	entry, ok := entries[diffHash(diff)]
+	if !ok || c.now().Sub(entry.CreatedAt) > c.ttl {
+		return "", false
+	}
+	return entry.Message, true
+}
+
+// Put stores message for diff and drops expired entries.
+func (c *messageCache) Put(diff, message string) error {
+	if c == nil {
+		return nil
+	}
+
+	entries, err := c.load()
+	if err != nil {
+		entries = map[string]cacheEntry{}
+	}
+
+	now := c.now()
+	for key, entry := range entries {
+		if now.Sub(entry.CreatedAt) > c.ttl {
+			delete(entries, key)
+		}
+	}
+	entries[diffHash(diff)] = cacheEntry{Message: message, CreatedAt: now}
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode commit message cache: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0700); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	if err := os.WriteFile(c.path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write commit message cache: %w", err)
+	}
+	return nil
+}
+
+func (c *messageCache) load() (map[string]cacheEntry, error) {
+	data, err := os.ReadFile(c.path)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := map[string]cacheEntry{}
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func diffHash(diff string) string {
+	sum := sha256.Sum256([]byte(diff))
+	return hex.EncodeToString(sum[:])
+}
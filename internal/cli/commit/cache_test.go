@@ -0,0 +1,56 @@
+package commit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestMessageCache(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	cache := &messageCache{
+		path: filepath.Join(t.TempDir(), "cache", "commit_messages.json"),
+		ttl:  time.Hour,
+		now:  func() time.Time { return now },
+	}
+
+	if _, ok := cache.Get("diff"); ok {
+		t.Fatal("expected empty cache miss")
+	}
+
+	if err := cache.Put("diff", "feat(cli): ✨ add cache"); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	message, ok := cache.Get("diff")
+	if !ok || message != "feat(cli): ✨ add cache" {
+		t.Fatalf("expected cache hit, got %q (%v)", message, ok)
+	}
+	if _, ok := cache.Get("other diff"); ok {
+		t.Fatal("expected miss for a different diff")
+	}
+
+	info, err := os.Stat(cache.path)
+	if err != nil {
+		t.Fatalf("stat cache file: %v", err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Fatalf("expected 0600 permissions, got %v", info.Mode().Perm())
+	}
+
+	now = now.Add(2 * time.Hour)
+	if _, ok := cache.Get("diff"); ok {
+		t.Fatal("expected expired entry to miss")
+	}
+}
+
+func TestNilMessageCache(t *testing.T) {
+	var cache *messageCache
+	if _, ok := cache.Get("diff"); ok {
+		t.Fatal("expected nil cache to miss")
+	}
+	if err := cache.Put("diff", "msg"); err != nil {
+		t.Fatalf("expected nil cache Put to be a no-op, got %v", err)
+	}
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/MagdielCAS/magi-cli/pkg/utils"
 	"github.com/pterm/pterm"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 var commitCmd = &cobra.Command{
@@ -27,6 +28,48 @@ and generates a conventional commit message using the configured AI provider.
 Data handling:
   • The command sends the git diff for the selected files to your configured AI provider.
   • No other file contents or metadata leave your machine.
+  • Values matching the redaction patterns (common API key formats, private keys,
+    password/token assignments and security.redaction_patterns) are replaced with
+    ***REDACTED*** before the diff is sent.
+  • Before any AI call the diff is scanned for likely secrets (known key formats, private
+    keys, sensitive .env assignments, high-entropy strings); matches are listed by file
+    and line and you must confirm to continue. Use --no-secrets-check to skip the scan.
+  • When cache.enabled is true, the last message generated for each diff is kept in
+    ~/.magi/cache/commit_messages.json (0600, keyed by diff hash, expires after cache.ttl)
+    so retries reuse it instead of calling the AI provider again.
+
+Use --group-staged to treat everything at once as a single logical change: all
+working-tree changes (including untracked files) are staged together with what is
+already staged, the selection UI is skipped, and one message is generated.
+
+Use --patch to stage only some hunks: after the file selection every hunk of the chosen
+files is shown and you decide whether to stage it, like git add -p. The message is then
+generated from the staged hunks. Untracked and binary files are staged whole. Aborting,
+cancelling the message, or any failure before the commit restores the original staging.
+
+Use --ignore-whitespace (config: commit.ignore_whitespace) to leave whitespace-only
+changes out of the diff sent to the AI provider, so reformatting does not drown the
+real change. When nothing but whitespace changed, magi says so and stops.
+
+Use --author "Name <email>" to record someone else as the commit author, and
+--co-authored-by "Name <email>" (repeatable) to add Co-authored-by trailers to the
+message body, which GitHub uses to credit pair-programming partners. Both apply to
+every commit created by --split as well.
+
+Use --split (experimental) to turn a messy working tree into a reviewable series: the
+AI proposes groups of files with a message each, and every confirmed group is staged
+and committed on its own. Files you skip, or everything left when you abort, get their
+original staging state back. Groups work on whole files.
+
+Messages are generated with the light model. Use --heavy (config: commit.use_heavy) to
+generate them with the heavy model for complex diffs; when a message fails validation,
+the corrected one is always requested from the heavy model if one is configured.
+
+Use --candidates N (up to 5) to generate several messages in parallel and pick the one
+that reads best; only candidates passing validation are offered.
+
+At the confirmation prompt you can use the message, edit it in $EDITOR (the edited
+message is validated again), regenerate it, or cancel.
 
 Usage:
   magi commit
@@ -38,16 +81,79 @@ Examples:
   # Select unstaged files interactively and commit them with an AI message
   magi commit
 
+  # Stage every change and commit it as one logical change
+  magi commit --group-staged
+
+  # Split all changes into several logical commits
+  magi commit --split
+
+  # Commit only some hunks of the changed files
+  magi commit --patch
+
+  # Choose among three suggested messages
+  magi commit --candidates 3
+
+  # Describe only the substantive changes of a reformatted file
+  magi commit --ignore-whitespace
+
+  # Credit a pairing partner
+  magi commit --co-authored-by "Jane Doe <jane@example.com>"
+
+  # Commit on behalf of someone else
+  magi commit --author "John Roe <john@example.com>"
+
 Security note: Requests are performed with the shared hardened HTTP client and only include
 the contextual diff needed to craft the message.`,
 	RunE: runCommit,
 }
 
+const (
+	// ignoreWhitespaceKey drops whitespace-only changes from the diff sent to the AI provider.
+	ignoreWhitespaceKey = "commit.ignore_whitespace"
+	// useHeavyKey generates messages with the heavy model instead of the light one.
+	useHeavyKey = "commit.use_heavy"
+)
+
+var (
+	groupStaged    bool
+	splitCommits   bool
+	patchMode      bool
+	noSecretsCheck bool
+	candidates     int
+)
+
+// maxCandidates caps --candidates, since every candidate is a separate AI request.
+const maxCandidates = 5
+
 func CommitCmd() *cobra.Command {
+	commitCmd.Flags().BoolVar(&groupStaged, "group-staged", false, "Stage all working-tree changes and commit them together as one logical change")
+	commitCmd.Flags().BoolVar(&splitCommits, "split", false, "Experimental: let the AI split all changes into a series of logical commits")
+	commitCmd.Flags().BoolVar(&patchMode, "patch", false, "Pick the hunks to stage interactively, like git add -p, before generating the message")
+	commitCmd.Flags().BoolVar(&noSecretsCheck, "no-secrets-check", false, "Skip the preflight scan that warns when the diff appears to add secrets")
+	commitCmd.Flags().StringVar(&commitAuthor, "author", "", "Record the commit with this author instead of the git identity (\"Name <email>\")")
+	commitCmd.Flags().StringArrayVar(&coAuthors, "co-authored-by", nil, "Add a Co-authored-by trailer to the message (\"Name <email>\", repeatable)")
+	commitCmd.Flags().Bool("ignore-whitespace", false, "Ignore whitespace-only changes in the diff sent to the AI provider (config: commit.ignore_whitespace)")
+	commitCmd.Flags().IntVar(&candidates, "candidates", 1, fmt.Sprintf("Generate up to %d candidate messages and pick one", maxCandidates))
+	commitCmd.Flags().Bool("heavy", false, "Generate the message with the heavy model instead of the light one (config: commit.use_heavy)")
+	commitCmd.MarkFlagsMutuallyExclusive("group-staged", "split", "patch")
+	viper.BindPFlag(ignoreWhitespaceKey, commitCmd.Flags().Lookup("ignore-whitespace"))
+	viper.BindPFlag(useHeavyKey, commitCmd.Flags().Lookup("heavy"))
 	return commitCmd
 }
 
-func runCommit(cmd *cobra.Command, _ []string) error {
+func runCommit(cmd *cobra.Command, _ []string) (err error) {
+	if err := validateAttribution(); err != nil {
+		return err
+	}
+	if patchMode {
+		if err := requirePatchSession(); err != nil {
+			return err
+		}
+	}
+	if candidates < 1 || candidates > maxCandidates {
+		return fmt.Errorf("invalid --candidates %d: expected a number from 1 to %d", candidates, maxCandidates)
+	}
+
 	if err := git.EnsureGitRepo(cmd.Context()); err != nil {
 		return err
 	}
@@ -57,16 +163,51 @@ func runCommit(cmd *cobra.Command, _ []string) error {
 		return err
 	}
 
+	if splitCommits {
+		return runSplitCommit(cmd.Context(), runtimeCtx)
+	}
+
 	staged, err := listGitFiles(cmd.Context(), true)
 	if err != nil {
 		return err
 	}
 
+	committed := false
 	var targetFiles []string
 	switch {
+	case patchMode:
+		snapshot, snapshotErr := snapshotStaging(cmd.Context())
+		if snapshotErr != nil {
+			return snapshotErr
+		}
+		defer func() {
+			if committed {
+				return
+			}
+			if restoreErr := snapshot.restore(cmd.Context(), nil); restoreErr != nil {
+				pterm.Error.Printf("Failed to restore the original staging state: %v\n", restoreErr)
+				err = errors.Join(err, restoreErr)
+			}
+		}()
+		targetFiles, err = selectPatchHunks(cmd.Context())
+		if errors.Is(err, errPatchAborted) {
+			pterm.Warning.Println("Hunk selection aborted; restoring the original staging state.")
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	case groupStaged:
+		targetFiles, err = stageAllChanges(cmd.Context())
+		if err != nil {
+			return err
+		}
+		pterm.Info.Printf("Grouping %d file(s) (staged and working tree) into a single commit.\n", len(targetFiles))
 	case len(staged) > 0:
 		pterm.Info.Printf("Detected %d staged file(s); skipping selection UI.\n", len(staged))
 		targetFiles = staged
+	case !shared.IsInteractive():
+		return shared.NonInteractiveError("staged files or --group-staged")
 	default:
 		targetFiles, err = promptForUnstagedFiles(cmd.Context())
 		if err != nil {
@@ -79,24 +220,189 @@ func runCommit(cmd *cobra.Command, _ []string) error {
 	}
 
 	diff, err := diffAgainstOrigin(cmd.Context(), targetFiles)
+	if errors.Is(err, git.ErrWhitespaceOnly) {
+		pterm.Warning.Printf("%v (--ignore-whitespace); no commit created.\n", err)
+		return nil
+	}
 	if err != nil {
 		return err
 	}
 
-	pterm.Info.Printf("Using light model: %s\n", runtimeCtx.LightModel)
+	cache := newMessageCache()
+	message, cached := cache.Get(diff)
+	if cached {
+		pterm.Info.Println("Reusing the commit message generated earlier for this diff (choose Regenerate for a new one).")
+	} else {
+		message, err = generateCommitMessage(cmd.Context(), runtimeCtx, diff)
+		if err != nil {
+			return err
+		}
+		cacheMessage(cache, diff, message)
+	}
+
+	for {
+		pterm.DefaultBox.WithTitle(shared.T("commit.suggested_title")).Println(message)
+
+		action, err := chooseCommitAction()
+		if err != nil {
+			return err
+		}
+
+		switch action {
+		case commitActionEdit:
+			edited, err := shared.OpenEditor(message, ".txt")
+			if err != nil {
+				pterm.Error.Printf("Failed to open editor: %v\n", err)
+				continue
+			}
+			edited = normalizeCommitMessage(edited)
+			if validationErr := validateCommitFormat(edited); validationErr != nil {
+				pterm.Warning.Printf("Edited commit message failed validation: %v. Keeping the previous message.\n", validationErr)
+				continue
+			}
+			message = edited
+			cacheMessage(cache, diff, message)
+			continue
+		case commitActionRegenerate:
+			regenerated, err := generateCommitMessage(cmd.Context(), runtimeCtx, diff)
+			if err != nil {
+				pterm.Error.Printf("Failed to regenerate commit message: %v\n", err)
+				continue
+			}
+			message = regenerated
+			cacheMessage(cache, diff, message)
+			continue
+		case commitActionCancel:
+			pterm.Warning.Println(shared.T("commit.aborted"))
+			return printCommitResult(commitResult{Message: message, Files: targetFiles})
+		}
+
+		break
+	}
+
+	if err := gitCommit(cmd.Context(), message); err != nil {
+		return err
+	}
+	committed = true
+
+	pterm.Success.Println(shared.T("commit.created"))
+	return printCommitResult(commitResult{Message: message, Files: targetFiles, Committed: true})
+}
+
+// Message keys of the options offered for a suggested commit message.
+const (
+	commitActionUse        = "commit.action.use"
+	commitActionEdit       = "commit.action.edit"
+	commitActionRegenerate = "commit.action.regenerate"
+	commitActionCancel     = "commit.action.cancel"
+)
+
+var commitActions = []string{commitActionUse, commitActionEdit, commitActionRegenerate, commitActionCancel}
+
+// chooseCommitAction asks what to do with the suggested message. Non-interactive sessions
+// use the message only when --assume-yes (or ui.confirm_default: yes) is set.
+func chooseCommitAction() (string, error) {
+	if !shared.IsInteractive() {
+		if !shared.ConfirmDefault(false) {
+			return "", shared.NonInteractiveError("--assume-yes")
+		}
+		return commitActionUse, nil
+	}
+
+	defaultAction := commitActionUse
+	if !shared.ConfirmDefault(true) {
+		defaultAction = commitActionCancel
+	}
+
+	choice, err := pterm.DefaultInteractiveSelect.
+		WithOptions(shared.TOptions(commitActions)).
+		WithDefaultOption(shared.T(defaultAction)).
+		Show(shared.T("commit.confirm"))
+	if err != nil {
+		return "", fmt.Errorf("confirmation prompt failed: %w", err)
+	}
+	return shared.OptionKey(commitActions, choice), nil
+}
+
+// generateCommitMessage asks the light model (the heavy one with --heavy) for a message
+// and retries once with guidance when the result does not pass validation.
+func generateCommitMessage(ctx context.Context, runtimeCtx *shared.RuntimeContext, diff string) (string, error) {
+	variant := commitModelVariant(runtimeCtx)
+	pterm.Info.Printf("Using %s model: %s\n", variantName(variant), variantModel(runtimeCtx, variant))
 	pterm.Info.Println("Generating commit message with the configured AI provider...")
 
-	message, err := llm.GenerateCommitMessage(cmd.Context(), runtimeCtx, diff)
+	if candidates > 1 {
+		return generateCommitCandidates(ctx, runtimeCtx, diff, variant)
+	}
+
+	message, err := llm.GenerateCommitMessage(ctx, runtimeCtx, diff, variant)
 	if err != nil {
-		return err
+		return "", err
+	}
+
+	return validateOrRetry(ctx, runtimeCtx, diff, message), nil
+}
+
+// generateCommitCandidates requests --candidates messages and lets the user pick one of
+// those passing validation. When none passes, the first goes through the usual retry.
+func generateCommitCandidates(ctx context.Context, runtimeCtx *shared.RuntimeContext, diff string, variant llm.ModelVariant) (string, error) {
+	generated, err := llm.GenerateCommitCandidates(ctx, runtimeCtx, diff, variant, candidates)
+	if err != nil {
+		return "", err
+	}
+
+	valid := validCandidates(generated)
+	switch {
+	case len(valid) == 0:
+		pterm.Warning.Println("None of the candidate messages passed validation.")
+		return validateOrRetry(ctx, runtimeCtx, diff, generated[0]), nil
+	case len(valid) == 1:
+		pterm.Info.Println("Only one distinct valid candidate was generated.")
+		return valid[0], nil
+	}
+	return chooseCandidate(valid)
+}
+
+// validCandidates normalizes the candidates and keeps the distinct ones passing validation.
+func validCandidates(generated []string) []string {
+	seen := make(map[string]bool, len(generated))
+	var valid []string
+	for _, message := range generated {
+		message = normalizeCommitMessage(utils.RemoveCodeBlock(message))
+		if seen[message] || validateCommitFormat(message) != nil {
+			continue
+		}
+		seen[message] = true
+		valid = append(valid, message)
 	}
+	return valid
+}
+
+// chooseCandidate asks which candidate to continue with. Non-interactive sessions take the
+// first one, generated at temperature 0.
+func chooseCandidate(valid []string) (string, error) {
+	if !shared.IsInteractive() {
+		return valid[0], nil
+	}
+	choice, err := pterm.DefaultInteractiveSelect.
+		WithOptions(valid).
+		WithDefaultOption(valid[0]).
+		Show("Pick a commit message (you can still edit it next)")
+	if err != nil {
+		return "", fmt.Errorf("candidate selection failed: %w", err)
+	}
+	return choice, nil
+}
 
+// validateOrRetry normalizes message and, when it fails validation, asks once for a
+// corrected one. The original message is kept when the retry fails.
+func validateOrRetry(ctx context.Context, runtimeCtx *shared.RuntimeContext, diff, message string) string {
 	message = utils.RemoveCodeBlock(message)
 	message = normalizeCommitMessage(message)
 	if validationErr := validateCommitFormat(message); validationErr != nil {
 		pterm.Warning.Printf("Generated commit message failed validation: %v. Retrying with guidance...\n", validationErr)
 		originalMessage := message
-		if fixedMessage, err := retryCommitMessage(cmd.Context(), runtimeCtx, diff, message, validationErr); err == nil {
+		if fixedMessage, err := retryCommitMessage(ctx, runtimeCtx, diff, message, validationErr); err == nil {
 			message = fixedMessage
 		} else {
 			pterm.Error.PrintOnError(err)
@@ -104,24 +410,50 @@ func runCommit(cmd *cobra.Command, _ []string) error {
 		}
 	}
 
-	pterm.DefaultBox.WithTitle("Suggested Commit Message").Println(message)
+	return message
+}
 
-	confirmed, err := pterm.DefaultInteractiveConfirm.WithDefaultValue(true).
-		Show("Use this commit message?")
-	if err != nil {
-		return fmt.Errorf("confirmation prompt failed: %w", err)
+// commitModelVariant returns the model tier used to generate messages: the light model
+// unless --heavy (commit.use_heavy) is set and a heavy model is configured.
+func commitModelVariant(runtimeCtx *shared.RuntimeContext) llm.ModelVariant {
+	if viper.GetBool(useHeavyKey) && runtimeCtx.HeavyModel != "" {
+		return llm.ModelVariantHeavy
 	}
-	if !confirmed {
-		pterm.Warning.Println("Commit aborted by user.")
-		return nil
+	return llm.ModelVariantLight
+}
+
+func variantName(variant llm.ModelVariant) string {
+	if variant == llm.ModelVariantHeavy {
+		return "heavy"
 	}
+	return "light"
+}
 
-	if err := gitCommit(cmd.Context(), message); err != nil {
-		return err
+func variantModel(runtimeCtx *shared.RuntimeContext, variant llm.ModelVariant) string {
+	if variant == llm.ModelVariantHeavy {
+		return runtimeCtx.HeavyModel
 	}
+	return runtimeCtx.LightModel
+}
 
-	pterm.Success.Println("Commit created successfully.")
-	return nil
+func cacheMessage(cache *messageCache, diff, message string) {
+	if err := cache.Put(diff, message); err != nil {
+		pterm.Debug.Printf("Unable to cache commit message: %v\n", err)
+	}
+}
+
+// commitResult is the structured output printed in JSON mode.
+type commitResult struct {
+	Message   string   `json:"message"`
+	Files     []string `json:"files"`
+	Committed bool     `json:"committed"`
+}
+
+func printCommitResult(result commitResult) error {
+	if !shared.IsJSONOutput() {
+		return nil
+	}
+	return shared.PrintJSON(result)
 }
 
 func listGitFiles(ctx context.Context, staged bool) ([]string, error) {
@@ -146,6 +478,38 @@ func listGitFiles(ctx context.Context, staged bool) ([]string, error) {
 }
 
 func promptForUnstagedFiles(ctx context.Context) ([]string, error) {
+	entries, err := chooseUnstagedEntries(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		paths = append(paths, entry.Path)
+	}
+
+	if err := gitAdd(ctx, paths); err != nil {
+		return nil, err
+	}
+
+	return paths, nil
+}
+
+// selectPatchHunks asks for the files and then the hunks to stage (--patch) and returns
+// every staged file, including what was staged before.
+func selectPatchHunks(ctx context.Context) ([]string, error) {
+	entries, err := chooseUnstagedEntries(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := stagePatchHunks(ctx, entries); err != nil {
+		return nil, err
+	}
+	return listGitFiles(ctx, true)
+}
+
+// chooseUnstagedEntries lets the user select among the files with unstaged changes.
+func chooseUnstagedEntries(ctx context.Context) ([]statusEntry, error) {
 	statusEntries, err := gitStatusEntries(ctx)
 	if err != nil {
 		return nil, err
@@ -156,11 +520,11 @@ func promptForUnstagedFiles(ctx context.Context) ([]string, error) {
 	}
 
 	options := make([]string, 0, len(statusEntries))
-	displayToPath := make(map[string]string, len(statusEntries))
+	displayToEntry := make(map[string]statusEntry, len(statusEntries))
 	for _, entry := range statusEntries {
-		display := fmt.Sprintf("%s %s", entry.Status, entry.Path)
+		display := entry.Label()
 		options = append(options, display)
-		displayToPath[display] = entry.Path
+		displayToEntry[display] = entry
 	}
 
 	selected, err := pterm.DefaultInteractiveMultiselect.
@@ -175,55 +539,110 @@ func promptForUnstagedFiles(ctx context.Context) ([]string, error) {
 		return nil, errors.New("no files were selected")
 	}
 
-	var paths []string
+	var entries []statusEntry
 	for _, label := range selected {
-		if path := displayToPath[label]; path != "" {
-			paths = append(paths, path)
+		if entry, ok := displayToEntry[label]; ok {
+			entries = append(entries, entry)
 		}
 	}
+	return entries, nil
+}
 
-	if err := gitAdd(ctx, paths); err != nil {
-		return nil, err
+// stageAllChanges stages every working-tree change, including untracked files, and
+// returns the resulting staged file list.
+func stageAllChanges(ctx context.Context) ([]string, error) {
+	if _, err := git.RunGit(ctx, "add", "--all"); err != nil {
+		return nil, fmt.Errorf("failed to stage working-tree changes: %w", err)
 	}
-
-	return paths, nil
+	return listGitFiles(ctx, true)
 }
 
+// statusEntry is one path of git status. For renames and copies Path is the new path,
+// which is what git add needs, and OldPath the original one.
 type statusEntry struct {
-	Status string
-	Path   string
+	Status  string
+	Path    string
+	OldPath string
 }
 
+// Unstaged reports whether the entry has working-tree changes or is untracked.
+func (e statusEntry) Unstaged() bool {
+	return e.Status == "??" || e.Status[1] != ' '
+}
+
+// Label describes the entry for the file selection, e.g. "renamed: old.go -> new.go".
+func (e statusEntry) Label() string {
+	path := e.Path
+	if e.OldPath != "" {
+		path = fmt.Sprintf("%s -> %s", e.OldPath, e.Path)
+	}
+	return fmt.Sprintf("%s: %s", statusDescription(e.Status), path)
+}
+
+// statusDescription names a two-letter status code, preferring the working-tree side.
+func statusDescription(status string) string {
+	if status == "??" {
+		return "untracked"
+	}
+	code := status[1]
+	if code == ' ' {
+		code = status[0]
+	}
+	switch code {
+	case 'M':
+		return "modified"
+	case 'A':
+		return "added"
+	case 'D':
+		return "deleted"
+	case 'R':
+		return "renamed"
+	case 'C':
+		return "copied"
+	case 'T':
+		return "type changed"
+	case 'U':
+		return "conflicted"
+	default:
+		return strings.TrimSpace(status)
+	}
+}
+
+// gitStatusEntries lists the files with unstaged changes, untracked files included.
 func gitStatusEntries(ctx context.Context) ([]statusEntry, error) {
-	output, err := git.RunGit(ctx, "status", "--short", "--untracked-files")
+	output, err := git.RunGit(ctx, "status", "--porcelain", "-z", "--untracked-files")
 	if err != nil {
 		return nil, err
 	}
 
 	var entries []statusEntry
-	lines := strings.Split(strings.TrimRight(output, "\n"), "\n")
-	for _, line := range lines {
-		line = strings.TrimRight(line, " \r")
-		if strings.TrimSpace(line) == "" {
-			continue
+	for _, entry := range parseStatusEntries(output) {
+		if entry.Unstaged() {
+			entries = append(entries, entry)
 		}
+	}
+	return entries, nil
+}
 
-		if len(line) < 3 {
+// parseStatusEntries reads `git status --porcelain -z` output. Paths are NUL-terminated and
+// unquoted; a rename or copy entry is followed by the original path.
+func parseStatusEntries(output string) []statusEntry {
+	var entries []statusEntry
+	fields := strings.Split(output, "\x00")
+	for i := 0; i < len(fields); i++ {
+		field := fields[i]
+		if len(field) < 4 {
 			continue
 		}
 
-		status := line[:2]
-		path := strings.TrimSpace(line[3:])
-
-		if len(status) == 2 && (status == "??" || status[1] != ' ') {
-			entries = append(entries, statusEntry{
-				Status: status,
-				Path:   path,
-			})
+		entry := statusEntry{Status: field[:2], Path: field[3:]}
+		if (entry.Status[0] == 'R' || entry.Status[0] == 'C' || entry.Status[1] == 'R' || entry.Status[1] == 'C') && i+1 < len(fields) {
+			i++
+			entry.OldPath = fields[i]
 		}
+		entries = append(entries, entry)
 	}
-
-	return entries, nil
+	return entries
 }
 
 func gitAdd(ctx context.Context, files []string) error {
@@ -239,7 +658,7 @@ func diffAgainstOrigin(ctx context.Context, files []string) (string, error) {
 	}
 
 	remoteRef := fmt.Sprintf("origin/%s", currentBranch)
-	args := []string{"diff", "--cached"}
+	args := []string{"--cached"}
 
 	// Check if the remote branch exists
 	if _, err := git.RunGit(ctx, "rev-parse", "--verify", remoteRef); err == nil {
@@ -254,7 +673,7 @@ func diffAgainstOrigin(ctx context.Context, files []string) (string, error) {
 	args = append(args, "--")
 	args = append(args, files...)
 
-	diff, err := git.RunGit(ctx, args...)
+	diff, err := git.Diff(ctx, viper.GetBool(ignoreWhitespaceKey), args...)
 	if err != nil {
 		return "", err
 	}
@@ -263,7 +682,40 @@ func diffAgainstOrigin(ctx context.Context, files []string) (string, error) {
 		return "", errors.New("diff is empty")
 	}
 
-	return diff, nil
+	if err := checkDiffForSecrets(diff); err != nil {
+		return "", err
+	}
+	return redactDiff(diff)
+}
+
+// checkDiffForSecrets warns about lines that appear to add secrets and asks before any
+// AI call is made, unless --no-secrets-check is set.
+func checkDiffForSecrets(diff string) error {
+	if noSecretsCheck {
+		return nil
+	}
+	proceed, err := shared.ConfirmSecretFindings(shared.ScanDiffForSecrets(diff))
+	if err != nil {
+		return err
+	}
+	if !proceed {
+		return errors.New("commit aborted: the diff appears to add secrets")
+	}
+	return nil
+}
+
+// redactDiff scrubs secrets matching the configured redaction patterns before the diff
+// is sent to the AI provider.
+func redactDiff(diff string) (string, error) {
+	redactor, err := shared.RedactorFromConfig()
+	if err != nil {
+		return "", err
+	}
+	redacted, count := redactor.Redact(diff)
+	if count > 0 {
+		pterm.Info.Printf("Redacted %d secret(s) from the diff before sending it to the AI provider.\n", count)
+	}
+	return redacted, nil
 }
 
 func gitCommit(ctx context.Context, message string) error {
@@ -274,7 +726,7 @@ func gitCommit(ctx context.Context, message string) error {
 		pterm.Warning.Printf("Detected pre-commit hook at %s. Hook output will be shown if it fails.\n", hookPath)
 	}
 
-	result, err := git.RunGitRaw(ctx, "commit", "-m", message)
+	result, err := git.RunGitRaw(ctx, commitArgs(message)...)
 	if err != nil {
 		git.LogGitFailure(err)
 		if hasHook && hookErr == nil {
@@ -298,38 +750,38 @@ func normalizeCommitMessage(message string) string {
 	return strings.TrimSpace(message)
 }
 
+// validateCommitFormat checks message against commit.format (see llm.CommitFormat): the
+// type must be a conventional commit type, and when the format includes them the scope
+// must be present and the gitmoji must be one of the allowed ones.
 func validateCommitFormat(message string) error {
 	if message == "" {
 		return errors.New("commit message is empty")
 	}
 
-	typeEnd := strings.Index(message, ":")
-	if typeEnd == -1 {
-		return errors.New("missing ':' separator")
-	}
-
-	meta := message[:typeEnd]
-	if !strings.Contains(meta, "(") || !strings.HasSuffix(meta, ")") {
-		return errors.New("missing scope in commit message")
-	}
-
-	scopeStart := strings.Index(meta, "(")
-	commitType := meta[:scopeStart]
-	if !isAllowedCommitType(commitType) {
-		return fmt.Errorf("unsupported commit type %q", commitType)
-	}
-
-	payload := strings.TrimSpace(message[typeEnd+1:])
-	if payload == "" {
-		return errors.New("missing commit description")
+	fields, names, err := llm.ParseCommitMessage(llm.CommitFormat(), message)
+	if err != nil {
+		return err
 	}
 
-	fields := strings.Fields(payload)
-	if len(fields) < 2 {
-		return errors.New("commit description must include an emoji and summary text")
-	}
-	if !isAllowedGitmoji(fields[0]) {
-		return fmt.Errorf("unsupported gitmoji %q", fields[0])
+	for _, name := range names {
+		switch name {
+		case "Type":
+			if !isAllowedCommitType(fields.Type) {
+				return fmt.Errorf("unsupported commit type %q", fields.Type)
+			}
+		case "Scope":
+			if fields.Scope == "" {
+				return errors.New("missing scope in commit message")
+			}
+		case "Gitmoji":
+			if !isAllowedGitmoji(fields.Gitmoji) {
+				return fmt.Errorf("unsupported gitmoji %q", fields.Gitmoji)
+			}
+		case "Description":
+			if fields.Description == "" {
+				return errors.New("missing commit description")
+			}
+		}
 	}
 
 	return nil
@@ -363,8 +815,14 @@ func isAllowedGitmoji(emoji string) bool {
 	return ok
 }
 
+// retryCommitMessage asks for a corrected message. The retry escalates to the heavy model
+// when one is configured, since the first answer already failed validation.
 func retryCommitMessage(ctx context.Context, runtimeCtx *shared.RuntimeContext, diff, previous string, validationErr error) (string, error) {
-	fixed, err := llm.FixCommitMessage(ctx, runtimeCtx, diff, previous, validationErr)
+	variant := llm.ModelVariantLight
+	if runtimeCtx.HeavyModel != "" {
+		variant = llm.ModelVariantHeavy
+	}
+	fixed, err := llm.FixCommitMessage(ctx, runtimeCtx, diff, previous, validationErr, variant)
 	if err != nil {
 		return "", fmt.Errorf("unable to refine commit message after validation failure: %w", err)
 	}
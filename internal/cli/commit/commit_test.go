@@ -1,6 +1,13 @@
 package commit
 
-import "testing"
+import (
+	"strings"
+	"testing"
+
+	"github.com/MagdielCAS/magi-cli/pkg/llm"
+	"github.com/MagdielCAS/magi-cli/pkg/shared"
+	"github.com/spf13/viper"
+)
 
 func TestNormalizeCommitMessage(t *testing.T) {
 	input := "feat(app): ✨ add\n\nextra details"
@@ -33,3 +40,91 @@ func TestValidateCommitFormat(t *testing.T) {
 		}
 	}
 }
+
+func TestValidateCommitFormatCustomFormats(t *testing.T) {
+	t.Cleanup(viper.Reset)
+
+	viper.Set(llm.CommitFormatKey, "[{{.Type}}] {{.Description}}")
+	if err := validateCommitFormat("[fix] prevent crash"); err != nil {
+		t.Fatalf("expected bracket format to be valid, got %v", err)
+	}
+	if err := validateCommitFormat("[oops] prevent crash"); err == nil {
+		t.Fatal("expected unsupported type to fail")
+	}
+
+	viper.Set(llm.CommitFormatKey, "{{.Type}}: {{.Description}}")
+	if err := validateCommitFormat("feat: add option"); err != nil {
+		t.Fatalf("expected scope-less format to be valid, got %v", err)
+	}
+	if err := validateCommitFormat("feat(cli): ✨ add option"); err == nil {
+		t.Fatal("expected the default format to fail when a scope-less format is configured")
+	}
+}
+
+func TestValidateIdentity(t *testing.T) {
+	valid := []string{"Jane Doe <jane@example.com>", "jane <jane+pair@example.co.uk>"}
+	for _, value := range valid {
+		if err := validateIdentity("--author", value); err != nil {
+			t.Fatalf("expected %q to be valid, got %v", value, err)
+		}
+	}
+
+	invalid := []string{"", "Jane Doe", "jane@example.com", "<jane@example.com>", "Jane <jane>", "Jane <jane@example.com> extra"}
+	for _, value := range invalid {
+		if err := validateIdentity("--author", value); err == nil {
+			t.Fatalf("expected %q to be rejected", value)
+		}
+	}
+}
+
+func TestCommitArgsAttribution(t *testing.T) {
+	t.Cleanup(func() { commitAuthor, coAuthors = "", nil })
+
+	got := strings.Join(commitArgs("feat(cli): ✨ add"), "|")
+	if got != "commit|-m|feat(cli): ✨ add" {
+		t.Fatalf("unexpected args without attribution: %q", got)
+	}
+
+	commitAuthor = "Jane Doe <jane@example.com>"
+	coAuthors = []string{"John Roe <john@example.com>", "john roe <JOHN@example.com>", "Ana Lima <ana@example.com>"}
+	args := commitArgs("feat(cli): ✨ add")
+	wantMessage := "feat(cli): ✨ add\n\nCo-authored-by: John Roe <john@example.com>\nCo-authored-by: Ana Lima <ana@example.com>"
+	if args[2] != wantMessage {
+		t.Fatalf("expected message %q, got %q", wantMessage, args[2])
+	}
+	if len(args) != 5 || args[3] != "--author" || args[4] != commitAuthor {
+		t.Fatalf("expected --author to be passed, got %q", args)
+	}
+}
+
+func TestCommitModelVariant(t *testing.T) {
+	t.Cleanup(viper.Reset)
+	runtimeCtx := &shared.RuntimeContext{LightModel: "light", HeavyModel: "heavy"}
+
+	if got := commitModelVariant(runtimeCtx); got != llm.ModelVariantLight {
+		t.Fatalf("expected the light model by default, got %v", got)
+	}
+
+	viper.Set(useHeavyKey, true)
+	if got := commitModelVariant(runtimeCtx); got != llm.ModelVariantHeavy {
+		t.Fatalf("expected the heavy model with commit.use_heavy, got %v", got)
+	}
+	if got := commitModelVariant(&shared.RuntimeContext{LightModel: "light"}); got != llm.ModelVariantLight {
+		t.Fatalf("expected the light model when no heavy model is configured, got %v", got)
+	}
+}
+
+func TestValidCandidates(t *testing.T) {
+	generated := []string{
+		"feat(cli): ✨ add candidates",
+		"```\nfeat(cli): ✨ add candidates\n```",
+		"add candidates",
+		"fix(cli): 🐛 pick a message\n\nbody",
+	}
+
+	got := validCandidates(generated)
+	want := []string{"feat(cli): ✨ add candidates", "fix(cli): 🐛 pick a message"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Fatalf("validCandidates() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,189 @@
+package commit
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/MagdielCAS/magi-cli/pkg/llm"
+)
+
+func TestStageAllChanges(t *testing.T) {
+	initCommitTestRepo(t)
+	writeTestFile(t, "README.md", "# changed\n")
+	writeTestFile(t, "new.txt", "untracked\n")
+	writeTestFile(t, "staged.txt", "staged\n")
+	runGitCmd(t, "add", "staged.txt")
+
+	files, err := stageAllChanges(context.Background())
+	if err != nil {
+		t.Fatalf("stageAllChanges failed: %v", err)
+	}
+
+	want := []string{"README.md", "new.txt", "staged.txt"}
+	if !reflect.DeepEqual(files, want) {
+		t.Fatalf("stageAllChanges() = %v, want %v", files, want)
+	}
+}
+
+// initCommitTestRepo creates a repository with one commit and makes it the working directory.
+func initCommitTestRepo(t *testing.T) {
+	t.Helper()
+	t.Chdir(t.TempDir())
+
+	runGitCmd(t, "init", "--initial-branch=main")
+	runGitCmd(t, "config", "user.name", "magi-tests")
+	runGitCmd(t, "config", "user.email", "magi@example.com")
+	runGitCmd(t, "config", "commit.gpgsign", "false")
+	writeTestFile(t, "README.md", "# test repo\n")
+	runGitCmd(t, "add", "README.md")
+	runGitCmd(t, "commit", "-m", "init")
+}
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+}
+
+func runGitCmd(t *testing.T, args ...string) string {
+	t.Helper()
+	output, err := exec.Command("git", args...).CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s failed: %v\n%s", strings.Join(args, " "), err, string(output))
+	}
+	return string(output)
+}
+
+func TestStagingSnapshotRestore(t *testing.T) {
+	initCommitTestRepo(t)
+	ctx := context.Background()
+
+	writeTestFile(t, "README.md", "# staged change\n")
+	writeTestFile(t, "added.txt", "new file\n")
+	runGitCmd(t, "add", "README.md", "added.txt")
+	writeTestFile(t, "README.md", "# staged change\nunstaged line\n")
+	writeTestFile(t, "other.txt", "untracked\n")
+
+	snapshot, err := snapshotStaging(ctx)
+	if err != nil {
+		t.Fatalf("snapshotStaging failed: %v", err)
+	}
+
+	// Simulate --split committing other.txt on its own.
+	runGitCmd(t, "reset", "-q")
+	runGitCmd(t, "add", "other.txt")
+	runGitCmd(t, "commit", "-q", "-m", "add other")
+
+	if err := snapshot.restore(ctx, map[string]bool{"other.txt": true}); err != nil {
+		t.Fatalf("restore failed: %v", err)
+	}
+
+	staged, err := listGitFiles(ctx, true)
+	if err != nil {
+		t.Fatalf("listGitFiles failed: %v", err)
+	}
+	if want := []string{"README.md", "added.txt"}; !reflect.DeepEqual(staged, want) {
+		t.Fatalf("staged files = %v, want %v", staged, want)
+	}
+	if got := runGitCmd(t, "show", ":README.md"); got != "# staged change\n" {
+		t.Fatalf("expected the partially staged content to be restored, got %q", got)
+	}
+}
+
+func TestNormalizeGroups(t *testing.T) {
+	groups, leftover := normalizeGroups([]llm.CommitGroup{
+		{Files: []string{"a.go", "unknown.go"}, Message: "feat(a): ✨ add a"},
+		{Files: []string{"a.go"}, Message: "fix(a): 🐛 duplicate"},
+		{Files: []string{" b.go "}, Message: "docs(b): 📚 document b"},
+	}, []string{"a.go", "b.go", "c.go"})
+
+	if len(groups) != 2 {
+		t.Fatalf("expected 2 groups, got %+v", groups)
+	}
+	if !reflect.DeepEqual(groups[0].Files, []string{"a.go"}) || !reflect.DeepEqual(groups[1].Files, []string{"b.go"}) {
+		t.Fatalf("unexpected groups %+v", groups)
+	}
+	if !reflect.DeepEqual(leftover, []string{"c.go"}) {
+		t.Fatalf("leftover = %v, want [c.go]", leftover)
+	}
+}
+
+func TestParseStatusEntries(t *testing.T) {
+	output := "A  added.txt\x00 M modified.txt\x00RM new name.txt\x00old name.txt\x00 D deleted.txt\x00?? untracked.txt\x00"
+
+	want := []statusEntry{
+		{Status: "A ", Path: "added.txt"},
+		{Status: " M", Path: "modified.txt"},
+		{Status: "RM", Path: "new name.txt", OldPath: "old name.txt"},
+		{Status: " D", Path: "deleted.txt"},
+		{Status: "??", Path: "untracked.txt"},
+	}
+	got := parseStatusEntries(output)
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("parseStatusEntries() = %+v, want %+v", got, want)
+	}
+
+	labels := []string{"added: added.txt", "modified: modified.txt", "modified: old name.txt -> new name.txt", "deleted: deleted.txt", "untracked: untracked.txt"}
+	for i, entry := range got {
+		if entry.Label() != labels[i] {
+			t.Fatalf("Label() = %q, want %q", entry.Label(), labels[i])
+		}
+	}
+	if got[0].Unstaged() || !got[2].Unstaged() || !got[4].Unstaged() {
+		t.Fatalf("unexpected Unstaged() results for %+v", got)
+	}
+	if label := (statusEntry{Status: "R ", Path: "b.txt", OldPath: "a.txt"}).Label(); label != "renamed: a.txt -> b.txt" {
+		t.Fatalf("unexpected rename label %q", label)
+	}
+}
+
+func TestGitStatusEntriesStagesRenamesAndDeletions(t *testing.T) {
+	initCommitTestRepo(t)
+	ctx := context.Background()
+
+	writeTestFile(t, "old.txt", "content\n")
+	writeTestFile(t, "gone.txt", "content\n")
+	runGitCmd(t, "add", "old.txt", "gone.txt")
+	runGitCmd(t, "commit", "-q", "-m", "files")
+
+	runGitCmd(t, "mv", "old.txt", "new.txt")
+	writeTestFile(t, "new.txt", "content\nchanged\n")
+	if err := os.Remove("gone.txt"); err != nil {
+		t.Fatalf("remove gone.txt: %v", err)
+	}
+	writeTestFile(t, "README.md", "# changed\n")
+	writeTestFile(t, "fresh.txt", "untracked\n")
+
+	entries, err := gitStatusEntries(ctx)
+	if err != nil {
+		t.Fatalf("gitStatusEntries failed: %v", err)
+	}
+	paths := make(map[string]statusEntry, len(entries))
+	for _, entry := range entries {
+		paths[entry.Path] = entry
+	}
+	if entry := paths["new.txt"]; entry.OldPath != "old.txt" {
+		t.Fatalf("expected the rename to be tracked, got %+v", entries)
+	}
+	for _, path := range []string{"gone.txt", "README.md", "fresh.txt"} {
+		if _, ok := paths[path]; !ok {
+			t.Fatalf("expected %s in %+v", path, entries)
+		}
+	}
+
+	var toStage []string
+	for _, entry := range entries {
+		toStage = append(toStage, entry.Path)
+	}
+	if err := gitAdd(ctx, toStage); err != nil {
+		t.Fatalf("gitAdd failed: %v", err)
+	}
+	if remaining, _ := gitStatusEntries(ctx); len(remaining) != 0 {
+		t.Fatalf("expected everything to be staged, got %+v", remaining)
+	}
+}
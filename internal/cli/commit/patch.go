@@ -0,0 +1,168 @@
+/**
+ * Copyright © 2025 Magdiel Campelo <github.com/MagdielCAS/magi-cli>
+ * This file is part of the magi-cli
+**/
+package commit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/MagdielCAS/magi-cli/pkg/git"
+	"github.com/MagdielCAS/magi-cli/pkg/shared"
+	"github.com/pterm/pterm"
+)
+
+const (
+	patchActionStage     = "Stage this hunk"
+	patchActionSkip      = "Skip this hunk"
+	patchActionStageFile = "Stage this and the remaining hunks of the file"
+	patchActionSkipFile  = "Skip the remaining hunks of the file"
+	patchActionAbort     = "Abort and restore staging"
+)
+
+var patchActions = []string{patchActionStage, patchActionSkip, patchActionStageFile, patchActionSkipFile, patchActionAbort}
+
+// errPatchAborted is returned when the user aborts the hunk selection.
+var errPatchAborted = errors.New("hunk selection aborted")
+
+// hunkChooser returns the patch action for hunk index of file.
+type hunkChooser func(file git.FileDiff, index int) (string, error)
+
+// stagePatchHunks lets the user pick hunks of the selected files, like git add -p, and
+// stages them with git apply --cached. Untracked files and files without text hunks
+// (binary or mode-only changes) are staged whole.
+func stagePatchHunks(ctx context.Context, entries []statusEntry) error {
+	var tracked, whole []string
+	for _, entry := range entries {
+		if entry.Status == "??" {
+			whole = append(whole, entry.Path)
+			continue
+		}
+		tracked = append(tracked, entry.Path)
+	}
+
+	var selected []git.FileDiff
+	if len(tracked) > 0 {
+		diff, err := git.RunGit(ctx, append([]string{"diff", "--"}, tracked...)...)
+		if err != nil {
+			return err
+		}
+		files, err := git.ParseUnifiedDiff(diff)
+		if err != nil {
+			return fmt.Errorf("failed to parse the working-tree diff: %w", err)
+		}
+
+		var textFiles []git.FileDiff
+		for _, file := range files {
+			if file.Binary || len(file.Hunks) == 0 {
+				pterm.Info.Printf("%s has no text hunks; staging the whole file.\n", file.Path())
+				whole = append(whole, file.Path())
+				continue
+			}
+			textFiles = append(textFiles, file)
+		}
+
+		if selected, err = pickHunks(textFiles, promptHunkAction); err != nil {
+			return err
+		}
+	}
+
+	if len(selected) == 0 && len(whole) == 0 {
+		return errors.New("no hunks were selected")
+	}
+	if len(whole) > 0 {
+		if err := gitAdd(ctx, whole); err != nil {
+			return err
+		}
+	}
+	if len(selected) > 0 {
+		return applyCachedPatch(ctx, git.FormatUnifiedDiff(selected))
+	}
+	return nil
+}
+
+// pickHunks asks choose about every hunk and returns the files narrowed to the chosen
+// hunks. Files without a chosen hunk are left out.
+func pickHunks(files []git.FileDiff, choose hunkChooser) ([]git.FileDiff, error) {
+	var selected []git.FileDiff
+	for _, file := range files {
+		var keep []int
+		decided := ""
+		for i := range file.Hunks {
+			action := decided
+			if action == "" {
+				var err error
+				if action, err = choose(file, i); err != nil {
+					return nil, err
+				}
+			}
+
+			switch action {
+			case patchActionAbort:
+				return nil, errPatchAborted
+			case patchActionStageFile:
+				decided = patchActionStage
+				keep = append(keep, i)
+			case patchActionSkipFile:
+				decided = patchActionSkip
+			case patchActionStage:
+				keep = append(keep, i)
+			}
+		}
+		if len(keep) > 0 {
+			selected = append(selected, file.WithHunks(keep...))
+		}
+	}
+	return selected, nil
+}
+
+// promptHunkAction shows one hunk and asks what to do with it.
+func promptHunkAction(file git.FileDiff, index int) (string, error) {
+	hunk := file.Hunks[index]
+	pterm.DefaultBox.
+		WithTitle(fmt.Sprintf("%s (hunk %d of %d)", file.Path(), index+1, len(file.Hunks))).
+		Println(hunk.Header() + "\n" + strings.Join(hunk.Lines, "\n"))
+
+	choice, err := pterm.DefaultInteractiveSelect.
+		WithOptions(patchActions).
+		WithDefaultOption(patchActionStage).
+		Show("Stage this hunk?")
+	if err != nil {
+		return "", fmt.Errorf("hunk selection failed: %w", err)
+	}
+	return choice, nil
+}
+
+// applyCachedPatch stages patch with git apply --cached.
+func applyCachedPatch(ctx context.Context, patch string) error {
+	file, err := os.CreateTemp("", "magi-commit-*.patch")
+	if err != nil {
+		return fmt.Errorf("failed to create patch file: %w", err)
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.WriteString(patch); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write patch file: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write patch file: %w", err)
+	}
+
+	if _, err := git.RunGit(ctx, "apply", "--cached", file.Name()); err != nil {
+		return fmt.Errorf("failed to stage the selected hunks: %w", err)
+	}
+	return nil
+}
+
+// requirePatchSession fails early when --patch cannot prompt for hunks.
+func requirePatchSession() error {
+	if shared.IsInteractive() {
+		return nil
+	}
+	return fmt.Errorf("%w: --patch picks hunks interactively", shared.ErrNonInteractive)
+}
@@ -0,0 +1,108 @@
+package commit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/MagdielCAS/magi-cli/pkg/git"
+)
+
+// scriptedChooser answers the hunk prompts in order and records how often it was asked.
+func scriptedChooser(actions ...string) (hunkChooser, *int) {
+	asked := 0
+	return func(git.FileDiff, int) (string, error) {
+		action := actions[asked]
+		asked++
+		return action, nil
+	}, &asked
+}
+
+func TestPickHunks(t *testing.T) {
+	hunks := func(n int) []git.Hunk {
+		var result []git.Hunk
+		for i := 0; i < n; i++ {
+			result = append(result, git.Hunk{OldStart: 10 * (i + 1), OldLines: 1, NewStart: 10 * (i + 1), NewLines: 1, Lines: []string{"-a", "+b"}})
+		}
+		return result
+	}
+	files := []git.FileDiff{
+		{OldPath: "a.go", NewPath: "a.go", Hunks: hunks(3)},
+		{OldPath: "b.go", NewPath: "b.go", Hunks: hunks(2)},
+		{OldPath: "c.go", NewPath: "c.go", Hunks: hunks(2)},
+	}
+
+	choose, asked := scriptedChooser(patchActionSkip, patchActionStageFile, patchActionSkipFile, patchActionStage, patchActionSkip)
+	selected, err := pickHunks(files, choose)
+	if err != nil {
+		t.Fatalf("pickHunks failed: %v", err)
+	}
+	if *asked != 5 {
+		t.Fatalf("expected 5 prompts, got %d", *asked)
+	}
+	if len(selected) != 2 || selected[0].Path() != "a.go" || len(selected[0].Hunks) != 2 || selected[1].Path() != "c.go" || len(selected[1].Hunks) != 1 {
+		t.Fatalf("unexpected selection: %+v", selected)
+	}
+
+	choose, _ = scriptedChooser(patchActionStage, patchActionAbort)
+	if _, err := pickHunks(files, choose); !errors.Is(err, errPatchAborted) {
+		t.Fatalf("expected errPatchAborted, got %v", err)
+	}
+}
+
+func TestApplyCachedPatchStagesSelectedHunks(t *testing.T) {
+	initCommitTestRepo(t)
+	ctx := context.Background()
+
+	var lines []string
+	for i := 1; i <= 20; i++ {
+		lines = append(lines, fmt.Sprintf("line %d", i))
+	}
+	writeTestFile(t, "list.txt", strings.Join(lines, "\n")+"\n")
+	runGitCmd(t, "add", "list.txt")
+	runGitCmd(t, "commit", "-q", "-m", "list")
+
+	lines[1] = "line 2 changed"
+	lines[18] = "line 19 changed"
+	writeTestFile(t, "list.txt", strings.Join(lines, "\n")+"\n")
+
+	diff, err := git.RunGit(ctx, "diff")
+	if err != nil {
+		t.Fatalf("git diff failed: %v", err)
+	}
+	files, err := git.ParseUnifiedDiff(diff)
+	if err != nil || len(files) != 1 || len(files[0].Hunks) != 2 {
+		t.Fatalf("expected one file with two hunks, got %+v (err=%v)", files, err)
+	}
+
+	snapshot, err := snapshotStaging(ctx)
+	if err != nil {
+		t.Fatalf("snapshotStaging failed: %v", err)
+	}
+
+	choose, _ := scriptedChooser(patchActionSkip, patchActionStage)
+	selected, err := pickHunks(files, choose)
+	if err != nil {
+		t.Fatalf("pickHunks failed: %v", err)
+	}
+	if err := applyCachedPatch(ctx, git.FormatUnifiedDiff(selected)); err != nil {
+		t.Fatalf("applyCachedPatch failed: %v", err)
+	}
+
+	staged := runGitCmd(t, "diff", "--cached")
+	if !strings.Contains(staged, "+line 19 changed") || strings.Contains(staged, "line 2 changed") {
+		t.Fatalf("expected only the second hunk to be staged, got:\n%s", staged)
+	}
+
+	if err := snapshot.restore(ctx, nil); err != nil {
+		t.Fatalf("restore failed: %v", err)
+	}
+	if staged := runGitCmd(t, "diff", "--cached"); staged != "" {
+		t.Fatalf("expected the original empty index after restoring, got:\n%s", staged)
+	}
+	if unstaged := runGitCmd(t, "diff"); !strings.Contains(unstaged, "+line 2 changed") || !strings.Contains(unstaged, "+line 19 changed") {
+		t.Fatalf("expected the working tree to keep both changes, got:\n%s", unstaged)
+	}
+}
@@ -0,0 +1,257 @@
+/**
+ * Copyright © 2025 Magdiel Campelo <github.com/MagdielCAS/magi-cli>
+ * This file is part of the magi-cli
+**/
+package commit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/MagdielCAS/magi-cli/pkg/git"
+	"github.com/MagdielCAS/magi-cli/pkg/llm"
+	"github.com/MagdielCAS/magi-cli/pkg/shared"
+	"github.com/pterm/pterm"
+	"github.com/spf13/viper"
+)
+
+const (
+	splitActionCommit = "Commit this group"
+	splitActionEdit   = "Edit message in $EDITOR"
+	splitActionSkip   = "Skip this group"
+	splitActionAbort  = "Abort and restore staging"
+)
+
+var splitActions = []string{splitActionCommit, splitActionEdit, splitActionSkip, splitActionAbort}
+
+// stagingSnapshot records the index before --split touches it so it can be restored.
+type stagingSnapshot struct {
+	tree   string
+	staged []string
+}
+
+// runSplitCommit asks the model to group every working-tree change into logical commits
+// and commits the confirmed groups one by one. Whatever is not committed gets its
+// original staging state back, including when the user aborts midway.
+func runSplitCommit(ctx context.Context, runtimeCtx *shared.RuntimeContext) (err error) {
+	snapshot, err := snapshotStaging(ctx)
+	if err != nil {
+		return err
+	}
+
+	committed := make(map[string]bool)
+	defer func() {
+		if restoreErr := snapshot.restore(ctx, committed); restoreErr != nil {
+			pterm.Error.Printf("Failed to restore the original staging state: %v\n", restoreErr)
+			err = errors.Join(err, restoreErr)
+		}
+	}()
+
+	changed, err := stageAllChanges(ctx)
+	if err != nil {
+		return err
+	}
+	if len(changed) == 0 {
+		return errors.New("no changes to split")
+	}
+
+	diff, err := git.Diff(ctx, viper.GetBool(ignoreWhitespaceKey), "--cached")
+	if errors.Is(err, git.ErrWhitespaceOnly) {
+		pterm.Warning.Printf("%v (--ignore-whitespace); nothing to split.\n", err)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := checkDiffForSecrets(diff); err != nil {
+		return err
+	}
+	if diff, err = redactDiff(diff); err != nil {
+		return err
+	}
+	if _, err := git.RunGit(ctx, "reset", "-q"); err != nil {
+		return fmt.Errorf("failed to reset staging area: %w", err)
+	}
+
+	variant := commitModelVariant(runtimeCtx)
+	pterm.Info.Printf("Asking %s to split %d file(s) into logical commits...\n", variantModel(runtimeCtx, variant), len(changed))
+	proposed, err := llm.ProposeCommitSplit(ctx, runtimeCtx, diff, changed, variant)
+	if err != nil {
+		return err
+	}
+
+	groups, leftover := normalizeGroups(proposed, changed)
+	if len(groups) == 0 {
+		return errors.New("the model did not propose any usable commit groups")
+	}
+	if len(leftover) > 0 {
+		pterm.Warning.Printf("Files not assigned to any group will stay uncommitted: %s\n", strings.Join(leftover, ", "))
+	}
+
+	var results []commitResult
+	for i, group := range groups {
+		action, message, promptErr := confirmGroup(i+1, len(groups), group)
+		if promptErr != nil {
+			return promptErr
+		}
+
+		switch action {
+		case splitActionSkip:
+			pterm.Info.Printf("Skipped group %d.\n", i+1)
+			continue
+		case splitActionAbort:
+			pterm.Warning.Println("Split aborted by user; restoring the original staging state.")
+			return printSplitResults(results)
+		}
+
+		if err := gitAdd(ctx, group.Files); err != nil {
+			return err
+		}
+		if err := gitCommit(ctx, message); err != nil {
+			return err
+		}
+		for _, file := range group.Files {
+			committed[file] = true
+		}
+		results = append(results, commitResult{Message: message, Files: group.Files, Committed: true})
+	}
+
+	pterm.Success.Printf("Created %d commit(s).\n", len(results))
+	return printSplitResults(results)
+}
+
+// confirmGroup shows one proposed group and returns the chosen action and final message.
+func confirmGroup(index, total int, group llm.CommitGroup) (string, string, error) {
+	message := normalizeCommitMessage(group.Message)
+	for {
+		pterm.DefaultBox.
+			WithTitle(fmt.Sprintf("Commit %d of %d", index, total)).
+			Println(message + "\n\n" + strings.Join(group.Files, "\n"))
+
+		validationErr := validateCommitFormat(message)
+		if validationErr != nil {
+			pterm.Warning.Printf("Proposed message failed validation: %v. Edit it before committing.\n", validationErr)
+		}
+
+		if !shared.IsInteractive() {
+			if !shared.ConfirmDefault(false) {
+				return "", "", shared.NonInteractiveError("--assume-yes")
+			}
+			if validationErr != nil {
+				return splitActionSkip, message, nil
+			}
+			return splitActionCommit, message, nil
+		}
+
+		defaultAction := splitActionCommit
+		if !shared.ConfirmDefault(true) {
+			defaultAction = splitActionSkip
+		}
+
+		action, err := pterm.DefaultInteractiveSelect.
+			WithOptions(splitActions).
+			WithDefaultOption(defaultAction).
+			Show("What should happen with this group?")
+		if err != nil {
+			return "", "", fmt.Errorf("confirmation prompt failed: %w", err)
+		}
+
+		switch action {
+		case splitActionCommit:
+			if validationErr != nil {
+				continue
+			}
+			return action, message, nil
+		case splitActionEdit:
+			edited, err := shared.OpenEditor(message, ".txt")
+			if err != nil {
+				pterm.Error.Printf("Failed to open editor: %v\n", err)
+				continue
+			}
+			message = normalizeCommitMessage(edited)
+		default:
+			return action, message, nil
+		}
+	}
+}
+
+// normalizeGroups drops unknown and duplicate paths and empty groups, and returns the
+// changed files that no group claimed.
+func normalizeGroups(groups []llm.CommitGroup, changed []string) ([]llm.CommitGroup, []string) {
+	known := make(map[string]bool, len(changed))
+	for _, file := range changed {
+		known[file] = true
+	}
+
+	assigned := make(map[string]bool, len(changed))
+	var normalized []llm.CommitGroup
+	for _, group := range groups {
+		var files []string
+		for _, file := range group.Files {
+			file = strings.TrimSpace(file)
+			if !known[file] || assigned[file] {
+				continue
+			}
+			assigned[file] = true
+			files = append(files, file)
+		}
+		if len(files) == 0 {
+			continue
+		}
+		normalized = append(normalized, llm.CommitGroup{Files: files, Message: group.Message})
+	}
+
+	var leftover []string
+	for _, file := range changed {
+		if !assigned[file] {
+			leftover = append(leftover, file)
+		}
+	}
+	return normalized, leftover
+}
+
+func snapshotStaging(ctx context.Context) (stagingSnapshot, error) {
+	tree, err := git.RunGit(ctx, "write-tree")
+	if err != nil {
+		return stagingSnapshot{}, fmt.Errorf("failed to snapshot staging area: %w", err)
+	}
+	staged, err := listGitFiles(ctx, true)
+	if err != nil {
+		return stagingSnapshot{}, err
+	}
+	return stagingSnapshot{tree: strings.TrimSpace(tree), staged: staged}, nil
+}
+
+// restore resets the index and re-stages the originally staged content of every file
+// that was not committed.
+func (s stagingSnapshot) restore(ctx context.Context, committed map[string]bool) error {
+	if _, err := git.RunGit(ctx, "reset", "-q"); err != nil {
+		return err
+	}
+
+	var remaining []string
+	for _, file := range s.staged {
+		if !committed[file] {
+			remaining = append(remaining, file)
+		}
+	}
+	if len(remaining) == 0 {
+		return nil
+	}
+
+	args := append([]string{"restore", "--staged", "--source=" + s.tree, "--"}, remaining...)
+	_, err := git.RunGit(ctx, args...)
+	return err
+}
+
+func printSplitResults(results []commitResult) error {
+	if !shared.IsJSONOutput() {
+		return nil
+	}
+	if results == nil {
+		results = []commitResult{}
+	}
+	return shared.PrintJSON(results)
+}
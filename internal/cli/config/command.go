@@ -19,6 +19,7 @@ Available subcommands:
   list    Lists all configuration values
   reset   Resets the configuration
   init    Initialize a local configuration file
+  migrate-tiers  Copy the global API settings into per-tier api.<tier>.* keys
 
 Usage:
   magi config [command]
@@ -45,6 +46,9 @@ Examples:
   # Initialize a local configuration file
   magi config init
 
+  # Give each model tier its own endpoint keys, starting from the global ones
+  magi config migrate-tiers
+
 Run 'magi config [command] --help' for more information on a specific command.`,
 }
 
@@ -55,6 +59,7 @@ func ConfigCmd() *cobra.Command {
 	configCmd.AddCommand(ListCmd)
 	configCmd.AddCommand(ResetCmd)
 	configCmd.AddCommand(InitCmd)
+	configCmd.AddCommand(MigrateTiersCmd)
 
 	return configCmd
 }
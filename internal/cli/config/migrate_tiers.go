@@ -0,0 +1,191 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/MagdielCAS/magi-cli/pkg/shared"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var MigrateTiersCmd = &cobra.Command{
+	Use:   "migrate-tiers",
+	Short: "Copies the global API settings into per-tier api.<tier>.* keys",
+	Long: `Copies the global API settings into the nested per-tier keys, so each model tier
+(light, heavy, fallback) can then be pointed at its own endpoint.
+
+magi reads both flat keys (api.key, api.base_url, api.provider, api.json_mode) and the
+nested api.<tier>.api_key, api.<tier>.base_url, api.<tier>.provider and
+api.<tier>.json_mode keys, which fall back to the flat ones when unset. This command
+shows the nested keys it would write, with the current global values, and writes them
+after confirmation. Nested keys that are already set are left alone. The model names
+stay in api.light_model, api.heavy_model and api.fallback_model.
+
+Only the global config file (~/.magi/config.yaml, or the file passed with --config) is
+read and written, never a project .magi.yaml. api.key is not copied unless you pass
+--include-api-key: each tier already falls back to it, and copies of a secret are easy
+to forget when the key is rotated.
+
+Usage:
+  magi config migrate-tiers [flags]
+
+Examples:
+  # Show the keys that would be written, then confirm
+  magi config migrate-tiers
+
+  # Only show the keys
+  magi config migrate-tiers --dry-run
+
+  # Also copy the API key into every tier
+  magi config migrate-tiers --include-api-key
+
+  # Then customize a single tier
+  magi config set api.heavy.base_url https://heavy.example.com/v1
+
+Run 'magi config migrate-tiers --help' for more information on a specific command.`,
+	Args: cobra.NoArgs,
+	RunE: runMigrateTiers,
+}
+
+func init() {
+	MigrateTiersCmd.Flags().Bool("dry-run", false, "Only show the keys that would be written")
+	MigrateTiersCmd.Flags().BoolP("yes", "y", false, "Write the keys without asking for confirmation")
+	MigrateTiersCmd.Flags().Bool("include-api-key", false, "Also copy api.key into api.<tier>.api_key")
+}
+
+// modelTiers are the model classes with their own api.<tier>.* endpoint keys.
+var modelTiers = []string{"light", "heavy", "fallback"}
+
+// tierEndpointKeys maps each nested endpoint key to the global key it falls back to.
+var tierEndpointKeys = []struct {
+	nested string
+	global string
+}{
+	{"api_key", "api.key"},
+	{"base_url", "api.base_url"},
+	{"provider", "api.provider"},
+	{"json_mode", "api.json_mode"},
+}
+
+// tierSetting is a nested key and the value migrate-tiers writes to it.
+type tierSetting struct {
+	Key   string
+	Value string
+}
+
+// globalConfigFile returns the file passed with --config, or ~/.magi/config.yaml.
+func globalConfigFile(cmd *cobra.Command) (string, error) {
+	if flag := cmd.Flag("config"); flag != nil && flag.Value.String() != "" {
+		return flag.Value.String(), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".magi", "config.yaml"), nil
+}
+
+// loadGlobalConfig reads path into a fresh viper instance, so neither a project .magi.yaml
+// nor the environment leaks into what is written back. A missing file is an empty config.
+func loadGlobalConfig(path string) (*viper.Viper, error) {
+	v := viper.New()
+	v.SetConfigFile(path)
+	v.SetConfigType("yaml")
+	if err := v.ReadInConfig(); err != nil {
+		var notFound viper.ConfigFileNotFoundError
+		if !os.IsNotExist(err) && !errors.As(err, &notFound) {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+	}
+	return v, nil
+}
+
+// planTierMigration lists the nested keys to write, copying every non-empty global value
+// of v to each tier. Nested keys already set in v are returned in skipped instead. api.key
+// is only copied when includeAPIKey is set.
+func planTierMigration(v *viper.Viper, includeAPIKey bool) (settings []tierSetting, skipped []string) {
+	for _, tier := range modelTiers {
+		for _, field := range tierEndpointKeys {
+			if field.global == "api.key" && !includeAPIKey {
+				continue
+			}
+			value := strings.TrimSpace(v.GetString(field.global))
+			if value == "" {
+				continue
+			}
+			key := "api." + tier + "." + field.nested
+			if v.IsSet(key) {
+				skipped = append(skipped, key)
+				continue
+			}
+			settings = append(settings, tierSetting{Key: key, Value: value})
+		}
+	}
+	return settings, skipped
+}
+
+func runMigrateTiers(cmd *cobra.Command, args []string) error {
+	path, err := globalConfigFile(cmd)
+	if err != nil {
+		return fmt.Errorf("failed to locate the global config file: %w", err)
+	}
+	v, err := loadGlobalConfig(path)
+	if err != nil {
+		return err
+	}
+
+	includeAPIKey, _ := cmd.Flags().GetBool("include-api-key")
+	settings, skipped := planTierMigration(v, includeAPIKey)
+	if len(skipped) > 0 {
+		pterm.Info.Printf("Leaving the per-tier keys that are already set: %s\n", strings.Join(skipped, ", "))
+	}
+	if len(settings) == 0 {
+		if len(skipped) > 0 {
+			pterm.Info.Println("Nothing to migrate. Change a tier with 'magi config set api.<tier>.<key> <value>'.")
+			return nil
+		}
+		pterm.Warning.Printf("No global API settings to copy in %s; set them first (magi setup or magi config set api.base_url ...).\n", path)
+		return nil
+	}
+
+	tableData := pterm.TableData{{"Key", "Value"}}
+	for _, setting := range settings {
+		value := setting.Value
+		if strings.HasSuffix(setting.Key, ".api_key") {
+			value = maskAPIKey(value)
+		}
+		tableData = append(tableData, []string{setting.Key, value})
+	}
+	pterm.DefaultTable.WithHasHeader().WithData(tableData).Render()
+
+	if dryRun, _ := cmd.Flags().GetBool("dry-run"); dryRun {
+		pterm.Info.Printf("Dry run: %d key(s) would be written to %s.\n", len(settings), path)
+		return nil
+	}
+	if yes, _ := cmd.Flags().GetBool("yes"); !yes {
+		confirmed, err := shared.Confirm(fmt.Sprintf("Write these keys to %s?", path), true)
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			pterm.Info.Println("Aborted.")
+			return nil
+		}
+	}
+
+	for _, setting := range settings {
+		v.Set(setting.Key, setting.Value)
+	}
+	if err := v.WriteConfigAs(path); err != nil {
+		return fmt.Errorf("failed to save configuration: %w", err)
+	}
+
+	pterm.Success.Printf("Wrote %d per-tier key(s) to %s. The tiers no longer follow later changes to the global keys.\n", len(settings), path)
+	pterm.Info.Println("Customize a tier with, for example, 'magi config set api.heavy.base_url <url>'.")
+	return nil
+}
@@ -0,0 +1,86 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrateTiersCmd(t *testing.T) {
+	teardown := setup(t)
+	defer teardown()
+	t.Cleanup(func() {
+		MigrateTiersCmd.Flags().Set("dry-run", "false")
+		MigrateTiersCmd.Flags().Set("yes", "false")
+		MigrateTiersCmd.Flags().Set("include-api-key", "false")
+	})
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	globalPath := filepath.Join(home, ".magi", "config.yaml")
+	require.NoError(t, os.MkdirAll(filepath.Dir(globalPath), 0755))
+	require.NoError(t, os.WriteFile(globalPath, []byte(`api:
+  key: sk-global-key-123456
+  base_url: https://api.example.com/v1
+  provider: openai
+  heavy:
+    base_url: https://heavy.example.com/v1
+`), 0600))
+
+	// The current config file stands in for a project .magi.yaml, which must stay untouched.
+	localPath := viper.ConfigFileUsed()
+	viper.Set("api.key", "sk-local-key-abcdef")
+
+	output := execute(t, MigrateTiersCmd, "--dry-run")
+	assert.Contains(t, output, "api.light.base_url")
+	assert.Contains(t, output, "api.heavy.base_url")
+	assert.NotContains(t, output, "api.light.api_key")
+
+	output = execute(t, MigrateTiersCmd, "--dry-run=false", "--yes")
+	assert.Contains(t, output, "Wrote 5 per-tier key(s)")
+
+	global, err := loadGlobalConfig(globalPath)
+	require.NoError(t, err)
+	assert.Equal(t, "https://heavy.example.com/v1", global.GetString("api.heavy.base_url"), "set keys are kept")
+	for _, tier := range []string{"light", "heavy", "fallback"} {
+		assert.Equal(t, "openai", global.GetString("api."+tier+".provider"))
+		assert.False(t, global.IsSet("api."+tier+".api_key"), "api.key is only copied on request")
+		assert.False(t, global.IsSet("api."+tier+".json_mode"), "empty global values should not be copied")
+	}
+	assert.Equal(t, "https://api.example.com/v1", global.GetString("api.light.base_url"))
+
+	local, err := os.ReadFile(localPath)
+	require.NoError(t, err)
+	assert.NotContains(t, string(local), "sk-")
+
+	output = execute(t, MigrateTiersCmd, "--include-api-key", "--yes")
+	assert.Contains(t, output, "Wrote 3 per-tier key(s)")
+	assert.Contains(t, output, "sk-g...3456")
+	assert.NotContains(t, output, "sk-global-key-123456")
+	global, err = loadGlobalConfig(globalPath)
+	require.NoError(t, err)
+	assert.Equal(t, "sk-global-key-123456", global.GetString("api.fallback.api_key"))
+
+	output = execute(t, MigrateTiersCmd, "--include-api-key=false")
+	assert.Contains(t, output, "Nothing to migrate")
+}
+
+func TestPlanTierMigrationWithoutGlobalSettings(t *testing.T) {
+	v := viper.New()
+	settings, skipped := planTierMigration(v, true)
+	assert.Empty(t, settings)
+	assert.Empty(t, skipped)
+
+	v.Set("api.base_url", "https://api.example.com")
+	v.Set("api.heavy.base_url", "https://heavy.example.com")
+	settings, skipped = planTierMigration(v, false)
+	assert.Equal(t, []tierSetting{
+		{Key: "api.light.base_url", Value: "https://api.example.com"},
+		{Key: "api.fallback.base_url", Value: "https://api.example.com"},
+	}, settings)
+	assert.Equal(t, []string{"api.heavy.base_url"}, skipped)
+}
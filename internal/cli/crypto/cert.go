@@ -0,0 +1,163 @@
+package crypto
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/MagdielCAS/magi-cli/pkg/shared"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+var (
+	certKeyPath    string
+	certAlgorithm  string
+	certCommonName string
+	certSANs       []string
+	certDays       int
+	certIsCA       bool
+	certOutput     string
+	certKeyOutput  string
+	certForce      bool
+)
+
+var CertCmd = &cobra.Command{
+	Use:   "cert",
+	Short: "Generate a self-signed certificate",
+	Long: `Generate a self-signed X.509 certificate in PEM format for local development.
+
+The certificate is signed with --key. Without --key a new private key is generated first
+(using --algorithm) and saved next to the certificate with 0600 permissions, so a key and
+certificate can be created in one shot. An existing file at the key path is only replaced
+after confirmation or with --force.
+
+Subject alternative names are classified automatically: IP addresses become IP SANs, values
+containing '@' become email SANs, everything else becomes a DNS name. The common name is also
+added as a DNS SAN when it is a host name. Use --ca to mark the certificate as a CA that can
+sign other certificates. The certificate is saved with 0644 permissions.`,
+	Example: `  # Self-signed certificate for localhost with a new Ed25519 key
+  magi crypto cert --cn localhost --san 127.0.0.1 --algorithm ed25519
+
+  # Certificate from an existing key, valid for one year
+  magi crypto cert --key priv.pem --cn localhost --days 365
+
+  # Local development CA
+  magi crypto cert --cn "Dev CA" --ca --days 3650 --output ca.pem --key-output ca-key.pem`,
+	Args: cobra.NoArgs,
+	RunE: runGenerateCert,
+}
+
+func init() {
+	CertCmd.Flags().StringVarP(&certKeyPath, "key", "k", "", "Existing private key (PEM); a new key is generated when empty")
+	CertCmd.Flags().StringVarP(&certAlgorithm, "algorithm", "a", "rsa", "Algorithm for a generated key (rsa, ecdsa, ed25519)")
+	CertCmd.Flags().StringVar(&certCommonName, "cn", "", "Subject common name")
+	CertCmd.Flags().StringSliceVar(&certSANs, "san", nil, "Subject alternative name (DNS name, IP, or email); repeatable")
+	CertCmd.Flags().IntVar(&certDays, "days", 365, "Validity period in days")
+	CertCmd.Flags().BoolVar(&certIsCA, "ca", false, "Mark the certificate as a certificate authority")
+	CertCmd.Flags().StringVarP(&certOutput, "output", "o", "cert.pem", "Output file for the PEM certificate")
+	CertCmd.Flags().StringVar(&certKeyOutput, "key-output", "", "Output file for a generated key (defaults to key.pem next to the certificate)")
+	CertCmd.Flags().BoolVarP(&certForce, "force", "f", false, "Overwrite an existing file at the generated key path without asking")
+	CertCmd.MarkFlagRequired("cn")
+}
+
+func runGenerateCert(cmd *cobra.Command, args []string) error {
+	if certDays <= 0 {
+		return fmt.Errorf("--days must be positive, got %d", certDays)
+	}
+
+	var priv crypto.Signer
+	if certKeyPath != "" {
+		var err error
+		if priv, err = loadPrivateKey(certKeyPath); err != nil {
+			return fmt.Errorf("failed to load private key: %w", err)
+		}
+	} else {
+		keyPath := certKeyOutput
+		if keyPath == "" {
+			keyPath = filepath.Join(filepath.Dir(certOutput), "key.pem")
+		}
+		if err := confirmKeyOverwrite(keyPath, certForce); err != nil {
+			return err
+		}
+		generated, _, err := generateKeys(certAlgorithm)
+		if err != nil {
+			return fmt.Errorf("failed to generate key: %w", err)
+		}
+		if err := savePrivateKey(generated, keyPath); err != nil {
+			return fmt.Errorf("failed to save private key: %w", err)
+		}
+		pterm.Success.Printf("Private key generated at %s\n", keyPath)
+		priv = generated.(crypto.Signer)
+	}
+
+	template, err := buildCertTemplate(certCommonName, certSANs, certDays, certIsCA, time.Now())
+	if err != nil {
+		return err
+	}
+	if _, ok := priv.(*rsa.PrivateKey); ok {
+		// RSA key exchange in older TLS versions encrypts with the certificate key.
+		template.KeyUsage |= x509.KeyUsageKeyEncipherment
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, priv.Public(), priv)
+	if err != nil {
+		return fmt.Errorf("failed to create certificate: %w", err)
+	}
+
+	if err := os.WriteFile(certOutput, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0644); err != nil {
+		return fmt.Errorf("failed to write certificate: %w", err)
+	}
+
+	pterm.Success.Printf("Self-signed certificate generated at %s (valid until %s)\n", certOutput, template.NotAfter.Format(time.DateOnly))
+	return nil
+}
+
+// confirmKeyOverwrite returns an error when path exists and the user does not agree to
+// replace it. force skips the question.
+func confirmKeyOverwrite(path string, force bool) error {
+	if _, err := os.Stat(path); err != nil || force {
+		return nil
+	}
+	overwrite, err := shared.Confirm(fmt.Sprintf("File %s already exists. Overwrite?", path), false)
+	if err != nil {
+		return err
+	}
+	if !overwrite {
+		return fmt.Errorf("%s already exists; pass --key-output to pick another file, --key to reuse it, or --force to replace it", path)
+	}
+	return nil
+}
+
+// buildCertTemplate returns a self-signed certificate template valid for days from now.
+func buildCertTemplate(commonName string, sans []string, days int, isCA bool, now time.Time) (*x509.Certificate, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate serial number: %w", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: strings.TrimSpace(commonName)},
+		NotBefore:             now.Add(-5 * time.Minute),
+		NotAfter:              now.AddDate(0, 0, days),
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  isCA,
+	}
+	template.DNSNames, template.IPAddresses, template.EmailAddresses = parseSANs(commonName, sans)
+	if isCA {
+		template.KeyUsage |= x509.KeyUsageCertSign | x509.KeyUsageCRLSign
+	}
+	return template, nil
+}
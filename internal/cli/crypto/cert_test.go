@@ -0,0 +1,118 @@
+package crypto
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCertCmd(t *testing.T) {
+	cmd := CertCmd
+	assert.NotNil(t, cmd)
+	assert.Equal(t, "cert", cmd.Use)
+}
+
+func TestBuildCertTemplate(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	template, err := buildCertTemplate("localhost", []string{"127.0.0.1", "dev.local"}, 30, true, now)
+	require.NoError(t, err)
+
+	assert.Equal(t, "localhost", template.Subject.CommonName)
+	assert.Equal(t, now.AddDate(0, 0, 30), template.NotAfter)
+	assert.Equal(t, []string{"localhost", "dev.local"}, template.DNSNames)
+	require.Len(t, template.IPAddresses, 1)
+	assert.Equal(t, "127.0.0.1", template.IPAddresses[0].String())
+	assert.True(t, template.IsCA)
+	assert.NotZero(t, template.KeyUsage&x509.KeyUsageCertSign)
+}
+
+func readCertificate(t *testing.T, path string) *x509.Certificate {
+	t.Helper()
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	block, _ := pem.Decode(content)
+	require.NotNil(t, block)
+	require.Equal(t, "CERTIFICATE", block.Type)
+	cert, err := x509.ParseCertificate(block.Bytes)
+	require.NoError(t, err)
+	return cert
+}
+
+func TestRunGenerateCert(t *testing.T) {
+	resetCertFlags := func() {
+		certKeyPath, certAlgorithm, certCommonName, certSANs = "", "rsa", "", nil
+		certDays, certIsCA, certOutput, certKeyOutput, certForce = 365, false, "cert.pem", "", false
+	}
+	t.Cleanup(resetCertFlags)
+
+	t.Run("generates key and certificate", func(t *testing.T) {
+		resetCertFlags()
+		dir := t.TempDir()
+		certAlgorithm, certCommonName, certSANs = "ed25519", "localhost", []string{"127.0.0.1"}
+		certOutput = filepath.Join(dir, "cert.pem")
+
+		require.NoError(t, runGenerateCert(CertCmd, nil))
+
+		info, err := os.Stat(filepath.Join(dir, "key.pem"))
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+		cert := readCertificate(t, certOutput)
+		assert.NoError(t, cert.CheckSignature(cert.SignatureAlgorithm, cert.RawTBSCertificate, cert.Signature))
+		assert.Equal(t, []string{"localhost"}, cert.DNSNames)
+		assert.False(t, cert.IsCA)
+	})
+
+	t.Run("uses an existing key", func(t *testing.T) {
+		resetCertFlags()
+		dir := t.TempDir()
+		priv, _, err := generateKeys("ecdsa")
+		require.NoError(t, err)
+		certKeyPath = filepath.Join(dir, "existing.pem")
+		require.NoError(t, savePrivateKey(priv, certKeyPath))
+		certCommonName, certIsCA, certDays = "Dev CA", true, 10
+		certOutput = filepath.Join(dir, "ca.pem")
+
+		require.NoError(t, runGenerateCert(CertCmd, nil))
+
+		cert := readCertificate(t, certOutput)
+		assert.True(t, cert.IsCA)
+		assert.Empty(t, cert.DNSNames, "a common name with spaces is not a host name")
+		_, err = os.Stat(filepath.Join(dir, "key.pem"))
+		assert.True(t, os.IsNotExist(err), "no key should be generated when --key is set")
+	})
+
+	t.Run("keeps an existing key unless forced", func(t *testing.T) {
+		resetCertFlags()
+		dir := t.TempDir()
+		keyPath := filepath.Join(dir, "key.pem")
+		require.NoError(t, os.WriteFile(keyPath, []byte("existing"), 0600))
+		certAlgorithm, certCommonName = "ed25519", "localhost"
+		certOutput = filepath.Join(dir, "cert.pem")
+
+		assert.Error(t, runGenerateCert(CertCmd, nil))
+		content, err := os.ReadFile(keyPath)
+		require.NoError(t, err)
+		assert.Equal(t, "existing", string(content))
+		_, err = os.Stat(certOutput)
+		assert.True(t, os.IsNotExist(err), "no certificate should be written when the key is kept")
+
+		certForce = true
+		require.NoError(t, runGenerateCert(CertCmd, nil))
+		content, err = os.ReadFile(keyPath)
+		require.NoError(t, err)
+		assert.NotEqual(t, "existing", string(content))
+	})
+
+	t.Run("rejects non-positive days", func(t *testing.T) {
+		resetCertFlags()
+		certCommonName, certDays = "localhost", 0
+		assert.Error(t, runGenerateCert(CertCmd, nil))
+	})
+}
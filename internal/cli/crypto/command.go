@@ -13,6 +13,8 @@ Available subcommands:
   salt        Generate a random salt key
   keyfile     Generate a MongoDB keyfile
   keypair     Generate a public/private key pair
+  csr         Generate a certificate signing request
+  cert        Generate a self-signed certificate
 
 Usage:
   magi crypto [command]
@@ -30,6 +32,12 @@ Examples:
   # Generate a key pair
   magi crypto keypair
 
+  # Generate a CSR from an existing private key
+  magi crypto csr --key key.pem --cn example.com
+
+  # Generate a self-signed certificate and key for localhost
+  magi crypto cert --cn localhost
+
 Run 'magi crypto [command] --help' for more information on a specific command.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		if len(args) == 0 {
@@ -43,5 +51,7 @@ func CryptoCmd() *cobra.Command {
 	cryptoCmd.AddCommand(SaltCmd)
 	cryptoCmd.AddCommand(KeyfileCmd)
 	cryptoCmd.AddCommand(KeypairCmd)
+	cryptoCmd.AddCommand(CSRCmd)
+	cryptoCmd.AddCommand(CertCmd)
 	return cryptoCmd
 }
@@ -0,0 +1,81 @@
+package crypto
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCSRCmd(t *testing.T) {
+	cmd := CSRCmd
+	assert.NotNil(t, cmd)
+	assert.Equal(t, "csr", cmd.Use)
+}
+
+func TestBuildCSRTemplate(t *testing.T) {
+	template := buildCSRTemplate("example.com", []string{"www.example.com", "10.0.0.5", "admin@example.com", "www.example.com"}, pkix.Name{
+		Organization: []string{"Example Inc"},
+	})
+
+	assert.Equal(t, "example.com", template.Subject.CommonName)
+	assert.Equal(t, []string{"Example Inc"}, template.Subject.Organization)
+	assert.Equal(t, []string{"example.com", "www.example.com"}, template.DNSNames)
+	assert.Equal(t, []string{"admin@example.com"}, template.EmailAddresses)
+	require.Len(t, template.IPAddresses, 1)
+	assert.Equal(t, "10.0.0.5", template.IPAddresses[0].String())
+}
+
+func TestCreateCSRWithSavedKeys(t *testing.T) {
+	for _, algo := range []string{"rsa", "ecdsa", "ed25519"} {
+		t.Run(algo, func(t *testing.T) {
+			priv, _, err := generateKeys(algo)
+			require.NoError(t, err)
+
+			keyPath := filepath.Join(t.TempDir(), "key.pem")
+			require.NoError(t, savePrivateKey(priv, keyPath))
+
+			signer, err := loadPrivateKey(keyPath)
+			require.NoError(t, err)
+
+			der, err := createCSR(buildCSRTemplate("example.com", []string{"www.example.com"}, pkix.Name{Country: []string{"US"}}), signer)
+			require.NoError(t, err)
+
+			csr, err := x509.ParseCertificateRequest(der)
+			require.NoError(t, err)
+			assert.NoError(t, csr.CheckSignature())
+			assert.Equal(t, "example.com", csr.Subject.CommonName)
+			assert.Equal(t, []string{"US"}, csr.Subject.Country)
+			assert.Equal(t, []string{"example.com", "www.example.com"}, csr.DNSNames)
+		})
+	}
+}
+
+func TestRunGenerateCSRWritesPEM(t *testing.T) {
+	dir := t.TempDir()
+	priv, _, err := generateKeys("ecdsa")
+	require.NoError(t, err)
+	keyPath := filepath.Join(dir, "key.pem")
+	require.NoError(t, savePrivateKey(priv, keyPath))
+
+	csrKeyPath, csrCommonName, csrSANs = keyPath, "example.com", []string{"www.example.com"}
+	csrOrg, csrOrgUnit, csrCountry = nil, nil, []string{"USA"}
+	csrOutput = filepath.Join(dir, "request.csr")
+	t.Cleanup(func() { csrCountry = nil })
+
+	assert.Error(t, runGenerateCSR(CSRCmd, nil), "three-letter country must be rejected")
+
+	csrCountry = []string{"US"}
+	require.NoError(t, runGenerateCSR(CSRCmd, nil))
+
+	content, err := os.ReadFile(csrOutput)
+	require.NoError(t, err)
+	block, _ := pem.Decode(content)
+	require.NotNil(t, block)
+	assert.Equal(t, "CERTIFICATE REQUEST", block.Type)
+}
@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/MagdielCAS/magi-cli/pkg/shared"
 	"github.com/pterm/pterm"
 	"github.com/spf13/cobra"
 )
@@ -62,7 +63,7 @@ func runGenerateKeyfile(cmd *cobra.Command, args []string) {
 			return
 		}
 	} else if !keyfileYes {
-		confirm, _ := pterm.DefaultInteractiveConfirm.WithDefaultValue(true).Show("Generate MongoDB keyfile?")
+		confirm, _ := shared.Confirm("Generate MongoDB keyfile?", true)
 		if !confirm {
 			pterm.Warning.Println("Operation cancelled")
 			return
@@ -83,7 +84,7 @@ func generateFileWithDirCheck(path, filename string) {
 
 	// Check if file exists
 	if _, err := os.Stat(fullPath); err == nil {
-		overwrite, _ := pterm.DefaultInteractiveConfirm.WithDefaultValue(false).Show(fmt.Sprintf("File %s already exists. Overwrite?", fullPath))
+		overwrite, _ := shared.Confirm(fmt.Sprintf("File %s already exists. Overwrite?", fullPath), false)
 		if !overwrite {
 			pterm.Warning.Println("Operation cancelled")
 			return
@@ -1,6 +1,7 @@
 package crypto
 
 import (
+	"crypto"
 	"crypto/ecdsa"
 	"crypto/ed25519"
 	"crypto/elliptic"
@@ -45,7 +46,7 @@ Public keys are saved with 0644 permissions.`,
 
   # Generate only public key from existing private key
   magi crypto keypair --public --private-key-path ./private.pem`,
-	Run: runGenerateKeypair,
+	RunE: runGenerateKeypair,
 }
 
 func init() {
@@ -58,16 +59,14 @@ func init() {
 	KeypairCmd.Flags().StringVar(&keypairPrivateKeyPath, "private-key-path", "", "Existing private key path (for public key generation)")
 }
 
-func runGenerateKeypair(cmd *cobra.Command, args []string) {
+func runGenerateKeypair(cmd *cobra.Command, args []string) error {
 	// Validate flags
 	if keypairGeneratePublic && keypairGeneratePrivate {
-		pterm.Error.Println("Cannot specify both --public and --private")
-		return
+		return fmt.Errorf("cannot specify both --public and --private")
 	}
 
 	if keypairGeneratePublic && keypairPrivateKeyPath == "" {
-		pterm.Error.Println("Must specify --private-key-path when using --public")
-		return
+		return fmt.Errorf("must specify --private-key-path when using --public")
 	}
 
 	// Interactive prompts if not skipped
@@ -78,21 +77,20 @@ func runGenerateKeypair(cmd *cobra.Command, args []string) {
 			WithDefaultOption(keypairAlgorithm).
 			Show("Select key algorithm")
 		if err != nil {
-			return
+			return fmt.Errorf("failed to read key algorithm: %w", err)
 		}
 
 		keypairFilename, err = pterm.DefaultInteractiveTextInput.
 			WithDefaultText(keypairFilename).
 			Show("Enter key filename (no extension)")
 		if err != nil {
-			return
+			return fmt.Errorf("failed to read key filename: %w", err)
 		}
 	}
 
 	// Ensure output directory exists
 	if err := os.MkdirAll(keypairPath, 0755); err != nil {
-		pterm.Error.Printf("Failed to create directory: %v\n", err)
-		return
+		return fmt.Errorf("failed to create directory: %w", err)
 	}
 
 	privateKeyPath := filepath.Join(keypairPath, keypairFilename+".pem")
@@ -101,25 +99,22 @@ func runGenerateKeypair(cmd *cobra.Command, args []string) {
 	if keypairGeneratePublic {
 		// Generate public key from existing private key
 		if err := generatePublicKeyFromPrivate(keypairPrivateKeyPath, publicKeyPath); err != nil {
-			pterm.Error.Printf("Failed to generate public key: %v\n", err)
-			return
+			return fmt.Errorf("failed to generate public key: %w", err)
 		}
 		pterm.Success.Printf("Public key generated at %s\n", publicKeyPath)
-		return
+		return nil
 	}
 
 	// Generate new key pair
 	priv, pub, err := generateKeys(keypairAlgorithm)
 	if err != nil {
-		pterm.Error.Printf("Failed to generate keys: %v\n", err)
-		return
+		return fmt.Errorf("failed to generate keys: %w", err)
 	}
 
 	// Save private key
 	if !keypairGeneratePublic {
 		if err := savePrivateKey(priv, privateKeyPath); err != nil {
-			pterm.Error.Printf("Failed to save private key: %v\n", err)
-			return
+			return fmt.Errorf("failed to save private key: %w", err)
 		}
 		pterm.Success.Printf("Private key generated at %s\n", privateKeyPath)
 	}
@@ -127,11 +122,11 @@ func runGenerateKeypair(cmd *cobra.Command, args []string) {
 	// Save public key
 	if !keypairGeneratePrivate {
 		if err := savePublicKey(pub, publicKeyPath); err != nil {
-			pterm.Error.Printf("Failed to save public key: %v\n", err)
-			return
+			return fmt.Errorf("failed to save public key: %w", err)
 		}
 		pterm.Success.Printf("Public key generated at %s\n", publicKeyPath)
 	}
+	return nil
 }
 
 func generateKeys(algo string) (interface{}, interface{}, error) {
@@ -211,44 +206,47 @@ func savePublicKey(key interface{}, path string) error {
 }
 
 func generatePublicKeyFromPrivate(privatePath, publicPath string) error {
-	data, err := os.ReadFile(privatePath)
+	priv, err := loadPrivateKey(privatePath)
 	if err != nil {
 		return err
 	}
+	return savePublicKey(priv.Public(), publicPath)
+}
+
+// loadPrivateKey reads a PEM private key written by savePrivateKey (PKCS#1 RSA, SEC 1 EC,
+// or PKCS#8) and returns it as a signer.
+func loadPrivateKey(path string) (crypto.Signer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
 
 	block, _ := pem.Decode(data)
 	if block == nil {
-		return fmt.Errorf("failed to decode PEM block containing private key")
+		return nil, fmt.Errorf("failed to decode PEM block containing private key")
 	}
 
-	var pub interface{}
 	switch block.Type {
 	case "RSA PRIVATE KEY":
-		priv, err := x509.ParsePKCS1PrivateKey(block.Bytes)
-		if err != nil {
-			return err
-		}
-		pub = &priv.PublicKey
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
 	case "EC PRIVATE KEY":
-		priv, err := x509.ParseECPrivateKey(block.Bytes)
-		if err != nil {
-			return err
-		}
-		pub = &priv.PublicKey
+		return x509.ParseECPrivateKey(block.Bytes)
 	case "PRIVATE KEY":
 		priv, err := x509.ParsePKCS8PrivateKey(block.Bytes)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		switch k := priv.(type) {
 		case ed25519.PrivateKey:
-			pub = k.Public()
+			return k, nil
+		case *rsa.PrivateKey:
+			return k, nil
+		case *ecdsa.PrivateKey:
+			return k, nil
 		default:
-			return fmt.Errorf("unsupported private key type in PKCS8")
+			return nil, fmt.Errorf("unsupported private key type in PKCS8")
 		}
 	default:
-		return fmt.Errorf("unsupported private key type: %s", block.Type)
+		return nil, fmt.Errorf("unsupported private key type: %s", block.Type)
 	}
-
-	return savePublicKey(pub, publicPath)
 }
@@ -118,3 +118,45 @@ func TestGeneratePublicKeyFromPrivate(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, pubKey)
 }
+
+func setKeypairFlags(t *testing.T, yes, public, private bool, path, privateKeyPath string) {
+	t.Helper()
+	saved := []any{keypairFilename, keypairPath, keypairAlgorithm, keypairYes, keypairGeneratePublic, keypairGeneratePrivate, keypairPrivateKeyPath}
+	t.Cleanup(func() {
+		keypairFilename = saved[0].(string)
+		keypairPath = saved[1].(string)
+		keypairAlgorithm = saved[2].(string)
+		keypairYes = saved[3].(bool)
+		keypairGeneratePublic = saved[4].(bool)
+		keypairGeneratePrivate = saved[5].(bool)
+		keypairPrivateKeyPath = saved[6].(string)
+	})
+
+	keypairFilename = "key"
+	keypairAlgorithm = "ed25519"
+	keypairYes = yes
+	keypairGeneratePublic = public
+	keypairGeneratePrivate = private
+	keypairPath = path
+	keypairPrivateKeyPath = privateKeyPath
+}
+
+func TestRunGenerateKeypairReturnsFlagErrors(t *testing.T) {
+	setKeypairFlags(t, true, true, true, t.TempDir(), "")
+	assert.ErrorContains(t, runGenerateKeypair(KeypairCmd, nil), "cannot specify both --public and --private")
+
+	setKeypairFlags(t, true, true, false, t.TempDir(), "")
+	assert.ErrorContains(t, runGenerateKeypair(KeypairCmd, nil), "must specify --private-key-path")
+
+	setKeypairFlags(t, true, true, false, t.TempDir(), filepath.Join(t.TempDir(), "missing.pem"))
+	assert.ErrorContains(t, runGenerateKeypair(KeypairCmd, nil), "failed to generate public key")
+}
+
+func TestRunGenerateKeypairWritesKeys(t *testing.T) {
+	dir := t.TempDir()
+	setKeypairFlags(t, true, false, false, dir, "")
+
+	assert.NoError(t, runGenerateKeypair(KeypairCmd, nil))
+	assert.FileExists(t, filepath.Join(dir, "key.pem"))
+	assert.FileExists(t, filepath.Join(dir, "key.pub"))
+}
@@ -0,0 +1,120 @@
+package crypto
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+)
+
+// GenerateSSHKey creates a key pair with algorithm (ed25519, rsa, or ecdsa) for use with
+// OpenSSH. The private key is written to privatePath with 0600 permissions and the public
+// key, in authorized_keys format, to privatePath + ".pub". It returns the public key line.
+func GenerateSSHKey(algorithm, privatePath, comment string) (string, error) {
+	priv, pub, err := generateKeys(algorithm)
+	if err != nil {
+		return "", err
+	}
+
+	authorizedKey, err := marshalAuthorizedKey(pub, comment)
+	if err != nil {
+		return "", err
+	}
+
+	if k, ok := priv.(ed25519.PrivateKey); ok {
+		// OpenSSH only reads Ed25519 keys in its own format.
+		data := pem.EncodeToMemory(&pem.Block{Type: "OPENSSH PRIVATE KEY", Bytes: marshalOpenSSHEd25519(k, comment)})
+		if err := os.WriteFile(privatePath, data, 0600); err != nil {
+			return "", err
+		}
+	} else if err := savePrivateKey(priv, privatePath); err != nil {
+		return "", err
+	}
+
+	if err := os.WriteFile(privatePath+".pub", []byte(authorizedKey+"\n"), 0644); err != nil {
+		return "", err
+	}
+	return authorizedKey, nil
+}
+
+// marshalAuthorizedKey formats pub as an authorized_keys line ("<type> <base64> <comment>").
+func marshalAuthorizedKey(pub interface{}, comment string) (string, error) {
+	keyType, blob, err := sshPublicKeyBlob(pub)
+	if err != nil {
+		return "", err
+	}
+	line := keyType + " " + base64.StdEncoding.EncodeToString(blob)
+	if comment = strings.TrimSpace(comment); comment != "" {
+		line += " " + comment
+	}
+	return line, nil
+}
+
+// sshPublicKeyBlob encodes pub in the SSH wire format (RFC 4253, RFC 5656, RFC 8709).
+func sshPublicKeyBlob(pub interface{}) (string, []byte, error) {
+	switch k := pub.(type) {
+	case ed25519.PublicKey:
+		return "ssh-ed25519", sshWire([]byte("ssh-ed25519"), []byte(k)), nil
+	case *rsa.PublicKey:
+		return "ssh-rsa", sshWire([]byte("ssh-rsa"), sshMPInt(big.NewInt(int64(k.E))), sshMPInt(k.N)), nil
+	case *ecdsa.PublicKey:
+		if k.Curve != elliptic.P256() {
+			return "", nil, fmt.Errorf("unsupported ECDSA curve for SSH: %s", k.Curve.Params().Name)
+		}
+		point, err := k.ECDH()
+		if err != nil {
+			return "", nil, err
+		}
+		return "ecdsa-sha2-nistp256", sshWire([]byte("ecdsa-sha2-nistp256"), []byte("nistp256"), point.Bytes()), nil
+	default:
+		return "", nil, fmt.Errorf("unsupported public key type for SSH")
+	}
+}
+
+// marshalOpenSSHEd25519 encodes an unencrypted "openssh-key-v1" private key.
+func marshalOpenSSHEd25519(priv ed25519.PrivateKey, comment string) []byte {
+	pub := priv.Public().(ed25519.PublicKey)
+	_, pubBlob, _ := sshPublicKeyBlob(pub)
+
+	var check [4]byte
+	_, _ = rand.Read(check[:])
+
+	private := append(append([]byte{}, check[:]...), check[:]...)
+	private = append(private, sshWire([]byte("ssh-ed25519"), []byte(pub), []byte(priv), []byte(comment))...)
+	for i := byte(1); len(private)%8 != 0; i++ {
+		private = append(private, i)
+	}
+
+	out := []byte("openssh-key-v1\x00")
+	out = append(out, sshWire([]byte("none"), []byte("none"), []byte{})...)
+	out = binary.BigEndian.AppendUint32(out, 1)
+	out = append(out, sshWire(pubBlob, private)...)
+	return out
+}
+
+// sshWire encodes each field as an SSH string (uint32 length followed by the bytes).
+func sshWire(fields ...[]byte) []byte {
+	var out []byte
+	for _, field := range fields {
+		out = binary.BigEndian.AppendUint32(out, uint32(len(field)))
+		out = append(out, field...)
+	}
+	return out
+}
+
+// sshMPInt returns the SSH mpint encoding of a non-negative n, without the length prefix.
+func sshMPInt(n *big.Int) []byte {
+	b := n.Bytes()
+	if len(b) > 0 && b[0]&0x80 != 0 {
+		b = append([]byte{0}, b...)
+	}
+	return b
+}
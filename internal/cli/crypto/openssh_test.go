@@ -0,0 +1,40 @@
+package crypto
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateSSHKey(t *testing.T) {
+	for _, algo := range []string{"ed25519", "ecdsa", "rsa"} {
+		t.Run(algo, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "magi_test")
+			authorizedKey, err := GenerateSSHKey(algo, path, "magi@test")
+			require.NoError(t, err)
+
+			info, err := os.Stat(path)
+			require.NoError(t, err)
+			assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+			pub, err := os.ReadFile(path + ".pub")
+			require.NoError(t, err)
+			assert.Equal(t, authorizedKey+"\n", string(pub))
+			assert.True(t, strings.HasSuffix(authorizedKey, " magi@test"))
+
+			// Cross-check with OpenSSH when it is installed.
+			if _, err := exec.LookPath("ssh-keygen"); err != nil {
+				t.Skip("ssh-keygen not installed")
+			}
+			derived, err := exec.Command("ssh-keygen", "-y", "-f", path).Output()
+			require.NoError(t, err)
+			fields := strings.Fields(authorizedKey)
+			assert.Equal(t, fields[0]+" "+fields[1], strings.Join(strings.Fields(string(derived))[:2], " "))
+		})
+	}
+}
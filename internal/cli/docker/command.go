@@ -9,6 +9,7 @@ import (
 
 	"github.com/MagdielCAS/magi-cli/internal/cli/docker/compose"
 	"github.com/MagdielCAS/magi-cli/internal/cli/docker/dockerfile"
+	"github.com/MagdielCAS/magi-cli/pkg/shared"
 	"github.com/pterm/pterm"
 	"github.com/spf13/cobra"
 )
@@ -40,8 +41,8 @@ Examples:
 
   # Set up Docker Compose
   magi docker compose`,
-		Run: func(cmd *cobra.Command, args []string) {
-			runDocker()
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDocker()
 		},
 	}
 
@@ -50,10 +51,9 @@ Examples:
 	return cmd
 }
 
-func runDocker() {
+func runDocker() error {
 	if !isDockerRunning() {
-		pterm.Error.Println("Docker is not running. Please start Docker and try again.")
-		return
+		return fmt.Errorf("docker is not running, please start Docker and try again")
 	}
 
 	projectType := identifyProjectType()
@@ -63,13 +63,15 @@ func runDocker() {
 		pterm.Warning.Println("Could not identify project type. Skipping Dockerfile generation.")
 	} else {
 		if _, err := os.Stat("Dockerfile"); os.IsNotExist(err) {
-			generateDockerfile(projectType)
+			if err := generateDockerfile(projectType); err != nil {
+				return err
+			}
 		} else {
 			pterm.Info.Println("Dockerfile already exists. Skipping generation.")
 		}
 	}
 
-	buildAndRunDocker()
+	return buildAndRunDocker()
 }
 
 func identifyProjectType() string {
@@ -86,15 +88,11 @@ func isDockerRunning() bool {
 	return cmd.Run() == nil
 }
 
-func generateDockerfile(projectType string) {
+func generateDockerfile(projectType string) error {
 	spinner, _ := pterm.DefaultSpinner.Start("Generating Dockerfile...")
 	var content string
 
-	// Ask user for port
-	port, _ := pterm.DefaultInteractiveTextInput.WithDefaultValue("8080").Show("Enter the port your application listens on")
-	if port == "" {
-		port = "8080"
-	}
+	port := promptWithDefault("Enter the port your application listens on", "8080")
 
 	switch projectType {
 	case "go":
@@ -107,34 +105,33 @@ func generateDockerfile(projectType string) {
 		content = dockerfile.GenerateNodeDockerfile(port)
 	}
 
-	if content != "" {
-		err := os.WriteFile("Dockerfile", []byte(content), 0644)
-		if err != nil {
-			spinner.Fail(fmt.Sprintf("Failed to create Dockerfile: %v", err))
-			return
-		}
-		spinner.Success("Dockerfile generated successfully")
-	} else {
+	if content == "" {
 		spinner.Fail("Failed to generate Dockerfile content")
+		return fmt.Errorf("no Dockerfile template for project type %q", projectType)
 	}
+
+	if err := os.WriteFile("Dockerfile", []byte(content), 0644); err != nil {
+		spinner.Fail("Failed to create Dockerfile")
+		return fmt.Errorf("failed to create Dockerfile: %w", err)
+	}
+	spinner.Success("Dockerfile generated successfully")
+	return nil
 }
 
-func buildAndRunDocker() {
+func buildAndRunDocker() error {
 	// Simple build and run implementation
 	// In the future, this could be more interactive or configurable
 
 	// Check if Dockerfile exists
 	if _, err := os.Stat("Dockerfile"); os.IsNotExist(err) {
-		pterm.Error.Println("No Dockerfile found. Cannot build.")
-		return
+		return fmt.Errorf("no Dockerfile found, cannot build")
 	}
 
 	pterm.Info.Println("Building Docker image...")
 
 	wd, err := os.Getwd()
 	if err != nil {
-		pterm.Error.Printf("Failed to get working directory: %v\n", err)
-		return
+		return fmt.Errorf("failed to get working directory: %w", err)
 	}
 	imageName := strings.ToLower(filepath.Base(wd))
 
@@ -142,23 +139,34 @@ func buildAndRunDocker() {
 	buildCmd.Stdout = os.Stdout
 	buildCmd.Stderr = os.Stderr
 	if err := buildCmd.Run(); err != nil {
-		pterm.Error.Printf("Docker build failed: %v\n", err)
-		return
+		return fmt.Errorf("docker build failed: %w", err)
 	}
 	pterm.Success.Println("Docker image built successfully")
 
 	pterm.Info.Println("Running Docker container...")
 
-	portMapping, _ := pterm.DefaultInteractiveTextInput.WithDefaultValue("8080:8080").Show("Enter port mapping (host:container)")
-	if portMapping == "" {
-		portMapping = "8080:8080"
-	}
+	portMapping := promptWithDefault("Enter port mapping (host:container)", "8080:8080")
 
 	runCmd := exec.Command("docker", "run", "-p", portMapping, imageName)
 	runCmd.Stdout = os.Stdout
 	runCmd.Stderr = os.Stderr
 	if err := runCmd.Run(); err != nil {
-		pterm.Error.Printf("Docker run failed: %v\n", err)
-		return
+		return fmt.Errorf("docker run failed: %w", err)
+	}
+	return nil
+}
+
+// promptWithDefault asks for a value and falls back to def when the answer is empty or the
+// session is not interactive.
+func promptWithDefault(prompt, def string) string {
+	if !shared.IsInteractive() {
+		pterm.Info.Printf("Non-interactive session, using %q for: %s\n", def, prompt)
+		return def
+	}
+
+	value, _ := pterm.DefaultInteractiveTextInput.WithDefaultValue(def).Show(prompt)
+	if value == "" {
+		return def
 	}
+	return value
 }
@@ -37,8 +37,8 @@ Examples:
 
 Security:
   This command sends the generated configuration and any custom service descriptions to the configured LLM provider for validation and generation. Ensure no secrets are hardcoded in your service descriptions.`,
-		Run: func(cmd *cobra.Command, args []string) {
-			runCompose(cmd.Context(), autoAccept)
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runCompose(cmd.Context(), autoAccept)
 		},
 	}
 
@@ -46,7 +46,7 @@ Security:
 	return cmd
 }
 
-func runCompose(ctx context.Context, autoAccept bool) {
+func runCompose(ctx context.Context, autoAccept bool) error {
 	// 1. Dockerfile Discovery
 	dockerfiles := findDockerfiles()
 	if len(dockerfiles) == 0 {
@@ -63,21 +63,28 @@ func runCompose(ctx context.Context, autoAccept bool) {
 	serviceNames = append(serviceNames, "Custom Service")
 	sort.Strings(serviceNames)
 
-	selectedServices, _ := pterm.DefaultInteractiveMultiselect.
-		WithOptions(serviceNames).
-		WithDefaultText("Select services to include").
-		Show()
+	var selectedServices []string
+	switch {
+	case shared.IsInteractive():
+		selectedServices, _ = pterm.DefaultInteractiveMultiselect.
+			WithOptions(serviceNames).
+			WithDefaultText("Select services to include").
+			Show()
+	case autoAccept:
+		pterm.Info.Println("Non-interactive session: skipping service selection, only services built from Dockerfiles are included.")
+	default:
+		return shared.NonInteractiveError("--yes")
+	}
 
 	if len(selectedServices) == 0 && len(dockerfiles) == 0 {
 		pterm.Warning.Println("No services selected and no Dockerfiles found. Exiting.")
-		return
+		return nil
 	}
 
 	// 3. Content Generation
 	composeContent, err := generateComposeContent(ctx, selectedServices, dockerfiles, autoAccept)
 	if err != nil {
-		pterm.Error.Printf("Failed to generate compose content: %v\n", err)
-		return
+		return fmt.Errorf("failed to generate compose content: %w", err)
 	}
 
 	// 4. AI Validation
@@ -90,8 +97,7 @@ func runCompose(ctx context.Context, autoAccept bool) {
 	// 5. File Creation
 	err = os.WriteFile("docker-compose.yml", []byte(validatedContent), 0644)
 	if err != nil {
-		pterm.Error.Printf("Failed to write docker-compose.yml: %v\n", err)
-		return
+		return fmt.Errorf("failed to write docker-compose.yml: %w", err)
 	}
 	pterm.Success.Println("docker-compose.yml created successfully")
 
@@ -104,6 +110,7 @@ func runCompose(ctx context.Context, autoAccept bool) {
 			}
 		}
 	}
+	return nil
 }
 
 func findDockerfiles() []string {
@@ -374,6 +374,6 @@ func generateSaltKey(length int) (string, error) {
 }
 
 func promptForConfirmation(question string) bool {
-	result, _ := pterm.DefaultInteractiveConfirm.Show(question)
+	result, _ := shared.Confirm(question, false)
 	return result
 }
@@ -1,15 +1,15 @@
 package i18n
 
 import (
-	"context"
 	"encoding/json"
 	"fmt"
 	"regexp"
+	"sort"
 	"strings"
-	"time"
 
 	"github.com/MagdielCAS/magi-cli/pkg/agent"
 	"github.com/MagdielCAS/magi-cli/pkg/llm"
+	"github.com/MagdielCAS/magi-cli/pkg/shared"
 )
 
 // Data Models
@@ -19,8 +19,11 @@ type TranslationData struct {
 }
 
 type I18nKey struct {
-	Key          string            `json:"key"`
-	Context      string            `json:"context"`
+	Key     string `json:"key"`
+	Context string `json:"context"`
+	// Source is the default copy the code passes with the key, such as the second argument
+	// of t('key', 'Default text') or a defaultValue. It is empty when the code has none.
+	Source       string            `json:"source,omitempty"`
 	Translations map[string]string `json:"translations"`
 }
 
@@ -35,18 +38,84 @@ type KeyExtractor struct {
 // We use two capturing groups: one for single quotes, one for double quotes
 // Defined as package-level variables to avoid repeated compilation.
 var keyExtractorPatterns = []*regexp.Regexp{
-	// t('key') or t("key")
-	regexp.MustCompile(`(?:^|[^a-zA-Z0-9_])t\((?:'([^']+)'|"([^"]+)")\)`),
+	// t('key') or t("key"), optionally followed by more arguments
+	regexp.MustCompile(`(?:^|[^a-zA-Z0-9_])t\((?:'([^']+)'|"([^"]+)")\s*[,)]`),
 	// i18n.t('key') or i18n.t("key")
-	regexp.MustCompile(`i18n\.t\((?:'([^']+)'|"([^"]+)")\)`),
+	regexp.MustCompile(`i18n\.t\((?:'([^']+)'|"([^"]+)")\s*[,)]`),
 	// $t('key') or $t("key")
-	regexp.MustCompile(`\$t\((?:'([^']+)'|"([^"]+)")\)`),
+	regexp.MustCompile(`\$t\((?:'([^']+)'|"([^"]+)")\s*[,)]`),
 	// <T key="key" />
 	regexp.MustCompile(`<T[^>]+key=(?:'([^']+)'|"([^"]+)")`),
 	// <T keyName="key" />
 	regexp.MustCompile(`<T[^>]+keyName=(?:'([^']+)'|"([^"]+)")`),
 }
 
+// sourceTextPatterns find the default copy that follows a key in the same call or element.
+var sourceTextPatterns = []*regexp.Regexp{
+	// t('key', 'Default text')
+	regexp.MustCompile(`^\s*,\s*(?:'([^']*)'|"([^"]*)")`),
+	// t('key', { defaultValue: 'Default text' }) or <T keyName="key" defaultValue="Default text" />
+	regexp.MustCompile(`defaultValue\s*[:=]\s*\{?\s*(?:'([^']*)'|"([^"]*)")`),
+}
+
+// keyMatch is a key found on a line. end is the index right after its closing quote.
+type keyMatch struct {
+	key        string
+	start, end int
+}
+
+// findKeys returns the keys used on content with the default copy passed next to each.
+func findKeys(content string) []I18nKey {
+	var matches []keyMatch
+	for _, pattern := range keyExtractorPatterns {
+		for _, m := range pattern.FindAllStringSubmatchIndex(content, -1) {
+			// m[2:4] is the single quote group, m[4:6] the double quote group.
+			for g := 1; g <= 2; g++ {
+				if m[2*g] >= 0 && m[2*g+1] > m[2*g] {
+					matches = append(matches, keyMatch{key: content[m[2*g]:m[2*g+1]], start: m[0], end: m[2*g+1] + 1})
+					break
+				}
+			}
+		}
+	}
+
+	// Basic context extraction (just the line content for now)
+	context := strings.TrimSpace(content)
+	if len(context) > 100 {
+		context = context[:100] + "..."
+	}
+
+	keys := make([]I18nKey, 0, len(matches))
+	for _, match := range matches {
+		// The default copy must come before the next key on the line.
+		limit := len(content)
+		for _, other := range matches {
+			if other.start >= match.end && other.start < limit {
+				limit = other.start
+			}
+		}
+		keys = append(keys, I18nKey{
+			Key:     match.key,
+			Context: context,
+			Source:  sourceText(content[match.end:limit]),
+		})
+	}
+	return keys
+}
+
+// sourceText returns the default copy at the start of rest, the text following a key.
+func sourceText(rest string) string {
+	for _, pattern := range sourceTextPatterns {
+		if m := pattern.FindStringSubmatch(rest); m != nil {
+			if m[1] != "" {
+				return m[1]
+			}
+			return m[2]
+		}
+	}
+	return ""
+}
+
 func NewKeyExtractor(diff string) *KeyExtractor {
 	return &KeyExtractor{diff: diff}
 }
@@ -79,50 +148,24 @@ func (a *KeyExtractor) Execute(input map[string]string) (string, error) {
 		}
 
 		// Remove the "+" prefix
-		content := line[1:]
-		for _, pattern := range keyExtractorPatterns {
-			matches := pattern.FindAllStringSubmatch(content, -1)
-			for _, match := range matches {
-				// match[0] is full match
-				// match[1] is single quote group
-				// match[2] is double quote group
-				var key string
-				if len(match) > 1 && match[1] != "" {
-					key = match[1]
-				} else if len(match) > 2 && match[2] != "" {
-					key = match[2]
-				}
-
-				if key != "" {
-					// Basic context extraction (just the line content for now)
-					context := strings.TrimSpace(content)
-					if len(context) > 100 {
-						context = context[:100] + "..."
-					}
-
-					keys = append(keys, I18nKey{
-						Key:     key,
-						Context: context,
-					})
-				}
-			}
-		}
+		keys = append(keys, findKeys(line[1:])...)
 	}
 
-	// Remove duplicates
-	uniqueKeys := make(map[string]I18nKey)
+	// Remove duplicates, keeping the first occurrence and the first default copy found
+	seen := make(map[string]int)
+	finalKeys := make([]I18nKey, 0, len(keys))
 	for _, k := range keys {
-		if _, exists := uniqueKeys[k.Key]; !exists {
-			uniqueKeys[k.Key] = k
+		if i, ok := seen[k.Key]; ok {
+			if finalKeys[i].Source == "" {
+				finalKeys[i].Source = k.Source
+			}
+			continue
 		}
-	}
-
-	finalKeys := make([]I18nKey, 0, len(uniqueKeys))
-	for _, k := range uniqueKeys {
+		seen[k.Key] = len(finalKeys)
 		finalKeys = append(finalKeys, k)
 	}
 
-	jsonData, err := json.Marshal(finalKeys)
+	jsonData, err := json.Marshal(sortedKeys(finalKeys))
 	if err != nil {
 		return "", fmt.Errorf("failed to marshal keys: %w", err)
 	}
@@ -132,12 +175,12 @@ func (a *KeyExtractor) Execute(input map[string]string) (string, error) {
 
 // TranslationGenerator Agent
 type TranslationGenerator struct {
-	llmService *llm.Service
+	translator Translator
 }
 
-func NewTranslationGenerator(service *llm.Service) *TranslationGenerator {
+func NewTranslationGenerator(translator Translator) *TranslationGenerator {
 	return &TranslationGenerator{
-		llmService: service,
+		translator: translator,
 	}
 }
 
@@ -160,86 +203,12 @@ func (a *TranslationGenerator) Execute(input map[string]string) (string, error)
 		return `{"keys": []}`, nil
 	}
 
-	langs := strings.Join(languages, ", ")
-	batchSize := 15 // Process 15 keys at a time to avoid timeouts
-	var allTranslatedKeys []I18nKey
-
-	for i := 0; i < len(keys); i += batchSize {
-		end := i + batchSize
-		if end > len(keys) {
-			end = len(keys)
-		}
-		batch := keys[i:end]
-		batchJSON, _ := json.Marshal(batch)
-
-		prompt := fmt.Sprintf(`You are a professional translator.
-Translate the following i18n keys to %s.
-The input is a JSON array of keys.
-Return a JSON object with the following structure:
-{
-  "keys": [
-    {
-      "key": "original_key",
-      "context": "context if available",
-      "translations": {
-        "en": "English translation",
-        "de": "German translation",
-        ... (one key for each target language code)
-      }
-    }
-  ]
-}
-
-Input Keys:
-%s
-`, langs, string(batchJSON))
-
-		req := llm.ChatCompletionRequest{
-			Messages: []llm.ChatMessage{
-				{Role: "system", Content: "You are a helpful assistant that generates i18n translations."},
-				{Role: "user", Content: prompt},
-			},
-			Temperature: 0.3,
-		}
-
-		// Retry logic
-		var response string
-		var err error
-		maxRetries := 3
-		for attempt := 0; attempt < maxRetries; attempt++ {
-			response, err = a.llmService.ChatCompletion(context.Background(), req)
-			if err == nil {
-				break
-			}
-			// Exponential backoff: 2s, 4s, 8s
-			sleepTime := time.Duration(1<<attempt) * 2 * time.Second
-			time.Sleep(sleepTime)
-		}
-
-		if err != nil {
-			return "", fmt.Errorf("failed to translate batch %d-%d after %d retries: %w", i, end, maxRetries, err)
-		}
-
-		// Clean response
-		response = strings.TrimPrefix(response, "```json")
-		response = strings.TrimPrefix(response, "```")
-		response = strings.TrimSuffix(response, "```")
-		response = strings.TrimSpace(response)
-
-		var batchResult TranslationData
-		if err := json.Unmarshal([]byte(response), &batchResult); err != nil {
-			// Try parsing as raw array if the model forgot the wrapper
-			var rawKeys []I18nKey
-			if err2 := json.Unmarshal([]byte(response), &rawKeys); err2 == nil {
-				allTranslatedKeys = append(allTranslatedKeys, rawKeys...)
-				continue
-			}
-			return "", fmt.Errorf("failed to parse batch response: %w", err)
-		}
-		allTranslatedKeys = append(allTranslatedKeys, batchResult.Keys...)
+	translatedKeys, err := a.translator.Translate(keys, languages)
+	if err != nil {
+		return "", err
 	}
 
-	finalResult := TranslationData{Keys: allTranslatedKeys}
+	finalResult := TranslationData{Keys: translatedKeys}
 	finalJSON, err := json.Marshal(finalResult)
 	if err != nil {
 		return "", fmt.Errorf("failed to marshal final result: %w", err)
@@ -270,38 +239,45 @@ func (a *TranslationEnhancer) WaitForResults() []string {
 func (a *TranslationEnhancer) Execute(input map[string]string) (string, error) {
 	translationsJSON := input["translation_generator"]
 
+	var data TranslationData
+	if err := json.Unmarshal([]byte(translationsJSON), &data); err != nil {
+		return "", fmt.Errorf("failed to parse translations: %w", err)
+	}
+
+	outputFormat := "Return the result in the exact same JSON structure."
+	if textFormat {
+		outputFormat = textFormatInstructions
+	}
+
 	// Construct prompt for enhancement
 	prompt := fmt.Sprintf(`You are a professional localization expert.
 Review the following translations and enhance them for clarity, consistency, and professional tone.
 If a context is provided, use it to ensure the translation fits the usage.
 Do not change the keys.
-Return the result in the exact same JSON structure.
+%s
 
 Input Translations:
 %s
-`, translationsJSON)
-
-	// Call LLM
-	req := llm.ChatCompletionRequest{
-		Messages: []llm.ChatMessage{
-			{Role: "system", Content: "You are a helpful assistant that enhances i18n translations."},
-			{Role: "user", Content: prompt},
-		},
-		Temperature: 0.2, // Lower temperature for consistency
-	}
+`, outputFormat, translationsJSON)
+
+	// Lower temperature for consistency
+	req := translationRequest("You are a helpful assistant that enhances i18n translations.", prompt, 0.2, languages)
 
-	response, err := a.llmService.ChatCompletion(context.Background(), req)
+	response, err := a.llmService.ChatCompletion(shared.BaseContext(), req)
 	if err != nil {
 		return "", fmt.Errorf("LLM enhancement failed: %w", err)
 	}
 
-	// Clean response
-	response = strings.TrimPrefix(response, "```json")
-	response = strings.TrimPrefix(response, "```")
-	response = strings.TrimSuffix(response, "```")
-	response = strings.TrimSpace(response)
+	enhanced, err := parseTranslationResponse(response, data.Keys)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse enhanced translations: %w", err)
+	}
 
-	return response, nil
+	finalJSON, err := json.Marshal(TranslationData{Keys: enhanced})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal enhanced translations: %w", err)
+	}
+	return string(finalJSON), nil
 }
 
 // SQLGenerator Agent
@@ -337,10 +313,10 @@ func (a *SQLGenerator) Execute(input map[string]string) (string, error) {
 	sb.WriteString("-- Auto-generated i18n SQL script\n")
 	sb.WriteString("BEGIN;\n\n")
 
-	for _, k := range translationData.Keys {
+	for _, k := range sortedKeys(translationData.Keys) {
 		sb.WriteString(fmt.Sprintf("-- Key: %s\n", k.Key))
-		for lang, content := range k.Translations {
-			escapedContent := strings.ReplaceAll(content, "'", "''")
+		for _, lang := range sortedLanguages(k.Translations) {
+			escapedContent := strings.ReplaceAll(k.Translations[lang], "'", "''")
 			sb.WriteString(fmt.Sprintf("INSERT INTO i18n_translations (key, locale, content) VALUES ('%s', '%s', '%s') ON CONFLICT (key, locale) DO UPDATE SET content = EXCLUDED.content;\n", k.Key, lang, escapedContent))
 		}
 		sb.WriteString("\n")
@@ -350,6 +326,26 @@ func (a *SQLGenerator) Execute(input map[string]string) (string, error) {
 	return sb.String(), nil
 }
 
+// sortedKeys returns a copy of keys ordered by key name, so generated files do not change
+// between runs with the same input.
+func sortedKeys(keys []I18nKey) []I18nKey {
+	sorted := append([]I18nKey(nil), keys...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Key < sorted[j].Key
+	})
+	return sorted
+}
+
+// sortedLanguages returns the languages of translations in alphabetical order.
+func sortedLanguages(translations map[string]string) []string {
+	langs := make([]string, 0, len(translations))
+	for lang := range translations {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	return langs
+}
+
 // Helper to ensure agents implement the interface
 var _ agent.AgentInstance = &KeyExtractor{}
 var _ agent.AgentInstance = &TranslationGenerator{}
@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"strings"
 	"testing"
+
+	"github.com/MagdielCAS/magi-cli/pkg/agent"
 )
 
 func TestKeyExtractor_Execute(t *testing.T) {
@@ -103,6 +105,38 @@ func TestKeyExtractor_Execute(t *testing.T) {
 	}
 }
 
+func TestKeyExtractorSourceText(t *testing.T) {
+	diff := `
++ t('errors.user_not_found', 'No account uses this email.')
++ i18n.t("auth.welcome", { defaultValue: "Welcome back!" }) + t('auth.bye')
++ <T keyName="nav.home" defaultValue="Home" />
++ t('errors.user_not_found')
+`
+	result, err := NewKeyExtractor(diff).Execute(nil)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	var keys []I18nKey
+	if err := json.Unmarshal([]byte(result), &keys); err != nil {
+		t.Fatalf("Failed to unmarshal result: %v", err)
+	}
+
+	want := map[string]string{
+		"errors.user_not_found": "No account uses this email.",
+		"auth.welcome":          "Welcome back!",
+		"auth.bye":              "",
+		"nav.home":              "Home",
+	}
+	if len(keys) != len(want) {
+		t.Fatalf("expected %d keys, got %+v", len(want), keys)
+	}
+	for _, k := range keys {
+		if source, ok := want[k.Key]; !ok || k.Source != source {
+			t.Errorf("key %q: source %q, want %q", k.Key, k.Source, source)
+		}
+	}
+}
+
 func TestSQLGenerator_Execute(t *testing.T) {
 	// Mock input data from TranslationEnhancer
 	inputData := TranslationData{
@@ -163,3 +197,49 @@ func TestSQLGenerator_Execute(t *testing.T) {
 		t.Error("Single quote not escaped correctly in French")
 	}
 }
+
+func TestPipelineDependenciesAreWired(t *testing.T) {
+	pool := agent.NewAgentPool()
+	pool.WithAgent(NewKeyExtractor(""))
+	pool.WithAgent(NewTranslationGenerator(nil))
+	pool.WithAgent(NewTranslationEnhancer(nil))
+	pool.WithAgent(NewSQLGenerator())
+
+	if err := pool.ValidateDependencies(nil); err != nil {
+		t.Fatalf("i18n pipeline has invalid dependencies: %v", err)
+	}
+}
+
+func TestSQLGenerator_ExecuteIsDeterministic(t *testing.T) {
+	jsonData, err := json.Marshal(TranslationData{Keys: []I18nKey{
+		{Key: "z.key", Translations: map[string]string{"fr": "Z", "en": "Z", "de": "Z", "pt": "Z"}},
+		{Key: "a.key", Translations: map[string]string{"pt": "A", "de": "A", "fr": "A", "en": "A"}},
+	}})
+	if err != nil {
+		t.Fatalf("Failed to marshal input data: %v", err)
+	}
+	input := map[string]string{"translation_enhancer": string(jsonData)}
+
+	first, err := NewSQLGenerator().Execute(input)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	for i := 0; i < 5; i++ {
+		again, err := NewSQLGenerator().Execute(input)
+		if err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		if again != first {
+			t.Fatalf("expected identical output across runs\nfirst:\n%s\nagain:\n%s", first, again)
+		}
+	}
+
+	last := -1
+	for _, row := range []string{"'a.key', 'de'", "'a.key', 'en'", "'a.key', 'fr'", "'a.key', 'pt'", "'z.key', 'de'", "'z.key', 'en'", "'z.key', 'fr'", "'z.key', 'pt'"} {
+		idx := strings.Index(first, "VALUES ("+row)
+		if idx <= last {
+			t.Fatalf("expected row %s after the previous one\n%s", row, first)
+		}
+		last = idx
+	}
+}
@@ -2,9 +2,10 @@ package i18n
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
-	"os"
+	"sort"
 	"strings"
 	"time"
 
@@ -14,8 +15,19 @@ import (
 	"github.com/MagdielCAS/magi-cli/pkg/shared"
 	"github.com/pterm/pterm"
 	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
 )
 
+// Output formats accepted by --format.
+const (
+	formatJSON   = "json"
+	formatSQL    = "sql"
+	formatTolgee = "tolgee"
+	formatYAML   = "yaml"
+)
+
+var supportedFormats = []string{formatJSON, formatSQL, formatTolgee, formatYAML}
+
 var (
 	originBranch string
 	maxTokens    int
@@ -24,6 +36,9 @@ var (
 	tolgeeOutput bool
 	languages    []string
 	outputFile   string
+	formats      []string
+	translator   string
+	dryRun       bool
 )
 
 var i18nCmd = &cobra.Command{
@@ -31,23 +46,57 @@ var i18nCmd = &cobra.Command{
 	Short: "AI-powered i18n translation management",
 	Long: `Automates the extraction and translation of i18n keys from code changes.
 It compares the current branch with an origin branch to find new keys,
-then uses AI agents to generate translations in specified languages.`,
+then uses AI agents to generate translations in specified languages.
+
+Output formats (--format, comma separated):
+  json    Combined translations file (--output, default i18n_translations.json)
+  sql     Upsert script written to i18n_insert.sql
+  tolgee  One flat <lang>.json file per language (same as --tolgee)
+  yaml    One nested <lang>.yml file per language (Rails, Symfony, Flutter)
+
+Translation engines (--translator):
+  llm     Translate with the configured heavy model (default)
+  deepl   Translate with DeepL (i18n.deepl.api_key, optional i18n.deepl.glossary_id);
+          it translates the default text passed with each key (t('key', 'Text') or
+          defaultValue), or the key name when there is none, and the LLM enhancer
+          still polishes the result using the key context
+
+Use --dry-run to list the keys found in the diff, with the line they come from, without
+calling the AI provider or writing any file.
+
+Examples:
+  # See which keys would be translated
+  magi i18n --dry-run
+
+  # Write the combined JSON file and YAML locale files
+  magi i18n --format json,yaml --languages en,es
+
+  # Translate with DeepL and polish with the LLM
+  magi i18n --translator deepl`,
 	RunE: runI18n,
 }
 
 func I18nCmd() *cobra.Command {
 	i18nCmd.Flags().StringVar(&originBranch, "origin", "main", "Origin branch to compare against")
-	i18nCmd.Flags().IntVar(&maxTokens, "max-tokens", 1000, "Max tokens for AI response")
-	i18nCmd.Flags().BoolVar(&textFormat, "text-format", false, "Use text format instead of JSON schema")
+	i18nCmd.Flags().IntVar(&maxTokens, "max-tokens", 0, "Max tokens for each translation response (0 leaves the limit to the provider)")
+	i18nCmd.Flags().BoolVar(&textFormat, "text-format", false, "Ask for \"<key> | <lang> | <translation>\" lines instead of JSON schema output")
 	i18nCmd.Flags().BoolVar(&autoConfirm, "yes", false, "Auto-confirm all prompts")
 	i18nCmd.Flags().BoolVar(&tolgeeOutput, "tolgee", false, "Generate Tolgee-compatible output files")
 	i18nCmd.Flags().StringSliceVar(&languages, "languages", []string{"en", "de"}, "Target languages for translation")
 	i18nCmd.Flags().StringVarP(&outputFile, "output", "o", "i18n_translations.json", "Output file for translations")
+	i18nCmd.Flags().StringVar(&translator, "translator", translatorLLM, "Translation engine: llm or deepl (deepl needs i18n.deepl.api_key)")
+	i18nCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Only list the keys that would be translated; no AI call is made and no file is written")
+	i18nCmd.Flags().StringSliceVar(&formats, "format", []string{formatJSON, formatSQL}, "Output formats: "+strings.Join(supportedFormats, ", "))
 
 	return i18nCmd
 }
 
 func runI18n(cmd *cobra.Command, args []string) error {
+	selectedFormats, err := resolveFormats(formats, tolgeeOutput)
+	if err != nil {
+		return err
+	}
+
 	pterm.DefaultSection.Println("Running AI-Powered I18n Extraction")
 
 	// 1. Git Integration
@@ -62,6 +111,7 @@ func runI18n(cmd *cobra.Command, args []string) error {
 	}
 
 	pterm.Info.Printf("Comparing branch '%s' with origin '%s'...\n", currentBranch, originBranch)
+	shared.SetStage("Reading the diff")
 
 	// Using 3 dots ... finds the merge base.
 	diffOutput, err := git.RunGit(ctx, "diff", "-U0", "--no-color", fmt.Sprintf("origin/%s...%s", originBranch, currentBranch))
@@ -80,11 +130,19 @@ func runI18n(cmd *cobra.Command, args []string) error {
 
 	// 2. Initialize Agents
 	pool := agent.NewAgentPool()
+	pool.WithLogger(shared.Logger())
+	pool.WithProgress(func(event agent.Event) {
+		shared.TrackAgent(event.Agent, string(event.Kind))
+	})
 
 	// Key Extractor
 	keyExtractor := NewKeyExtractor(diffOutput)
 	pool.WithAgent(keyExtractor)
 
+	if dryRun {
+		return listExtractedKeys(pool)
+	}
+
 	// Translation Generator
 	runtimeCtx, err := shared.BuildRuntimeContext()
 	if err != nil {
@@ -112,7 +170,12 @@ func runI18n(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to build LLM service: %w", err)
 	}
 
-	translationGenerator := NewTranslationGenerator(llmService)
+	keyTranslator, err := newTranslator(translator, llmService)
+	if err != nil {
+		return err
+	}
+
+	translationGenerator := NewTranslationGenerator(keyTranslator)
 	pool.WithAgent(translationGenerator)
 
 	// Translation Enhancer
@@ -121,14 +184,17 @@ func runI18n(cmd *cobra.Command, args []string) error {
 	// SQL Generator
 	pool.WithAgent(NewSQLGenerator())
 
+	// Catch miswired dependencies before any LLM call is made.
+	if err := pool.ValidateDependencies(nil); err != nil {
+		return err
+	}
+
 	// Execute Agents
-	spinner, _ := pterm.DefaultSpinner.Start("Analyzing code, extracting keys, and generating translations...")
-	results, err := pool.ExecuteAgents(nil)
+	shared.SetStage("Extracting keys and generating translations")
+	results, err := executeWithProgress(pool, "Analyzing code, extracting keys, and generating translations...")
 	if err != nil {
-		spinner.Fail("Agent execution failed: " + err.Error())
 		return err
 	}
-	spinner.Success("Analysis complete!")
 
 	// 3. Process Results
 	// We are interested in the final output from TranslationEnhancer (for JSON/Tolgee) and SQLGenerator (for SQL)
@@ -163,46 +229,74 @@ func runI18n(cmd *cobra.Command, args []string) error {
 
 	// Display Results
 	pterm.DefaultSection.Println("Generated Translations")
-	for _, k := range translationData.Keys {
+	for _, k := range sortedKeys(translationData.Keys) {
 		pterm.Println(pterm.Cyan(k.Key))
-		for lang, trans := range k.Translations {
-			pterm.Printf("  %s: %s\n", strings.ToUpper(lang), trans)
+		for _, lang := range sortedLanguages(k.Translations) {
+			pterm.Printf("  %s: %s\n", strings.ToUpper(lang), k.Translations[lang])
 		}
 		pterm.Println()
 	}
 
 	// Output Handling
 	if !autoConfirm {
-		confirmed, _ := pterm.DefaultInteractiveConfirm.Show("Save these translations?")
+		confirmed, _ := shared.Confirm("Save these translations?", false)
 		if !confirmed {
 			pterm.Info.Println("Aborted.")
 			return nil
 		}
 	}
 
+	// Write every selected format even if an earlier one fails, then report all failures.
+	shared.SetStage("Writing translation files")
+	var writeErrs []error
+
 	// Save JSON
-	if err := createTranslationFile(&translationData); err != nil {
-		pterm.Error.Println("Failed to save JSON file:", err)
+	if selectedFormats[formatJSON] {
+		if err := createTranslationFile(&translationData); err != nil {
+			pterm.Error.Println("Failed to save JSON file:", err)
+			writeErrs = append(writeErrs, err)
+		}
 	}
 
 	// Save SQL
-	sqlScript := results["sql_generator"]
-	if err := createSQLFile(sqlScript); err != nil {
-		pterm.Error.Println("Failed to save SQL file:", err)
+	if selectedFormats[formatSQL] {
+		sqlScript := results["sql_generator"]
+		if err := createSQLFile(sqlScript); err != nil {
+			pterm.Error.Println("Failed to save SQL file:", err)
+			writeErrs = append(writeErrs, err)
+		}
 	}
 
-	// Save Tolgee
-	if tolgeeOutput {
+	// Save Tolgee (per-file failures are already reported)
+	if selectedFormats[formatTolgee] {
 		if err := createTolgeeFiles(&translationData); err != nil {
-			pterm.Error.Println("Failed to save Tolgee files:", err)
+			writeErrs = append(writeErrs, err)
+		}
+	}
+
+	// Save YAML (per-file failures are already reported)
+	if selectedFormats[formatYAML] {
+		if err := createYAMLFiles(&translationData); err != nil {
+			writeErrs = append(writeErrs, err)
 		}
 	}
 
+	if shared.IsJSONOutput() {
+		if err := shared.PrintJSON(translationData); err != nil {
+			writeErrs = append(writeErrs, err)
+		}
+	}
+
+	if len(writeErrs) > 0 {
+		return fmt.Errorf("failed to save some translation files: %w", errors.Join(writeErrs...))
+	}
 	return nil
 }
 
+// createTranslationFile writes the translations as JSON, keys sorted by name (the
+// translations of a key are maps, which encoding/json already sorts).
 func createTranslationFile(data *TranslationData) error {
-	jsonData, err := json.MarshalIndent(data, "", "  ")
+	jsonData, err := json.MarshalIndent(TranslationData{Keys: sortedKeys(data.Keys)}, "", "  ")
 	if err != nil {
 		return err
 	}
@@ -211,7 +305,7 @@ func createTranslationFile(data *TranslationData) error {
 	if filename == "" {
 		filename = "i18n_translations.json"
 	}
-	if err := os.WriteFile(filename, jsonData, 0644); err != nil {
+	if err := shared.WriteFileAtomic(filename, jsonData, 0644); err != nil {
 		return err
 	}
 	pterm.Success.Println("Saved translations to " + filename)
@@ -220,15 +314,45 @@ func createTranslationFile(data *TranslationData) error {
 
 func createSQLFile(content string) error {
 	filename := "i18n_insert.sql"
-	if err := os.WriteFile(filename, []byte(content), 0644); err != nil {
+	if err := shared.WriteFileAtomic(filename, []byte(content), 0644); err != nil {
 		return err
 	}
 	pterm.Success.Println("Saved SQL script to " + filename)
 	return nil
 }
 
-func createTolgeeFiles(data *TranslationData) error {
-	// Convert to map[lang]map[key]value
+// resolveFormats validates the requested output formats. The legacy --tolgee flag adds
+// the tolgee format.
+func resolveFormats(requested []string, tolgee bool) (map[string]bool, error) {
+	selected := make(map[string]bool)
+	for _, format := range requested {
+		format = strings.ToLower(strings.TrimSpace(format))
+		if format == "" {
+			continue
+		}
+		valid := false
+		for _, supported := range supportedFormats {
+			if format == supported {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return nil, fmt.Errorf("unsupported output format %q (supported: %s)", format, strings.Join(supportedFormats, ", "))
+		}
+		selected[format] = true
+	}
+	if tolgee {
+		selected[formatTolgee] = true
+	}
+	if len(selected) == 0 {
+		return nil, fmt.Errorf("at least one output format is required (supported: %s)", strings.Join(supportedFormats, ", "))
+	}
+	return selected, nil
+}
+
+// buildLangMaps converts the translations to map[lang]map[key]value.
+func buildLangMaps(data *TranslationData) map[string]map[string]string {
 	langMaps := make(map[string]map[string]string)
 
 	for _, k := range data.Keys {
@@ -240,23 +364,163 @@ func createTolgeeFiles(data *TranslationData) error {
 		}
 	}
 
+	return langMaps
+}
+
+// createTolgeeFiles writes one <lang>.json per language; languages are written in order and
+// encoding/json sorts the keys of each file.
+func createTolgeeFiles(data *TranslationData) error {
+	return writeLocaleFiles("Tolgee", "json", buildLangMaps(data), func(content map[string]string) ([]byte, error) {
+		return json.MarshalIndent(content, "", "  ")
+	})
+}
+
+func createYAMLFiles(data *TranslationData) error {
+	return writeLocaleFiles("YAML", "yml", buildLangMaps(data), func(content map[string]string) ([]byte, error) {
+		return marshalYAML(nestKeys(content))
+	})
+}
+
+// writeLocaleFiles writes one <lang>.<ext> file per language. Each file is written
+// atomically and a failure does not stop the remaining languages; all failures are
+// reported and returned together.
+func writeLocaleFiles(label, ext string, langMaps map[string]map[string]string, encode func(map[string]string) ([]byte, error)) error {
+	langs := make([]string, 0, len(langMaps))
+	for lang := range langMaps {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+
 	var savedFiles []string
-	for lang, content := range langMaps {
-		filename := fmt.Sprintf("%s.json", lang)
-		if err := writeJSONFile(filename, content); err != nil {
-			return err
+	var failures []error
+	for _, lang := range langs {
+		filename := fmt.Sprintf("%s.%s", lang, ext)
+		content, err := encode(langMaps[lang])
+		if err == nil {
+			err = shared.WriteFileAtomic(filename, content, 0644)
+		}
+		if err != nil {
+			pterm.Error.Printf("Failed to write %s: %v\n", filename, err)
+			failures = append(failures, fmt.Errorf("%s: %w", filename, err))
+			continue
 		}
 		savedFiles = append(savedFiles, filename)
 	}
 
-	pterm.Success.Printf("Saved Tolgee files (%s)\n", strings.Join(savedFiles, ", "))
+	if len(savedFiles) > 0 {
+		pterm.Success.Printf("Saved %s files (%s)\n", label, strings.Join(savedFiles, ", "))
+	}
+	if len(failures) > 0 {
+		return fmt.Errorf("%d of %d %s files failed: %w", len(failures), len(langs), label, errors.Join(failures...))
+	}
 	return nil
 }
 
-func writeJSONFile(filename string, data interface{}) error {
-	jsonData, err := json.MarshalIndent(data, "", "  ")
+// nestKeys turns dotted keys ("auth.login.title") into nested maps. When a key collides
+// with a prefix of another key ("auth" and "auth.login"), the longer key is kept flat at
+// the deepest level that is still a map.
+func nestKeys(flat map[string]string) map[string]interface{} {
+	keys := make([]string, 0, len(flat))
+	for key := range flat {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	root := make(map[string]interface{})
+	for _, key := range keys {
+		parts := strings.Split(key, ".")
+		node := root
+		for i, part := range parts {
+			if i == len(parts)-1 {
+				node[part] = flat[key]
+				break
+			}
+			child, exists := node[part]
+			if !exists {
+				next := make(map[string]interface{})
+				node[part] = next
+				node = next
+				continue
+			}
+			next, ok := child.(map[string]interface{})
+			if !ok {
+				node[strings.Join(parts[i:], ".")] = flat[key]
+				break
+			}
+			node = next
+		}
+	}
+	return root
+}
+
+func marshalYAML(data interface{}) ([]byte, error) {
+	var sb strings.Builder
+	encoder := yaml.NewEncoder(&sb)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(data); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return []byte(sb.String()), nil
+}
+
+// listExtractedKeys runs a pool that only holds the key extractor and prints the keys it
+// found with their context, for --dry-run.
+func listExtractedKeys(pool *agent.AgentPool) error {
+	results, err := pool.ExecuteAgents(nil)
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(filename, jsonData, 0644)
+	var keys []I18nKey
+	if err := json.Unmarshal([]byte(results["key_extractor"]), &keys); err != nil {
+		return fmt.Errorf("failed to parse extracted keys: %w", err)
+	}
+
+	if shared.IsJSONOutput() {
+		return shared.PrintJSON(TranslationData{Keys: keys})
+	}
+	if len(keys) == 0 {
+		pterm.Info.Println("No new i18n keys found.")
+		return nil
+	}
+
+	data := pterm.TableData{{"Key", "Default text", "Context"}}
+	for _, k := range keys {
+		data = append(data, []string{k.Key, k.Source, k.Context})
+	}
+	if err := pterm.DefaultTable.WithHasHeader().WithData(data).Render(); err != nil {
+		return err
+	}
+	pterm.Info.Printf("Dry run: %d key(s) would be translated to %s; no AI call was made.\n", len(keys), strings.Join(languages, ", "))
+	return nil
+}
+
+// executeWithProgress runs the pool behind the live per-agent progress list, or behind a
+// single spinner when progress is disabled.
+func executeWithProgress(pool *agent.AgentPool, title string) (map[string]string, error) {
+	if !shared.ProgressEnabled() {
+		spinner, _ := pterm.DefaultSpinner.Start(title)
+		results, err := pool.ExecuteAgents(nil)
+		if err != nil {
+			spinner.Fail("Agent execution failed: " + err.Error())
+			return nil, err
+		}
+		spinner.Success("Analysis complete!")
+		return results, nil
+	}
+
+	progress := shared.StartAgentProgress(title)
+	pool.WithProgress(func(event agent.Event) {
+		progress.Update(event.Agent, string(event.Kind), event.Elapsed, event.Err)
+	})
+	results, err := pool.ExecuteAgents(nil)
+	progress.Stop()
+	if err != nil {
+		pterm.Error.Println("Agent execution failed: " + err.Error())
+		return nil, err
+	}
+	pterm.Success.Println("Analysis complete!")
+	return results, nil
 }
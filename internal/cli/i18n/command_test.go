@@ -0,0 +1,133 @@
+package i18n
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/MagdielCAS/magi-cli/pkg/agent"
+	"github.com/pterm/pterm"
+)
+
+func TestResolveFormats(t *testing.T) {
+	selected, err := resolveFormats([]string{"JSON", " yaml "}, true)
+	if err != nil {
+		t.Fatalf("resolveFormats failed: %v", err)
+	}
+	want := map[string]bool{formatJSON: true, formatYAML: true, formatTolgee: true}
+	if !reflect.DeepEqual(selected, want) {
+		t.Fatalf("resolveFormats() = %v, want %v", selected, want)
+	}
+
+	if _, err := resolveFormats([]string{"xml"}, false); err == nil {
+		t.Fatal("expected error for unsupported format")
+	}
+	if _, err := resolveFormats(nil, false); err == nil {
+		t.Fatal("expected error when no format is selected")
+	}
+}
+
+func TestNestKeys(t *testing.T) {
+	got := nestKeys(map[string]string{
+		"auth.login.title": "Sign in",
+		"auth.logout":      "Sign out",
+		"home":             "Home",
+		"home.title":       "Welcome",
+	})
+
+	want := map[string]interface{}{
+		"auth": map[string]interface{}{
+			"login":  map[string]interface{}{"title": "Sign in"},
+			"logout": "Sign out",
+		},
+		"home":       "Home",
+		"home.title": "Welcome",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("nestKeys() = %#v, want %#v", got, want)
+	}
+}
+
+func TestWriteLocaleFilesReportsFailuresWithoutAborting(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	langMaps := map[string]map[string]string{
+		"de": {"greeting": "Hallo"},
+		"en": {"greeting": "Hello"},
+	}
+	err := writeLocaleFiles("Test", "json", langMaps, func(content map[string]string) ([]byte, error) {
+		if content["greeting"] == "Hallo" {
+			return nil, errors.New("boom")
+		}
+		return []byte(content["greeting"]), nil
+	})
+	if err == nil {
+		t.Fatal("expected aggregated error")
+	}
+	if !strings.Contains(err.Error(), "de.json") {
+		t.Fatalf("expected failing file in error, got %v", err)
+	}
+
+	data, readErr := os.ReadFile("en.json")
+	if readErr != nil || string(data) != "Hello" {
+		t.Fatalf("expected en.json to be written, got %q (%v)", data, readErr)
+	}
+}
+
+func TestCreateTranslationFileIsSorted(t *testing.T) {
+	t.Chdir(t.TempDir())
+	original := outputFile
+	t.Cleanup(func() { outputFile = original })
+	outputFile = "out.json"
+
+	data := &TranslationData{Keys: []I18nKey{
+		{Key: "z.key", Translations: map[string]string{"en": "Z", "de": "Z"}},
+		{Key: "a.key", Translations: map[string]string{"en": "A", "de": "A"}},
+	}}
+
+	var runs []string
+	for i := 0; i < 2; i++ {
+		if err := createTranslationFile(data); err != nil {
+			t.Fatalf("createTranslationFile() error = %v", err)
+		}
+		content, err := os.ReadFile("out.json")
+		if err != nil {
+			t.Fatalf("read output: %v", err)
+		}
+		runs = append(runs, string(content))
+	}
+
+	if runs[0] != runs[1] {
+		t.Fatalf("expected identical files across runs\nfirst:\n%s\nsecond:\n%s", runs[0], runs[1])
+	}
+	if strings.Index(runs[0], "a.key") > strings.Index(runs[0], "z.key") {
+		t.Fatalf("expected keys sorted by name, got:\n%s", runs[0])
+	}
+	if data.Keys[0].Key != "z.key" {
+		t.Fatal("expected the input order to be left untouched")
+	}
+}
+
+func TestListExtractedKeys(t *testing.T) {
+	buf := new(bytes.Buffer)
+	originalTable, originalInfo := pterm.DefaultTable, pterm.Info
+	t.Cleanup(func() { pterm.DefaultTable, pterm.Info = originalTable, originalInfo })
+	pterm.DefaultTable = *pterm.DefaultTable.WithWriter(buf)
+	pterm.Info = *pterm.Info.WithWriter(buf)
+
+	pool := agent.NewAgentPool()
+	pool.WithAgent(NewKeyExtractor("+ const title = t('auth.login.title');\n"))
+	if err := listExtractedKeys(pool); err != nil {
+		t.Fatalf("listExtractedKeys() error = %v", err)
+	}
+
+	output := buf.String()
+	for _, want := range []string{"auth.login.title", "const title = t('auth.login.title');", "1 key(s) would be translated"} {
+		if !strings.Contains(output, want) {
+			t.Fatalf("expected %q in output, got:\n%s", want, output)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package i18n
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/MagdielCAS/magi-cli/pkg/llm"
+
+	openai "github.com/openai/openai-go/v3"
+	openaiShared "github.com/openai/openai-go/v3/shared"
+)
+
+// textFormatInstructions replace the JSON structure in the prompts when --text-format is set,
+// for providers or models that handle structured output poorly.
+const textFormatInstructions = `Reply with one line per key and language, formatted exactly as:
+<key> | <language code> | <translation>
+Do not add headings, numbering, code fences, or any other text.`
+
+// translationRequest builds the chat request of the translation agents: --max-tokens caps
+// the response when positive, and unless --text-format is set the response must match the
+// translation JSON schema for langs.
+func translationRequest(system, prompt string, temperature float64, langs []string) llm.ChatCompletionRequest {
+	req := llm.ChatCompletionRequest{
+		Messages: []llm.ChatMessage{
+			{Role: "system", Content: system},
+			{Role: "user", Content: prompt},
+		},
+		Temperature: temperature,
+	}
+	if maxTokens > 0 {
+		req.MaxTokens = float64(maxTokens)
+	}
+	if !textFormat {
+		req.ResponseFormat = translationSchema(langs)
+	}
+	return req
+}
+
+// translationSchema describes TranslationData with one required translation per language.
+func translationSchema(langs []string) *openai.ChatCompletionNewParamsResponseFormatUnion {
+	langProperties := make(map[string]interface{}, len(langs))
+	for _, lang := range langs {
+		langProperties[lang] = map[string]interface{}{"type": "string"}
+	}
+
+	return &openai.ChatCompletionNewParamsResponseFormatUnion{
+		OfJSONSchema: &openaiShared.ResponseFormatJSONSchemaParam{
+			JSONSchema: openaiShared.ResponseFormatJSONSchemaJSONSchemaParam{
+				Name:        "i18n_translations",
+				Description: openai.String("Translations of the i18n keys"),
+				Schema: interface{}(map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"keys": map[string]interface{}{
+							"type": "array",
+							"items": map[string]interface{}{
+								"type": "object",
+								"properties": map[string]interface{}{
+									"key":     map[string]interface{}{"type": "string"},
+									"context": map[string]interface{}{"type": "string"},
+									"source":  map[string]interface{}{"type": "string"},
+									"translations": map[string]interface{}{
+										"type":                 "object",
+										"properties":           langProperties,
+										"required":             langs,
+										"additionalProperties": false,
+									},
+								},
+								"required":             []string{"key", "context", "source", "translations"},
+								"additionalProperties": false,
+							},
+						},
+					},
+					"required":             []string{"keys"},
+					"additionalProperties": false,
+				}),
+				Strict: openai.Bool(true),
+			},
+		},
+	}
+}
+
+// parseTranslationResponse reads the translated keys from a model response, in the text
+// format when --text-format is set and as JSON otherwise. keys supplies the context and
+// the order of the keys in text mode.
+func parseTranslationResponse(response string, keys []I18nKey) ([]I18nKey, error) {
+	response = strings.TrimPrefix(response, "```json")
+	response = strings.TrimPrefix(response, "```")
+	response = strings.TrimSuffix(response, "```")
+	response = strings.TrimSpace(response)
+
+	if textFormat {
+		return parseTextTranslations(response, keys)
+	}
+
+	var data TranslationData
+	if err := json.Unmarshal([]byte(response), &data); err != nil {
+		// Try parsing as raw array if the model forgot the wrapper
+		var rawKeys []I18nKey
+		if err2 := json.Unmarshal([]byte(response), &rawKeys); err2 == nil {
+			return withSourceText(rawKeys, keys), nil
+		}
+		return nil, err
+	}
+	return withSourceText(data.Keys, keys), nil
+}
+
+// withSourceText fills the default copy of translated keys the model returned without one
+// from the matching extracted key, so later stages still see the text found in the code.
+func withSourceText(translated, keys []I18nKey) []I18nKey {
+	sources := make(map[string]string, len(keys))
+	for _, k := range keys {
+		sources[k.Key] = k.Source
+	}
+	for i := range translated {
+		if translated[i].Source == "" {
+			translated[i].Source = sources[translated[i].Key]
+		}
+	}
+	return translated
+}
+
+// parseTextTranslations reads "<key> | <lang> | <translation>" lines. Lines without that
+// shape are ignored; keys the model added are kept after the known ones.
+func parseTextTranslations(response string, keys []I18nKey) ([]I18nKey, error) {
+	result := make([]I18nKey, 0, len(keys))
+	index := make(map[string]int, len(keys))
+	for _, k := range keys {
+		index[k.Key] = len(result)
+		result = append(result, I18nKey{Key: k.Key, Context: k.Context, Source: k.Source, Translations: make(map[string]string)})
+	}
+
+	found := 0
+	for _, line := range strings.Split(response, "\n") {
+		parts := strings.SplitN(line, "|", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		key := strings.TrimSpace(parts[0])
+		lang := strings.ToLower(strings.TrimSpace(parts[1]))
+		text := strings.TrimSpace(parts[2])
+		if key == "" || lang == "" || text == "" {
+			continue
+		}
+
+		i, ok := index[key]
+		if !ok {
+			i = len(result)
+			index[key] = i
+			result = append(result, I18nKey{Key: key, Translations: make(map[string]string)})
+		}
+		result[i].Translations[lang] = text
+		found++
+	}
+
+	if found == 0 {
+		return nil, fmt.Errorf("no \"<key> | <lang> | <translation>\" lines found in the response")
+	}
+	return result, nil
+}
@@ -0,0 +1,70 @@
+package i18n
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTranslationRequest(t *testing.T) {
+	originalTokens, originalText := maxTokens, textFormat
+	t.Cleanup(func() { maxTokens, textFormat = originalTokens, originalText })
+
+	maxTokens, textFormat = 0, false
+	req := translationRequest("system", "prompt", 0.3, []string{"en", "de"})
+	if req.MaxTokens != 0 {
+		t.Fatalf("expected no token limit, got %v", req.MaxTokens)
+	}
+	if req.ResponseFormat == nil || req.ResponseFormat.OfJSONSchema == nil {
+		t.Fatal("expected the JSON schema response format by default")
+	}
+	schema := req.ResponseFormat.OfJSONSchema.JSONSchema.Schema.(map[string]interface{})
+	item := schema["properties"].(map[string]interface{})["keys"].(map[string]interface{})["items"].(map[string]interface{})
+	if _, ok := item["properties"].(map[string]interface{})["source"]; !ok {
+		t.Fatalf("expected the schema to keep the source field, got %+v", item)
+	}
+	if !reflect.DeepEqual(item["required"], []string{"key", "context", "source", "translations"}) {
+		t.Fatalf("unexpected required fields %v", item["required"])
+	}
+
+	maxTokens, textFormat = 2048, true
+	req = translationRequest("system", "prompt", 0.3, []string{"en"})
+	if req.MaxTokens != 2048 {
+		t.Fatalf("expected --max-tokens to be applied, got %v", req.MaxTokens)
+	}
+	if req.ResponseFormat != nil {
+		t.Fatal("expected no response format with --text-format")
+	}
+}
+
+func TestParseTranslationResponse(t *testing.T) {
+	original := textFormat
+	t.Cleanup(func() { textFormat = original })
+	keys := []I18nKey{{Key: "auth.title", Context: "t('auth.title')", Source: "Sign in"}, {Key: "auth.pipe"}}
+
+	textFormat = false
+	got, err := parseTranslationResponse("```json\n{\"keys\":[{\"key\":\"auth.title\",\"translations\":{\"de\":\"Anmelden\"}}]}\n```", keys)
+	if err != nil || len(got) != 1 || got[0].Translations["de"] != "Anmelden" {
+		t.Fatalf("unexpected JSON parse result %+v (err=%v)", got, err)
+	}
+	if got[0].Source != "Sign in" {
+		t.Fatalf("expected the source text to be kept, got %q", got[0].Source)
+	}
+
+	textFormat = true
+	got, err = parseTranslationResponse("Here you go:\nauth.title | DE | Anmelden\nauth.pipe | en | a | b\nextra.key | en | Extra\n", keys)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []I18nKey{
+		{Key: "auth.title", Context: "t('auth.title')", Source: "Sign in", Translations: map[string]string{"de": "Anmelden"}},
+		{Key: "auth.pipe", Translations: map[string]string{"en": "a | b"}},
+		{Key: "extra.key", Translations: map[string]string{"en": "Extra"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+
+	if _, err := parseTranslationResponse("Sorry, I cannot help.", keys); err == nil {
+		t.Fatal("expected an error when no translation lines are present")
+	}
+}
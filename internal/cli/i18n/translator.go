@@ -0,0 +1,307 @@
+package i18n
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/MagdielCAS/magi-cli/pkg/llm"
+	"github.com/MagdielCAS/magi-cli/pkg/shared"
+	"github.com/pterm/pterm"
+	"github.com/spf13/viper"
+)
+
+// Translator produces translations for the extracted keys in every requested language.
+// The returned keys carry a Translations entry per language code.
+type Translator interface {
+	Translate(keys []I18nKey, langs []string) ([]I18nKey, error)
+}
+
+// Translator names accepted by --translator.
+const (
+	translatorLLM   = "llm"
+	translatorDeepL = "deepl"
+)
+
+// newTranslator returns the translator selected by name.
+func newTranslator(name string, llmService *llm.Service) (Translator, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", translatorLLM:
+		return NewLLMTranslator(llmService), nil
+	case translatorDeepL:
+		return NewDeepLTranslator(
+			viper.GetString("i18n.deepl.api_key"),
+			viper.GetString("i18n.deepl.base_url"),
+			viper.GetString("i18n.deepl.glossary_id"),
+		)
+	default:
+		return nil, fmt.Errorf("unsupported translator %q (supported: %s, %s)", name, translatorLLM, translatorDeepL)
+	}
+}
+
+// translatorJSONFormat describes the JSON response expected from the LLM translator.
+const translatorJSONFormat = `Return a JSON object with the following structure:
+{
+  "keys": [
+    {
+      "key": "original_key",
+      "context": "context if available",
+      "source": "default text from the input, or an empty string",
+      "translations": {
+        "en": "English translation",
+        "de": "German translation",
+        ... (one key for each target language code)
+      }
+    }
+  ]
+}`
+
+// LLMTranslator translates keys with the configured LLM, using the key context.
+type LLMTranslator struct {
+	llmService *llm.Service
+	batchSize  int
+}
+
+func NewLLMTranslator(service *llm.Service) *LLMTranslator {
+	return &LLMTranslator{
+		llmService: service,
+		batchSize:  15, // Process 15 keys at a time to avoid timeouts
+	}
+}
+
+func (t *LLMTranslator) Translate(keys []I18nKey, langList []string) ([]I18nKey, error) {
+	langs := strings.Join(langList, ", ")
+	var allTranslatedKeys []I18nKey
+
+	for i := 0; i < len(keys); i += t.batchSize {
+		end := i + t.batchSize
+		if end > len(keys) {
+			end = len(keys)
+		}
+		batch := keys[i:end]
+		batchJSON, _ := json.Marshal(batch)
+
+		outputFormat := translatorJSONFormat
+		if textFormat {
+			outputFormat = textFormatInstructions
+		}
+		prompt := fmt.Sprintf(`You are a professional translator.
+Translate the following i18n keys to %s.
+The input is a JSON array of keys.
+%s
+
+Input Keys:
+%s
+`, langs, outputFormat, string(batchJSON))
+
+		req := translationRequest("You are a helpful assistant that generates i18n translations.", prompt, 0.3, langList)
+
+		// Retry logic
+		ctx := shared.BaseContext()
+		var response string
+		var err error
+		maxRetries := 3
+	retry:
+		for attempt := 0; attempt < maxRetries; attempt++ {
+			response, err = t.llmService.ChatCompletion(ctx, req)
+			// A truncated batch comes back truncated again, so only transient errors are retried.
+			if err == nil || errors.Is(err, llm.ErrProviderUnavailable) || errors.Is(err, llm.ErrResponseTruncated) {
+				break
+			}
+			// Exponential backoff: 2s, 4s, 8s, cut short by Ctrl-C or --timeout
+			select {
+			case <-time.After(time.Duration(1<<attempt) * 2 * time.Second):
+			case <-ctx.Done():
+				break retry
+			}
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to translate batch %d-%d after %d retries: %w", i, end, maxRetries, err)
+		}
+
+		translatedKeys, err := parseTranslationResponse(response, batch)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse batch response: %w", err)
+		}
+		allTranslatedKeys = append(allTranslatedKeys, translatedKeys...)
+	}
+
+	return allTranslatedKeys, nil
+}
+
+const (
+	deeplFreeBaseURL = "https://api-free.deepl.com"
+	deeplProBaseURL  = "https://api.deepl.com"
+	// deeplMaxTexts is the most text parameters DeepL accepts in one request.
+	deeplMaxTexts = 50
+)
+
+// deeplTargetLangs maps language codes whose bare form DeepL rejects as a target.
+var deeplTargetLangs = map[string]string{
+	"en": "EN-US",
+	"pt": "PT-BR",
+}
+
+// DeepLTranslator translates keys with the DeepL API. DeepL only sees text, so each key is
+// sent as the default copy found next to it in the code, or as its humanised last segment
+// ("auth.login_title" -> "login title") when there is none; the LLM enhancer stage then
+// polishes the result using the key context.
+type DeepLTranslator struct {
+	apiKey     string
+	baseURL    string
+	glossaryID string
+	httpClient *http.Client
+}
+
+func NewDeepLTranslator(apiKey, baseURL, glossaryID string) (*DeepLTranslator, error) {
+	apiKey = strings.TrimSpace(apiKey)
+	if apiKey == "" {
+		return nil, fmt.Errorf("DeepL API key is not configured; set it with 'magi config set i18n.deepl.api_key <key>'")
+	}
+
+	baseURL = strings.TrimRight(strings.TrimSpace(baseURL), "/")
+	if baseURL == "" {
+		// DeepL Free keys end with ":fx" and use a dedicated endpoint.
+		baseURL = deeplProBaseURL
+		if strings.HasSuffix(apiKey, ":fx") {
+			baseURL = deeplFreeBaseURL
+		}
+	}
+	if err := shared.CheckHostAllowed(baseURL, shared.AllowedHosts()); err != nil {
+		return nil, fmt.Errorf("DeepL endpoint rejected: %w", err)
+	}
+
+	return &DeepLTranslator{
+		apiKey:     apiKey,
+		baseURL:    baseURL,
+		glossaryID: strings.TrimSpace(glossaryID),
+		httpClient: shared.DefaultHTTPClient(),
+	}, nil
+}
+
+type deeplRequest struct {
+	Text       []string `json:"text"`
+	TargetLang string   `json:"target_lang"`
+	SourceLang string   `json:"source_lang,omitempty"`
+	GlossaryID string   `json:"glossary_id,omitempty"`
+}
+
+type deeplResponse struct {
+	Translations []struct {
+		Text string `json:"text"`
+	} `json:"translations"`
+}
+
+func (t *DeepLTranslator) Translate(keys []I18nKey, langs []string) ([]I18nKey, error) {
+	texts := make([]string, len(keys))
+	missingSource := 0
+	for i, k := range keys {
+		texts[i] = k.Source
+		if strings.TrimSpace(texts[i]) == "" {
+			texts[i] = humanizeKey(k.Key)
+			missingSource++
+		}
+	}
+	if missingSource > 0 {
+		pterm.Warning.Printf("%d key(s) have no default text in the code; DeepL translates their key names instead.\n", missingSource)
+	}
+
+	translated := make([]I18nKey, len(keys))
+	for i, k := range keys {
+		translated[i] = I18nKey{Key: k.Key, Context: k.Context, Source: k.Source, Translations: make(map[string]string)}
+	}
+
+	for _, lang := range langs {
+		results, err := t.translateTexts(texts, lang)
+		if err != nil {
+			return nil, fmt.Errorf("DeepL translation to %s failed: %w", lang, err)
+		}
+		if len(results) != len(keys) {
+			return nil, fmt.Errorf("DeepL returned %d translations for %d keys (%s)", len(results), len(keys), lang)
+		}
+		for i, text := range results {
+			translated[i].Translations[lang] = text
+		}
+	}
+
+	return translated, nil
+}
+
+// translateTexts translates texts to lang in requests of at most deeplMaxTexts texts.
+func (t *DeepLTranslator) translateTexts(texts []string, lang string) ([]string, error) {
+	results := make([]string, 0, len(texts))
+	for start := 0; start < len(texts); start += deeplMaxTexts {
+		end := min(start+deeplMaxTexts, len(texts))
+		batch, err := t.translateBatch(texts[start:end], lang)
+		if err != nil {
+			return nil, err
+		}
+		if len(batch) != end-start {
+			return nil, fmt.Errorf("DeepL returned %d translations for %d texts", len(batch), end-start)
+		}
+		results = append(results, batch...)
+	}
+	return results, nil
+}
+
+func (t *DeepLTranslator) translateBatch(texts []string, lang string) ([]string, error) {
+	target := strings.ToUpper(lang)
+	if mapped, ok := deeplTargetLangs[strings.ToLower(lang)]; ok {
+		target = mapped
+	}
+
+	payload := deeplRequest{Text: texts, TargetLang: target}
+	if t.glossaryID != "" {
+		// DeepL requires the source language when a glossary is used.
+		payload.GlossaryID = t.glossaryID
+		payload.SourceLang = "EN"
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(shared.BaseContext(), http.MethodPost, t.baseURL+"/v2/translate", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "DeepL-Auth-Key "+t.apiKey)
+
+	resp, err := t.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("DeepL returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	var decoded deeplResponse
+	if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	results := make([]string, len(decoded.Translations))
+	for i, tr := range decoded.Translations {
+		results[i] = tr.Text
+	}
+	return results, nil
+}
+
+// humanizeKey turns the last segment of a key into plain text for machine translation.
+func humanizeKey(key string) string {
+	if idx := strings.LastIndex(key, "."); idx >= 0 && idx < len(key)-1 {
+		key = key[idx+1:]
+	}
+	key = strings.NewReplacer("_", " ", "-", " ").Replace(key)
+	return strings.TrimSpace(key)
+}
@@ -0,0 +1,113 @@
+package i18n
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDeepLTranslator_Translate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "DeepL-Auth-Key test-key" {
+			t.Errorf("unexpected Authorization header %q", got)
+		}
+		var req deeplRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		if req.GlossaryID != "gloss" || req.SourceLang != "EN" {
+			t.Errorf("expected glossary and source language, got %+v", req)
+		}
+
+		var resp deeplResponse
+		for _, text := range req.Text {
+			resp.Translations = append(resp.Translations, struct {
+				Text string `json:"text"`
+			}{Text: req.TargetLang + ":" + text})
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	translator, err := NewDeepLTranslator("test-key", server.URL, "gloss")
+	if err != nil {
+		t.Fatalf("NewDeepLTranslator failed: %v", err)
+	}
+	translator.httpClient = server.Client()
+
+	keys, err := translator.Translate([]I18nKey{{Key: "auth.login_title", Context: "ctx"}}, []string{"en", "de"})
+	if err != nil {
+		t.Fatalf("Translate failed: %v", err)
+	}
+	if len(keys) != 1 || keys[0].Context != "ctx" {
+		t.Fatalf("unexpected keys %+v", keys)
+	}
+	if got := keys[0].Translations["en"]; got != "EN-US:login title" {
+		t.Fatalf("unexpected en translation %q", got)
+	}
+	if got := keys[0].Translations["de"]; got != "DE:login title" {
+		t.Fatalf("unexpected de translation %q", got)
+	}
+}
+
+func TestDeepLTranslatorSendsSourceTextInBatches(t *testing.T) {
+	var requests []int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req deeplRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		requests = append(requests, len(req.Text))
+
+		var resp deeplResponse
+		for _, text := range req.Text {
+			resp.Translations = append(resp.Translations, struct {
+				Text string `json:"text"`
+			}{Text: "de:" + text})
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	translator, err := NewDeepLTranslator("test-key", server.URL, "")
+	if err != nil {
+		t.Fatalf("NewDeepLTranslator failed: %v", err)
+	}
+	translator.httpClient = server.Client()
+
+	keys := make([]I18nKey, 120)
+	for i := range keys {
+		keys[i] = I18nKey{Key: fmt.Sprintf("errors.e%d", i), Source: fmt.Sprintf("Error %d happened", i)}
+	}
+	translated, err := translator.Translate(keys, []string{"de"})
+	if err != nil {
+		t.Fatalf("Translate failed: %v", err)
+	}
+	if want := []int{50, 50, 20}; !reflect.DeepEqual(requests, want) {
+		t.Fatalf("expected requests of %v texts, got %v", want, requests)
+	}
+	if got := translated[119].Translations["de"]; got != "de:Error 119 happened" {
+		t.Fatalf("expected the source text to be translated, got %q", got)
+	}
+}
+
+func TestNewTranslator(t *testing.T) {
+	if _, err := newTranslator("google", nil); err == nil || !strings.Contains(err.Error(), "unsupported translator") {
+		t.Fatalf("expected unsupported translator error, got %v", err)
+	}
+	if _, err := NewDeepLTranslator("", "", ""); err == nil {
+		t.Fatal("expected error when the DeepL API key is missing")
+	}
+
+	translator, err := NewDeepLTranslator("free-key:fx", "", "")
+	if err != nil {
+		t.Fatalf("NewDeepLTranslator failed: %v", err)
+	}
+	if translator.baseURL != deeplFreeBaseURL {
+		t.Fatalf("expected free endpoint for :fx keys, got %s", translator.baseURL)
+	}
+}
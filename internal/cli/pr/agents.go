@@ -8,15 +8,46 @@ import (
 
 	"github.com/MagdielCAS/magi-cli/pkg/llm"
 	"github.com/MagdielCAS/magi-cli/pkg/shared"
+	"github.com/spf13/viper"
 )
 
+const (
+	analysisMaxTokensKey     = "pr.analysis_max_tokens"
+	writerMaxTokensKey       = "pr.writer_max_tokens"
+	defaultAnalysisMaxTokens = 4096
+	defaultWriterMaxTokens   = 2048
+
+	// lightThresholdKey is the number of changed diff lines up to which the analysis runs on
+	// the light model. 0 disables the fast path.
+	lightThresholdKey     = "pr.light_threshold"
+	defaultLightThreshold = 20
+)
+
+// resolveMaxTokens reads a token limit from configuration (or its bound flag), falling
+// back to def when unset and rejecting non-positive values.
+func resolveMaxTokens(key string, def int) (int, error) {
+	if !viper.IsSet(key) {
+		return def, nil
+	}
+	value := viper.GetInt(key)
+	if value <= 0 {
+		return 0, fmt.Errorf("%s must be a positive integer, got %q", key, viper.GetString(key))
+	}
+	return value, nil
+}
+
 // AnalysisAgent performs the initial code analysis
 type AnalysisAgent struct {
-	runtime *shared.RuntimeContext
+	runtime   *shared.RuntimeContext
+	maxTokens int
+	// withLinters makes the agent wait for the LinterAgent and include its output.
+	withLinters bool
+	// preferLight runs the analysis on the light model first, for small diffs.
+	preferLight bool
 }
 
 func NewAnalysisAgent(runtime *shared.RuntimeContext) *AnalysisAgent {
-	return &AnalysisAgent{runtime: runtime}
+	return &AnalysisAgent{runtime: runtime, maxTokens: defaultAnalysisMaxTokens}
 }
 
 func (a *AnalysisAgent) Name() string {
@@ -24,9 +55,53 @@ func (a *AnalysisAgent) Name() string {
 }
 
 func (a *AnalysisAgent) WaitForResults() []string {
+	if a.withLinters {
+		return []string{linterAgentName}
+	}
 	return []string{}
 }
 
+// modelVariants returns the models to try in order: heavy first, or light first when the
+// diff is small enough for the fast path.
+func (a *AnalysisAgent) modelVariants() []llm.ModelVariant {
+	if a.preferLight {
+		return []llm.ModelVariant{llm.ModelVariantLight, llm.ModelVariantFallback, llm.ModelVariantHeavy}
+	}
+	return []llm.ModelVariant{llm.ModelVariantHeavy, llm.ModelVariantFallback, llm.ModelVariantLight}
+}
+
+// lightThreshold returns pr.light_threshold, defaulting to defaultLightThreshold.
+func lightThreshold() int {
+	if !viper.IsSet(lightThresholdKey) {
+		return defaultLightThreshold
+	}
+	return viper.GetInt(lightThresholdKey)
+}
+
+// useLightAnalysis reports whether diff changes at most pr.light_threshold lines.
+func useLightAnalysis(diff string) bool {
+	threshold := lightThreshold()
+	if threshold <= 0 {
+		return false
+	}
+	changed := countChangedLines(diff)
+	return changed > 0 && changed <= threshold
+}
+
+// countChangedLines counts the added and removed lines of a unified diff.
+func countChangedLines(diff string) int {
+	count := 0
+	for _, line := range strings.Split(diff, "\n") {
+		if strings.HasPrefix(line, "+++") || strings.HasPrefix(line, "---") {
+			continue
+		}
+		if strings.HasPrefix(line, "+") || strings.HasPrefix(line, "-") {
+			count++
+		}
+	}
+	return count
+}
+
 func (a *AnalysisAgent) Execute(input map[string]string) (string, error) {
 	// Reconstruct ReviewInput from input map
 	// We expect the payload to be pre-rendered or passed as raw components.
@@ -38,12 +113,11 @@ func (a *AnalysisAgent) Execute(input map[string]string) (string, error) {
 	if payload == "" {
 		return "", fmt.Errorf("payload is missing")
 	}
+	if a.withLinters {
+		payload = appendLinterOutput(payload, input[linterAgentName])
+	}
 
-	service, err := buildServiceWithFallback(a.runtime, []llm.ModelVariant{
-		llm.ModelVariantHeavy,
-		llm.ModelVariantFallback,
-		llm.ModelVariantLight,
-	})
+	service, err := buildServiceWithFallback(a.runtime, a.modelVariants())
 	if err != nil {
 		return "", fmt.Errorf("failed to build LLM service: %w", err)
 	}
@@ -54,11 +128,11 @@ func (a *AnalysisAgent) Execute(input map[string]string) (string, error) {
 			{Role: "user", Content: payload},
 		},
 		Temperature:    0.2,
-		MaxTokens:      4096,
+		MaxTokens:      float64(a.maxTokens),
 		ResponseFormat: AnalysisSchema,
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), a.runtime.AnalysisTimeout)
+	ctx, cancel := context.WithTimeout(shared.BaseContext(), a.runtime.AnalysisTimeout)
 	defer cancel()
 
 	return service.ChatCompletion(ctx, req)
@@ -66,11 +140,18 @@ func (a *AnalysisAgent) Execute(input map[string]string) (string, error) {
 
 // WriterAgent generates the PR description
 type WriterAgent struct {
-	runtime *shared.RuntimeContext
+	runtime   *shared.RuntimeContext
+	maxTokens int
+	// strictTemplate restricts the body to the template headings and re-prompts once
+	// when the model adds others.
+	strictTemplate bool
+	// err keeps the failure reason for the fallback warning, since the pool only reports
+	// the first failing agent.
+	err error
 }
 
 func NewWriterAgent(runtime *shared.RuntimeContext) *WriterAgent {
-	return &WriterAgent{runtime: runtime}
+	return &WriterAgent{runtime: runtime, maxTokens: defaultWriterMaxTokens}
 }
 
 func (a *WriterAgent) Name() string {
@@ -82,6 +163,12 @@ func (a *WriterAgent) WaitForResults() []string {
 }
 
 func (a *WriterAgent) Execute(input map[string]string) (string, error) {
+	output, err := a.run(input)
+	a.err = err
+	return output, err
+}
+
+func (a *WriterAgent) run(input map[string]string) (string, error) {
 	analysisJSON := input["AnalysisAgent"]
 	if analysisJSON == "" {
 		return "", fmt.Errorf("analysis result is missing")
@@ -116,20 +203,57 @@ func (a *WriterAgent) Execute(input map[string]string) (string, error) {
 		return "", fmt.Errorf("failed to build LLM service: %w", err)
 	}
 
+	if a.strictTemplate {
+		writerPayload += strictTemplateInstruction(templateHeadings(templateContent))
+	}
+
 	req := llm.ChatCompletionRequest{
 		Messages: []llm.ChatMessage{
 			{Role: "system", Content: writerSystemPrompt},
 			{Role: "user", Content: writerPayload},
 		},
 		Temperature:    0.25,
-		MaxTokens:      2048,
+		MaxTokens:      float64(a.maxTokens),
 		ResponseFormat: WriterSchema,
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), a.runtime.WriterTimeout)
+	ctx, cancel := context.WithTimeout(shared.BaseContext(), a.runtime.WriterTimeout)
 	defer cancel()
 
-	return service.ChatCompletion(ctx, req)
+	response, err := service.ChatCompletion(ctx, req)
+	if err != nil || !a.strictTemplate {
+		return response, err
+	}
+
+	var plan PullRequestPlan
+	if json.Unmarshal([]byte(sanitizeLLMJSON(response)), &plan) != nil {
+		return response, nil
+	}
+	extras := extraHeadings(templateContent, plan.Body)
+	if len(extras) == 0 {
+		return response, nil
+	}
+
+	// Re-prompt once; anything still extra is stripped by the reviewer.
+	req.Messages = append(req.Messages,
+		llm.ChatMessage{Role: "assistant", Content: response},
+		llm.ChatMessage{Role: "user", Content: fmt.Sprintf("The body contains headings that are not in the template: %s. Return the same JSON using only the template headings.", strings.Join(extras, ", "))},
+	)
+	// The first call used up most of ctx, so the retry gets a full WriterTimeout of its own.
+	retryCtx, retryCancel := context.WithTimeout(shared.BaseContext(), a.runtime.WriterTimeout)
+	defer retryCancel()
+	retried, err := service.ChatCompletion(retryCtx, req)
+	if err != nil {
+		return response, nil
+	}
+	return retried, nil
+}
+
+func strictTemplateInstruction(headings []string) string {
+	if len(headings) == 0 {
+		return "\n\nStrict template mode: the template has no headings, so do not add any markdown headings."
+	}
+	return fmt.Sprintf("\n\nStrict template mode: use only these headings, in this order, and do not add any other heading:\n- %s", strings.Join(headings, "\n- "))
 }
 
 func buildServiceWithFallback(runtime *shared.RuntimeContext, variants []llm.ModelVariant) (*llm.Service, error) {
@@ -259,7 +383,7 @@ func (a *I18nAgent) Execute(input map[string]string) (string, error) {
 	}
 
 	// We reuse WriterTimeout as it's a generation task
-	ctx, cancel := context.WithTimeout(context.Background(), a.runtime.WriterTimeout)
+	ctx, cancel := context.WithTimeout(shared.BaseContext(), a.runtime.WriterTimeout)
 	defer cancel()
 
 	return service.ChatCompletion(ctx, req)
@@ -3,7 +3,9 @@ package pr
 import (
 	"testing"
 
+	"github.com/MagdielCAS/magi-cli/pkg/llm"
 	"github.com/MagdielCAS/magi-cli/pkg/shared"
+	"github.com/spf13/viper"
 )
 
 func TestAnalysisAgent_Execute_MissingPayload(t *testing.T) {
@@ -75,3 +77,53 @@ func TestI18nAgent_Execute(t *testing.T) {
 		t.Errorf("expected no error (silent skip) for invalid JSON, got %v", err)
 	}
 }
+
+func TestResolveMaxTokens(t *testing.T) {
+	t.Cleanup(viper.Reset)
+
+	got, err := resolveMaxTokens(analysisMaxTokensKey, defaultAnalysisMaxTokens)
+	if err != nil || got != defaultAnalysisMaxTokens {
+		t.Fatalf("expected default %d, got %d (%v)", defaultAnalysisMaxTokens, got, err)
+	}
+
+	viper.Set(analysisMaxTokensKey, 8192)
+	got, err = resolveMaxTokens(analysisMaxTokensKey, defaultAnalysisMaxTokens)
+	if err != nil || got != 8192 {
+		t.Fatalf("expected configured 8192, got %d (%v)", got, err)
+	}
+
+	viper.Set(writerMaxTokensKey, -1)
+	if _, err := resolveMaxTokens(writerMaxTokensKey, defaultWriterMaxTokens); err == nil {
+		t.Fatal("expected error for non-positive value")
+	}
+}
+
+func TestUseLightAnalysis(t *testing.T) {
+	t.Cleanup(viper.Reset)
+
+	small := "diff --git a/a.go b/a.go\n--- a/a.go\n+++ b/a.go\n@@ -1 +1 @@\n-old\n+new\n"
+	if got := countChangedLines(small); got != 2 {
+		t.Fatalf("countChangedLines() = %d, want 2", got)
+	}
+	if !useLightAnalysis(small) {
+		t.Fatal("expected a small diff to use the light model by default")
+	}
+	if useLightAnalysis("") {
+		t.Fatal("expected an empty diff to keep the heavy model")
+	}
+
+	viper.Set(lightThresholdKey, 1)
+	if useLightAnalysis(small) {
+		t.Fatal("expected a diff above pr.light_threshold to keep the heavy model")
+	}
+
+	viper.Set(lightThresholdKey, 0)
+	if useLightAnalysis(small) {
+		t.Fatal("expected pr.light_threshold 0 to disable the fast path")
+	}
+
+	agent := &AnalysisAgent{preferLight: true}
+	if variants := agent.modelVariants(); variants[0] != llm.ModelVariantLight {
+		t.Fatalf("expected the light model first, got %v", variants)
+	}
+}
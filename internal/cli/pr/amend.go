@@ -0,0 +1,280 @@
+package pr
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+
+	"github.com/MagdielCAS/magi-cli/pkg/git"
+	"github.com/MagdielCAS/magi-cli/pkg/shared"
+)
+
+// findingsCommentMarker is a hidden tag written into every findings comment so
+// `magi pr amend-comment` can find the comment again.
+const findingsCommentMarker = "<!-- magi:findings -->"
+
+// Values of --comment-mode.
+const (
+	commentModeNew    = "new"
+	commentModeAmend  = "amend"
+	commentModeThread = "thread"
+)
+
+// maxCommentLength is the GitHub limit for a comment body.
+const maxCommentLength = 65536
+
+type amendCommentOptions struct {
+	target          string
+	notes           string
+	mode            string
+	iterationHeader bool
+	noSecrets       bool
+	verbose         bool
+}
+
+func newAmendCommentCmd() *cobra.Command {
+	opts := &amendCommentOptions{}
+	cmd := &cobra.Command{
+		Use:   "amend-comment",
+		Short: "Refresh the findings comment on the current branch's pull request",
+		Long: `Review the branch again and replace the findings comment magi posted on the open pull
+request for the current branch, instead of adding a new comment. The comment is found by the
+hidden marker magi writes into it; when none exists a new comment is posted.
+
+--comment-mode chooses how the fresh findings are published:
+  amend   Replace the findings comment (default)
+  thread  Append the findings as a new section under an iteration header (commit and
+          time), so the comment shows how the findings evolved across pushes
+  new     Post a separate comment
+
+--iteration-header adds the same header in the amend and new modes.
+
+Push your fixes first: the review uses the local diff between HEAD and the base branch.
+
+Data handling:
+  • Sends the git diff between HEAD and the base branch, AGENTS.md contents, and --notes to your
+    configured AI provider.
+
+Security note:
+  • The diff is scanned for likely secrets before any AI call (skip with --no-secrets-check).
+  • Secrets matching the redaction patterns are removed before the diff is sent.
+  • Shells out to 'gh' with explicit arguments to read and edit the comment.`,
+		Example: `  # Refresh the findings after pushing fixes
+  magi pr amend-comment
+
+  # Keep every review iteration in the same comment
+  magi pr amend-comment --comment-mode thread
+
+  # Give the reviewers context about the follow-up
+  magi pr amend-comment --notes "Addressed the nil checks from the first review"`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runAmendComment(cmd, opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.target, "target-branch", "", "Base branch to diff against (defaults to the detected base branch)")
+	cmd.Flags().StringVar(&opts.notes, "notes", "", "Additional context for the AI reviewers")
+	cmd.Flags().StringVar(&opts.mode, "comment-mode", commentModeAmend, "How to publish the findings: amend, thread, or new")
+	cmd.Flags().BoolVar(&opts.iterationHeader, "iteration-header", false, "Start the findings with the reviewed commit and time (always on with --comment-mode thread)")
+	cmd.Flags().BoolVar(&opts.noSecrets, "no-secrets-check", false, "Skip the preflight scan that warns when the diff appears to add secrets")
+	cmd.Flags().BoolVar(&opts.verbose, "verbose-findings", false, "Show the referenced diff lines under findings that cite <file>:<line>")
+	return cmd
+}
+
+func runAmendComment(cmd *cobra.Command, opts *amendCommentOptions) error {
+	ctx := cmd.Context()
+	mode := strings.ToLower(strings.TrimSpace(opts.mode))
+	switch mode {
+	case commentModeNew, commentModeAmend, commentModeThread:
+	default:
+		return fmt.Errorf("invalid --comment-mode %q (supported: %s, %s, %s)", opts.mode, commentModeAmend, commentModeThread, commentModeNew)
+	}
+
+	if err := git.EnsureGitRepo(ctx); err != nil {
+		return err
+	}
+
+	runtimeCtx, err := shared.BuildRuntimeContext()
+	if err != nil {
+		return err
+	}
+
+	number, err := currentPullRequestNumber(ctx)
+	if err != nil {
+		return err
+	}
+
+	input := ReviewInput{AdditionalContext: strings.TrimSpace(opts.notes)}
+	if input.RepoRoot, err = repoRootPath(ctx); err != nil {
+		return err
+	}
+	if input.Branch, err = git.CurrentBranchName(ctx); err != nil {
+		return err
+	}
+	if input.Diff, input.RemoteRef, _, err = diffAgainstBaseBranch(ctx, input.Branch, opts.target); err != nil {
+		return err
+	}
+	if input.Template, err = loadReviewTemplate(input.RepoRoot); err != nil {
+		return err
+	}
+	if input.Guidelines, err = CollectAgentGuidelines(input.RepoRoot); err != nil {
+		return err
+	}
+
+	if !opts.noSecrets {
+		proceed, err := shared.ConfirmSecretFindings(shared.ScanDiffForSecrets(input.Diff))
+		if err != nil {
+			return err
+		}
+		if !proceed {
+			return fmt.Errorf("amend aborted: the diff appears to add secrets")
+		}
+	}
+
+	artifacts, err := runReviewAgents(ctx, NewAgenticReviewer(runtimeCtx), input, "AI Analysis complete")
+	if err != nil {
+		return err
+	}
+	for _, warning := range artifacts.Warnings {
+		pterm.Warning.Println(warning)
+	}
+	if opts.verbose {
+		attachFindingExcerpts(artifacts, input.Diff)
+	}
+	logFindings(*artifacts)
+
+	findings := FormatFindingsComment(*artifacts)
+	if opts.iterationHeader || mode == commentModeThread {
+		head, err := git.RunGit(ctx, "rev-parse", "HEAD")
+		if err != nil {
+			return err
+		}
+		findings = withIterationHeader(findings, strings.TrimSpace(head), time.Now())
+	}
+
+	if mode == commentModeNew {
+		return commentOnPullRequest(ctx, findings)
+	}
+
+	spinnerComment, _ := pterm.DefaultSpinner.Start(fmt.Sprintf("Updating the findings comment on PR #%d...", number))
+	previous, found, err := findFindingsComment(ctx, number)
+	if err != nil {
+		spinnerComment.Fail(fmt.Sprintf("Failed to list PR comments: %v", err))
+		return err
+	}
+	if !found {
+		spinnerComment.Info("No previous findings comment found; posting a new one.")
+		return commentOnPullRequest(ctx, findings)
+	}
+
+	body := findings
+	if mode == commentModeThread {
+		body = appendIteration(previous.Body, findings)
+		if len(body) > maxCommentLength {
+			spinnerComment.Fail("The threaded comment would exceed GitHub's size limit")
+			return fmt.Errorf("the findings comment would grow past %d characters; use --comment-mode amend or new", maxCommentLength)
+		}
+	}
+	if err := editIssueComment(ctx, previous.ID, body); err != nil {
+		spinnerComment.Fail(fmt.Sprintf("Failed to edit comment: %v", err))
+		return err
+	}
+	spinnerComment.Success(fmt.Sprintf("Findings comment updated on PR #%d", number))
+	return nil
+}
+
+// withIterationHeader puts a header naming the reviewed commit and the review time at the
+// top of a findings comment, right after the marker.
+func withIterationHeader(comment, sha string, at time.Time) string {
+	if len(sha) > 7 {
+		sha = sha[:7]
+	}
+	header := fmt.Sprintf("## Iteration `%s` (%s)", sha, at.UTC().Format("2006-01-02 15:04 UTC"))
+	body := strings.TrimPrefix(comment, findingsCommentMarker)
+	return findingsCommentMarker + "\n" + header + "\n\n" + strings.TrimLeft(body, "\n")
+}
+
+// appendIteration adds the findings of a new iteration below the previous ones. The marker
+// stays at the top so the comment keeps being found.
+func appendIteration(previous, findings string) string {
+	section := strings.TrimLeft(strings.TrimPrefix(findings, findingsCommentMarker), "\n")
+	return strings.TrimRight(previous, "\n") + "\n\n---\n\n" + section
+}
+
+// currentPullRequestNumber returns the number of the open pull request for the current branch.
+func currentPullRequestNumber(ctx context.Context) (int, error) {
+	out, err := runGH(ctx, "pr", "view", "--json", "number")
+	if err != nil {
+		return 0, fmt.Errorf("no pull request found for the current branch: %w", err)
+	}
+	var info struct {
+		Number int `json:"number"`
+	}
+	if err := json.Unmarshal([]byte(out), &info); err != nil || info.Number == 0 {
+		return 0, fmt.Errorf("failed to parse gh pr view response")
+	}
+	return info.Number, nil
+}
+
+// findFindingsComment looks up the latest comment of the pull request that carries
+// findingsCommentMarker.
+func findFindingsComment(ctx context.Context, number int) (ghComment, bool, error) {
+	out, err := runGH(ctx, "api", "--paginate", fmt.Sprintf("repos/{owner}/{repo}/issues/%d/comments", number))
+	if err != nil {
+		return ghComment{}, false, err
+	}
+	return latestFindingsComment(out)
+}
+
+// ghComment is the subset of a GitHub issue comment used to find the findings comment.
+type ghComment struct {
+	ID   int64  `json:"id"`
+	Body string `json:"body"`
+}
+
+// latestFindingsComment parses the output of `gh api --paginate`, which prints one JSON
+// array per page, and returns the last comment containing the marker.
+func latestFindingsComment(output string) (ghComment, bool, error) {
+	var latest ghComment
+	found := false
+	decoder := json.NewDecoder(strings.NewReader(output))
+	for {
+		var page []ghComment
+		err := decoder.Decode(&page)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return ghComment{}, false, fmt.Errorf("failed to parse PR comments: %w", err)
+		}
+		for _, comment := range page {
+			if strings.Contains(comment.Body, findingsCommentMarker) {
+				latest, found = comment, true
+			}
+		}
+	}
+	return latest, found, nil
+}
+
+func editIssueComment(ctx context.Context, id int64, body string) error {
+	bodyFile, err := writeTempFile("magi-pr-comment-*.md", body)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(bodyFile)
+
+	_, err = runGH(ctx, "api", "--method", "PATCH",
+		"repos/{owner}/{repo}/issues/comments/"+strconv.FormatInt(id, 10),
+		"--field", "body=@"+bodyFile)
+	return err
+}
@@ -0,0 +1,224 @@
+package pr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/MagdielCAS/magi-cli/pkg/git"
+	"github.com/MagdielCAS/magi-cli/pkg/shared"
+)
+
+const unreleasedHeading = "## [Unreleased]"
+
+var (
+	conventionalSubjectPattern = regexp.MustCompile(`^([a-zA-Z]+)(?:\(([^)]*)\))?!?:\s*(.+)$`)
+	gitmojiShortcodePattern    = regexp.MustCompile(`^:[a-z0-9_+-]+:\s*`)
+)
+
+// changelogSectionOrder lists the Keep a Changelog sections magi writes, in order.
+var changelogSectionOrder = []string{"Added", "Changed", "Fixed"}
+
+// changelogSectionForType maps a conventional-commit type to its changelog section.
+// Types that do not affect users (chore, ci, test) return an empty string.
+func changelogSectionForType(commitType string) string {
+	switch strings.ToLower(commitType) {
+	case "feat":
+		return "Added"
+	case "fix":
+		return "Fixed"
+	case "chore", "ci", "test":
+		return ""
+	default:
+		return "Changed"
+	}
+}
+
+type changelogSection struct {
+	Title string
+	Items []string
+}
+
+// buildChangelogSections groups the branch commit subjects by conventional-commit type.
+// When no subject yields an entry, the analysis summary becomes a single Changed item so
+// the snippet is never empty.
+func buildChangelogSections(subjects []string, summary string) []changelogSection {
+	grouped := make(map[string][]string)
+	for _, subject := range subjects {
+		match := conventionalSubjectPattern.FindStringSubmatch(strings.TrimSpace(subject))
+		if match == nil {
+			continue
+		}
+		section := changelogSectionForType(match[1])
+		if section == "" {
+			continue
+		}
+		description := cleanChangelogDescription(match[3])
+		if description == "" {
+			continue
+		}
+		if scope := strings.TrimSpace(match[2]); scope != "" {
+			description = fmt.Sprintf("**%s:** %s", scope, description)
+		}
+		grouped[section] = append(grouped[section], description)
+	}
+
+	if len(grouped) == 0 {
+		if line := strings.TrimSpace(strings.SplitN(strings.TrimSpace(summary), "\n", 2)[0]); line != "" {
+			grouped["Changed"] = []string{line}
+		}
+	}
+
+	var sections []changelogSection
+	for _, title := range changelogSectionOrder {
+		if items := grouped[title]; len(items) > 0 {
+			sections = append(sections, changelogSection{Title: title, Items: items})
+		}
+	}
+	return sections
+}
+
+// cleanChangelogDescription drops leading gitmoji (shortcodes or emoji) and capitalises
+// the first letter.
+func cleanChangelogDescription(description string) string {
+	description = strings.TrimSpace(description)
+	for {
+		trimmed := gitmojiShortcodePattern.ReplaceAllString(description, "")
+		trimmed = strings.TrimLeftFunc(trimmed, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '`' && r != '"' && r != '\''
+		})
+		if trimmed == description {
+			break
+		}
+		description = trimmed
+	}
+	if description == "" {
+		return ""
+	}
+	runes := []rune(description)
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
+
+// renderChangelogSections formats sections as Keep a Changelog subsections.
+func renderChangelogSections(sections []changelogSection) string {
+	var b strings.Builder
+	for i, section := range sections {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "### %s\n", section.Title)
+		for _, item := range section.Items {
+			fmt.Fprintf(&b, "- %s\n", item)
+		}
+	}
+	return b.String()
+}
+
+// insertUnreleased merges sections into the "## [Unreleased]" section of changelog,
+// appending to existing subsections and creating the Unreleased section (before the first
+// release) when it is missing.
+func insertUnreleased(changelog string, sections []changelogSection) string {
+	if strings.TrimSpace(changelog) == "" {
+		return "# Changelog\n\n" + unreleasedHeading + "\n\n" + renderChangelogSections(sections)
+	}
+
+	lines := strings.Split(strings.TrimRight(changelog, "\n"), "\n")
+	start := -1
+	for i, line := range lines {
+		if strings.EqualFold(strings.TrimSpace(line), unreleasedHeading) {
+			start = i
+			break
+		}
+	}
+
+	if start == -1 {
+		block := strings.Split(unreleasedHeading+"\n\n"+renderChangelogSections(sections), "\n")
+		for i, line := range lines {
+			if strings.HasPrefix(line, "## ") {
+				merged := append(append(append([]string{}, lines[:i]...), block...), lines[i:]...)
+				return strings.Join(merged, "\n") + "\n"
+			}
+		}
+		return strings.Join(lines, "\n") + "\n\n" + strings.Join(block, "\n")
+	}
+
+	end := len(lines)
+	for i := start + 1; i < len(lines); i++ {
+		if strings.HasPrefix(lines[i], "## ") {
+			end = i
+			break
+		}
+	}
+
+	unreleased := append([]string{}, lines[start+1:end]...)
+	for _, section := range sections {
+		unreleased = mergeChangelogSection(unreleased, section)
+	}
+
+	merged := append(append([]string{}, lines[:start+1]...), unreleased...)
+	if end < len(lines) {
+		merged = append(merged, "")
+		merged = append(merged, lines[end:]...)
+	}
+	return strings.Join(merged, "\n") + "\n"
+}
+
+// mergeChangelogSection appends section items to the matching "### <Title>" block of
+// body, or adds the block at the end.
+func mergeChangelogSection(body []string, section changelogSection) []string {
+	items := make([]string, 0, len(section.Items))
+	for _, item := range section.Items {
+		items = append(items, "- "+item)
+	}
+
+	for i, line := range body {
+		if !strings.EqualFold(strings.TrimSpace(line), "### "+section.Title) {
+			continue
+		}
+		insertAt := i + 1
+		for insertAt < len(body) && !strings.HasPrefix(body[insertAt], "### ") {
+			insertAt++
+		}
+		for insertAt > i+1 && strings.TrimSpace(body[insertAt-1]) == "" {
+			insertAt--
+		}
+		return append(append(append([]string{}, body[:insertAt]...), items...), body[insertAt:]...)
+	}
+
+	for len(body) > 0 && strings.TrimSpace(body[len(body)-1]) == "" {
+		body = body[:len(body)-1]
+	}
+	body = append(body, "", "### "+section.Title)
+	return append(body, items...)
+}
+
+// branchCommitSubjects returns the subjects of the non-merge commits in baseRef..HEAD.
+func branchCommitSubjects(ctx context.Context, baseRef string) ([]string, error) {
+	output, err := git.RunGit(ctx, "log", "--no-merges", "--format=%s", fmt.Sprintf("%s..HEAD", baseRef))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list branch commits: %w", err)
+	}
+	var subjects []string
+	for _, line := range strings.Split(output, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			subjects = append(subjects, line)
+		}
+	}
+	return subjects, nil
+}
+
+// appendChangelogEntry merges sections into the Unreleased section of path, creating the
+// file when it does not exist.
+func appendChangelogEntry(path string, sections []changelogSection) error {
+	existing, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	updated := insertUnreleased(string(existing), sections)
+	return shared.WriteFileAtomic(path, []byte(updated), 0644)
+}
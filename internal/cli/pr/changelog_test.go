@@ -0,0 +1,87 @@
+package pr
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestBuildChangelogSections(t *testing.T) {
+	subjects := []string{
+		"feat(pr): :sparkles: add auto labels",
+		"fix: ✅ handle empty diff",
+		"refactor(agent): simplify pool",
+		"chore: bump deps",
+		"Merge branch 'main'",
+	}
+
+	got := buildChangelogSections(subjects, "Summary is ignored")
+	want := []changelogSection{
+		{Title: "Added", Items: []string{"**pr:** Add auto labels"}},
+		{Title: "Changed", Items: []string{"**agent:** Simplify pool"}},
+		{Title: "Fixed", Items: []string{"Handle empty diff"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("buildChangelogSections() = %+v, want %+v", got, want)
+	}
+}
+
+func TestBuildChangelogSectionsFallsBackToSummary(t *testing.T) {
+	got := buildChangelogSections([]string{"wip"}, "Speeds up the review.\nMore detail.")
+	want := []changelogSection{{Title: "Changed", Items: []string{"Speeds up the review."}}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("buildChangelogSections() = %+v, want %+v", got, want)
+	}
+}
+
+func TestInsertUnreleased(t *testing.T) {
+	sections := []changelogSection{
+		{Title: "Added", Items: []string{"New flag"}},
+		{Title: "Fixed", Items: []string{"Crash on empty diff"}},
+	}
+
+	tests := []struct {
+		name      string
+		changelog string
+		want      string
+	}{
+		{
+			name:      "new file",
+			changelog: "",
+			want:      "# Changelog\n\n## [Unreleased]\n\n### Added\n- New flag\n\n### Fixed\n- Crash on empty diff\n",
+		},
+		{
+			name:      "merges into existing unreleased",
+			changelog: "# Changelog\n\n## [Unreleased]\n\n### Added\n- Old feature\n\n## [1.0.0] - 2025-01-01\n\n### Added\n- First release\n",
+			want:      "# Changelog\n\n## [Unreleased]\n\n### Added\n- Old feature\n- New flag\n\n### Fixed\n- Crash on empty diff\n\n## [1.0.0] - 2025-01-01\n\n### Added\n- First release\n",
+		},
+		{
+			name:      "adds unreleased before first release",
+			changelog: "# Changelog\n\n## [1.0.0] - 2025-01-01\n\n### Added\n- First release\n",
+			want:      "# Changelog\n\n## [Unreleased]\n\n### Added\n- New flag\n\n### Fixed\n- Crash on empty diff\n\n## [1.0.0] - 2025-01-01\n\n### Added\n- First release\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := insertUnreleased(tt.changelog, sections); got != tt.want {
+				t.Fatalf("insertUnreleased() =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAppendChangelogEntryCreatesFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "CHANGELOG.md")
+	if err := appendChangelogEntry(path, []changelogSection{{Title: "Changed", Items: []string{"Tweak"}}}); err != nil {
+		t.Fatalf("appendChangelogEntry() error = %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read changelog: %v", err)
+	}
+	if want := "# Changelog\n\n## [Unreleased]\n\n### Changed\n- Tweak\n"; string(data) != want {
+		t.Fatalf("changelog = %q, want %q", data, want)
+	}
+}
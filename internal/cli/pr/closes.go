@@ -0,0 +1,139 @@
+package pr
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	// closeKeywordKey picks the GitHub closing keyword written into the PR body.
+	closeKeywordKey = "pr.close_keyword"
+	// issuePatternKey overrides the regular expression that finds the issue number in the
+	// branch name. An empty value disables the detection.
+	issuePatternKey = "pr.issue_pattern"
+
+	defaultCloseKeyword = "Closes"
+	// defaultIssuePattern matches a number right after an issue-style prefix, such as
+	// fix/123-crash, bugfix/45 or feature/7_login. Other numeric segments (release/2024,
+	// hotfix/1-2, v2/3_x) are versions or dates, and closing them would close unrelated issues.
+	defaultIssuePattern = `^(?:fix|bug|issue|feat)[^/]*/([0-9]+)(?:[-_]|$)`
+)
+
+// closeKeywords are the supported values of pr.close_keyword.
+var closeKeywords = []string{"Closes", "Fixes", "Resolves"}
+
+// closingReferencePattern matches any GitHub closing keyword followed by an issue number,
+// in the notes given to the agents or in a body that already closes an issue.
+var closingReferencePattern = regexp.MustCompile(`(?i)\b(?:close[sd]?|fix(?:e[sd])?|resolve[sd]?)\s+#([0-9]+)\b`)
+
+// closingReferences holds the issues the pull request closes and the keyword used for them.
+type closingReferences struct {
+	Keyword string
+	Issues  []int
+}
+
+// closeKeyword returns pr.close_keyword in its canonical spelling.
+func closeKeyword() (string, error) {
+	configured := strings.TrimSpace(viper.GetString(closeKeywordKey))
+	if configured == "" {
+		return defaultCloseKeyword, nil
+	}
+	for _, keyword := range closeKeywords {
+		if strings.EqualFold(configured, keyword) {
+			return keyword, nil
+		}
+	}
+	return "", fmt.Errorf("invalid %s %q (supported: %s)", closeKeywordKey, configured, strings.Join(closeKeywords, ", "))
+}
+
+// resolveClosingReferences collects the issues given with --closes, the one named by the
+// branch, and those the notes close with a closing keyword ("fixes #12").
+func resolveClosingReferences(branch, notes string, explicit []int) (closingReferences, error) {
+	keyword, err := closeKeyword()
+	if err != nil {
+		return closingReferences{}, err
+	}
+
+	issues := make(map[int]bool)
+	for _, issue := range explicit {
+		if issue <= 0 {
+			return closingReferences{}, fmt.Errorf("invalid --closes %d: expected an issue number", issue)
+		}
+		issues[issue] = true
+	}
+
+	if issue, err := issueFromBranch(branch); err != nil {
+		return closingReferences{}, err
+	} else if issue > 0 {
+		issues[issue] = true
+	}
+
+	for _, match := range closingReferencePattern.FindAllStringSubmatch(notes, -1) {
+		if issue, err := strconv.Atoi(match[1]); err == nil && issue > 0 {
+			issues[issue] = true
+		}
+	}
+
+	refs := closingReferences{Keyword: keyword}
+	for issue := range issues {
+		refs.Issues = append(refs.Issues, issue)
+	}
+	sort.Ints(refs.Issues)
+	return refs, nil
+}
+
+// issueFromBranch returns the issue number in the branch name, or 0 when there is none.
+func issueFromBranch(branch string) (int, error) {
+	pattern := defaultIssuePattern
+	if viper.IsSet(issuePatternKey) {
+		pattern = strings.TrimSpace(viper.GetString(issuePatternKey))
+		if pattern == "" {
+			return 0, nil
+		}
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", issuePatternKey, err)
+	}
+
+	match := re.FindStringSubmatch(branch)
+	if match == nil {
+		return 0, nil
+	}
+	value := match[0]
+	if len(match) > 1 {
+		value = match[1]
+	}
+	issue, err := strconv.Atoi(strings.Trim(value, "#/-_"))
+	if err != nil {
+		return 0, nil
+	}
+	return issue, nil
+}
+
+// withClosingReferences appends one closing line per issue at the end of body, after the
+// template sections. Issues the body already closes are not repeated.
+func withClosingReferences(body string, refs closingReferences) string {
+	closed := make(map[string]bool)
+	for _, match := range closingReferencePattern.FindAllStringSubmatch(body, -1) {
+		closed[match[1]] = true
+	}
+
+	var lines []string
+	for _, issue := range refs.Issues {
+		number := strconv.Itoa(issue)
+		if closed[number] {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("%s #%s", refs.Keyword, number))
+	}
+	if len(lines) == 0 {
+		return body
+	}
+	return strings.TrimRight(body, "\n") + "\n\n" + strings.Join(lines, "\n") + "\n"
+}
@@ -0,0 +1,82 @@
+package pr
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestIssueFromBranch(t *testing.T) {
+	t.Cleanup(viper.Reset)
+
+	cases := map[string]int{
+		"fix/123-crash":          123,
+		"bugfix/45":              45,
+		"feature/7_login":        7,
+		"issue/8":                8,
+		"45_docs":                0,
+		"feature/proj-123-login": 0,
+		"release/v1.2":           0,
+		"release/2024":           0,
+		"hotfix/1-2":             0,
+		"v2/3_x":                 0,
+		"docs/2025-notes":        0,
+	}
+	for branch, want := range cases {
+		got, err := issueFromBranch(branch)
+		if err != nil || got != want {
+			t.Fatalf("issueFromBranch(%q) = %d, %v; want %d", branch, got, err, want)
+		}
+	}
+
+	viper.Set(issuePatternKey, `gh-([0-9]+)`)
+	if got, _ := issueFromBranch("feature/gh-77"); got != 77 {
+		t.Fatalf("expected the configured pattern to find 77, got %d", got)
+	}
+	viper.Set(issuePatternKey, "")
+	if got, _ := issueFromBranch("fix/123-crash"); got != 0 {
+		t.Fatalf("expected an empty pattern to disable detection, got %d", got)
+	}
+}
+
+func TestResolveClosingReferences(t *testing.T) {
+	t.Cleanup(viper.Reset)
+
+	refs, err := resolveClosingReferences("fix/12-crash", "This also fixes #30, see #31", []int{40, 12})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := (closingReferences{Keyword: "Closes", Issues: []int{12, 30, 40}}); !reflect.DeepEqual(refs, want) {
+		t.Fatalf("resolveClosingReferences() = %+v, want %+v", refs, want)
+	}
+
+	viper.Set(closeKeywordKey, "fixes")
+	if refs, _ := resolveClosingReferences("main", "", nil); refs.Keyword != "Fixes" {
+		t.Fatalf("expected the canonical keyword, got %q", refs.Keyword)
+	}
+
+	viper.Set(closeKeywordKey, "Ends")
+	if _, err := resolveClosingReferences("main", "", nil); err == nil {
+		t.Fatal("expected an unsupported keyword to fail")
+	}
+
+	viper.Reset()
+	if _, err := resolveClosingReferences("main", "", []int{0}); err == nil {
+		t.Fatal("expected a non-positive issue number to fail")
+	}
+}
+
+func TestWithClosingReferences(t *testing.T) {
+	refs := closingReferences{Keyword: "Closes", Issues: []int{7, 9}}
+
+	got := withClosingReferences("## Summary\nFixed the crash, resolves #9\n", refs)
+	want := "## Summary\nFixed the crash, resolves #9\n\nCloses #7\n"
+	if got != want {
+		t.Fatalf("withClosingReferences() = %q, want %q", got, want)
+	}
+
+	if got := withClosingReferences(want, refs); got != want {
+		t.Fatalf("expected the body to be left alone when every issue is closed, got %q", got)
+	}
+}
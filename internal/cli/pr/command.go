@@ -3,6 +3,7 @@ package pr
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -11,8 +12,10 @@ import (
 
 	"github.com/pterm/pterm"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 
 	"github.com/MagdielCAS/magi-cli/internal/cli/push"
+	"github.com/MagdielCAS/magi-cli/pkg/agent"
 	"github.com/MagdielCAS/magi-cli/pkg/git"
 	"github.com/MagdielCAS/magi-cli/pkg/shared"
 )
@@ -22,9 +25,32 @@ var (
 	prOutputFile   string
 	prNoComment    bool
 	prOnlyCreate   bool
+	prNoPush       bool
 	prTargetBranch string
+	prNoSecrets    bool
+	prAutoLabel    bool
+	prChangelog    bool
+	prChangelogOut string
+	prVerbose      bool
+	prCloses       []int
+	prFiles        []string
 )
 
+const (
+	assignMeKey = "pr.assign_me"
+	// ignoreWhitespaceKey drops whitespace-only changes from the reviewed diff.
+	ignoreWhitespaceKey = "pr.ignore_whitespace"
+)
+
+// Message keys of the options offered before the pull request is created.
+const (
+	prActionSubmit = "pr.action.submit"
+	prActionEdit   = "pr.action.edit"
+	prActionCancel = "pr.action.cancel"
+)
+
+var prActions = []string{prActionSubmit, prActionEdit, prActionCancel}
+
 var prCmd = &cobra.Command{
 	Use:   "pr",
 	Short: "Review local commits with AI agents and open a GitHub pull request",
@@ -37,13 +63,16 @@ and creates the PR using the GitHub CLI ('gh').
 Data handling:
   • Sends the git diff between HEAD and origin/<branch>, AGENTS.md contents, and optional user context
     to your configured AI provider.
+  • When pr.linters is configured, the output of those local linters on the changed files is sent too.
   • No other files are uploaded.
 
 Security note:
   • The review agents run with a hardened HTTP client.
   • API keys are redacted.
   • Model responses are not persisted unless --output-file is used.
-  • Shells out to 'git' and 'gh' with explicit arguments.`,
+  • Shells out to 'git' and 'gh' with explicit arguments.
+  • The diff is scanned for likely secrets before any AI call; you must confirm to continue
+    when something is found (skip with --no-secrets-check).`,
 	Example: `  # Interactive mode (default)
   magi pr
 
@@ -54,7 +83,34 @@ Security note:
   magi pr --target-branch develop
 
   # Create PR without commenting findings
-  magi pr --no-comment`,
+  magi pr --no-comment
+
+  # Skip the push when the branch is already on the remote (e.g. pushed by CI)
+  magi pr --no-push
+
+  # Close issue 123 when the PR is merged (fix/123-... branches are detected)
+  magi pr --closes 123
+
+  # Assign the PR to yourself (config: pr.assign_me)
+  magi pr --assign-me
+
+  # Show the referenced diff lines under each finding
+  magi pr --verbose-findings
+
+  # Label the PR from the review findings (security, needs-tests, docs)
+  magi pr --auto-label
+
+  # Append a Keep a Changelog entry for the branch under ## [Unreleased]
+  magi pr --changelog-file CHANGELOG.md
+
+  # Review a reformatting branch without the whitespace noise
+  magi pr --ignore-whitespace
+
+  # Focus the review on the risky parts of the branch (the PR still covers every file)
+  magi pr --files 'internal/auth/**' --files '*.sql'
+
+  # Allow longer analysis responses on large PRs
+  magi pr --analysis-max-tokens 8192`,
 	RunE: runPR,
 }
 
@@ -63,7 +119,28 @@ func PRCmd() *cobra.Command {
 	prCmd.Flags().StringVar(&prOutputFile, "output-file", "", "Write the agent results to a markdown file")
 	prCmd.Flags().BoolVar(&prNoComment, "no-comment", false, "Do not add the agent findings as a comment to the PR")
 	prCmd.Flags().BoolVar(&prOnlyCreate, "only-create", false, "Create the PR but do not add any comments")
+	prCmd.Flags().BoolVar(&prNoPush, "no-push", false, "Do not push the branch before creating the PR")
 	prCmd.Flags().StringVar(&prTargetBranch, "target-branch", "", "Specify the target branch for the Pull Request")
+	prCmd.Flags().BoolVar(&prNoSecrets, "no-secrets-check", false, "Skip the preflight scan that warns when the diff appears to add secrets")
+	prCmd.Flags().BoolVar(&prAutoLabel, "auto-label", false, "Apply labels mapped from the review findings to the PR (config: pr.labels)")
+	prCmd.Flags().BoolVar(&prAutoLabel, "labels-from-findings", false, "Alias for --auto-label")
+	prCmd.Flags().MarkHidden("labels-from-findings")
+	prCmd.Flags().BoolVar(&prChangelog, "changelog", false, "Print a changelog entry built from the branch commits and the analysis summary")
+	prCmd.Flags().StringVar(&prChangelogOut, "changelog-file", "", "Append the changelog entry under ## [Unreleased] in this file (implies --changelog)")
+	prCmd.Flags().BoolVar(&prVerbose, "verbose-findings", false, "Show the referenced diff lines under findings that cite <file>:<line>")
+	prCmd.Flags().IntSliceVar(&prCloses, "closes", nil, "Add a closing reference for this issue number to the PR body (repeatable; keyword from pr.close_keyword)")
+	prCmd.Flags().StringArrayVar(&prFiles, "files", nil, "Only review changed files matching this glob; the PR still covers the whole branch (repeatable)")
+	prCmd.Flags().Bool("assign-me", false, "Assign the pull request to yourself (config: pr.assign_me)")
+	prCmd.Flags().Int("analysis-max-tokens", defaultAnalysisMaxTokens, "Max tokens for the analysis agent response (config: pr.analysis_max_tokens)")
+	prCmd.Flags().Int("writer-max-tokens", defaultWriterMaxTokens, "Max tokens for the PR writer agent response (config: pr.writer_max_tokens)")
+	prCmd.PersistentFlags().Bool("ignore-whitespace", false, "Ignore whitespace-only changes in the reviewed diff (config: pr.ignore_whitespace)")
+	viper.BindPFlag(assignMeKey, prCmd.Flags().Lookup("assign-me"))
+	viper.BindPFlag(ignoreWhitespaceKey, prCmd.PersistentFlags().Lookup("ignore-whitespace"))
+	viper.BindPFlag(analysisMaxTokensKey, prCmd.Flags().Lookup("analysis-max-tokens"))
+	viper.BindPFlag(writerMaxTokensKey, prCmd.Flags().Lookup("writer-max-tokens"))
+	prCmd.AddCommand(newReviewCmd())
+	prCmd.AddCommand(newExplainCmd())
+	prCmd.AddCommand(newAmendCommentCmd())
 
 	return prCmd
 }
@@ -74,6 +151,11 @@ func runPR(cmd *cobra.Command, _ []string) error {
 		return err
 	}
 
+	// Fail before the review runs when the PR could not be confirmed anyway.
+	if !prDryRun && !shared.IsInteractive() && !shared.ConfirmDefault(false) {
+		return shared.NonInteractiveError("--assume-yes (or --dry-run)")
+	}
+
 	runtimeCtx, err := shared.BuildRuntimeContext()
 	if err != nil {
 		return err
@@ -81,6 +163,7 @@ func runPR(cmd *cobra.Command, _ []string) error {
 
 	pterm.Info.Printf("Using models - Analysis: %s | Writer: %s\n", runtimeCtx.HeavyModel, runtimeCtx.LightModel)
 
+	shared.SetStage("Gathering repository context and diff")
 	spinnerContext, _ := pterm.DefaultSpinner.Start("Gathering repository context and diff...")
 
 	branch, err := git.CurrentBranchName(ctx)
@@ -96,11 +179,27 @@ func runPR(cmd *cobra.Command, _ []string) error {
 	}
 
 	diff, baseRef, baseBranch, err := diffAgainstBaseBranch(ctx, branch, prTargetBranch)
+	if errors.Is(err, git.ErrWhitespaceOnly) {
+		spinnerContext.Warning(fmt.Sprintf("%v (--ignore-whitespace)", err))
+		return nil
+	}
 	if err != nil {
 		spinnerContext.Fail(fmt.Sprintf("Failed to get diff: %v", err))
 		return err
 	}
 
+	fullDiff := diff
+	diff, err = scopeDiff(diff, prFiles)
+	if err != nil {
+		spinnerContext.Fail(err.Error())
+		return err
+	}
+	// An empty scope skips the analysis; the PR is still created for the whole branch.
+	skipAnalysis := strings.TrimSpace(diff) == ""
+	if skipAnalysis {
+		spinnerContext.Warning(fmt.Sprintf("No changed files match --files %s; skipping the analysis.", strings.Join(prFiles, ", ")))
+	}
+
 	templatePath := filepath.Join(repoRoot, ".github", "pull_request_template.md")
 	templateBody, err := LoadPullRequestTemplate(templatePath)
 	if err != nil {
@@ -114,31 +213,68 @@ func runPR(cmd *cobra.Command, _ []string) error {
 		return err
 	}
 	spinnerContext.Success("Repository context gathered")
+	warnAboutPRHead(ctx, branch, baseBranch, baseRef)
+
+	if !prNoSecrets {
+		proceed, err := shared.ConfirmSecretFindings(shared.ScanDiffForSecrets(diff))
+		if err != nil {
+			return err
+		}
+		if !proceed {
+			return fmt.Errorf("pull request aborted: the diff appears to add secrets")
+		}
+	}
 
 	additionalContext, err := promptAdditionalContext()
 	if err != nil {
 		return err
 	}
 
-	// Start spinner for AI Analysis
-	spinnerReview, _ := pterm.DefaultSpinner.Start("Running AI Agents to analyze changes...")
-	reviewer := NewAgenticReviewer(runtimeCtx)
-	artifacts, err := reviewer.Review(ctx, ReviewInput{
-		Diff:              diff,
-		Branch:            branch,
-		RemoteRef:         baseRef,
-		Guidelines:        guidelines,
-		AdditionalContext: additionalContext,
-		Template:          templateBody,
-	})
+	closing, err := resolveClosingReferences(branch, additionalContext, prCloses)
 	if err != nil {
-		spinnerReview.Fail(fmt.Sprintf("AI Review failed: %v", err))
 		return err
 	}
-	spinnerReview.Success("AI Analysis and PR drafting complete")
 
+	var artifacts *ReviewArtifacts
+	if skipAnalysis {
+		artifacts, err = unscopedArtifacts(fullDiff, branch, prFiles)
+	} else {
+		shared.SetStage("Reviewing the diff and drafting the pull request")
+		artifacts, err = runReviewAgents(ctx, NewAgenticReviewer(runtimeCtx), ReviewInput{
+			Diff:              diff,
+			Branch:            branch,
+			RemoteRef:         baseRef,
+			Guidelines:        guidelines,
+			AdditionalContext: additionalContext,
+			Template:          templateBody,
+			RepoRoot:          repoRoot,
+		}, "AI Analysis and PR drafting complete")
+	}
+	if err != nil {
+		return err
+	}
+	if artifacts.LightAnalysis {
+		pterm.Info.Printf("Small diff: analyzed with the light model (%s: %d changed lines).\n", lightThresholdKey, lightThreshold())
+	}
+	if artifacts.Redactions > 0 {
+		pterm.Info.Printf("Redacted %d secret(s) from the diff and notes before sending them to the AI provider.\n", artifacts.Redactions)
+	}
+	for _, warning := range artifacts.Warnings {
+		pterm.Warning.Println(warning)
+	}
+	artifacts.Plan.Body = withClosingReferences(artifacts.Plan.Body, closing)
+
+	if prVerbose {
+		attachFindingExcerpts(artifacts, diff)
+	}
 	logFindings(*artifacts)
 
+	if prChangelog || prChangelogOut != "" {
+		if err := generateChangelog(ctx, baseRef, artifacts); err != nil {
+			return err
+		}
+	}
+
 	if prDryRun || prOutputFile != "" {
 		report := generateMarkdownReport(*artifacts)
 		if prOutputFile != "" {
@@ -149,25 +285,26 @@ func runPR(cmd *cobra.Command, _ []string) error {
 		}
 
 		if prDryRun {
+			if shared.IsJSONOutput() {
+				return shared.PrintJSON(artifacts)
+			}
 			fmt.Println(report)
 			return nil
 		}
 	}
 
 	for {
-		action, err := pterm.DefaultInteractiveSelect.
-			WithOptions([]string{"Submit PR", "Edit Title/Body", "Cancel"}).
-			Show("What would you like to do?")
+		action, err := choosePRAction()
 		if err != nil {
-			return fmt.Errorf("interactive select failed: %w", err)
+			return err
 		}
 
-		if action == "Cancel" {
-			pterm.Warning.Println("Pull request creation cancelled by user.")
+		if action == prActionCancel {
+			pterm.Warning.Println(shared.T("pr.cancelled"))
 			return nil
 		}
 
-		if action == "Edit Title/Body" {
+		if action == prActionEdit {
 			// Combine title and body for editing
 			fullContent := fmt.Sprintf("Title: %s\n\n%s", artifacts.Plan.Title, artifacts.Plan.Body)
 			edited, err := shared.OpenEditor(fullContent, ".md")
@@ -192,7 +329,7 @@ func runPR(cmd *cobra.Command, _ []string) error {
 
 			pterm.Info.Println("Content updated. Current state:")
 			pterm.DefaultSection.Println("Updated Pull Request Template")
-			fmt.Printf("Title: %s\n\n%s\n", artifacts.Plan.Title, artifacts.Plan.Body)
+			pterm.Printf("Title: %s\n\n%s\n", artifacts.Plan.Title, artifacts.Plan.Body)
 			continue
 		}
 
@@ -200,13 +337,24 @@ func runPR(cmd *cobra.Command, _ []string) error {
 		break
 	}
 
-	pterm.Info.Println("Ensuring the branch is pushed before creating the pull request...")
-	if err := push.RunPush(cmd, nil); err != nil {
-		return fmt.Errorf("failed to push branch prior to PR creation: %w", err)
+	if prNoPush {
+		pterm.Info.Println("Skipping push (--no-push); the branch must already exist on the remote.")
+	} else {
+		pterm.Info.Println("Ensuring the branch is pushed before creating the pull request...")
+		shared.SetStage("Pushing the branch")
+		if err := push.RunPush(cmd, nil); err != nil {
+			return fmt.Errorf("failed to push branch prior to PR creation: %w", err)
+		}
+	}
+
+	var labels []string
+	if prAutoLabel {
+		labels = resolvePRLabels(ctx, artifacts.Analysis)
 	}
 
+	shared.SetStage("Creating the pull request")
 	spinnerPR, _ := pterm.DefaultSpinner.Start("Creating Pull Request on GitHub...")
-	prURL, err := createPullRequest(ctx, branch, baseBranch, artifacts.Plan)
+	prURL, err := createPullRequest(ctx, branch, baseBranch, artifacts.Plan, labels, viper.GetBool(assignMeKey))
 	if err != nil {
 		spinnerPR.Fail(fmt.Sprintf("Failed to create PR: %v", err))
 		return err
@@ -214,7 +362,8 @@ func runPR(cmd *cobra.Command, _ []string) error {
 	spinnerPR.Success("Pull request created successfully")
 
 	comment := FormatFindingsComment(*artifacts)
-	if !prNoComment && !prOnlyCreate {
+	if !prNoComment && !prOnlyCreate && !skipAnalysis {
+		shared.SetStage("Posting the review comment")
 		spinnerComment, _ := pterm.DefaultSpinner.Start("Posting analysis findings as a comment...")
 		if err := commentOnPullRequest(ctx, comment); err != nil {
 			spinnerComment.Fail(fmt.Sprintf("Failed to post comment: %v", err))
@@ -224,13 +373,102 @@ func runPR(cmd *cobra.Command, _ []string) error {
 	}
 
 	pterm.Success.Printf("PR URL: %s\n", prURL)
+	if shared.IsJSONOutput() {
+		return shared.PrintJSON(prResult{URL: prURL, ReviewArtifacts: *artifacts})
+	}
+	return nil
+}
+
+// prResult is the structured output printed in JSON mode once the PR exists.
+type prResult struct {
+	URL string `json:"url"`
+	ReviewArtifacts
+}
+
+// generateChangelog turns the branch commit types and the analysis summary into a
+// Keep a Changelog entry without another LLM call, prints it, and appends it to
+// --changelog-file when set.
+func generateChangelog(ctx context.Context, baseRef string, artifacts *ReviewArtifacts) error {
+	subjects, err := branchCommitSubjects(ctx, baseRef)
+	if err != nil {
+		return err
+	}
+
+	sections := buildChangelogSections(subjects, artifacts.Analysis.Summary)
+	if len(sections) == 0 {
+		pterm.Warning.Println("No changelog entry generated: the branch has no user-facing commits and no summary.")
+		return nil
+	}
+	artifacts.Changelog = renderChangelogSections(sections)
+
+	pterm.DefaultSection.Println("Changelog Entry")
+	pterm.Println(strings.TrimSpace(artifacts.Changelog))
+
+	if prChangelogOut != "" {
+		if err := appendChangelogEntry(prChangelogOut, sections); err != nil {
+			return err
+		}
+		pterm.Success.Printf("Changelog entry added under %s in %s\n", unreleasedHeading, prChangelogOut)
+	}
 	return nil
 }
 
+// runReviewAgents runs the review behind the live per-agent progress list, or behind a
+// single spinner when progress is disabled (--no-progress, ui.progress, --raw).
+func runReviewAgents(ctx context.Context, reviewer *AgenticReviewer, input ReviewInput, done string) (*ReviewArtifacts, error) {
+	const title = "Running AI Agents to analyze changes..."
+	if !shared.ProgressEnabled() {
+		spinner, _ := pterm.DefaultSpinner.Start(title)
+		artifacts, err := reviewer.Review(ctx, input)
+		if err != nil {
+			spinner.Fail(fmt.Sprintf("AI Review failed: %v", err))
+			return nil, err
+		}
+		spinner.Success(done)
+		return artifacts, nil
+	}
+
+	progress := shared.StartAgentProgress(title)
+	reviewer.WithProgress(func(event agent.Event) {
+		progress.Update(event.Agent, string(event.Kind), event.Elapsed, event.Err)
+	})
+	artifacts, err := reviewer.Review(ctx, input)
+	progress.Stop()
+	if err != nil {
+		pterm.Error.Printf("AI Review failed: %v\n", err)
+		return nil, err
+	}
+	pterm.Success.Println(done)
+	return artifacts, nil
+}
+
+// choosePRAction asks whether to submit, edit or cancel the pull request. Non-interactive
+// sessions submit only when --assume-yes (or ui.confirm_default: yes) is set.
+func choosePRAction() (string, error) {
+	if !shared.IsInteractive() {
+		if !shared.ConfirmDefault(false) {
+			return "", shared.NonInteractiveError("--assume-yes (or --dry-run)")
+		}
+		return prActionSubmit, nil
+	}
+
+	defaultAction := prActionSubmit
+	if !shared.ConfirmDefault(true) {
+		defaultAction = prActionCancel
+	}
+
+	choice, err := pterm.DefaultInteractiveSelect.
+		WithOptions(shared.TOptions(prActions)).
+		WithDefaultOption(shared.T(defaultAction)).
+		Show(shared.T("pr.confirm"))
+	if err != nil {
+		return "", fmt.Errorf("interactive select failed: %w", err)
+	}
+	return shared.OptionKey(prActions, choice), nil
+}
+
 func promptAdditionalContext() (string, error) {
-	wantContext, err := pterm.DefaultInteractiveConfirm.
-		WithDefaultValue(false).
-		Show("Add optional context for the AI reviewers?")
+	wantContext, err := shared.Confirm(shared.T("pr.add_context"), false)
 	if err != nil {
 		return "", fmt.Errorf("context confirmation failed: %w", err)
 	}
@@ -240,7 +478,7 @@ func promptAdditionalContext() (string, error) {
 
 	content, err := pterm.DefaultInteractiveTextInput.
 		WithMultiLine().
-		WithDefaultText("Enter any risk, testing, or deployment notes").
+		WithDefaultText(shared.T("pr.context_placeholder")).
 		Show()
 	if err != nil {
 		return "", fmt.Errorf("context input failed: %w", err)
@@ -251,12 +489,15 @@ func promptAdditionalContext() (string, error) {
 func logFindings(artifacts ReviewArtifacts) {
 	pterm.DefaultSection.Println("Agent Findings")
 	printList("Summary", []string{artifacts.Analysis.Summary})
-	printList("Code Smells", artifacts.Analysis.CodeSmells)
-	printList("Security Concerns", artifacts.Analysis.SecurityConcerns)
-	printList("AGENTS Alerts", artifacts.Analysis.AgentsGuidelineAlerts)
-	printList("Test Recommendations", artifacts.Analysis.TestRecommendations)
-	printList("Documentation Updates", artifacts.Analysis.DocumentationUpdates)
-	printList("Risk Callouts", artifacts.Analysis.RiskCallouts)
+	printFindingList("Code Smells", artifacts.Analysis.CodeSmells, artifacts.Excerpts)
+	printFindingList("Security Concerns", artifacts.Analysis.SecurityConcerns, artifacts.Excerpts)
+	printFindingList("AGENTS Alerts", artifacts.Analysis.AgentsGuidelineAlerts, artifacts.Excerpts)
+	printFindingList("Test Recommendations", artifacts.Analysis.TestRecommendations, artifacts.Excerpts)
+	printFindingList("Documentation Updates", artifacts.Analysis.DocumentationUpdates, artifacts.Excerpts)
+	printFindingList("Risk Callouts", artifacts.Analysis.RiskCallouts, artifacts.Excerpts)
+	if len(artifacts.CustomFindings) > 0 {
+		printFindingList("Custom Findings", artifacts.CustomFindings, artifacts.Excerpts)
+	}
 
 	if artifacts.I18nFindings != nil && len(artifacts.I18nFindings.Translations) > 0 {
 		pterm.DefaultSection.Println("I18n Recommendations")
@@ -266,10 +507,16 @@ func logFindings(artifacts ReviewArtifacts) {
 	}
 
 	pterm.DefaultSection.Println("Filled Pull Request Template")
-	fmt.Println(strings.TrimSpace(artifacts.Plan.Body))
+	pterm.Println(strings.TrimSpace(artifacts.Plan.Body))
 }
 
 func printList(title string, entries []string) {
+	printFindingList(title, entries, nil)
+}
+
+// printFindingList prints entries as a bullet list, with the diff excerpt of an entry
+// indented under it when --verbose-findings found one.
+func printFindingList(title string, entries []string, excerpts map[string]string) {
 	pterm.Println(pterm.ThemeDefault.SectionStyle.Sprint(title))
 	if len(entries) == 0 {
 		pterm.Println("  • None")
@@ -281,6 +528,11 @@ func printList(title string, entries []string) {
 			continue
 		}
 		pterm.Println("  • " + entry)
+		if excerpt, ok := excerpts[entry]; ok {
+			for _, line := range strings.Split(excerpt, "\n") {
+				pterm.Println(pterm.Gray("      " + line))
+			}
+		}
 	}
 }
 
@@ -308,7 +560,7 @@ func diffAgainstBaseBranch(ctx context.Context, branch, targetBranch string) (st
 		}
 	}
 
-	diff, err := git.RunGit(ctx, "diff", fmt.Sprintf("%s..HEAD", baseRef))
+	diff, err := git.Diff(ctx, viper.GetBool(ignoreWhitespaceKey), fmt.Sprintf("%s..HEAD", baseRef))
 	if err != nil {
 		return "", "", "", err
 	}
@@ -320,6 +572,51 @@ func diffAgainstBaseBranch(ctx context.Context, branch, targetBranch string) (st
 	return diff, baseRef, baseBranch, nil
 }
 
+// prCreateArgs builds the gh pr create arguments.
+func prCreateArgs(branch, base, title, bodyFile string, labels []string, assignMe bool) []string {
+	args := []string{
+		"pr", "create",
+		"--title", title,
+		"--body-file", bodyFile,
+		"--head", branch,
+	}
+	if base != "" {
+		args = append(args, "--base", base)
+	}
+	for _, label := range labels {
+		args = append(args, "--label", label)
+	}
+	if assignMe {
+		args = append(args, "--assignee", "@me")
+	}
+	return args
+}
+
+// warnAboutPRHead warns when the pull request would point at itself or has nothing to
+// review: the base is the current branch, or HEAD has no commits ahead of the base.
+func warnAboutPRHead(ctx context.Context, branch, baseBranch, baseRef string) {
+	ahead := -1
+	if out, err := git.RunGit(ctx, "rev-list", "--count", fmt.Sprintf("%s..HEAD", baseRef)); err == nil {
+		fmt.Sscanf(strings.TrimSpace(out), "%d", &ahead)
+	}
+	for _, warning := range prHeadWarnings(branch, baseBranch, ahead) {
+		pterm.Warning.Println(warning)
+	}
+}
+
+// prHeadWarnings returns the guard warnings for a PR from branch into baseBranch whose head
+// is ahead commits in front of the base. A negative ahead means the count is unknown.
+func prHeadWarnings(branch, baseBranch string, ahead int) []string {
+	var warnings []string
+	if baseBranch != "" && baseBranch == branch {
+		warnings = append(warnings, fmt.Sprintf("The base branch is the current branch (%s); the pull request would merge the branch into itself. Use --target-branch to pick another base.", branch))
+	}
+	if ahead == 0 {
+		warnings = append(warnings, fmt.Sprintf("%s has no commits ahead of %s; the diff only reflects commits missing from your branch. Rebase or merge the base before opening the PR.", branch, baseBranch))
+	}
+	return warnings
+}
+
 func resolveBaseBranch(ctx context.Context, branch string) (string, string, error) {
 	remote, err := git.BranchRemote(ctx, branch)
 	if err != nil {
@@ -348,22 +645,21 @@ func repoRootPath(ctx context.Context) (string, error) {
 	return strings.TrimSpace(output), nil
 }
 
-func createPullRequest(ctx context.Context, branch, base string, plan PullRequestPlan) (string, error) {
+func createPullRequest(ctx context.Context, branch, base string, plan PullRequestPlan, labels []string, assignMe bool) (string, error) {
+	title, err := formatPRTitle(branch, plan.Title)
+	if errors.Is(err, errNoTicket) {
+		pterm.Warning.Printf("%s has a ticket placeholder but %v; using the title without it.\n", titleTemplateKey, err)
+	} else if err != nil {
+		return "", err
+	}
+
 	bodyFile, err := writeTempFile("magi-pr-body-*.md", plan.Body)
 	if err != nil {
 		return "", err
 	}
 	defer os.Remove(bodyFile)
 
-	args := []string{
-		"pr", "create",
-		"--title", strings.TrimSpace(plan.Title),
-		"--body-file", bodyFile,
-		"--head", branch,
-	}
-	if base != "" {
-		args = append(args, "--base", base)
-	}
+	args := prCreateArgs(branch, base, title, bodyFile, labels, assignMe)
 	if _, err := runGH(ctx, args...); err != nil {
 		return "", err
 	}
@@ -388,6 +684,31 @@ func createPullRequest(ctx context.Context, branch, base string, plan PullReques
 	return parsed.URL, nil
 }
 
+// resolvePRLabels maps the findings to labels and keeps only those that exist in the
+// repository, since gh refuses to create a PR with an unknown label.
+func resolvePRLabels(ctx context.Context, findings AgentFindings) []string {
+	labels := labelsFromFindings(findings, findingLabelMapping())
+	if len(labels) == 0 {
+		pterm.Info.Println("No labels matched the review findings.")
+		return nil
+	}
+
+	existing, err := listRepoLabels(ctx)
+	if err != nil {
+		pterm.Warning.Printf("Skipping auto-labels: %v\n", err)
+		return nil
+	}
+
+	found, missing := filterExistingLabels(labels, existing)
+	if len(missing) > 0 {
+		pterm.Warning.Printf("Labels not defined in the repository were skipped: %s\n", strings.Join(missing, ", "))
+	}
+	if len(found) > 0 {
+		pterm.Info.Printf("Applying labels: %s\n", strings.Join(found, ", "))
+	}
+	return found
+}
+
 func commentOnPullRequest(ctx context.Context, body string) error {
 	if strings.TrimSpace(body) == "" {
 		return nil
@@ -527,5 +848,9 @@ func generateMarkdownReport(artifacts ReviewArtifacts) string {
 	sb.WriteString("---\n\n")
 	sb.WriteString("# Agent Findings\n\n")
 	sb.WriteString(FormatFindingsComment(artifacts))
+	if artifacts.Changelog != "" {
+		sb.WriteString("\n---\n\n# Changelog Entry\n\n")
+		sb.WriteString(artifacts.Changelog)
+	}
 	return sb.String()
 }
@@ -5,11 +5,14 @@ import (
 	"strings"
 )
 
-// FormatFindingsComment produces a markdown comment from analysis findings.
+// FormatFindingsComment produces a markdown comment from analysis findings. The comment
+// starts with findingsCommentMarker so it can be refreshed by `magi pr amend-comment`.
 func FormatFindingsComment(artifacts ReviewArtifacts) string {
 	var b strings.Builder
 	findings := artifacts.Analysis
 
+	b.WriteString(findingsCommentMarker)
+	b.WriteString("\n")
 	b.WriteString("## 🤖 Agent Review Summary\n\n")
 	if strings.TrimSpace(findings.Summary) != "" {
 		b.WriteString(findings.Summary)
@@ -18,12 +21,15 @@ func FormatFindingsComment(artifacts ReviewArtifacts) string {
 		b.WriteString("No high-level summary was provided.\n\n")
 	}
 
-	writeSection(&b, "Code Smells", findings.CodeSmells)
-	writeSection(&b, "Security Concerns", findings.SecurityConcerns)
-	writeSection(&b, "Policy Alerts (AGENTS.md)", findings.AgentsGuidelineAlerts)
-	writeSection(&b, "Suggested Tests", findings.TestRecommendations)
-	writeSection(&b, "Documentation Updates", findings.DocumentationUpdates)
-	writeSection(&b, "Risk Callouts", findings.RiskCallouts)
+	writeFindingSection(&b, "Code Smells", findings.CodeSmells, artifacts.Excerpts)
+	writeFindingSection(&b, "Security Concerns", findings.SecurityConcerns, artifacts.Excerpts)
+	writeFindingSection(&b, "Policy Alerts (AGENTS.md)", findings.AgentsGuidelineAlerts, artifacts.Excerpts)
+	writeFindingSection(&b, "Suggested Tests", findings.TestRecommendations, artifacts.Excerpts)
+	writeFindingSection(&b, "Documentation Updates", findings.DocumentationUpdates, artifacts.Excerpts)
+	writeFindingSection(&b, "Risk Callouts", findings.RiskCallouts, artifacts.Excerpts)
+	if len(artifacts.CustomFindings) > 0 {
+		writeFindingSection(&b, "Custom Findings", artifacts.CustomFindings, artifacts.Excerpts)
+	}
 
 	if artifacts.I18nFindings != nil && len(artifacts.I18nFindings.Translations) > 0 {
 		b.WriteString("### I18n Recommendations\n")
@@ -38,6 +44,12 @@ func FormatFindingsComment(artifacts ReviewArtifacts) string {
 }
 
 func writeSection(b *strings.Builder, title string, items []string) {
+	writeFindingSection(b, title, items, nil)
+}
+
+// writeFindingSection writes items as a markdown list, adding the diff excerpt of an item
+// as an indented code block when --verbose-findings found one.
+func writeFindingSection(b *strings.Builder, title string, items []string, excerpts map[string]string) {
 	b.WriteString("### ")
 	b.WriteString(title)
 	b.WriteString("\n")
@@ -55,6 +67,13 @@ func writeSection(b *strings.Builder, title string, items []string) {
 		b.WriteString("- ")
 		b.WriteString(trimmed)
 		b.WriteString("\n")
+		if excerpt, ok := excerpts[trimmed]; ok {
+			b.WriteString("  ```\n")
+			for _, line := range strings.Split(excerpt, "\n") {
+				b.WriteString("  " + line + "\n")
+			}
+			b.WriteString("  ```\n")
+		}
 	}
 	b.WriteString("\n")
 }
@@ -3,6 +3,7 @@ package pr
 import (
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestFormatFindingsComment(t *testing.T) {
@@ -43,3 +44,47 @@ func TestFormatFindingsComment(t *testing.T) {
 		}
 	}
 }
+
+func TestFormatFindingsCommentIncludesMarker(t *testing.T) {
+	comment := FormatFindingsComment(ReviewArtifacts{Analysis: AgentFindings{Summary: "ok"}})
+	if !strings.HasPrefix(comment, findingsCommentMarker) {
+		t.Fatalf("expected comment to start with the marker\ncomment: %s", comment)
+	}
+}
+
+func TestLatestFindingsComment(t *testing.T) {
+	pages := `[{"id":1,"body":"LGTM"},{"id":2,"body":"` + findingsCommentMarker + `\n## old"}]
+[{"id":3,"body":"` + findingsCommentMarker + `\n## newer"},{"id":4,"body":"thanks"}]`
+
+	comment, found, err := latestFindingsComment(pages)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !found || comment.ID != 3 || !strings.HasSuffix(comment.Body, "## newer") {
+		t.Fatalf("expected comment 3, got %+v (found=%v)", comment, found)
+	}
+
+	if _, found, err := latestFindingsComment(`[{"id":1,"body":"LGTM"}]`); err != nil || found {
+		t.Fatalf("expected no findings comment, got found=%v err=%v", found, err)
+	}
+	if _, _, err := latestFindingsComment(`not json`); err == nil {
+		t.Fatal("expected an error for invalid output")
+	}
+}
+
+func TestIterationThreading(t *testing.T) {
+	at := time.Date(2026, 10, 16, 17, 5, 0, 0, time.FixedZone("BRT", -3*60*60))
+	first := withIterationHeader(findingsCommentMarker+"\n## 🤖 Agent Review Summary\n\nfirst", "0123456789abcdef", at)
+	if !strings.HasPrefix(first, findingsCommentMarker+"\n## Iteration `0123456` (2026-10-16 20:05 UTC)\n\n## 🤖 Agent Review Summary") {
+		t.Fatalf("unexpected header placement:\n%s", first)
+	}
+
+	second := withIterationHeader(findingsCommentMarker+"\n## 🤖 Agent Review Summary\n\nsecond", "fedcba9", at.Add(time.Hour))
+	threaded := appendIteration(first+"\n", second)
+	if strings.Count(threaded, findingsCommentMarker) != 1 || !strings.HasPrefix(threaded, findingsCommentMarker) {
+		t.Fatalf("expected a single marker at the top:\n%s", threaded)
+	}
+	if !strings.Contains(threaded, "first\n\n---\n\n## Iteration `fedcba9` (2026-10-16 21:05 UTC)") {
+		t.Fatalf("expected the new iteration after the previous one:\n%s", threaded)
+	}
+}
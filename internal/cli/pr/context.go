@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 )
 
 // LoadPullRequestTemplate returns the contents of the GitHub pull request template.
@@ -17,38 +18,104 @@ func LoadPullRequestTemplate(path string) (string, error) {
 	return string(data), nil
 }
 
-// CollectAgentGuidelines aggregates every AGENTS.md file discovered under root.
+// guidelineSkipDirs are directories CollectAgentGuidelines does not descend into.
+var guidelineSkipDirs = map[string]bool{".git": true, "node_modules": true, "vendor": true}
+
+// guidelineCacheEntry is the aggregated text of paths as they were when fingerprint was taken.
+type guidelineCacheEntry struct {
+	paths       []string
+	fingerprint string
+	content     string
+}
+
+// guidelineCache holds the aggregated guidelines per repository root for the lifetime of
+// the process, so commands that review and then create a PR only walk the tree once.
+var guidelineCache = struct {
+	sync.Mutex
+	entries map[string]guidelineCacheEntry
+}{entries: map[string]guidelineCacheEntry{}}
+
+// CollectAgentGuidelines aggregates every AGENTS.md file discovered under root, skipping
+// .git, node_modules and vendor. Each file gets a "### From <path>" header so the analysis
+// can cite where an alert comes from. The result is cached in memory per root and reused
+// while the known files keep their mtimes and sizes; a change triggers a new walk.
 func CollectAgentGuidelines(root string) (string, error) {
-	var sections []string
+	key := root
+	if abs, err := filepath.Abs(root); err == nil {
+		key = abs
+	}
+
+	guidelineCache.Lock()
+	defer guidelineCache.Unlock()
+
+	if cached, ok := guidelineCache.entries[key]; ok {
+		if fingerprint, err := guidelineFingerprint(cached.paths); err == nil && fingerprint == cached.fingerprint {
+			return cached.content, nil
+		}
+	}
 
+	var paths []string
 	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
 		if d.IsDir() {
+			if path != root && guidelineSkipDirs[d.Name()] {
+				return fs.SkipDir
+			}
 			return nil
 		}
-		if !strings.EqualFold(filepath.Base(path), "AGENTS.md") {
-			return nil
-		}
-		data, readErr := os.ReadFile(path)
-		if readErr != nil {
-			return fmt.Errorf("failed to read %s: %w", path, readErr)
+		if strings.EqualFold(filepath.Base(path), "AGENTS.md") {
+			paths = append(paths, path)
 		}
-		relPath, relErr := filepath.Rel(root, path)
-		if relErr != nil {
-			relPath = path
-		}
-		sections = append(sections, fmt.Sprintf("## %s\n%s", relPath, string(data)))
 		return nil
 	})
 	if err != nil {
 		return "", err
 	}
 
-	if len(sections) == 0 {
+	fingerprint, err := guidelineFingerprint(paths)
+	if err != nil {
+		return "", err
+	}
+	content, err := readAgentGuidelines(root, paths)
+	if err != nil {
+		return "", err
+	}
+	guidelineCache.entries[key] = guidelineCacheEntry{paths: paths, fingerprint: fingerprint, content: content}
+	return content, nil
+}
+
+// guidelineFingerprint describes the mtime and size of each file at paths.
+func guidelineFingerprint(paths []string) (string, error) {
+	var b strings.Builder
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return "", fmt.Errorf("failed to stat %s: %w", path, err)
+		}
+		fmt.Fprintf(&b, "%s\x00%d\x00%d\n", path, info.ModTime().UnixNano(), info.Size())
+	}
+	return b.String(), nil
+}
+
+// readAgentGuidelines reads and joins the guideline files at paths.
+func readAgentGuidelines(root string, paths []string) (string, error) {
+	if len(paths) == 0 {
 		return "No AGENTS.md files were detected in this repository.", nil
 	}
 
+	sections := make([]string, 0, len(paths))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		relPath, relErr := filepath.Rel(root, path)
+		if relErr != nil {
+			relPath = path
+		}
+		sections = append(sections, fmt.Sprintf("### From %s\n%s", filepath.ToSlash(relPath), string(data)))
+	}
 	return strings.Join(sections, "\n\n---\n\n"), nil
 }
@@ -5,6 +5,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestCollectAgentGuidelines(t *testing.T) {
@@ -24,6 +25,29 @@ func TestCollectAgentGuidelines(t *testing.T) {
 	}
 }
 
+func TestCollectAgentGuidelinesNamesSources(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "pkg", "llm"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "AGENTS.md"), []byte("# Root rules"), 0o600); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "pkg", "llm", "AGENTS.md"), []byte("# LLM rules"), 0o600); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+
+	guidelines, err := CollectAgentGuidelines(dir)
+	if err != nil {
+		t.Fatalf("CollectAgentGuidelines() error: %v", err)
+	}
+	for _, header := range []string{"### From AGENTS.md\n# Root rules", "### From pkg/llm/AGENTS.md\n# LLM rules"} {
+		if !strings.Contains(guidelines, header) {
+			t.Fatalf("expected guidelines to contain %q, got: %s", header, guidelines)
+		}
+	}
+}
+
 func TestLoadPullRequestTemplate(t *testing.T) {
 	dir := t.TempDir()
 	path := filepath.Join(dir, "pull_request_template.md")
@@ -40,3 +64,71 @@ func TestLoadPullRequestTemplate(t *testing.T) {
 		t.Fatalf("expected %q, got %q", content, result)
 	}
 }
+
+func TestCollectAgentGuidelinesSkipsDependencyDirs(t *testing.T) {
+	dir := t.TempDir()
+	for _, sub := range []string{".git", "node_modules/pkg", "vendor/mod", "src"} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, sub, "AGENTS.md"), []byte("# Rules in "+sub), 0o600); err != nil {
+			t.Fatalf("write file: %v", err)
+		}
+	}
+
+	guidelines, err := CollectAgentGuidelines(dir)
+	if err != nil {
+		t.Fatalf("CollectAgentGuidelines() error: %v", err)
+	}
+	if !strings.Contains(guidelines, "# Rules in src") {
+		t.Fatalf("expected src/AGENTS.md, got: %s", guidelines)
+	}
+	for _, skipped := range []string{".git", "node_modules", "vendor"} {
+		if strings.Contains(guidelines, "# Rules in "+skipped) {
+			t.Fatalf("expected %s to be skipped, got: %s", skipped, guidelines)
+		}
+	}
+}
+
+func TestCollectAgentGuidelinesCache(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "AGENTS.md")
+	if err := os.WriteFile(file, []byte("# First"), 0o600); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	if _, err := CollectAgentGuidelines(dir); err != nil {
+		t.Fatalf("CollectAgentGuidelines() error: %v", err)
+	}
+
+	// Same size and mtime: the cached text is reused without reading the file.
+	info, err := os.Stat(file)
+	if err != nil {
+		t.Fatalf("stat: %v", err)
+	}
+	if err := os.WriteFile(file, []byte("# Other"), 0o600); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	if err := os.Chtimes(file, info.ModTime(), info.ModTime()); err != nil {
+		t.Fatalf("chtimes: %v", err)
+	}
+	guidelines, err := CollectAgentGuidelines(dir)
+	if err != nil || !strings.Contains(guidelines, "# First") {
+		t.Fatalf("expected the cached guidelines, got %q, %v", guidelines, err)
+	}
+
+	later := info.ModTime().Add(time.Second)
+	if err := os.Chtimes(file, later, later); err != nil {
+		t.Fatalf("chtimes: %v", err)
+	}
+	if guidelines, _ := CollectAgentGuidelines(dir); !strings.Contains(guidelines, "# Other") {
+		t.Fatalf("expected a modified file to be read again, got %q", guidelines)
+	}
+
+	// A removed file invalidates the entry and the tree is walked again.
+	if err := os.Remove(file); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	if guidelines, _ := CollectAgentGuidelines(dir); !strings.Contains(guidelines, "No AGENTS.md files") {
+		t.Fatalf("expected a removed file to invalidate the cache, got %q", guidelines)
+	}
+}
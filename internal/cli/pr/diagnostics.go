@@ -0,0 +1,102 @@
+package pr
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// jsonFailureKind classifies why an agent response could not be parsed as JSON.
+type jsonFailureKind string
+
+const (
+	jsonFailureEmpty     jsonFailureKind = "empty response"
+	jsonFailureTruncated jsonFailureKind = "truncated response"
+	jsonFailureFence     jsonFailureKind = "leftover markdown fence"
+	jsonFailureProse     jsonFailureKind = "prose before JSON"
+	jsonFailureTrailing  jsonFailureKind = "trailing text after JSON"
+	jsonFailureMalformed jsonFailureKind = "malformed JSON"
+)
+
+var jsonFailureHints = map[jsonFailureKind]string{
+	jsonFailureEmpty:     "the model returned no content; check the provider status and that the model supports JSON responses",
+	jsonFailureTruncated: "the response ends before the JSON is closed, which usually means the max token limit was hit; raise pr.analysis_max_tokens / pr.writer_max_tokens (or the matching flags) or reduce the diff size",
+	jsonFailureFence:     "the response contains an unterminated ``` fence, which usually means it was cut off mid-answer; raise pr.analysis_max_tokens / pr.writer_max_tokens",
+	jsonFailureProse:     "the model wrote text before the JSON object; use a model that supports structured outputs or retry",
+	jsonFailureTrailing:  "the model wrote text after the JSON object; use a model that supports structured outputs or retry",
+	jsonFailureMalformed: "the JSON is syntactically invalid; retry or switch to a model with structured output support",
+}
+
+// diagnoseJSONFailure inspects the raw agent output and returns the failure kind with an
+// actionable hint. cleaned is the output after sanitizeLLMJSON.
+func diagnoseJSONFailure(raw, cleaned string) (jsonFailureKind, string) {
+	kind := classifyJSONFailure(raw, cleaned)
+	return kind, jsonFailureHints[kind]
+}
+
+func classifyJSONFailure(raw, cleaned string) jsonFailureKind {
+	trimmed := strings.TrimSpace(cleaned)
+	if trimmed == "" {
+		if strings.Count(raw, "```")%2 == 1 {
+			return jsonFailureFence
+		}
+		return jsonFailureEmpty
+	}
+
+	if strings.Count(raw, "```")%2 == 1 {
+		return jsonFailureFence
+	}
+
+	if trimmed[0] != '{' && trimmed[0] != '[' {
+		if start := strings.IndexAny(trimmed, "{["); start > 0 {
+			return jsonFailureProse
+		}
+		return jsonFailureMalformed
+	}
+
+	if unclosedJSON(trimmed) {
+		return jsonFailureTruncated
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(trimmed))
+	var value any
+	if err := decoder.Decode(&value); err == nil {
+		if strings.TrimSpace(trimmed[decoder.InputOffset():]) != "" {
+			return jsonFailureTrailing
+		}
+	}
+
+	return jsonFailureMalformed
+}
+
+// unclosedJSON reports whether the input leaves braces, brackets or a string open,
+// ignoring characters inside string literals.
+func unclosedJSON(input string) bool {
+	depth := 0
+	inString := false
+	escaped := false
+
+	for _, r := range input {
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case r == '\\':
+				escaped = true
+			case r == '"':
+				inString = false
+			}
+			continue
+		}
+
+		switch r {
+		case '"':
+			inString = true
+		case '{', '[':
+			depth++
+		case '}', ']':
+			depth--
+		}
+	}
+
+	return inString || depth > 0
+}
@@ -0,0 +1,32 @@
+package pr
+
+import "testing"
+
+func TestClassifyJSONFailure(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want jsonFailureKind
+	}{
+		{"empty", "   ", jsonFailureEmpty},
+		{"truncated object", `{"summary": "Adds a flag", "code_smells": ["one"`, jsonFailureTruncated},
+		{"truncated string", `{"summary": "Adds a fl`, jsonFailureTruncated},
+		{"braces inside strings", `{"summary": "uses { and [ in text"} trailing`, jsonFailureTrailing},
+		{"unterminated fence", "```json\n{\"summary\": \"x\"", jsonFailureFence},
+		{"prose before", `Here is the analysis: {"summary": "x"}`, jsonFailureProse},
+		{"trailing prose", `{"summary": "x"} Let me know if you need more.`, jsonFailureTrailing},
+		{"malformed", `{"summary": "x",}`, jsonFailureMalformed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kind, hint := diagnoseJSONFailure(tt.raw, sanitizeLLMJSON(tt.raw))
+			if kind != tt.want {
+				t.Fatalf("expected %q, got %q", tt.want, kind)
+			}
+			if hint == "" {
+				t.Fatal("expected a hint")
+			}
+		})
+	}
+}
@@ -1,5 +1,5 @@
-// Package pr hosts reusable helpers for the pull request workflows that power
-// the `magi pr` command. It includes prompt builders, AGENTS guideline loaders,
-// and the AgenticReviewer used in cmd/pr.go so commands can share hardened logic
+// Package pr implements the `magi pr` command, registered from cmd/root.go through
+// PRCmd. It includes prompt builders, AGENTS guideline loaders, and the
+// AgenticReviewer so the pr, pr review and pr explain commands share hardened logic
 // without reimplementing multi-agent orchestration or sanitization steps.
 package pr
@@ -0,0 +1,148 @@
+package pr
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/MagdielCAS/magi-cli/pkg/git"
+	"github.com/MagdielCAS/magi-cli/pkg/shared"
+)
+
+// excerptContextLines is the number of lines shown around the referenced line.
+const excerptContextLines = 2
+
+// findingRefPattern matches the <file>:<line> references the analysis prompt asks for.
+var findingRefPattern = regexp.MustCompile(`([\w./-]+\.[\w]+):(\d+)`)
+
+// diffLines maps each file of a diff to the post-change lines the diff shows, keyed by
+// line number. Removed lines are left out because findings refer to the new code.
+type diffLines map[string]map[int]string
+
+// parseDiffLines collects the post-change lines of diff. Each hunk is redacted as a whole,
+// so secrets spanning several lines such as PEM keys are caught; a hunk whose line count
+// changes through redaction is left out rather than shown with shifted line numbers.
+func parseDiffLines(diff string, redactor *shared.Redactor) (diffLines, error) {
+	files, err := git.ParseUnifiedDiff(diff)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(diffLines)
+	for _, file := range files {
+		if file.IsDeleted() || file.Binary {
+			continue
+		}
+		lines := make(map[int]string)
+		for _, hunk := range file.Hunks {
+			var text []string
+			for _, line := range hunk.Lines {
+				if line[0] == ' ' || line[0] == '+' {
+					text = append(text, line[1:])
+				}
+			}
+			redacted, _ := redactor.Redact(strings.Join(text, "\n"))
+			newLines := strings.Split(redacted, "\n")
+			if len(newLines) != len(text) {
+				continue
+			}
+			for i, line := range newLines {
+				lines[hunk.NewStart+i] = line
+			}
+		}
+		result[file.NewPath] = lines
+	}
+	return result, nil
+}
+
+// lookupFile resolves path to a file of the diff, accepting a unique suffix match so
+// references like "command.go:42" still resolve.
+func (d diffLines) lookupFile(path string) (string, map[int]string) {
+	if lines, ok := d[path]; ok {
+		return path, lines
+	}
+	var match string
+	for file := range d {
+		if strings.HasSuffix(file, "/"+path) {
+			if match != "" {
+				return "", nil
+			}
+			match = file
+		}
+	}
+	if match == "" {
+		return "", nil
+	}
+	return match, d[match]
+}
+
+// excerptForFinding returns the diff lines around the first <file>:<line> reference in
+// finding, or false when the reference is missing or not part of the diff.
+func (d diffLines) excerptForFinding(finding string) (string, bool) {
+	for _, m := range findingRefPattern.FindAllStringSubmatch(finding, -1) {
+		target, err := strconv.Atoi(m[2])
+		if err != nil {
+			continue
+		}
+		file, lines := d.lookupFile(m[1])
+		if lines == nil {
+			continue
+		}
+		if _, ok := lines[target]; !ok {
+			continue
+		}
+
+		var b strings.Builder
+		fmt.Fprintf(&b, "%s:%d\n", file, target)
+		width := len(strconv.Itoa(target + excerptContextLines))
+		for n := target - excerptContextLines; n <= target+excerptContextLines; n++ {
+			text, ok := lines[n]
+			if !ok {
+				continue
+			}
+			marker := " "
+			if n == target {
+				marker = ">"
+			}
+			fmt.Fprintf(&b, "%s %*d | %s\n", marker, width, n, text)
+		}
+		return strings.TrimRight(b.String(), "\n"), true
+	}
+	return "", false
+}
+
+// attachFindingExcerpts stores a diff excerpt for every finding that references a
+// <file>:<line> present in diff. The excerpts are redacted because they end up in the PR
+// comment; when the redaction patterns or the diff cannot be parsed, no excerpt is added.
+func attachFindingExcerpts(artifacts *ReviewArtifacts, diff string) {
+	redactor, err := shared.RedactorFromConfig()
+	if err != nil {
+		return
+	}
+	lines, err := parseDiffLines(diff, redactor)
+	if err != nil {
+		return
+	}
+	findings := artifacts.Analysis
+	for _, group := range [][]string{
+		findings.CodeSmells,
+		findings.SecurityConcerns,
+		findings.AgentsGuidelineAlerts,
+		findings.TestRecommendations,
+		findings.DocumentationUpdates,
+		findings.RiskCallouts,
+		artifacts.CustomFindings,
+	} {
+		for _, finding := range group {
+			excerpt, ok := lines.excerptForFinding(finding)
+			if !ok {
+				continue
+			}
+			if artifacts.Excerpts == nil {
+				artifacts.Excerpts = make(map[string]string)
+			}
+			artifacts.Excerpts[strings.TrimSpace(finding)] = excerpt
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package pr
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+
+	"github.com/MagdielCAS/magi-cli/pkg/git"
+	"github.com/MagdielCAS/magi-cli/pkg/shared"
+)
+
+func newExplainCmd() *cobra.Command {
+	var targetBranch string
+	var noSecrets bool
+
+	cmd := &cobra.Command{
+		Use:   "explain <file>",
+		Short: "Review the branch changes of a single file",
+		Long: `Filter the branch diff (HEAD against the base branch) down to one file and run the
+analysis agent on just that file's hunks. Findings are printed for that file only; no PR body
+is written and nothing is posted to GitHub.
+
+Useful to iterate on a risky file without re-analyzing the whole pull request.
+
+Data handling:
+  • Sends only the selected file's diff and AGENTS.md contents to your configured AI provider.
+
+Security note:
+  • The file diff is scanned for likely secrets before the AI call (skip with --no-secrets-check).
+  • Secrets matching the redaction patterns are removed before the diff is sent.`,
+		Example: `  # Review the changes to one file
+  magi pr explain internal/cli/pr/command.go
+
+  # Compare against a specific base branch
+  magi pr explain pkg/llm/service.go --target-branch develop`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runExplain(cmd, args[0], targetBranch, noSecrets)
+		},
+	}
+
+	cmd.Flags().StringVar(&targetBranch, "target-branch", "", "Base branch to diff against (defaults to the detected base branch)")
+	cmd.Flags().BoolVar(&noSecrets, "no-secrets-check", false, "Skip the preflight scan that warns when the diff appears to add secrets")
+	return cmd
+}
+
+func runExplain(cmd *cobra.Command, file, targetBranch string, noSecrets bool) error {
+	ctx := cmd.Context()
+	if err := git.EnsureGitRepo(ctx); err != nil {
+		return err
+	}
+
+	runtimeCtx, err := shared.BuildRuntimeContext()
+	if err != nil {
+		return err
+	}
+
+	repoRoot, err := repoRootPath(ctx)
+	if err != nil {
+		return err
+	}
+	relPath, err := repoRelativePath(repoRoot, file)
+	if err != nil {
+		return err
+	}
+
+	branch, err := git.CurrentBranchName(ctx)
+	if err != nil {
+		return err
+	}
+	diff, baseRef, _, err := diffAgainstBaseBranch(ctx, branch, targetBranch)
+	if err != nil {
+		return err
+	}
+
+	fileDiff := filterDiffByPath(diff, relPath)
+	if fileDiff == "" {
+		return fmt.Errorf("%s has no changes between HEAD and %s", relPath, baseRef)
+	}
+
+	if !noSecrets {
+		proceed, err := shared.ConfirmSecretFindings(shared.ScanDiffForSecrets(fileDiff))
+		if err != nil {
+			return err
+		}
+		if !proceed {
+			return fmt.Errorf("explain aborted: the diff appears to add secrets")
+		}
+	}
+
+	guidelines, err := CollectAgentGuidelines(repoRoot)
+	if err != nil {
+		return err
+	}
+
+	payload, _, err := renderAnalysisPayload(ReviewInput{
+		Diff:       fileDiff,
+		Branch:     branch,
+		RemoteRef:  baseRef,
+		Guidelines: guidelines,
+		// The analysis payload does not include the template; validate requires one.
+		Template: fallbackReviewTemplate,
+	})
+	if err != nil {
+		return err
+	}
+
+	analysisAgent := NewAnalysisAgent(runtimeCtx)
+	if analysisAgent.maxTokens, err = resolveMaxTokens(analysisMaxTokensKey, defaultAnalysisMaxTokens); err != nil {
+		return err
+	}
+
+	spinner, _ := pterm.DefaultSpinner.Start(fmt.Sprintf("Analyzing changes to %s...", relPath))
+	output, err := analysisAgent.Execute(map[string]string{"payload": payload})
+	if err != nil {
+		spinner.Fail(fmt.Sprintf("Analysis failed: %v", err))
+		return err
+	}
+
+	var findings AgentFindings
+	cleaned := sanitizeLLMJSON(output)
+	if err := json.Unmarshal([]byte(cleaned), &findings); err != nil {
+		spinner.Fail("Analysis returned invalid JSON")
+		kind, hint := diagnoseJSONFailure(output, cleaned)
+		return fmt.Errorf("analysis agent produced invalid JSON (%s): %w\nHint: %s", kind, err, hint)
+	}
+	spinner.Success("Analysis complete")
+
+	if shared.IsJSONOutput() {
+		return shared.PrintJSON(struct {
+			File     string        `json:"file"`
+			Analysis AgentFindings `json:"analysis"`
+		}{File: relPath, Analysis: findings})
+	}
+
+	pterm.DefaultSection.Printf("Findings for %s\n", relPath)
+	printList("Summary", []string{findings.Summary})
+	printList("Code Smells", findings.CodeSmells)
+	printList("Security Concerns", findings.SecurityConcerns)
+	printList("AGENTS Alerts", findings.AgentsGuidelineAlerts)
+	printList("Test Recommendations", findings.TestRecommendations)
+	printList("Documentation Updates", findings.DocumentationUpdates)
+	printList("Risk Callouts", findings.RiskCallouts)
+	return nil
+}
+
+// repoRelativePath converts a path given on the command line (relative to the working
+// directory) into the slash-separated path git uses in diffs.
+func repoRelativePath(repoRoot, file string) (string, error) {
+	abs, err := filepath.Abs(file)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", file, err)
+	}
+	// Resolve symlinks on both sides (e.g. /tmp on macOS) so Rel works.
+	if resolved, err := filepath.EvalSymlinks(filepath.Dir(abs)); err == nil {
+		abs = filepath.Join(resolved, filepath.Base(abs))
+	}
+	if resolved, err := filepath.EvalSymlinks(repoRoot); err == nil {
+		repoRoot = resolved
+	}
+
+	rel, err := filepath.Rel(repoRoot, abs)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(os.PathSeparator)) {
+		return "", fmt.Errorf("%s is outside the repository", file)
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+// filterDiffByPath returns the sections of a unified diff that touch path, matching
+// either side so renamed and deleted files are included.
+func filterDiffByPath(diff, path string) string {
+	var b strings.Builder
+	keep := false
+	for _, line := range strings.SplitAfter(diff, "\n") {
+		if strings.HasPrefix(line, "diff --git ") {
+			header := strings.TrimSpace(strings.TrimPrefix(line, "diff --git "))
+			keep = header == fmt.Sprintf("a/%s b/%s", path, path) ||
+				strings.HasPrefix(header, "a/"+path+" ") ||
+				strings.HasSuffix(header, " b/"+path)
+		}
+		if keep {
+			b.WriteString(line)
+		}
+	}
+	return b.String()
+}
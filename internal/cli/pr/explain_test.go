@@ -0,0 +1,58 @@
+package pr
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const explainTestDiff = `diff --git a/cmd/main.go b/cmd/main.go
+index 1..2 100644
+--- a/cmd/main.go
++++ b/cmd/main.go
+@@ -1 +1 @@
+-package main
++package main // changed
+diff --git a/cmd/main_test.go b/cmd/main_test.go
+--- a/cmd/main_test.go
++++ b/cmd/main_test.go
+@@ -1 +1 @@
+-package main
++package main_test
+diff --git a/old/name.go b/new/name.go
+similarity index 90%
+rename from old/name.go
+rename to new/name.go
+`
+
+func TestFilterDiffByPath(t *testing.T) {
+	got := filterDiffByPath(explainTestDiff, "cmd/main.go")
+	want := "diff --git a/cmd/main.go b/cmd/main.go\nindex 1..2 100644\n--- a/cmd/main.go\n+++ b/cmd/main.go\n@@ -1 +1 @@\n-package main\n+package main // changed\n"
+	if got != want {
+		t.Fatalf("filterDiffByPath() =\n%q\nwant\n%q", got, want)
+	}
+
+	if got := filterDiffByPath(explainTestDiff, "new/name.go"); got == "" {
+		t.Fatal("expected renamed file to match its new path")
+	}
+	if got := filterDiffByPath(explainTestDiff, "cmd"); got != "" {
+		t.Fatalf("expected no match for a directory, got %q", got)
+	}
+}
+
+func TestRepoRelativePath(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "pkg"), 0o755); err != nil {
+		t.Fatalf("mkdir failed: %v", err)
+	}
+	t.Chdir(filepath.Join(root, "pkg"))
+
+	got, err := repoRelativePath(root, "file.go")
+	if err != nil || got != "pkg/file.go" {
+		t.Fatalf("repoRelativePath() = %q, %v", got, err)
+	}
+
+	if _, err := repoRelativePath(root, filepath.Join("..", "..", "elsewhere.go")); err == nil {
+		t.Fatal("expected an error for a path outside the repository")
+	}
+}
@@ -0,0 +1,226 @@
+package pr
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/MagdielCAS/magi-cli/pkg/shared"
+	"github.com/spf13/viper"
+)
+
+const (
+	// externalAgentsKey lists user-defined review agents that run alongside the built-in ones.
+	externalAgentsKey = "pr.agents"
+
+	defaultExternalAgentTimeout = 5 * time.Minute
+	maxExternalAgentStderrBytes = 2000
+)
+
+// builtinAgentNames are reserved so external agents cannot replace the built-in workflow.
+var builtinAgentNames = []string{"AnalysisAgent", "WriterAgent", "I18nAgent", linterAgentName}
+
+// ExternalAgentConfig describes one entry of pr.agents. Command is split into words like a
+// shell would, honoring quotes and backslashes but without expansions, and run from the
+// repository root. DependsOn names built-in or external agents whose output
+// the command receives; the diff is always available.
+type ExternalAgentConfig struct {
+	Name      string        `mapstructure:"name"`
+	Command   string        `mapstructure:"command"`
+	DependsOn []string      `mapstructure:"depends_on"`
+	Timeout   time.Duration `mapstructure:"timeout"`
+}
+
+// externalAgentInput is the JSON document an external agent reads from stdin.
+type externalAgentInput struct {
+	Branch string `json:"branch"`
+	// Diff is redacted with the same rules as the LLM payload.
+	Diff string `json:"diff"`
+	// Results holds the raw output of the agents listed in depends_on.
+	Results map[string]string `json:"results,omitempty"`
+}
+
+// externalAgentOutput is the JSON document an external agent prints on stdout.
+type externalAgentOutput struct {
+	Findings []string `json:"findings"`
+}
+
+// loadExternalAgents reads and validates pr.agents.
+func loadExternalAgents() ([]ExternalAgentConfig, error) {
+	var configs []ExternalAgentConfig
+	if err := viper.UnmarshalKey(externalAgentsKey, &configs); err != nil {
+		return nil, fmt.Errorf("invalid %s configuration: %w", externalAgentsKey, err)
+	}
+
+	seen := make(map[string]bool, len(configs))
+	for i, cfg := range configs {
+		name := strings.TrimSpace(cfg.Name)
+		switch {
+		case name == "":
+			return nil, fmt.Errorf("invalid %s configuration: entry %d has no name", externalAgentsKey, i+1)
+		case strings.TrimSpace(cfg.Command) == "":
+			return nil, fmt.Errorf("invalid %s configuration: agent %q has no command", externalAgentsKey, name)
+		case isBuiltinAgent(name):
+			return nil, fmt.Errorf("invalid %s configuration: %q is a built-in agent name", externalAgentsKey, name)
+		case seen[name]:
+			return nil, fmt.Errorf("invalid %s configuration: agent %q is defined twice", externalAgentsKey, name)
+		}
+		if _, err := shared.SplitCommandLine(cfg.Command); err != nil {
+			return nil, fmt.Errorf("invalid %s configuration: agent %q: %w", externalAgentsKey, name, err)
+		}
+		seen[name] = true
+		configs[i].Name = name
+	}
+	return configs, nil
+}
+
+func isBuiltinAgent(name string) bool {
+	for _, builtin := range builtinAgentNames {
+		if strings.EqualFold(name, builtin) {
+			return true
+		}
+	}
+	return false
+}
+
+// ExternalAgent runs a user-defined command as part of the review. The command gets an
+// externalAgentInput document on stdin and must print an externalAgentOutput document.
+type ExternalAgent struct {
+	config ExternalAgentConfig
+	dir    string
+	// err keeps the failure reason, which the pool only reports for the first failing agent.
+	err error
+}
+
+func NewExternalAgent(config ExternalAgentConfig, dir string) *ExternalAgent {
+	if config.Timeout <= 0 {
+		config.Timeout = defaultExternalAgentTimeout
+	}
+	return &ExternalAgent{config: config, dir: dir}
+}
+
+func (a *ExternalAgent) Name() string {
+	return a.config.Name
+}
+
+func (a *ExternalAgent) WaitForResults() []string {
+	return append([]string{"branch", "diff"}, a.config.DependsOn...)
+}
+
+func (a *ExternalAgent) Execute(input map[string]string) (string, error) {
+	output, err := a.run(input)
+	a.err = err
+	return output, err
+}
+
+func (a *ExternalAgent) run(input map[string]string) (string, error) {
+	payload := externalAgentInput{Branch: input["branch"], Diff: input["diff"]}
+	redactor, err := shared.RedactorFromConfig()
+	if err != nil {
+		return "", err
+	}
+	payload.Diff, _ = redactor.Redact(payload.Diff)
+	for _, dep := range a.config.DependsOn {
+		if payload.Results == nil {
+			payload.Results = make(map[string]string)
+		}
+		payload.Results[dep] = input[dep]
+	}
+
+	stdin, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode input: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(shared.BaseContext(), a.config.Timeout)
+	defer cancel()
+
+	args, err := shared.SplitCommandLine(a.config.Command)
+	if err != nil {
+		return "", fmt.Errorf("invalid command: %w", err)
+	}
+	if len(args) == 0 {
+		return "", errors.New("empty command")
+	}
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Dir = a.dir
+	cmd.Stdin = bytes.NewReader(stdin)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return "", fmt.Errorf("timed out after %s", a.config.Timeout)
+		}
+		message := strings.TrimSpace(stderr.String())
+		if len(message) > maxExternalAgentStderrBytes {
+			message = message[:maxExternalAgentStderrBytes] + "... (truncated)"
+		}
+		if message != "" {
+			return "", fmt.Errorf("%w: %s", err, message)
+		}
+		return "", err
+	}
+	return stdout.String(), nil
+}
+
+// parseExternalFindings reads the findings printed by an external agent. A bare JSON array
+// of strings is accepted as well.
+func parseExternalFindings(output string) ([]string, error) {
+	cleaned := strings.TrimSpace(sanitizeLLMJSON(output))
+	if cleaned == "" {
+		return nil, fmt.Errorf("no output was produced")
+	}
+
+	var findings []string
+	if strings.HasPrefix(cleaned, "[") {
+		if err := json.Unmarshal([]byte(cleaned), &findings); err != nil {
+			return nil, fmt.Errorf("invalid JSON: %w", err)
+		}
+	} else {
+		var result externalAgentOutput
+		if err := json.Unmarshal([]byte(cleaned), &result); err != nil {
+			return nil, fmt.Errorf("invalid JSON: %w", err)
+		}
+		findings = result.Findings
+	}
+
+	var trimmed []string
+	for _, finding := range findings {
+		if finding = strings.TrimSpace(finding); finding != "" {
+			trimmed = append(trimmed, finding)
+		}
+	}
+	return trimmed, nil
+}
+
+// mergeExternalFindings adds the findings of every external agent to artifacts, prefixed
+// with the agent name, and records a warning for agents that failed.
+func mergeExternalFindings(artifacts *ReviewArtifacts, agents []*ExternalAgent, results map[string]string) {
+	for _, agent := range agents {
+		name := agent.Name()
+		output, ok := results[name]
+		if !ok {
+			reason := "a dependency failed"
+			if agent.err != nil {
+				reason = agent.err.Error()
+			}
+			artifacts.Warnings = append(artifacts.Warnings, fmt.Sprintf("external agent %s failed (%s); its findings are missing", name, reason))
+			continue
+		}
+		findings, err := parseExternalFindings(output)
+		if err != nil {
+			artifacts.Warnings = append(artifacts.Warnings, fmt.Sprintf("external agent %s produced unusable output: %v", name, err))
+			continue
+		}
+		for _, finding := range findings {
+			artifacts.CustomFindings = append(artifacts.CustomFindings, fmt.Sprintf("[%s] %s", name, finding))
+		}
+	}
+}
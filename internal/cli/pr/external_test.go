@@ -0,0 +1,145 @@
+package pr
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/MagdielCAS/magi-cli/pkg/shared"
+	"github.com/spf13/viper"
+)
+
+func TestLoadExternalAgents(t *testing.T) {
+	t.Cleanup(viper.Reset)
+
+	viper.Set(externalAgentsKey, []map[string]any{
+		{"name": " security-scan ", "command": "scan --json", "depends_on": []string{"AnalysisAgent"}, "timeout": "30s"},
+	})
+	configs, err := loadExternalAgents()
+	if err != nil {
+		t.Fatalf("loadExternalAgents() error = %v", err)
+	}
+	if len(configs) != 1 || configs[0].Name != "security-scan" || configs[0].Timeout.String() != "30s" {
+		t.Fatalf("unexpected configs: %+v", configs)
+	}
+
+	invalid := map[string][]map[string]any{
+		"no name":   {{"command": "scan"}},
+		"no cmd":    {{"name": "scan"}},
+		"built-in":  {{"name": "AnalysisAgent", "command": "scan"}},
+		"duplicate": {{"name": "scan", "command": "a"}, {"name": "scan", "command": "b"}},
+		"quote":     {{"name": "scan", "command": "scan 'oops"}},
+	}
+	for name, entries := range invalid {
+		viper.Set(externalAgentsKey, entries)
+		if _, err := loadExternalAgents(); err == nil {
+			t.Fatalf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestParseExternalFindings(t *testing.T) {
+	got, err := parseExternalFindings("```json\n{\"findings\": [\"a\", \" \", \"b \"]}\n```")
+	if err != nil {
+		t.Fatalf("parseExternalFindings() error = %v", err)
+	}
+	if want := []string{"a", "b"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	if got, err := parseExternalFindings(`["only one"]`); err != nil || len(got) != 1 {
+		t.Fatalf("expected a bare array to be accepted, got %v, %v", got, err)
+	}
+	if _, err := parseExternalFindings("not json"); err == nil {
+		t.Fatal("expected invalid output to fail")
+	}
+}
+
+func TestExternalAgentExecute(t *testing.T) {
+	t.Cleanup(viper.Reset)
+	dir := t.TempDir()
+	script := filepath.Join(dir, "agent.sh")
+	body := "#!/bin/sh\ncat > input.json\necho '{\"findings\": [\"looks fine\"]}'\n"
+	if err := os.WriteFile(script, []byte(body), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	agent := NewExternalAgent(ExternalAgentConfig{Name: "scan", Command: script, DependsOn: []string{"AnalysisAgent"}}, dir)
+	output, err := agent.Execute(map[string]string{
+		"branch":        "feature",
+		"diff":          "+api_key = \"sk-abcdefghijklmnopqrstuvwxyz123456\"",
+		"AnalysisAgent": "{}",
+	})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	artifacts := ReviewArtifacts{}
+	mergeExternalFindings(&artifacts, []*ExternalAgent{agent}, map[string]string{"scan": output})
+	if want := []string{"[scan] looks fine"}; !reflect.DeepEqual(artifacts.CustomFindings, want) {
+		t.Fatalf("CustomFindings = %v, want %v", artifacts.CustomFindings, want)
+	}
+	if comment := FormatFindingsComment(artifacts); !strings.Contains(comment, "### Custom Findings\n- [scan] looks fine") {
+		t.Fatalf("expected a Custom Findings section, got:\n%s", comment)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "input.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var input externalAgentInput
+	if err := json.Unmarshal(data, &input); err != nil {
+		t.Fatalf("agent input is not JSON: %v", err)
+	}
+	if input.Branch != "feature" || input.Results["AnalysisAgent"] != "{}" {
+		t.Fatalf("unexpected agent input: %+v", input)
+	}
+	if strings.Contains(input.Diff, "sk-abcdefghijklmnopqrstuvwxyz123456") {
+		t.Fatalf("expected the diff to be redacted, got %q", input.Diff)
+	}
+}
+
+func TestExternalAgentQuotedArgumentsAndRedactionErrors(t *testing.T) {
+	t.Cleanup(viper.Reset)
+	dir := t.TempDir()
+	script := filepath.Join(dir, "agent.sh")
+	body := "#!/bin/sh\nprintf '%s' \"$1\" > arg.txt\necho '{\"findings\": []}'\n"
+	if err := os.WriteFile(script, []byte(body), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	agent := NewExternalAgent(ExternalAgentConfig{Name: "scan", Command: script + ` "two words"`}, dir)
+	if _, err := agent.Execute(map[string]string{"diff": "+x"}); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if data, err := os.ReadFile(filepath.Join(dir, "arg.txt")); err != nil || string(data) != "two words" {
+		t.Fatalf("expected the quoted argument to stay whole, got %q, %v", data, err)
+	}
+
+	viper.Set(shared.RedactionPatternsKey, []string{"("})
+	if err := os.Remove(filepath.Join(dir, "arg.txt")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := agent.Execute(map[string]string{"diff": "+x"}); err == nil {
+		t.Fatal("expected an invalid redaction pattern to fail the agent")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "arg.txt")); !os.IsNotExist(err) {
+		t.Fatal("expected the command not to run with an unredacted diff")
+	}
+}
+
+func TestMergeExternalFindingsReportsFailures(t *testing.T) {
+	agent := NewExternalAgent(ExternalAgentConfig{Name: "scan", Command: "false"}, t.TempDir())
+	if _, err := agent.Execute(map[string]string{}); err == nil {
+		t.Fatal("expected the command to fail")
+	}
+
+	artifacts := ReviewArtifacts{}
+	mergeExternalFindings(&artifacts, []*ExternalAgent{agent}, map[string]string{})
+	if len(artifacts.Warnings) != 1 || !strings.Contains(artifacts.Warnings[0], "external agent scan failed (exit status 1)") {
+		t.Fatalf("unexpected warnings: %v", artifacts.Warnings)
+	}
+}
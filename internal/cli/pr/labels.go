@@ -0,0 +1,112 @@
+package pr
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// labelsKey maps finding categories to the labels applied by --auto-label.
+const labelsKey = "pr.labels"
+
+// defaultFindingLabels is used for every category not overridden through pr.labels.
+var defaultFindingLabels = map[string]string{
+	"security_concerns":     "security",
+	"test_recommendations":  "needs-tests",
+	"documentation_updates": "docs",
+}
+
+// findingLabelMapping merges the pr.labels configuration over the defaults. Setting a
+// category to an empty string disables it.
+func findingLabelMapping() map[string]string {
+	mapping := make(map[string]string, len(defaultFindingLabels))
+	for category, label := range defaultFindingLabels {
+		mapping[category] = label
+	}
+	for category, label := range viper.GetStringMapString(labelsKey) {
+		mapping[strings.ToLower(strings.TrimSpace(category))] = strings.TrimSpace(label)
+	}
+	return mapping
+}
+
+// labelsFromFindings returns the sorted, de-duplicated labels for every finding category
+// that has at least one entry.
+func labelsFromFindings(findings AgentFindings, mapping map[string]string) []string {
+	categories := map[string][]string{
+		"code_smells":             findings.CodeSmells,
+		"security_concerns":       findings.SecurityConcerns,
+		"agents_guideline_alerts": findings.AgentsGuidelineAlerts,
+		"test_recommendations":    findings.TestRecommendations,
+		"documentation_updates":   findings.DocumentationUpdates,
+		"risk_callouts":           findings.RiskCallouts,
+	}
+	if findings.NeedsI18n {
+		categories["needs_i18n"] = []string{findings.I18nReason}
+	}
+
+	seen := make(map[string]bool)
+	var labels []string
+	for category, entries := range categories {
+		label := mapping[category]
+		if label == "" || seen[label] || !hasEntries(entries) {
+			continue
+		}
+		seen[label] = true
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+	return labels
+}
+
+func hasEntries(entries []string) bool {
+	for _, entry := range entries {
+		if strings.TrimSpace(entry) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// filterExistingLabels splits labels into those present in the repository and those
+// missing. Matching is case-insensitive, as on GitHub; the repository spelling is kept.
+func filterExistingLabels(labels, existing []string) ([]string, []string) {
+	known := make(map[string]string, len(existing))
+	for _, name := range existing {
+		known[strings.ToLower(name)] = name
+	}
+
+	var found, missing []string
+	for _, label := range labels {
+		if name, ok := known[strings.ToLower(label)]; ok {
+			found = append(found, name)
+			continue
+		}
+		missing = append(missing, label)
+	}
+	return found, missing
+}
+
+// listRepoLabels returns the label names defined in the current GitHub repository.
+func listRepoLabels(ctx context.Context) ([]string, error) {
+	output, err := runGH(ctx, "label", "list", "--json", "name", "--limit", "1000")
+	if err != nil {
+		return nil, err
+	}
+
+	var parsed []struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal([]byte(output), &parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse gh label list response: %w", err)
+	}
+
+	names := make([]string, 0, len(parsed))
+	for _, label := range parsed {
+		names = append(names, label.Name)
+	}
+	return names, nil
+}
@@ -0,0 +1,55 @@
+package pr
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestLabelsFromFindings(t *testing.T) {
+	findings := AgentFindings{
+		SecurityConcerns:     []string{"Token logged in plain text"},
+		TestRecommendations:  []string{"  "},
+		DocumentationUpdates: []string{"Document the new flag"},
+	}
+
+	got := labelsFromFindings(findings, defaultFindingLabels)
+	if want := []string{"docs", "security"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("labelsFromFindings() = %v, want %v", got, want)
+	}
+}
+
+func TestFindingLabelMappingOverrides(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	viper.Set(labelsKey, map[string]string{
+		"documentation_updates": "",
+		"risk_callouts":         "risky",
+	})
+
+	mapping := findingLabelMapping()
+	findings := AgentFindings{
+		SecurityConcerns:     []string{"Unsafe eval"},
+		DocumentationUpdates: []string{"Update README"},
+		RiskCallouts:         []string{"Migration is irreversible"},
+	}
+
+	got := labelsFromFindings(findings, mapping)
+	if want := []string{"risky", "security"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("labelsFromFindings() = %v, want %v", got, want)
+	}
+}
+
+func TestFilterExistingLabels(t *testing.T) {
+	found, missing := filterExistingLabels(
+		[]string{"docs", "needs-tests", "security"},
+		[]string{"Security", "docs", "bug"},
+	)
+	if want := []string{"docs", "Security"}; !reflect.DeepEqual(found, want) {
+		t.Fatalf("found = %v, want %v", found, want)
+	}
+	if want := []string{"needs-tests"}; !reflect.DeepEqual(missing, want) {
+		t.Fatalf("missing = %v, want %v", missing, want)
+	}
+}
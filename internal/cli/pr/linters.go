@@ -0,0 +1,219 @@
+package pr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/MagdielCAS/magi-cli/pkg/git"
+	"github.com/MagdielCAS/magi-cli/pkg/shared"
+	"github.com/spf13/viper"
+)
+
+const (
+	// lintersKey lists the local linters run on the changed files before the analysis.
+	lintersKey = "pr.linters"
+	// linterAgentName is the LinterAgent result key the analysis agent waits for.
+	linterAgentName = "LinterAgent"
+
+	linterTimeout        = 2 * time.Minute
+	maxLinterOutputBytes = 8000
+)
+
+// LinterConfig describes one entry of pr.linters. Command is split on whitespace; the
+// {files} placeholder expands to the matching changed files and {dirs} to their
+// directories (as ./dir). Without a placeholder the files are appended.
+type LinterConfig struct {
+	Name       string   `mapstructure:"name"`
+	Command    string   `mapstructure:"command"`
+	Extensions []string `mapstructure:"extensions"`
+}
+
+// loadLinterConfigs reads pr.linters, dropping entries without a command.
+func loadLinterConfigs() ([]LinterConfig, error) {
+	var configs []LinterConfig
+	if err := viper.UnmarshalKey(lintersKey, &configs); err != nil {
+		return nil, fmt.Errorf("invalid %s configuration: %w", lintersKey, err)
+	}
+
+	valid := configs[:0]
+	for _, cfg := range configs {
+		if strings.TrimSpace(cfg.Command) == "" {
+			continue
+		}
+		if strings.TrimSpace(cfg.Name) == "" {
+			cfg.Name = strings.Fields(cfg.Command)[0]
+		}
+		valid = append(valid, cfg)
+	}
+	return valid, nil
+}
+
+// changedFilesFromDiff returns the sorted paths added or modified by a unified diff.
+// Deleted and binary files are skipped because there is nothing to lint.
+func changedFilesFromDiff(diff string) ([]string, error) {
+	parsed, err := git.ParseUnifiedDiff(diff)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool)
+	var files []string
+	for _, file := range parsed {
+		if file.IsDeleted() || file.Binary || seen[file.NewPath] {
+			continue
+		}
+		seen[file.NewPath] = true
+		files = append(files, file.NewPath)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// filesForLinter keeps the files whose extension is listed in cfg.Extensions; every file
+// matches when no extension is configured.
+func filesForLinter(cfg LinterConfig, files []string) []string {
+	if len(cfg.Extensions) == 0 {
+		return files
+	}
+	var matched []string
+	for _, file := range files {
+		ext := filepath.Ext(file)
+		for _, want := range cfg.Extensions {
+			if strings.EqualFold(ext, "."+strings.TrimPrefix(want, ".")) {
+				matched = append(matched, file)
+				break
+			}
+		}
+	}
+	return matched
+}
+
+// linterArgs expands the placeholders of cfg.Command for files.
+func linterArgs(cfg LinterConfig, files []string) []string {
+	fields := strings.Fields(cfg.Command)
+	args := make([]string, 0, len(fields)+len(files))
+	expanded := false
+	for _, field := range fields {
+		switch field {
+		case "{files}":
+			args = append(args, files...)
+			expanded = true
+		case "{dirs}":
+			args = append(args, fileDirs(files)...)
+			expanded = true
+		default:
+			args = append(args, field)
+		}
+	}
+	if !expanded {
+		args = append(args, files...)
+	}
+	return args
+}
+
+func fileDirs(files []string) []string {
+	seen := make(map[string]bool)
+	var dirs []string
+	for _, file := range files {
+		dir := "./" + path.Dir(file)
+		if dir == "./." {
+			dir = "."
+		}
+		if !seen[dir] {
+			seen[dir] = true
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+// LinterAgent runs the configured local linters on the files changed by the diff and
+// returns their combined output for the analysis agent. It never fails: missing tools and
+// timeouts are reported in the output instead, so the review still runs.
+type LinterAgent struct {
+	linters []LinterConfig
+	dir     string
+	timeout time.Duration
+}
+
+func NewLinterAgent(linters []LinterConfig, dir string) *LinterAgent {
+	return &LinterAgent{linters: linters, dir: dir, timeout: linterTimeout}
+}
+
+func (a *LinterAgent) Name() string {
+	return linterAgentName
+}
+
+func (a *LinterAgent) WaitForResults() []string {
+	return []string{"diff"}
+}
+
+func (a *LinterAgent) Execute(input map[string]string) (string, error) {
+	files, err := changedFilesFromDiff(input["diff"])
+	if err != nil {
+		return "", fmt.Errorf("failed to read the changed files from the diff: %w", err)
+	}
+
+	var b strings.Builder
+	for _, cfg := range a.linters {
+		matched := filesForLinter(cfg, files)
+		if len(matched) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "### %s\n%s\n\n", cfg.Name, a.run(cfg, matched))
+	}
+	if b.Len() == 0 {
+		return "No configured linter applies to the changed files.", nil
+	}
+	return strings.TrimSpace(b.String()), nil
+}
+
+func (a *LinterAgent) run(cfg LinterConfig, files []string) string {
+	args := linterArgs(cfg, files)
+	if _, err := exec.LookPath(args[0]); err != nil {
+		return fmt.Sprintf("Skipped: %s is not installed.", args[0])
+	}
+
+	ctx, cancel := context.WithTimeout(shared.BaseContext(), a.timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Dir = a.dir
+	output, err := cmd.CombinedOutput()
+	text := strings.TrimSpace(string(output))
+	if len(text) > maxLinterOutputBytes {
+		text = text[:maxLinterOutputBytes] + "\n... (output truncated)"
+	}
+
+	var exitErr *exec.ExitError
+	switch {
+	case ctx.Err() != nil:
+		return fmt.Sprintf("Timed out after %s.", a.timeout)
+	case err != nil && !errors.As(err, &exitErr):
+		return fmt.Sprintf("Failed to run: %v", err)
+	case text == "":
+		return "No issues reported."
+	case err != nil:
+		return fmt.Sprintf("Exit code %d:\n%s", exitErr.ExitCode(), text)
+	default:
+		return text
+	}
+}
+
+// appendLinterOutput adds the LinterAgent result to the analysis payload, redacting any
+// secret echoed by the tools.
+func appendLinterOutput(payload, linterOutput string) string {
+	if strings.TrimSpace(linterOutput) == "" {
+		return payload
+	}
+	if redactor, err := shared.RedactorFromConfig(); err == nil {
+		linterOutput, _ = redactor.Redact(linterOutput)
+	}
+	return payload + "\n\nLocal linter output for the changed files (ground your findings in it; ignore issues outside the diff):\n" + linterOutput
+}
@@ -0,0 +1,124 @@
+package pr
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+const linterTestDiff = `diff --git a/cmd/main.go b/cmd/main.go
+--- a/cmd/main.go
++++ b/cmd/main.go
+@@ -1 +1 @@
+-package main
++package main // updated
+diff --git a/web/app.ts b/web/app.ts
+--- /dev/null
++++ b/web/app.ts
+@@ -0,0 +1 @@
++export const x = 1;
+diff --git a/old.go b/old.go
+--- a/old.go
++++ /dev/null
+@@ -1 +0,0 @@
+-package old
+`
+
+func TestChangedFilesFromDiff(t *testing.T) {
+	got, err := changedFilesFromDiff(linterTestDiff)
+	if err != nil {
+		t.Fatalf("changedFilesFromDiff returned error: %v", err)
+	}
+	if want := []string{"cmd/main.go", "web/app.ts"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("changedFilesFromDiff() = %v, want %v", got, want)
+	}
+}
+
+func TestChangedFilesFromDiffIgnoresDashedContent(t *testing.T) {
+	diff := `diff --git a/db.sql b/db.sql
+--- a/db.sql
++++ b/db.sql
+@@ -1,2 +1,2 @@
+--- removed comment
++++ b/not-a-file.sql
+ SELECT 1;
+`
+	got, err := changedFilesFromDiff(diff)
+	if err != nil {
+		t.Fatalf("changedFilesFromDiff returned error: %v", err)
+	}
+	if want := []string{"db.sql"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("changedFilesFromDiff() = %v, want %v", got, want)
+	}
+}
+
+func TestLinterArgs(t *testing.T) {
+	files := []string{"cmd/main.go", "cmd/util.go", "main.go"}
+	tests := []struct {
+		name    string
+		command string
+		want    []string
+	}{
+		{"appends files", "gofmt -l", []string{"gofmt", "-l", "cmd/main.go", "cmd/util.go", "main.go"}},
+		{"files placeholder", "golangci-lint run {files} --fast", []string{"golangci-lint", "run", "cmd/main.go", "cmd/util.go", "main.go", "--fast"}},
+		{"dirs placeholder", "go vet {dirs}", []string{"go", "vet", "./cmd", "."}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := linterArgs(LinterConfig{Command: tt.command}, files)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("linterArgs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilesForLinter(t *testing.T) {
+	files := []string{"cmd/main.go", "web/app.ts", "web/app.tsx"}
+	got := filesForLinter(LinterConfig{Extensions: []string{"ts", ".TSX"}}, files)
+	if want := []string{"web/app.ts", "web/app.tsx"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("filesForLinter() = %v, want %v", got, want)
+	}
+}
+
+func TestLoadLinterConfigs(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	viper.Set(lintersKey, []map[string]any{
+		{"command": "go vet {dirs}", "extensions": []string{".go"}},
+		{"name": "empty", "command": " "},
+	})
+
+	got, err := loadLinterConfigs()
+	if err != nil {
+		t.Fatalf("loadLinterConfigs() error = %v", err)
+	}
+	want := []LinterConfig{{Name: "go", Command: "go vet {dirs}", Extensions: []string{".go"}}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("loadLinterConfigs() = %+v, want %+v", got, want)
+	}
+}
+
+func TestLinterAgentDegradesGracefully(t *testing.T) {
+	agent := NewLinterAgent([]LinterConfig{
+		{Name: "missing", Command: "magi-linter-that-does-not-exist", Extensions: []string{".go"}},
+		{Name: "echo", Command: "echo checked", Extensions: []string{".ts"}},
+		{Name: "python", Command: "ruff", Extensions: []string{".py"}},
+	}, t.TempDir())
+
+	output, err := agent.Execute(map[string]string{"diff": linterTestDiff})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if !strings.Contains(output, "### missing\nSkipped: magi-linter-that-does-not-exist is not installed.") {
+		t.Fatalf("expected missing linter to be skipped, got:\n%s", output)
+	}
+	if !strings.Contains(output, "### echo\nchecked web/app.ts") {
+		t.Fatalf("expected echo output, got:\n%s", output)
+	}
+	if strings.Contains(output, "python") {
+		t.Fatalf("expected linter without matching files to be skipped, got:\n%s", output)
+	}
+}
@@ -58,3 +58,33 @@ func TestGenerateMarkdownReport(t *testing.T) {
 		t.Error("Report missing code smell")
 	}
 }
+
+func TestPRCreateArgs(t *testing.T) {
+	args := prCreateArgs("feature", "main", "Add thing", "/tmp/body.md", []string{"docs"}, true)
+	got := strings.Join(args, " ")
+	want := "pr create --title Add thing --body-file /tmp/body.md --head feature --base main --label docs --assignee @me"
+	if got != want {
+		t.Fatalf("prCreateArgs = %q, want %q", got, want)
+	}
+
+	args = prCreateArgs("feature", "", "t", "b", nil, false)
+	if got := strings.Join(args, " "); strings.Contains(got, "--assignee") || strings.Contains(got, "--base") {
+		t.Fatalf("unexpected optional args: %q", got)
+	}
+}
+
+func TestPRHeadWarnings(t *testing.T) {
+	if warnings := prHeadWarnings("feature", "main", 2); len(warnings) != 0 {
+		t.Fatalf("expected no warnings, got %v", warnings)
+	}
+	if warnings := prHeadWarnings("feature", "main", -1); len(warnings) != 0 {
+		t.Fatalf("expected no warnings when the count is unknown, got %v", warnings)
+	}
+	warnings := prHeadWarnings("main", "main", 0)
+	if len(warnings) != 2 {
+		t.Fatalf("expected two warnings, got %v", warnings)
+	}
+	if !strings.Contains(warnings[0], "into itself") || !strings.Contains(warnings[1], "no commits ahead of main") {
+		t.Fatalf("unexpected warnings: %v", warnings)
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"strings"
 	"text/template"
 
+	"github.com/MagdielCAS/magi-cli/pkg/shared"
+
 	openai "github.com/openai/openai-go/v3"
 	openaiShared "github.com/openai/openai-go/v3/shared"
 )
@@ -110,7 +112,7 @@ Provide analysis in JSON format:
   "summary": "<one concise paragraph>",
   "code_smells": ["<issue>: <file>:<line> - <detail>"],
   "security_concerns": ["..."],
-  "agents_guideline_alerts": ["..."],
+  "agents_guideline_alerts": ["<violation> (source: <guideline file> > <section heading>)"],
   "test_recommendations": ["..."],
   "documentation_updates": ["..."],
   "risk_callouts": ["..."],
@@ -121,6 +123,7 @@ Provide analysis in JSON format:
 Rules:
 - Keep responses grounded in the provided diff and guidelines.
 - Reference file paths when possible.
+- Every agents_guideline_alerts entry must cite the guideline it relies on: the file named in its "### From <file>" header and the section heading of the rule.
 - Use empty arrays when a section has no findings.
 - Do not emit markdown, prose paragraphs, or additional commentary outside the JSON.
 - Make sure the JSON is valid.
@@ -203,6 +206,8 @@ type ReviewInput struct {
 	Guidelines        string
 	AdditionalContext string
 	Template          string
+	// RepoRoot is the working directory of the configured linters (pr.linters).
+	RepoRoot string
 }
 
 func (ri ReviewInput) validate() error {
@@ -221,10 +226,19 @@ func (ri ReviewInput) validate() error {
 	return nil
 }
 
-func renderAnalysisPayload(input ReviewInput) (string, error) {
+// renderAnalysisPayload renders the analysis prompt after scrubbing secrets from the
+// diff and reviewer notes. It returns the number of redactions made.
+func renderAnalysisPayload(input ReviewInput) (string, int, error) {
 	if err := input.validate(); err != nil {
-		return "", err
+		return "", 0, err
+	}
+
+	redactor, err := shared.RedactorFromConfig()
+	if err != nil {
+		return "", 0, err
 	}
+	diff, diffRedactions := redactor.Redact(input.Diff)
+	additionalContext, contextRedactions := redactor.Redact(input.AdditionalContext)
 
 	data := struct {
 		Branch            string
@@ -236,15 +250,15 @@ func renderAnalysisPayload(input ReviewInput) (string, error) {
 		Branch:            input.Branch,
 		RemoteRef:         input.RemoteRef,
 		Guidelines:        fallbackText(input.Guidelines, "No AGENTS.md files were detected in this repository."),
-		AdditionalContext: fallbackText(input.AdditionalContext, "N/A"),
-		Diff:              input.Diff,
+		AdditionalContext: fallbackText(additionalContext, "N/A"),
+		Diff:              diff,
 	}
 
 	var buf bytes.Buffer
 	if err := analysisInputTemplate.Execute(&buf, data); err != nil {
-		return "", fmt.Errorf("failed to render analysis payload: %w", err)
+		return "", 0, fmt.Errorf("failed to render analysis payload: %w", err)
 	}
-	return buf.String(), nil
+	return buf.String(), diffRedactions + contextRedactions, nil
 }
 
 type writerPayloadParams struct {
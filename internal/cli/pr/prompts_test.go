@@ -16,10 +16,13 @@ func TestRenderAnalysisPayload(t *testing.T) {
 		Template:          "template",
 	}
 
-	payload, err := renderAnalysisPayload(input)
+	payload, redactions, err := renderAnalysisPayload(input)
 	if err != nil {
 		t.Fatalf("renderAnalysisPayload() unexpected error: %v", err)
 	}
+	if redactions != 0 {
+		t.Fatalf("renderAnalysisPayload() redacted %d values from a clean diff", redactions)
+	}
 
 	assertContains(t, payload, input.Diff)
 	assertContains(t, payload, input.Branch)
@@ -28,6 +31,27 @@ func TestRenderAnalysisPayload(t *testing.T) {
 	assertContains(t, payload, input.AdditionalContext)
 }
 
+func TestRenderAnalysisPayloadRedactsSecrets(t *testing.T) {
+	input := ReviewInput{
+		Diff:              "+API_KEY=sk-abcdefghijklmnopqrstuvwxyz",
+		Branch:            "feature/pr",
+		RemoteRef:         "origin/feature/pr",
+		AdditionalContext: "password: hunter2secret",
+		Template:          "template",
+	}
+
+	payload, redactions, err := renderAnalysisPayload(input)
+	if err != nil {
+		t.Fatalf("renderAnalysisPayload() unexpected error: %v", err)
+	}
+	if redactions != 2 {
+		t.Fatalf("expected 2 redactions, got %d", redactions)
+	}
+	if strings.Contains(payload, "sk-abcdefghijklmnopqrstuvwxyz") || strings.Contains(payload, "hunter2secret") {
+		t.Fatalf("payload still contains secrets:\n%s", payload)
+	}
+}
+
 func TestRenderWriterPayload(t *testing.T) {
 	params := writerPayloadParams{
 		AnalysisJSON: `{"summary":"ok"}`,
@@ -0,0 +1,215 @@
+package pr
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+
+	"github.com/MagdielCAS/magi-cli/pkg/git"
+	"github.com/MagdielCAS/magi-cli/pkg/shared"
+)
+
+const (
+	defaultExternalBranch = "external-diff"
+	defaultExternalRef    = "external"
+	// fallbackReviewTemplate is used by `magi pr review` when the repository has no
+	// pull request template.
+	fallbackReviewTemplate = "## Summary\n\n## Changes\n\n## Testing\n"
+)
+
+type reviewOptions struct {
+	diffFile   string
+	branch     string
+	baseRef    string
+	notes      string
+	outputFile string
+	target     string
+	noSecrets  bool
+	verbose    bool
+}
+
+func newReviewCmd() *cobra.Command {
+	opts := &reviewOptions{}
+	cmd := &cobra.Command{
+		Use:   "review",
+		Short: "Review a diff with the AI agents without creating a pull request",
+		Long: `Review a diff with the same agents used by 'magi pr' and print the findings and the
+filled pull request template. No pull request is created.
+
+By default the diff between HEAD and the base branch is reviewed. Use --diff-file to review a
+diff produced elsewhere (a CI artifact, a colleague's patch); pass '-' to read it from stdin.
+The branch and base reference are only used as context for the agents and can be set with
+--branch and --base-ref.
+
+Data handling:
+  • Sends the diff, AGENTS.md contents from the current repository (or directory), and --notes
+    to your configured AI provider.
+
+Security note:
+  • The diff is scanned for likely secrets before any AI call (skip with --no-secrets-check).
+  • Secrets matching the redaction patterns are removed before the diff is sent.`,
+		Example: `  # Review the local branch without opening a PR
+  magi pr review
+
+  # Review a patch file
+  magi pr review --diff-file patch.diff
+
+  # Review a diff from stdin and save the report
+  git diff main...feature | magi pr review --diff - --output-file review.md`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runReview(cmd, opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.diffFile, "diff-file", "", "Review the diff in this file instead of the local git diff ('-' reads stdin)")
+	cmd.Flags().StringVar(&opts.diffFile, "diff", "", "Alias for --diff-file")
+	cmd.Flags().StringVar(&opts.branch, "branch", "", "Branch name given to the agents as context (defaults to the current branch or "+defaultExternalBranch+")")
+	cmd.Flags().StringVar(&opts.baseRef, "base-ref", "", "Base reference given to the agents as context (defaults to the base branch or "+defaultExternalRef+")")
+	cmd.Flags().StringVar(&opts.target, "target-branch", "", "Base branch for the local git diff (ignored with --diff-file)")
+	cmd.Flags().StringVar(&opts.notes, "notes", "", "Additional context for the AI reviewers")
+	cmd.Flags().StringVar(&opts.outputFile, "output-file", "", "Write the agent results to a markdown file")
+	cmd.Flags().BoolVar(&opts.noSecrets, "no-secrets-check", false, "Skip the preflight scan that warns when the diff appears to add secrets")
+	cmd.Flags().BoolVar(&opts.verbose, "verbose-findings", false, "Show the referenced diff lines under findings that cite <file>:<line>")
+	return cmd
+}
+
+func runReview(cmd *cobra.Command, opts *reviewOptions) error {
+	ctx := cmd.Context()
+
+	runtimeCtx, err := shared.BuildRuntimeContext()
+	if err != nil {
+		return err
+	}
+
+	input := ReviewInput{
+		Branch:            opts.branch,
+		RemoteRef:         opts.baseRef,
+		AdditionalContext: strings.TrimSpace(opts.notes),
+	}
+
+	inRepo := git.EnsureGitRepo(ctx) == nil
+	if inRepo {
+		if input.RepoRoot, err = repoRootPath(ctx); err != nil {
+			return err
+		}
+	} else if input.RepoRoot, err = os.Getwd(); err != nil {
+		return fmt.Errorf("failed to determine working directory: %w", err)
+	}
+
+	if opts.diffFile != "" {
+		if input.Diff, err = readDiffInput(opts.diffFile, cmd.InOrStdin()); err != nil {
+			return err
+		}
+		input.Branch = fallbackText(input.Branch, defaultExternalBranch)
+		input.RemoteRef = fallbackText(input.RemoteRef, defaultExternalRef)
+	} else {
+		if !inRepo {
+			return fmt.Errorf("not inside a git repository; use --diff-file to review a diff from a file or stdin")
+		}
+		branch, err := git.CurrentBranchName(ctx)
+		if err != nil {
+			return err
+		}
+		diff, baseRef, _, err := diffAgainstBaseBranch(ctx, branch, opts.target)
+		if err != nil {
+			return err
+		}
+		input.Diff = diff
+		input.Branch = fallbackText(input.Branch, branch)
+		input.RemoteRef = fallbackText(input.RemoteRef, baseRef)
+	}
+
+	if input.Template, err = loadReviewTemplate(input.RepoRoot); err != nil {
+		return err
+	}
+	if input.Guidelines, err = CollectAgentGuidelines(input.RepoRoot); err != nil {
+		return err
+	}
+
+	if !opts.noSecrets {
+		proceed, err := shared.ConfirmSecretFindings(shared.ScanDiffForSecrets(input.Diff))
+		if err != nil {
+			return err
+		}
+		if !proceed {
+			return fmt.Errorf("review aborted: the diff appears to add secrets")
+		}
+	}
+
+	artifacts, err := runReviewAgents(ctx, NewAgenticReviewer(runtimeCtx), input, "AI Analysis and PR drafting complete")
+	if err != nil {
+		return err
+	}
+	if artifacts.Redactions > 0 {
+		pterm.Info.Printf("Redacted %d secret(s) from the diff and notes before sending them to the AI provider.\n", artifacts.Redactions)
+	}
+	for _, warning := range artifacts.Warnings {
+		pterm.Warning.Println(warning)
+	}
+
+	if opts.verbose {
+		attachFindingExcerpts(artifacts, input.Diff)
+	}
+
+	report := generateMarkdownReport(*artifacts)
+	if opts.outputFile != "" {
+		if err := os.WriteFile(opts.outputFile, []byte(report), 0644); err != nil {
+			return fmt.Errorf("failed to write output file: %w", err)
+		}
+		pterm.Success.Printf("Report written to %s\n", opts.outputFile)
+	}
+
+	if shared.IsJSONOutput() {
+		return shared.PrintJSON(artifacts)
+	}
+	logFindings(*artifacts)
+	return nil
+}
+
+// readDiffInput reads a unified diff from path, or from stdin when path is "-".
+func readDiffInput(path string, stdin io.Reader) (string, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read diff from %s: %w", diffSourceName(path), err)
+	}
+
+	diff := string(data)
+	if strings.TrimSpace(diff) == "" {
+		return "", fmt.Errorf("diff from %s is empty", diffSourceName(path))
+	}
+	if !strings.Contains(diff, "\n+++ ") && !strings.HasPrefix(diff, "+++ ") {
+		return "", fmt.Errorf("%s does not look like a unified diff", diffSourceName(path))
+	}
+	return diff, nil
+}
+
+func diffSourceName(path string) string {
+	if path == "-" {
+		return "stdin"
+	}
+	return path
+}
+
+// loadReviewTemplate returns the repository pull request template, or a minimal template
+// when the repository has none.
+func loadReviewTemplate(root string) (string, error) {
+	template, err := LoadPullRequestTemplate(filepath.Join(root, ".github", "pull_request_template.md"))
+	if errors.Is(err, os.ErrNotExist) {
+		pterm.Info.Println("No pull request template found; using a minimal Summary/Changes/Testing template.")
+		return fallbackReviewTemplate, nil
+	}
+	return template, err
+}
@@ -0,0 +1,63 @@
+package pr
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const reviewTestDiff = "diff --git a/main.go b/main.go\n--- a/main.go\n+++ b/main.go\n@@ -1 +1 @@\n-package main\n+package main // changed\n"
+
+func TestReadDiffInput(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "patch.diff")
+	if err := os.WriteFile(path, []byte(reviewTestDiff), 0o600); err != nil {
+		t.Fatalf("failed to write patch: %v", err)
+	}
+
+	got, err := readDiffInput(path, nil)
+	if err != nil || got != reviewTestDiff {
+		t.Fatalf("readDiffInput(file) = %q, %v", got, err)
+	}
+
+	got, err = readDiffInput("-", strings.NewReader(reviewTestDiff))
+	if err != nil || got != reviewTestDiff {
+		t.Fatalf("readDiffInput(stdin) = %q, %v", got, err)
+	}
+}
+
+func TestReadDiffInputRejectsInvalidInput(t *testing.T) {
+	tests := map[string]string{
+		"empty":    "  \n",
+		"not diff": "just some notes\n",
+	}
+	for name, input := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := readDiffInput("-", strings.NewReader(input)); err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+
+	if _, err := readDiffInput(filepath.Join(t.TempDir(), "missing.diff"), nil); err == nil {
+		t.Fatal("expected an error for a missing file")
+	}
+}
+
+func TestLoadReviewTemplateFallsBack(t *testing.T) {
+	root := t.TempDir()
+	got, err := loadReviewTemplate(root)
+	if err != nil || got != fallbackReviewTemplate {
+		t.Fatalf("loadReviewTemplate() = %q, %v", got, err)
+	}
+
+	if err := os.MkdirAll(filepath.Join(root, ".github"), 0o755); err != nil {
+		t.Fatalf("mkdir failed: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, ".github", "pull_request_template.md"), []byte("## Custom\n"), 0o600); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if got, err := loadReviewTemplate(root); err != nil || got != "## Custom\n" {
+		t.Fatalf("loadReviewTemplate() = %q, %v", got, err)
+	}
+}
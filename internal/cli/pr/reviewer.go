@@ -9,6 +9,7 @@ import (
 	"github.com/MagdielCAS/magi-cli/pkg/agent"
 	"github.com/MagdielCAS/magi-cli/pkg/shared"
 	"github.com/MagdielCAS/magi-cli/pkg/utils"
+	"github.com/spf13/viper"
 )
 
 // AgentFindings captures the structured response from the analysis agent.
@@ -43,14 +44,28 @@ type I18nResult struct {
 
 // ReviewArtifacts groups the analysis findings with the final PR plan.
 type ReviewArtifacts struct {
-	Analysis     AgentFindings
-	Plan         PullRequestPlan
-	I18nFindings *I18nResult
+	Analysis     AgentFindings   `json:"analysis"`
+	Plan         PullRequestPlan `json:"plan"`
+	I18nFindings *I18nResult     `json:"i18n_findings,omitempty"`
+	// Warnings lists non-fatal agent failures that were recovered from.
+	Warnings []string `json:"warnings,omitempty"`
+	// Redactions counts the secrets scrubbed from the diff and notes before sending them.
+	Redactions int `json:"redactions,omitempty"`
+	// Changelog holds the Keep a Changelog entry generated by --changelog.
+	Changelog string `json:"changelog,omitempty"`
+	// Excerpts maps a finding to the diff lines it references, filled by --verbose-findings.
+	Excerpts map[string]string `json:"excerpts,omitempty"`
+	// LightAnalysis is set when the diff was small enough to analyze with the light model.
+	LightAnalysis bool `json:"light_analysis,omitempty"`
+	// CustomFindings holds the findings of the external agents from pr.agents, each
+	// prefixed with "[agent name]".
+	CustomFindings []string `json:"custom_findings,omitempty"`
 }
 
 // AgenticReviewer orchestrates the agent workflow for PR prep.
 type AgenticReviewer struct {
-	runtime *shared.RuntimeContext
+	runtime  *shared.RuntimeContext
+	progress agent.ProgressFunc
 }
 
 // NewAgenticReviewer creates a reviewer bound to the shared runtime context.
@@ -58,6 +73,12 @@ func NewAgenticReviewer(runtime *shared.RuntimeContext) *AgenticReviewer {
 	return &AgenticReviewer{runtime: runtime}
 }
 
+// WithProgress forwards the agent progress events of the next reviews to fn.
+func (r *AgenticReviewer) WithProgress(fn agent.ProgressFunc) *AgenticReviewer {
+	r.progress = fn
+	return r
+}
+
 // Review executes the multi-agent workflow and returns structured artifacts.
 func (r *AgenticReviewer) Review(ctx context.Context, input ReviewInput) (*ReviewArtifacts, error) {
 	if r == nil || r.runtime == nil {
@@ -65,64 +86,176 @@ func (r *AgenticReviewer) Review(ctx context.Context, input ReviewInput) (*Revie
 	}
 
 	// Render payload for AnalysisAgent
-	payload, err := renderAnalysisPayload(input)
+	payload, redactions, err := renderAnalysisPayload(input)
+	if err != nil {
+		return nil, err
+	}
+
+	analysisAgent := NewAnalysisAgent(r.runtime)
+	if analysisAgent.maxTokens, err = resolveMaxTokens(analysisMaxTokensKey, defaultAnalysisMaxTokens); err != nil {
+		return nil, err
+	}
+	analysisAgent.preferLight = useLightAnalysis(input.Diff)
+	writerAgent := NewWriterAgent(r.runtime)
+	if writerAgent.maxTokens, err = resolveMaxTokens(writerMaxTokensKey, defaultWriterMaxTokens); err != nil {
+		return nil, err
+	}
+	writerAgent.strictTemplate = viper.GetBool(strictTemplateKey)
+
+	linters, err := loadLinterConfigs()
+	if err != nil {
+		return nil, err
+	}
+	externalConfigs, err := loadExternalAgents()
 	if err != nil {
 		return nil, err
 	}
 
 	// Initialize AgentManager
 	am := agent.NewAgentPool()
-	am.WithAgent(NewAnalysisAgent(r.runtime))
-	am.WithAgent(NewWriterAgent(r.runtime))
+	am.WithLogger(shared.Logger())
+	am.WithProgress(func(event agent.Event) {
+		shared.TrackAgent(event.Agent, string(event.Kind))
+	})
+	am.WithProgress(r.progress)
+	if len(linters) > 0 {
+		analysisAgent.withLinters = true
+		am.WithAgent(NewLinterAgent(linters, input.RepoRoot))
+	}
+	am.WithAgent(analysisAgent)
+	am.WithAgent(writerAgent)
 	am.WithAgent(NewI18nAgent(r.runtime))
 
+	externalAgents := make([]*ExternalAgent, 0, len(externalConfigs))
+	for _, cfg := range externalConfigs {
+		externalAgent := NewExternalAgent(cfg, input.RepoRoot)
+		externalAgents = append(externalAgents, externalAgent)
+		am.WithAgent(externalAgent)
+	}
+
 	// Prepare initial input
 	initialInput := map[string]string{
 		"payload":  payload,
 		"template": input.Template,
 		"branch":   input.Branch,
+		"diff":     input.Diff,
+	}
+	if len(externalAgents) > 0 {
+		if err := am.ValidateDependencies(initialInput); err != nil {
+			return nil, fmt.Errorf("invalid %s configuration: %w", externalAgentsKey, err)
+		}
 	}
 
-	// Execute agents
-	results, err := am.ExecuteAgents(initialInput)
-	if err != nil {
-		return nil, fmt.Errorf("agent execution failed: %w", err)
+	// Execute agents. Failures of agents other than the analysis are recovered below.
+	results, execErr := am.ExecuteAgents(initialInput)
+	if _, ok := results["AnalysisAgent"]; !ok {
+		if execErr == nil {
+			execErr = fmt.Errorf("analysis agent produced no output")
+		}
+		return nil, fmt.Errorf("agent execution failed: %w", execErr)
 	}
 
 	// Parse results
-	var artifacts ReviewArtifacts
+	artifacts := ReviewArtifacts{Redactions: redactions, LightAnalysis: analysisAgent.preferLight}
 
 	analysisOutput := sanitizeLLMJSON(results["AnalysisAgent"])
 	if err := json.Unmarshal([]byte(analysisOutput), &artifacts.Analysis); err != nil {
-		return nil, fmt.Errorf("analysis agent produced invalid JSON: %w (raw: %s)", err, sanitizeForError(results["AnalysisAgent"]))
+		kind, hint := diagnoseJSONFailure(results["AnalysisAgent"], analysisOutput)
+		return nil, fmt.Errorf("analysis agent produced invalid JSON (%s): %w\nHint: %s\n(raw: %s)", kind, err, hint, sanitizeForError(results["AnalysisAgent"]))
 	}
 
-	writerOutput := sanitizeLLMJSON(results["WriterAgent"])
-	if err := json.Unmarshal([]byte(writerOutput), &artifacts.Plan); err != nil {
-		return nil, fmt.Errorf("PR writer agent produced invalid JSON: %w (raw: %s)", err, sanitizeForError(results["WriterAgent"]))
+	plan, planErr := parseWriterPlan(results, writerAgent.err)
+	if planErr != nil {
+		artifacts.Plan = fallbackPlan(artifacts.Analysis, input.Branch)
+		artifacts.Warnings = append(artifacts.Warnings, fmt.Sprintf("PR writer agent failed (%v); the PR body was generated locally from the analysis findings", planErr))
+	} else {
+		artifacts.Plan = plan
+		if writerAgent.strictTemplate {
+			if extras := extraHeadings(input.Template, plan.Body); len(extras) > 0 {
+				artifacts.Plan.Body = stripExtraSections(input.Template, plan.Body)
+				artifacts.Warnings = append(artifacts.Warnings, fmt.Sprintf("removed sections not in the PR template: %s", strings.Join(extras, ", ")))
+			}
+		}
 	}
 
+	mergeExternalFindings(&artifacts, externalAgents, results)
+
 	if artifacts.Analysis.NeedsI18n {
-		i18nOutput := sanitizeLLMJSON(results["I18nAgent"])
-		if i18nOutput != "" {
+		i18nOutput, ok := results["I18nAgent"]
+		i18nOutput = sanitizeLLMJSON(i18nOutput)
+		switch {
+		case !ok:
+			artifacts.Warnings = append(artifacts.Warnings, "i18n agent failed; no translation recommendations were generated")
+		case i18nOutput != "":
 			var i18nRes I18nResult
 			if err := json.Unmarshal([]byte(i18nOutput), &i18nRes); err != nil {
-				// We don't fail the whole PR creation if i18n fails, just log it?
-				// Or maybe we should warn. For now let's treat it similarly being strict.
-				return nil, fmt.Errorf("i18n agent produced invalid JSON: %w (raw: %s)", err, sanitizeForError(results["I18nAgent"]))
+				artifacts.Warnings = append(artifacts.Warnings, fmt.Sprintf("i18n agent produced invalid JSON: %v", err))
+				break
 			}
 			artifacts.I18nFindings = &i18nRes
 		}
 	}
 
-	if strings.TrimSpace(artifacts.Plan.Title) == "" {
-		return nil, fmt.Errorf("PR writer did not return a title")
+	return &artifacts, nil
+}
+
+// parseWriterPlan extracts the PR plan from the writer agent output. writerErr is the
+// writer's own error, reported when it produced no output.
+func parseWriterPlan(results map[string]string, writerErr error) (PullRequestPlan, error) {
+	var plan PullRequestPlan
+
+	raw, ok := results["WriterAgent"]
+	if !ok {
+		if writerErr != nil {
+			return plan, writerErr
+		}
+		return plan, fmt.Errorf("no output was produced")
 	}
-	if strings.TrimSpace(artifacts.Plan.Body) == "" {
-		return nil, fmt.Errorf("PR writer did not return a body")
+
+	writerOutput := sanitizeLLMJSON(raw)
+	if err := json.Unmarshal([]byte(writerOutput), &plan); err != nil {
+		kind, hint := diagnoseJSONFailure(raw, writerOutput)
+		return plan, fmt.Errorf("invalid JSON (%s): %w; hint: %s", kind, err, hint)
 	}
 
-	return &artifacts, nil
+	if strings.TrimSpace(plan.Title) == "" {
+		return plan, fmt.Errorf("no title was returned")
+	}
+	if strings.TrimSpace(plan.Body) == "" {
+		return plan, fmt.Errorf("no body was returned")
+	}
+
+	return plan, nil
+}
+
+// fallbackPlan builds a basic PR title and body from the analysis findings without
+// another LLM call, so a writer failure does not waste the analysis.
+func fallbackPlan(findings AgentFindings, branch string) PullRequestPlan {
+	title := strings.TrimSpace(strings.SplitN(strings.TrimSpace(findings.Summary), "\n", 2)[0])
+	if title == "" {
+		title = fmt.Sprintf("Changes from %s", branch)
+	}
+	const maxTitleLen = 72
+	if len(title) > maxTitleLen {
+		title = strings.TrimSpace(title[:maxTitleLen-3]) + "..."
+	}
+
+	var b strings.Builder
+	b.WriteString("## Summary\n\n")
+	if summary := strings.TrimSpace(findings.Summary); summary != "" {
+		b.WriteString(summary)
+	} else {
+		b.WriteString("No summary was provided by the analysis agent.")
+	}
+	b.WriteString("\n\n")
+
+	writeSection(&b, "Risk Callouts", findings.RiskCallouts)
+	writeSection(&b, "Suggested Tests", findings.TestRecommendations)
+	writeSection(&b, "Documentation Updates", findings.DocumentationUpdates)
+
+	b.WriteString("_This description was generated from the analysis findings because the PR writer agent failed. Review it before submitting._")
+
+	return PullRequestPlan{Title: title, Body: b.String()}
 }
 
 func sanitizeForError(output string) string {
@@ -0,0 +1,52 @@
+package pr
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseWriterPlan(t *testing.T) {
+	plan, err := parseWriterPlan(map[string]string{
+		"WriterAgent": "```json\n{\"title\":\"feat: add flag\",\"body\":\"## Summary\"}\n```",
+	}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if plan.Title != "feat: add flag" {
+		t.Fatalf("unexpected title %q", plan.Title)
+	}
+
+	if _, err := parseWriterPlan(map[string]string{}, nil); err == nil {
+		t.Fatal("expected error when writer produced no output")
+	}
+	writerErr := errors.New("context deadline exceeded")
+	if _, err := parseWriterPlan(map[string]string{}, writerErr); !errors.Is(err, writerErr) {
+		t.Fatalf("expected the writer's own error, got %v", err)
+	}
+	if _, err := parseWriterPlan(map[string]string{"WriterAgent": `{"title":"x","body":""}`}, nil); err == nil {
+		t.Fatal("expected error for empty body")
+	}
+}
+
+func TestFallbackPlan(t *testing.T) {
+	plan := fallbackPlan(AgentFindings{
+		Summary:             "Adds retry support to the MCP client.\nMore details follow.",
+		TestRecommendations: []string{"Cover retry exhaustion"},
+	}, "feat/mcp-retry")
+
+	if plan.Title != "Adds retry support to the MCP client." {
+		t.Fatalf("unexpected title %q", plan.Title)
+	}
+	assertContains(t, plan.Body, "## Summary")
+	assertContains(t, plan.Body, "- Cover retry exhaustion")
+	assertContains(t, plan.Body, "PR writer agent failed")
+
+	empty := fallbackPlan(AgentFindings{}, "feat/x")
+	if empty.Title != "Changes from feat/x" {
+		t.Fatalf("unexpected fallback title %q", empty.Title)
+	}
+	if !strings.Contains(empty.Body, "No summary was provided") {
+		t.Fatalf("expected placeholder summary, got %q", empty.Body)
+	}
+}
@@ -0,0 +1,96 @@
+package pr
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/MagdielCAS/magi-cli/pkg/git"
+)
+
+// scopeDiff keeps the files of diff whose path matches one of globs, so the review only
+// covers part of the branch. A rename matches on either path. It returns diff unchanged
+// when no globs are given.
+func scopeDiff(diff string, globs []string) (string, error) {
+	if len(globs) == 0 {
+		return diff, nil
+	}
+	for _, glob := range globs {
+		if _, err := path.Match(normalizeScopeGlob(glob), ""); err != nil {
+			return "", fmt.Errorf("invalid --files pattern %q: %w", glob, err)
+		}
+	}
+
+	files, err := git.ParseUnifiedDiff(diff)
+	if err != nil {
+		return "", err
+	}
+	var scoped []git.FileDiff
+	for _, file := range files {
+		if scopeMatches(globs, file.OldPath) || scopeMatches(globs, file.NewPath) {
+			scoped = append(scoped, file)
+		}
+	}
+	return git.FormatUnifiedDiff(scoped), nil
+}
+
+// scopeMatches reports whether file matches one of globs. A pattern matches the path, any
+// of its parent directories (so "internal/auth" and "internal/auth/**" cover the whole
+// directory) or, when it has no slash, the file name, like "*.sql".
+func scopeMatches(globs []string, file string) bool {
+	if file == "" {
+		return false
+	}
+	for _, glob := range globs {
+		pattern := normalizeScopeGlob(glob)
+		if !strings.Contains(pattern, "/") {
+			if matched, _ := path.Match(pattern, path.Base(file)); matched {
+				return true
+			}
+		}
+		for candidate := file; candidate != "." && candidate != "/"; candidate = path.Dir(candidate) {
+			if matched, _ := path.Match(pattern, candidate); matched {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// normalizeScopeGlob drops a leading "./" and a trailing "/" or "/**" from glob.
+func normalizeScopeGlob(glob string) string {
+	glob = strings.TrimPrefix(strings.TrimSpace(glob), "./")
+	glob = strings.TrimSuffix(glob, "/**")
+	return strings.TrimSuffix(glob, "/")
+}
+
+// unscopedArtifacts builds the artifacts of a PR whose --files scope matched no changed
+// file. The analysis is skipped, so the plan only lists the files of the whole branch
+// diff and leaves the description to the author.
+func unscopedArtifacts(fullDiff, branch string, globs []string) (*ReviewArtifacts, error) {
+	files, err := git.ParseUnifiedDiff(fullDiff)
+	if err != nil {
+		return nil, err
+	}
+	changed := make([]string, 0, len(files))
+	for _, file := range files {
+		switch {
+		case file.NewPath == "":
+			changed = append(changed, fmt.Sprintf("`%s` (deleted)", file.OldPath))
+		case file.OldPath != "" && file.OldPath != file.NewPath:
+			changed = append(changed, fmt.Sprintf("`%s` → `%s`", file.OldPath, file.NewPath))
+		default:
+			changed = append(changed, fmt.Sprintf("`%s`", file.NewPath))
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("## Summary\n\n")
+	fmt.Fprintf(&b, "No AI analysis was run: no changed file matches --files %s.\n\n", strings.Join(globs, ", "))
+	writeSection(&b, "Changed Files", changed)
+	b.WriteString("_Describe the changes before submitting._")
+
+	return &ReviewArtifacts{
+		Plan: PullRequestPlan{Title: fmt.Sprintf("Changes from %s", branch), Body: b.String()},
+	}, nil
+}
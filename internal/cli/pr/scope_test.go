@@ -0,0 +1,88 @@
+package pr
+
+import (
+	"strings"
+	"testing"
+)
+
+const scopeTestDiff = `diff --git a/internal/auth/login.go b/internal/auth/login.go
+index 1111111..2222222 100644
+--- a/internal/auth/login.go
++++ b/internal/auth/login.go
+@@ -1 +1 @@
+-old
++new
+diff --git a/db/schema.sql b/db/schema.sql
+index 3333333..4444444 100644
+--- a/db/schema.sql
++++ b/db/schema.sql
+@@ -1 +1 @@
+-a
++b
+diff --git a/docs/old.md b/notes/new.md
+similarity index 90%
+rename from docs/old.md
+rename to notes/new.md
+`
+
+func TestScopeDiff(t *testing.T) {
+	cases := map[string][]string{
+		"internal/auth":      {"internal/auth/login.go"},
+		"./internal/auth/**": {"internal/auth/login.go"},
+		"internal/*/*.go":    {"internal/auth/login.go"},
+		"*.sql":              {"db/schema.sql"},
+		"docs":               {"notes/new.md"},
+		"cmd":                nil,
+	}
+	for glob, want := range cases {
+		got, err := scopeDiff(scopeTestDiff, []string{glob})
+		if err != nil {
+			t.Fatalf("scopeDiff(%q) failed: %v", glob, err)
+		}
+		if count := strings.Count(got, "diff --git"); count != len(want) {
+			t.Fatalf("scopeDiff(%q) kept %d files, want %v:\n%s", glob, count, want, got)
+		}
+		for _, file := range want {
+			if !strings.Contains(got, "b/"+file) {
+				t.Fatalf("scopeDiff(%q) dropped %s:\n%s", glob, file, got)
+			}
+		}
+	}
+
+	got, err := scopeDiff(scopeTestDiff, []string{"cmd", "*.sql", "internal/auth"})
+	if err != nil || strings.Count(got, "diff --git") != 2 {
+		t.Fatalf("expected the globs to add up, got %v:\n%s", err, got)
+	}
+	if got, _ := scopeDiff(scopeTestDiff, nil); got != scopeTestDiff {
+		t.Fatal("expected the diff to be unchanged without globs")
+	}
+	if _, err := scopeDiff(scopeTestDiff, []string{"[a-"}); err == nil || !strings.Contains(err.Error(), "--files") {
+		t.Fatalf("expected an invalid pattern to be reported, got %v", err)
+	}
+}
+
+func TestUnscopedArtifactsListsWholeBranch(t *testing.T) {
+	scoped, err := scopeDiff(scopeTestDiff, []string{"cmd"})
+	if err != nil {
+		t.Fatalf("scopeDiff failed: %v", err)
+	}
+	if strings.TrimSpace(scoped) != "" {
+		t.Fatalf("expected an empty scope, got %q", scoped)
+	}
+
+	artifacts, err := unscopedArtifacts(scopeTestDiff, "feature/login", []string{"cmd"})
+	if err != nil {
+		t.Fatalf("unscopedArtifacts failed: %v", err)
+	}
+	if artifacts.Plan.Title != "Changes from feature/login" {
+		t.Fatalf("unexpected title %q", artifacts.Plan.Title)
+	}
+	for _, want := range []string{"--files cmd", "`internal/auth/login.go`", "`db/schema.sql`", "`docs/old.md` → `notes/new.md`"} {
+		if !strings.Contains(artifacts.Plan.Body, want) {
+			t.Fatalf("body is missing %q:\n%s", want, artifacts.Plan.Body)
+		}
+	}
+	if artifacts.Analysis.Summary != "" {
+		t.Fatalf("expected no analysis, got %+v", artifacts.Analysis)
+	}
+}
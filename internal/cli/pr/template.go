@@ -0,0 +1,104 @@
+package pr
+
+import (
+	"regexp"
+	"strings"
+)
+
+// strictTemplateKey enables the strict template mode: the writer may only produce the
+// headings found in the pull request template.
+const strictTemplateKey = "pr.strict_template"
+
+var markdownHeadingPattern = regexp.MustCompile(`^(#{1,6})\s+(.+?)\s*#*\s*$`)
+
+type markdownHeading struct {
+	level int
+	text  string
+	line  int
+}
+
+// parseHeadings returns the ATX headings in markdown, ignoring fenced code blocks.
+func parseHeadings(markdown string) []markdownHeading {
+	var headings []markdownHeading
+	inFence := false
+	for i, line := range strings.Split(markdown, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		if match := markdownHeadingPattern.FindStringSubmatch(trimmed); match != nil {
+			headings = append(headings, markdownHeading{level: len(match[1]), text: match[2], line: i})
+		}
+	}
+	return headings
+}
+
+// templateHeadings lists the heading texts of the pull request template.
+func templateHeadings(template string) []string {
+	var texts []string
+	for _, heading := range parseHeadings(template) {
+		texts = append(texts, heading.text)
+	}
+	return texts
+}
+
+// extraHeadings returns the headings in body that do not appear in the template.
+func extraHeadings(template, body string) []string {
+	allowed := allowedHeadingSet(template)
+	var extras []string
+	for _, heading := range parseHeadings(body) {
+		if !allowed[normalizeHeading(heading.text)] {
+			extras = append(extras, heading.text)
+		}
+	}
+	return extras
+}
+
+// stripExtraSections removes every section whose heading is not in the template,
+// including its nested subsections.
+func stripExtraSections(template, body string) string {
+	allowed := allowedHeadingSet(template)
+	lines := strings.Split(body, "\n")
+	headings := parseHeadings(body)
+
+	drop := make([]bool, len(lines))
+	for i, heading := range headings {
+		if allowed[normalizeHeading(heading.text)] {
+			continue
+		}
+		end := len(lines)
+		for _, next := range headings[i+1:] {
+			if next.level <= heading.level {
+				end = next.line
+				break
+			}
+		}
+		for l := heading.line; l < end; l++ {
+			drop[l] = true
+		}
+	}
+
+	kept := make([]string, 0, len(lines))
+	for i, line := range lines {
+		if !drop[i] {
+			kept = append(kept, line)
+		}
+	}
+	return strings.TrimRight(strings.Join(kept, "\n"), "\n")
+}
+
+func allowedHeadingSet(template string) map[string]bool {
+	allowed := make(map[string]bool)
+	for _, text := range templateHeadings(template) {
+		allowed[normalizeHeading(text)] = true
+	}
+	return allowed
+}
+
+func normalizeHeading(text string) string {
+	return strings.ToLower(strings.Join(strings.Fields(strings.Trim(text, "*_ ")), " "))
+}
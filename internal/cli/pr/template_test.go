@@ -0,0 +1,52 @@
+package pr
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const strictTestTemplate = `## Summary
+
+Describe the change.
+
+## Testing
+
+` + "```md\n# not a heading\n```"
+
+func TestTemplateHeadings(t *testing.T) {
+	got := templateHeadings(strictTestTemplate)
+	if want := []string{"Summary", "Testing"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("templateHeadings() = %v, want %v", got, want)
+	}
+}
+
+func TestExtraHeadingsAndStrip(t *testing.T) {
+	body := `## Summary
+Adds retries.
+
+## Risks
+Network flakiness.
+
+### Mitigation
+Backoff.
+
+## testing
+go test ./...`
+
+	extras := extraHeadings(strictTestTemplate, body)
+	if want := []string{"Risks", "Mitigation"}; !reflect.DeepEqual(extras, want) {
+		t.Fatalf("extraHeadings() = %v, want %v", extras, want)
+	}
+
+	stripped := stripExtraSections(strictTestTemplate, body)
+	if strings.Contains(stripped, "Risks") || strings.Contains(stripped, "Backoff") {
+		t.Fatalf("expected extra sections to be removed:\n%s", stripped)
+	}
+	if !strings.Contains(stripped, "Adds retries.") || !strings.Contains(stripped, "go test ./...") {
+		t.Fatalf("expected template sections to be kept:\n%s", stripped)
+	}
+	if len(extraHeadings(strictTestTemplate, stripped)) != 0 {
+		t.Fatalf("expected no extra headings after stripping")
+	}
+}
@@ -0,0 +1,128 @@
+package pr
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+	"unicode/utf8"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	// titleTemplateKey wraps the writer's title, e.g. "[{{.Ticket}}] {{.Title}}".
+	titleTemplateKey = "pr.title_template"
+	// ticketPatternKey overrides the regular expression that finds the ticket in the branch name.
+	ticketPatternKey = "pr.ticket_pattern"
+
+	// defaultTicketPattern matches upper-case Jira-style keys such as PROJ-123. Lower-case
+	// words are left out so branch names like fix-123-crash or release-2024 have no ticket.
+	defaultTicketPattern = `(?:^|[^A-Za-z0-9])([A-Z][A-Z0-9]+-[0-9]+)`
+
+	// maxPRTitleLength follows the writer prompt, which asks for titles under 80 characters.
+	maxPRTitleLength = 79
+)
+
+// errNoTicket is returned when the title template uses .Ticket but the branch name has none.
+var errNoTicket = errors.New("no ticket found in the branch name")
+
+// prTitleData is the data available to pr.title_template.
+type prTitleData struct {
+	Ticket string
+	Title  string
+}
+
+// ticketFromBranch returns the upper-cased ticket named in branch, using pr.ticket_pattern
+// when set. The first capture group is used when the pattern has one.
+func ticketFromBranch(branch string) (string, error) {
+	pattern := strings.TrimSpace(viper.GetString(ticketPatternKey))
+	if pattern == "" {
+		pattern = defaultTicketPattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return "", fmt.Errorf("invalid %s: %w", ticketPatternKey, err)
+	}
+	match := re.FindStringSubmatch(branch)
+	if match == nil {
+		return "", nil
+	}
+	if len(match) > 1 {
+		return strings.ToUpper(match[1]), nil
+	}
+	return strings.ToUpper(match[0]), nil
+}
+
+// formatPRTitle applies pr.title_template to the writer's title and enforces the title
+// rules of the writer prompt: no trailing punctuation and fewer than 80 characters. Only
+// the model's title is shortened to make room for the template. When the template needs
+// a ticket and the branch has none, the plain title is returned with errNoTicket.
+func formatPRTitle(branch, title string) (string, error) {
+	title = trimTitle(title)
+	tmpl := strings.TrimSpace(viper.GetString(titleTemplateKey))
+	if tmpl == "" {
+		return truncateTitle(title, maxPRTitleLength), nil
+	}
+
+	parsed, err := template.New("title").Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("invalid %s: %w", titleTemplateKey, err)
+	}
+
+	ticket, err := ticketFromBranch(branch)
+	if err != nil {
+		return "", err
+	}
+	usesTicket := strings.Contains(tmpl, ".Ticket")
+	switch {
+	case usesTicket && ticket == "":
+		return truncateTitle(title, maxPRTitleLength), errNoTicket
+	case usesTicket && strings.Contains(strings.ToUpper(title), ticket):
+		// The title already names the ticket, for example after editing it by hand.
+		return truncateTitle(title, maxPRTitleLength), nil
+	}
+
+	frame, err := renderTitle(parsed, prTitleData{Ticket: ticket})
+	if err != nil {
+		return "", err
+	}
+	budget := maxPRTitleLength - utf8.RuneCountInString(frame)
+	if budget < 1 {
+		return "", fmt.Errorf("%s leaves no room for the title within %d characters", titleTemplateKey, maxPRTitleLength)
+	}
+
+	rendered, err := renderTitle(parsed, prTitleData{Ticket: ticket, Title: truncateTitle(title, budget)})
+	if err != nil {
+		return "", err
+	}
+	return trimTitle(rendered), nil
+}
+
+func renderTitle(tmpl *template.Template, data prTitleData) (string, error) {
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("invalid %s: %w", titleTemplateKey, err)
+	}
+	return b.String(), nil
+}
+
+// trimTitle drops surrounding whitespace and trailing punctuation.
+func trimTitle(title string) string {
+	return strings.TrimRight(strings.TrimSpace(title), " .,;:!?")
+}
+
+// truncateTitle shortens title to at most limit characters, cutting at a word boundary
+// when one is close enough.
+func truncateTitle(title string, limit int) string {
+	runes := []rune(title)
+	if len(runes) <= limit {
+		return title
+	}
+	cut := string(runes[:limit])
+	if space := strings.LastIndex(cut, " "); space > len(cut)/2 {
+		cut = cut[:space]
+	}
+	return trimTitle(cut)
+}
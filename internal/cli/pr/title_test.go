@@ -0,0 +1,82 @@
+package pr
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/spf13/viper"
+)
+
+func TestTicketFromBranch(t *testing.T) {
+	t.Cleanup(viper.Reset)
+
+	cases := map[string]string{
+		"feature/PROJ-123-add-login": "PROJ-123",
+		"PROJ-7_fix":                 "PROJ-7",
+		"AB2-9":                      "AB2-9",
+		"fix/123-crash":              "",
+		"fix-123-crash":              "",
+		"release-2024":               "",
+		"hotfix-2":                   "",
+		"feature/proj-123-add-login": "",
+		"main":                       "",
+	}
+	for branch, want := range cases {
+		got, err := ticketFromBranch(branch)
+		if err != nil {
+			t.Fatalf("unexpected error for %q: %v", branch, err)
+		}
+		if got != want {
+			t.Fatalf("expected ticket %q for %q, got %q", want, branch, got)
+		}
+	}
+
+	viper.Set(ticketPatternKey, `^(\d+)-`)
+	if got, _ := ticketFromBranch("42-docs"); got != "42" {
+		t.Fatalf("expected the capture group of pr.ticket_pattern, got %q", got)
+	}
+	viper.Set(ticketPatternKey, `(`)
+	if _, err := ticketFromBranch("42-docs"); err == nil {
+		t.Fatal("expected an invalid pattern to fail")
+	}
+}
+
+func TestFormatPRTitle(t *testing.T) {
+	t.Cleanup(viper.Reset)
+
+	if got, _ := formatPRTitle("feature/PROJ-1", "Add login page."); got != "Add login page" {
+		t.Fatalf("expected trailing punctuation to be removed without a template, got %q", got)
+	}
+
+	viper.Set(titleTemplateKey, "[{{.Ticket}}] {{.Title}}")
+	got, err := formatPRTitle("feature/PROJ-123-login", "Add login page")
+	if err != nil || got != "[PROJ-123] Add login page" {
+		t.Fatalf("expected templated title, got %q (%v)", got, err)
+	}
+
+	got, err = formatPRTitle("feature/PROJ-123-login", "[PROJ-123] Add login page")
+	if err != nil || got != "[PROJ-123] Add login page" {
+		t.Fatalf("expected a title naming the ticket to be kept, got %q (%v)", got, err)
+	}
+
+	got, err = formatPRTitle("fix/login", "Add login page")
+	if !errors.Is(err, errNoTicket) || got != "Add login page" {
+		t.Fatalf("expected errNoTicket with the plain title, got %q (%v)", got, err)
+	}
+
+	long := strings.Repeat("word ", 30)
+	got, err = formatPRTitle("feature/PROJ-123-login", long)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if utf8.RuneCountInString(got) > maxPRTitleLength || !strings.HasPrefix(got, "[PROJ-123] word") || strings.HasSuffix(got, " ") {
+		t.Fatalf("expected the model title to be shortened to fit, got %q", got)
+	}
+
+	viper.Set(titleTemplateKey, "{{.Missing}} {{.Title}}")
+	if _, err := formatPRTitle("feature/PROJ-123-login", "Add login page"); err == nil {
+		t.Fatal("expected an unknown template field to fail")
+	}
+}
@@ -0,0 +1,110 @@
+package project
+
+import (
+	"embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// actionTemplateFiles holds the curated action templates shipped with the binary.
+//
+//go:embed templates/*.yaml
+var actionTemplateFiles embed.FS
+
+// actionTemplateSet is a group of curated actions for one kind of project.
+type actionTemplateSet struct {
+	Name string `yaml:"name"`
+	// Architectures are lower-case keywords looked up in the detected architecture. An
+	// empty list matches any architecture.
+	Architectures []string `yaml:"architectures"`
+	// Markers are files that must all exist at the project root, such as go.mod.
+	Markers []string `yaml:"markers"`
+	Actions []Action `yaml:"actions"`
+}
+
+// loadActionTemplates parses the embedded template sets. Sets tied to an architecture come
+// first so their actions win over the generic ones when names collide.
+func loadActionTemplates() ([]actionTemplateSet, error) {
+	entries, err := actionTemplateFiles.ReadDir("templates")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read action templates: %w", err)
+	}
+
+	var sets []actionTemplateSet
+	for _, entry := range entries {
+		data, err := actionTemplateFiles.ReadFile("templates/" + entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read action template %s: %w", entry.Name(), err)
+		}
+		var set actionTemplateSet
+		if err := yaml.Unmarshal(data, &set); err != nil {
+			return nil, fmt.Errorf("failed to parse action template %s: %w", entry.Name(), err)
+		}
+		sets = append(sets, set)
+	}
+	sort.SliceStable(sets, func(i, j int) bool {
+		return len(sets[i].Architectures) > 0 && len(sets[j].Architectures) == 0
+	})
+	return sets, nil
+}
+
+// matches reports whether the set applies to the project at root with the given analysis.
+func (s actionTemplateSet) matches(root string, analysis *AnalysisResult) bool {
+	for _, marker := range s.Markers {
+		if _, err := os.Stat(filepath.Join(root, marker)); err != nil {
+			return false
+		}
+	}
+	if len(s.Architectures) == 0 {
+		return true
+	}
+	architecture := strings.ToLower(analysis.Architecture)
+	for _, keyword := range s.Architectures {
+		if strings.Contains(architecture, strings.ToLower(keyword)) {
+			return true
+		}
+	}
+	return false
+}
+
+// templateActions returns the curated actions that apply to the project and the names of
+// the template sets they came from.
+func templateActions(root string, analysis *AnalysisResult) ([]Action, []string, error) {
+	sets, err := loadActionTemplates()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var actions []Action
+	var used []string
+	for _, set := range sets {
+		if !set.matches(root, analysis) {
+			continue
+		}
+		actions = mergeActions(actions, set.Actions)
+		used = append(used, set.Name)
+	}
+	return actions, used, nil
+}
+
+// mergeActions appends the actions of extra whose names are not in primary. Actions keep
+// their order and the first action with a given name wins.
+func mergeActions(primary, extra []Action) []Action {
+	seen := make(map[string]bool, len(primary))
+	merged := make([]Action, 0, len(primary)+len(extra))
+	for _, list := range [][]Action{primary, extra} {
+		for _, action := range list {
+			if seen[action.Name] {
+				continue
+			}
+			seen[action.Name] = true
+			merged = append(merged, action)
+		}
+	}
+	return merged
+}
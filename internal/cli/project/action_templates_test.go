@@ -0,0 +1,61 @@
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestActionTemplatesAreValid(t *testing.T) {
+	sets, err := loadActionTemplates()
+	require.NoError(t, err)
+	require.NotEmpty(t, sets)
+
+	names := make(map[string]bool)
+	for _, set := range sets {
+		assert.NotEmpty(t, set.Name)
+		assert.False(t, names[set.Name], "duplicate template set %s", set.Name)
+		names[set.Name] = true
+
+		config := MagiConfig{Actions: set.Actions}
+		assert.Empty(t, validateConfig(&config), "template set %s", set.Name)
+	}
+}
+
+func TestTemplateActions(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "go.mod"), []byte("module example\n"), 0644))
+
+	actions, used, err := templateActions(root, &AnalysisResult{Architecture: "Vertical Slice Architecture"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"vertical-slice-go", "go"}, used)
+	assert.Equal(t, []string{"create_slice", "add_handler", "run_tests", "tidy_modules"}, actionNames(actions))
+
+	_, used, err = templateActions(root, &AnalysisResult{Architecture: "Layered"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"go"}, used)
+
+	_, used, err = templateActions(t.TempDir(), &AnalysisResult{Architecture: "Vertical Slice"})
+	require.NoError(t, err)
+	assert.Empty(t, used)
+}
+
+func TestMergeActions(t *testing.T) {
+	primary := []Action{{Name: "run_tests", Description: "template"}, {Name: "create_slice"}}
+	extra := []Action{{Name: "run_tests", Description: "llm"}, {Name: "add_route"}, {Name: "add_route"}}
+
+	merged := mergeActions(primary, extra)
+	assert.Equal(t, []string{"run_tests", "create_slice", "add_route"}, actionNames(merged))
+	assert.Equal(t, "template", merged[0].Description)
+}
+
+func actionNames(actions []Action) []string {
+	names := make([]string, 0, len(actions))
+	for _, action := range actions {
+		names = append(names, action.Name)
+	}
+	return names
+}
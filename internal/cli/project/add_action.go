@@ -0,0 +1,332 @@
+package project
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/MagdielCAS/magi-cli/pkg/shared"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+// actionParameterTypes are the parameter types offered by add-action.
+var actionParameterTypes = []string{"string", "number", "boolean"}
+
+// stepToolParameter is a step parameter the Executor reads for a given tool.
+type stepToolParameter struct {
+	Name        string
+	Description string
+	Required    bool
+}
+
+// stepToolParameters lists, per tool, the step parameters add-action asks for.
+var stepToolParameters = map[string][]stepToolParameter{
+	"create_file": nil,
+	"edit_file": {
+		{Name: "target", Description: "File to edit (leave empty to pick it when the action runs)"},
+	},
+	"append_file": {
+		{Name: "target", Description: "File to append to (created when missing)", Required: true},
+		{Name: "content", Description: "Literal content to insert (leave empty to generate it from the instruction)"},
+		{Name: "anchor", Description: "Text of the marker line to insert at instead of the end (optional)"},
+		{Name: "position", Description: "Insert before or after the anchor line (default after)"},
+	},
+	"search_replace": {
+		{Name: "target", Description: "File to edit (leave empty to pick it when the action runs)"},
+	},
+	"read_file": {
+		{Name: "target", Description: "File to read", Required: true},
+	},
+	"run_command": {
+		{Name: "command", Description: "Command to run", Required: true},
+		{Name: "cwd", Description: "Directory to run it in, relative to the project root (optional)"},
+		{Name: "timeout", Description: "Timeout such as 90s or 5m (optional)"},
+		{Name: "interactive", Description: "true to drive the command in the terminal without a timeout, false to give it no input (optional)"},
+	},
+}
+
+// stepToolDescriptions explain each tool in the step tool selector.
+var stepToolDescriptions = map[string]string{
+	"create_file":    "generate new files from the instruction",
+	"edit_file":      "change an existing file following the instruction",
+	"append_file":    "add content at the end of a file or next to a marker line",
+	"search_replace": "make a targeted change to an existing file",
+	"read_file":      "read a file as context for later steps",
+	"run_command":    "run a shell command",
+}
+
+var (
+	parameterNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	// placeholderPattern matches {param} references but not ${ENV} ones.
+	placeholderPattern = regexp.MustCompile(`(?:^|[^$])\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+)
+
+// NewAddActionCmd creates the add-action command, which builds an action step by step.
+func NewAddActionCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "add-action",
+		Short: "Define a new action interactively",
+		Long: `Walks you through defining an action and appends it to .magi.yaml: its name and description,
+the parameters it asks for, and its steps in execution order. Each step picks one of the tools
+the executor knows (create_file, edit_file, append_file, read_file, search_replace, run_command) and then
+asks only for the parameters that tool reads.
+
+Steps can reference the action parameters as {name} and environment variables as ${NAME}.
+The action goes through the same checks as 'magi project validate' before it is saved, and
+no LLM calls are made.
+
+Usage:
+  magi project add-action
+
+Examples:
+  # Define a new action and append it to .magi.yaml
+  magi project add-action`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !shared.IsInteractive() {
+				return fmt.Errorf("%w: add-action builds the action with prompts; edit %s instead", shared.ErrNonInteractive, configFileName)
+			}
+			cwd, err := os.Getwd()
+			if err != nil {
+				return fmt.Errorf("failed to get cwd: %w", err)
+			}
+			return runAddAction(cwd)
+		},
+	}
+	return cmd
+}
+
+func runAddAction(dir string) error {
+	config, err := loadMagiConfig(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		pterm.Warning.Println(".magi.yaml not found. Run 'magi project init' first.")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	action, err := promptAction(config)
+	if err != nil {
+		return err
+	}
+
+	if err := validationError(validateNewAction(config, action)); err != nil {
+		return err
+	}
+	for _, name := range undefinedPlaceholders(action) {
+		pterm.Warning.Printf("{%s} is not a parameter of '%s' and will be left as is.\n", name, action.Name)
+	}
+
+	printActionPlan(&action)
+	confirm, err := shared.Confirm(fmt.Sprintf("Append '%s' to %s?", action.Name, configFileName), true)
+	if err != nil {
+		return err
+	}
+	if !confirm {
+		pterm.Info.Println("Aborted by user.")
+		return nil
+	}
+
+	config.Actions = append(config.Actions, action)
+	if err := writeMagiConfig(dir, config); err != nil {
+		return err
+	}
+	pterm.Success.Printf("Added '%s' to %s. Run it with 'magi project exec %s'.\n", action.Name, configFileName, action.Name)
+	return nil
+}
+
+// promptAction asks for every field of a new action.
+func promptAction(config *MagiConfig) (Action, error) {
+	var action Action
+	var err error
+
+	action.Name, err = promptText("Action name (snake_case, e.g. create_slice)", true, func(name string) error {
+		if !snakeCasePattern.MatchString(name) {
+			return fmt.Errorf("'%s' must be snake_case", name)
+		}
+		if findAction(config, name) != nil {
+			return fmt.Errorf("action '%s' already exists", name)
+		}
+		return nil
+	})
+	if err != nil {
+		return action, err
+	}
+	if action.Description, err = promptText("Description", true, nil); err != nil {
+		return action, err
+	}
+
+	if action.Parameters, err = promptParameters(); err != nil {
+		return action, err
+	}
+	if action.Steps, err = promptSteps(action.Parameters); err != nil {
+		return action, err
+	}
+	return action, nil
+}
+
+func promptParameters() ([]ActionParameter, error) {
+	var params []ActionParameter
+	for {
+		more, err := shared.Confirm("Add a parameter?", len(params) == 0)
+		if err != nil {
+			return nil, err
+		}
+		if !more {
+			return params, nil
+		}
+
+		var param ActionParameter
+		param.Name, err = promptText("Parameter name", true, func(name string) error {
+			if !parameterNamePattern.MatchString(name) {
+				return fmt.Errorf("'%s' must contain only letters, digits and underscores", name)
+			}
+			for _, existing := range params {
+				if existing.Name == name {
+					return fmt.Errorf("parameter '%s' is already defined", name)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		if param.Description, err = promptText("Parameter description", false, nil); err != nil {
+			return nil, err
+		}
+		param.Type, err = pterm.DefaultInteractiveSelect.
+			WithOptions(actionParameterTypes).
+			WithDefaultOption(actionParameterTypes[0]).
+			Show("Parameter type")
+		if err != nil {
+			return nil, fmt.Errorf("parameter type selection failed: %w", err)
+		}
+		if param.Required, err = shared.Confirm("Is the parameter required?", true); err != nil {
+			return nil, err
+		}
+		params = append(params, param)
+	}
+}
+
+func promptSteps(params []ActionParameter) ([]ActionStep, error) {
+	if len(params) > 0 {
+		names := make([]string, 0, len(params))
+		for _, p := range params {
+			names = append(names, "{"+p.Name+"}")
+		}
+		pterm.Info.Printf("Steps can use %s in instructions and parameters.\n", strings.Join(names, ", "))
+	}
+
+	const done = "done"
+	options := make([]string, 0, len(knownStepTools)+1)
+	for _, tool := range knownStepTools {
+		options = append(options, fmt.Sprintf("%s - %s", tool, stepToolDescriptions[tool]))
+	}
+
+	var steps []ActionStep
+	for {
+		choices := options
+		if len(steps) > 0 {
+			choices = append(append([]string(nil), options...), done)
+		}
+		choice, err := pterm.DefaultInteractiveSelect.
+			WithOptions(choices).
+			Show(fmt.Sprintf("Tool for step %d", len(steps)+1))
+		if err != nil {
+			return nil, fmt.Errorf("step tool selection failed: %w", err)
+		}
+		if choice == done {
+			return steps, nil
+		}
+
+		step := ActionStep{Tool: strings.SplitN(choice, " ", 2)[0]}
+		if step.Instruction, err = promptText("Instruction", true, nil); err != nil {
+			return nil, err
+		}
+		for _, param := range stepToolParameters[step.Tool] {
+			value, err := promptText(fmt.Sprintf("%s: %s", param.Name, param.Description), param.Required, nil)
+			if err != nil {
+				return nil, err
+			}
+			if value == "" {
+				continue
+			}
+			if step.Parameters == nil {
+				step.Parameters = make(map[string]string)
+			}
+			step.Parameters[param.Name] = value
+		}
+		steps = append(steps, step)
+	}
+}
+
+// promptText asks for a line of text until it is non-empty (when required) and passes check.
+func promptText(prompt string, required bool, check func(string) error) (string, error) {
+	for {
+		value, err := pterm.DefaultInteractiveTextInput.Show(prompt)
+		if err != nil {
+			return "", fmt.Errorf("input failed: %w", err)
+		}
+		value = strings.TrimSpace(value)
+		switch {
+		case value == "" && required:
+			pterm.Warning.Println("A value is required.")
+		case value != "" && check != nil:
+			if err := check(value); err != nil {
+				pterm.Warning.Println(err.Error())
+				continue
+			}
+			return value, nil
+		default:
+			return value, nil
+		}
+	}
+}
+
+// validateNewAction runs the .magi.yaml checks on action alone, plus the name clash with the
+// actions already in config. Problems in the existing actions are not reported.
+func validateNewAction(config *MagiConfig, action Action) []configIssue {
+	issues := validateConfig(&MagiConfig{Actions: []Action{action}})
+	if action.Name != "" && findAction(config, action.Name) != nil {
+		issues = append(issues, configIssue{Message: fmt.Sprintf("action '%s' is defined more than once", action.Name)})
+	}
+	if len(action.Steps) == 0 {
+		issues = append(issues, configIssue{Message: fmt.Sprintf("action '%s' has no steps", action.Name)})
+	}
+	return issues
+}
+
+// undefinedPlaceholders returns the {name} references in the steps of action that do not
+// match one of its parameters, in order of first use.
+func undefinedPlaceholders(action Action) []string {
+	defined := make(map[string]bool, len(action.Parameters))
+	for _, p := range action.Parameters {
+		defined[p.Name] = true
+	}
+
+	var missing []string
+	check := func(text string) {
+		for _, match := range placeholderPattern.FindAllStringSubmatch(text, -1) {
+			if !defined[match[1]] {
+				defined[match[1]] = true
+				missing = append(missing, match[1])
+			}
+		}
+	}
+	for _, step := range action.Steps {
+		check(step.Instruction)
+		keys := make([]string, 0, len(step.Parameters))
+		for k := range step.Parameters {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			check(step.Parameters[k])
+		}
+	}
+	return missing
+}
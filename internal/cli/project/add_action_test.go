@@ -0,0 +1,70 @@
+package project
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewAddActionCmd(t *testing.T) {
+	cmd := NewAddActionCmd()
+	assert.Equal(t, "add-action", cmd.Use)
+	assert.NotEmpty(t, cmd.Short)
+	assert.NotEmpty(t, cmd.Long)
+	assert.NotNil(t, cmd.RunE)
+}
+
+func TestStepToolParametersCoverKnownTools(t *testing.T) {
+	for _, tool := range knownStepTools {
+		_, ok := stepToolParameters[tool]
+		assert.True(t, ok, "no step parameters for %s", tool)
+		assert.NotEmpty(t, stepToolDescriptions[tool], "no description for %s", tool)
+	}
+	assert.Len(t, stepToolParameters, len(knownStepTools))
+}
+
+func TestValidateNewAction(t *testing.T) {
+	config := &MagiConfig{Actions: []Action{
+		{Name: "create_slice"},
+		// Problems in existing actions are not reported for the new one.
+		{Name: "Bad Name"},
+	}}
+
+	valid := Action{
+		Name:  "run_tests",
+		Steps: []ActionStep{{Tool: "run_command", Instruction: "Run tests", Parameters: map[string]string{"command": "go test ./..."}}},
+	}
+	assert.Empty(t, validateNewAction(config, valid))
+
+	duplicate := valid
+	duplicate.Name = "create_slice"
+	issues := validateNewAction(config, duplicate)
+	if assert.Len(t, issues, 1) {
+		assert.Contains(t, issues[0].Message, "more than once")
+	}
+
+	noCommand := Action{Name: "build", Steps: []ActionStep{{Tool: "run_command", Instruction: "Build"}}}
+	issues = validateNewAction(config, noCommand)
+	if assert.Len(t, issues, 1) {
+		assert.Contains(t, issues[0].Message, "without a 'command' parameter")
+	}
+
+	issues = validateNewAction(config, Action{Name: "empty"})
+	if assert.Len(t, issues, 1) {
+		assert.Contains(t, issues[0].Message, "has no steps")
+	}
+}
+
+func TestUndefinedPlaceholders(t *testing.T) {
+	action := Action{
+		Name:       "create_slice",
+		Parameters: []ActionParameter{{Name: "name"}},
+		Steps: []ActionStep{
+			{Tool: "create_file", Instruction: "Create {name} in {module}"},
+			{Tool: "run_command", Instruction: "Test {module}", Parameters: map[string]string{
+				"command": "go test ./internal/{name}/... -run ${TEST_FILTER} {pkg}",
+			}},
+		},
+	}
+	assert.Equal(t, []string{"module", "pkg"}, undefinedPlaceholders(action))
+}
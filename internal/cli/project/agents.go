@@ -1,7 +1,6 @@
 package project
 
 import (
-	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -47,12 +46,16 @@ CRITICAL RULES FOR ACTIONS:
 - You MUST identify necessary parameters for each action (e.g., "name" for creating a component, "method" for a handler).
 - Parameters must have a name, description, type (string, bool, int), and required status.
 - You MUST define the list of steps to execute this action.
-    - Tools available: "create_file", "edit_file", "read_file", "search_replace", "run_command"
+    - Tools available: "create_file", "edit_file", "append_file", "read_file", "search_replace", "run_command"
     - "create_file": instruction should describe the file purpose.
     - "edit_file": instruction should describe the change.
+    - "append_file": adds content to a file without rewriting it (e.g., registering a route in an index file).
+        - "parameters": MUST contain "target" (the file). MAY contain "content" (literal text to insert; otherwise it is generated from the instruction) and "anchor" (text of a marker line) with "position" ("before" or "after", default "after") to insert next to that line instead of at the end.
     - "run_command": 
         - "instruction": A brief description of what the command does (e.g., "Run all tests").
         - "parameters": MUST contain a key "command" with the EXACT executable shell command (e.g., "go test ./...").
+        - "parameters" MAY contain a key "cwd" with a directory relative to the project root when the command must run in a subdirectory (e.g., "frontend").
+        - "parameters" MAY contain a key "interactive": "true" when the user drives the command in the terminal (no timeout applies), or "false" when the command must never wait for input. Leave it out otherwise; commands that prompt (e.g., "npm init") still get the terminal's input.
 
 Return the result in strictly valid JSON format matching this schema:
 {
@@ -90,7 +93,7 @@ Return the result in strictly valid JSON format matching this schema:
 		// Using strict system prompt instead of ResponseFormat due to library version limitations/mismatch
 	}
 
-	resp, err := service.ChatCompletion(context.Background(), req)
+	resp, err := service.ChatCompletion(shared.BaseContext(), req)
 	if err != nil {
 		return nil, err
 	}
@@ -115,31 +118,34 @@ func NewValidatorAgent(runtime *shared.RuntimeContext) *ValidatorAgent {
 
 // Validate checks the analysis result for common issues and attempts to fix them via LLM.
 func (v *ValidatorAgent) Validate(result *AnalysisResult) (*AnalysisResult, error) {
-	// 1. Check for invalid steps programmaticall first to save tokens
-	needsFix := false
-	var issues []string
-
-	for _, action := range result.Actions {
-		for i, step := range action.Steps {
-			if step.Tool == "run_command" {
-				if _, ok := step.Parameters["command"]; !ok {
-					needsFix = true
-					issues = append(issues, fmt.Sprintf("Action '%s' Step %d ('%s'): Missing 'command' parameter in run_command.", action.Name, i+1, step.Instruction))
-				}
-			}
-		}
-	}
-
-	if !needsFix {
+	// 1. Check for invalid steps programmatically first to save tokens
+	issues := analysisIssues(result)
+	if len(issues) == 0 {
 		return result, nil
 	}
 
 	// 2. Fix via LLM
+	return v.Fix(result, issues)
+}
+
+// analysisIssues runs the local .magi.yaml checks against the actions of an analysis result.
+func analysisIssues(result *AnalysisResult) []string {
+	var issues []string
+	for _, issue := range validateConfig(&MagiConfig{Actions: result.Actions}) {
+		issues = append(issues, issue.Message)
+	}
+	return issues
+}
+
+// Fix asks the LLM to correct the reported issues in result.
+func (v *ValidatorAgent) Fix(result *AnalysisResult, issues []string) (*AnalysisResult, error) {
 	resultJSON, _ := json.Marshal(result)
 	issuesStr := strings.Join(issues, "\n")
 
 	systemPrompt := `You are a Strict Configuration Validator.
 Your task is to FIX the provided Project Analysis JSON based on the reported validity issues.
+Action names must be unique and in snake_case.
+Step tools must be one of: ` + strings.Join(knownStepTools, ", ") + `.
 Verify that all "run_command" steps have a "command" parameter with the actual executable shell command.
 If the "instruction" contains the command, move it to "parameters.command" and keep "instruction" as a description.
 
@@ -163,7 +169,7 @@ Return the CORRECTED JSON strictly matching the input schema.`
 		Temperature: 0.1,
 	}
 
-	resp, err := service.ChatCompletion(context.Background(), req)
+	resp, err := service.ChatCompletion(shared.BaseContext(), req)
 	if err != nil {
 		return nil, err
 	}
@@ -199,8 +205,27 @@ type FileContent struct {
 	Content string `json:"content"`
 }
 
-// PlanGeneration asks LLM which files to create.
-func (g *GeneratorAgent) PlanGeneration(rootPath string, architecture, projectType string, action Action, params map[string]string) (*FileGenerationPlan, error) {
+// ReferenceFile is a file read by an earlier read_file step, given to later steps as context.
+type ReferenceFile struct {
+	Path    string
+	Content string
+}
+
+// formatReferenceFiles renders refs as a prompt section, or returns "" when there are none.
+func formatReferenceFiles(refs []ReferenceFile) string {
+	if len(refs) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("\n\nReference files (read by earlier steps; use them as context, do not copy them blindly):\n")
+	for _, ref := range refs {
+		fmt.Fprintf(&b, "\n--- %s ---\n%s\n", ref.Path, strings.TrimRight(ref.Content, "\n"))
+	}
+	return b.String()
+}
+
+// PlanGeneration asks LLM which files to create. refs are files read by earlier steps.
+func (g *GeneratorAgent) PlanGeneration(rootPath string, architecture, projectType string, action Action, params map[string]string, refs []ReferenceFile) (*FileGenerationPlan, error) {
 	// 1. Build Prompt
 	paramsJSON, _ := json.Marshal(params)
 	systemPrompt := fmt.Sprintf(`You are an expert Software Architect for a %s project (%s).
@@ -214,7 +239,7 @@ Schema:
   "files": [
     { "path": "path/to/file.go", "description": "Brief description" }
   ]
-}`, architecture, projectType, action.Name, action.Description, string(paramsJSON))
+}`, architecture, projectType, action.Name, action.Description, string(paramsJSON)) + formatReferenceFiles(refs)
 
 	// 2. Call LLM (Light model likely enough for planning)
 	service, err := llm.NewServiceBuilder(g.runtime).UseLightModel().Build()
@@ -230,7 +255,7 @@ Schema:
 		Temperature: 0.1,
 	}
 
-	resp, err := service.ChatCompletion(context.Background(), req)
+	resp, err := service.ChatCompletion(shared.BaseContext(), req)
 	if err != nil {
 		return nil, err
 	}
@@ -244,8 +269,8 @@ Schema:
 	return &plan, nil
 }
 
-// GenerateContent generates the content for a specific file.
-func (g *GeneratorAgent) GenerateContent(rootPath, architecture, projectType string, action Action, params map[string]string, file GeneratedFile) (*FileContent, error) {
+// GenerateContent generates the content for a specific file. refs are files read by earlier steps.
+func (g *GeneratorAgent) GenerateContent(rootPath, architecture, projectType string, action Action, params map[string]string, file GeneratedFile, refs []ReferenceFile) (*FileContent, error) {
 	paramsJSON, _ := json.Marshal(params)
 	systemPrompt := fmt.Sprintf(`You are an expert Software Architect for a %s project (%s).
 Your task is to GENERATE the content for the file "%s".
@@ -254,7 +279,7 @@ Parameters: %s
 File Description: %s
 
 Return ONLY the code content for the file. No markdown code blocks, no explanations. 
-If it is a go file, include the package declaration.`, architecture, projectType, file.Path, action.Name, action.Description, string(paramsJSON), file.Description)
+If it is a go file, include the package declaration.`, architecture, projectType, file.Path, action.Name, action.Description, string(paramsJSON), file.Description) + formatReferenceFiles(refs)
 
 	service, err := llm.NewServiceBuilder(g.runtime).UseHeavyModel().Build()
 	if err != nil {
@@ -269,16 +294,14 @@ If it is a go file, include the package declaration.`, architecture, projectType
 		Temperature: 0.1,
 	}
 
-	resp, err := service.ChatCompletion(context.Background(), req)
+	resp, err := service.ChatCompletion(shared.BaseContext(), req)
 	if err != nil {
 		return nil, err
 	}
 
-	// Strip markdown code blocks if present
-	content := strings.TrimSpace(resp)
-	content = strings.TrimPrefix(content, "```go")
-	content = strings.TrimPrefix(content, "```")
-	content = strings.TrimSuffix(content, "```")
+	content := ensureGoSyntax(file.Path, stripCodeFence(resp), func(content string, parseErr error) (string, error) {
+		return fixGoSyntax(service, file.Path, content, parseErr)
+	})
 
 	return &FileContent{
 		Path:    file.Path,
@@ -286,8 +309,8 @@ If it is a go file, include the package declaration.`, architecture, projectType
 	}, nil
 }
 
-// UpdateContent updates an existing file based on instructions.
-func (g *GeneratorAgent) UpdateContent(filePath, originalContent, instruction, architecture, projectType string) (*FileContent, error) {
+// UpdateContent updates an existing file based on instructions. refs are files read by earlier steps.
+func (g *GeneratorAgent) UpdateContent(filePath, originalContent, instruction, architecture, projectType string, refs []ReferenceFile) (*FileContent, error) {
 	systemPrompt := fmt.Sprintf(`You are an expert Software Architect for a %s project (%s).
 Your task is to UPDATE the content of the file "%s" based on the user's instruction.
 
@@ -298,7 +321,7 @@ User Instruction:
 %s
 
 Return ONLY the updated code content. No markdown code blocks, no explanations. 
-Maintain the existing style and conventions.`, architecture, projectType, filePath, originalContent, instruction)
+Maintain the existing style and conventions.`, architecture, projectType, filePath, originalContent, instruction) + formatReferenceFiles(refs)
 
 	service, err := llm.NewServiceBuilder(g.runtime).UseHeavyModel().Build()
 	if err != nil {
@@ -313,16 +336,14 @@ Maintain the existing style and conventions.`, architecture, projectType, filePa
 		Temperature: 0.1,
 	}
 
-	resp, err := service.ChatCompletion(context.Background(), req)
+	resp, err := service.ChatCompletion(shared.BaseContext(), req)
 	if err != nil {
 		return nil, err
 	}
 
-	// Strip markdown code blocks if present
-	content := strings.TrimSpace(resp)
-	content = strings.TrimPrefix(content, "```go")
-	content = strings.TrimPrefix(content, "```")
-	content = strings.TrimSuffix(content, "```")
+	content := ensureGoSyntax(filePath, stripCodeFence(resp), func(content string, parseErr error) (string, error) {
+		return fixGoSyntax(service, filePath, content, parseErr)
+	})
 
 	return &FileContent{
 		Path:    filePath,
@@ -330,6 +351,45 @@ Maintain the existing style and conventions.`, architecture, projectType, filePa
 	}, nil
 }
 
+// GenerateSnippet generates the lines an append_file step adds to filePath, at the end of
+// the file or next to the anchor line. refs are files read by earlier steps.
+func (g *GeneratorAgent) GenerateSnippet(filePath, currentContent, instruction, anchor, architecture, projectType string, refs []ReferenceFile) (string, error) {
+	placement := "at the end of the file"
+	if anchor != "" {
+		placement = fmt.Sprintf("next to the line containing %q", anchor)
+	}
+	systemPrompt := fmt.Sprintf(`You are an expert Software Architect for a %s project (%s).
+Your task is to write the lines to ADD to the file "%s", %s, based on the user's instruction.
+
+Current Content:
+%s
+
+User Instruction:
+%s
+
+Return ONLY the new lines to insert, not the whole file. No markdown code blocks, no explanations.
+Maintain the existing style and conventions.`, architecture, projectType, filePath, placement, currentContent, instruction) + formatReferenceFiles(refs)
+
+	service, err := llm.NewServiceBuilder(g.runtime).UseHeavyModel().Build()
+	if err != nil {
+		return "", fmt.Errorf("failed to build LLM service: %w", err)
+	}
+
+	req := llm.ChatCompletionRequest{
+		Messages: []llm.ChatMessage{
+			{Role: "system", Content: systemPrompt},
+			{Role: "user", Content: "Write the lines to add."},
+		},
+		Temperature: 0.1,
+	}
+
+	resp, err := service.ChatCompletion(shared.BaseContext(), req)
+	if err != nil {
+		return "", err
+	}
+	return strings.Trim(stripCodeFence(resp), "\n"), nil
+}
+
 // ReviewerAgent checks project compliance.
 type ReviewerAgent struct {
 	runtime *shared.RuntimeContext
@@ -380,7 +440,7 @@ Format the output as a bulleted list of issues.`
 		Temperature: 0.1,
 	}
 
-	resp, err := service.ChatCompletion(context.Background(), req)
+	resp, err := service.ChatCompletion(shared.BaseContext(), req)
 	if err != nil {
 		return "", err
 	}
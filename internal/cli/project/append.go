@@ -0,0 +1,97 @@
+package project
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pterm/pterm"
+)
+
+// validAppendPosition reports whether position is a value append_file accepts.
+func validAppendPosition(position string) bool {
+	switch strings.ToLower(strings.TrimSpace(position)) {
+	case "", "before", "after":
+		return true
+	}
+	return false
+}
+
+// handleAppendFile adds content to the target file instead of rewriting it: at the end, or
+// next to the first line containing the anchor. The content is the literal 'content'
+// parameter or, without one, generated from the instruction.
+func (e *Executor) handleAppendFile(step ActionStep) error {
+	targetFile := e.resolveVariable(step.Parameters["target"])
+	if targetFile == "" {
+		return fmt.Errorf("append_file needs a 'target' parameter")
+	}
+	anchor := e.resolveVariable(step.Parameters["anchor"])
+	position := strings.ToLower(strings.TrimSpace(step.Parameters["position"]))
+
+	fullPath, err := e.outputPath(targetFile)
+	if err != nil {
+		return err
+	}
+	original, err := os.ReadFile(e.sourcePath(targetFile))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to read target file '%s': %w", targetFile, err)
+	}
+
+	content := e.resolveVariable(step.Parameters["content"])
+	if content == "" {
+		generated, err := e.Agent.GenerateSnippet(targetFile, string(original), step.Instruction, anchor, e.Architecture, e.ProjectType, e.referenceFiles(targetFile))
+		if err != nil {
+			return fmt.Errorf("failed to generate content: %w", err)
+		}
+		content = generated
+	}
+
+	updated, err := insertContent(string(original), content, anchor, position)
+	if err != nil {
+		return fmt.Errorf("cannot append to '%s': %w", targetFile, err)
+	}
+
+	pterm.Info.Printf("Content to add to %s:\n%s\n", targetFile, content)
+	if e.confirmStep("Add it to "+targetFile+"?", true) {
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+			return err
+		}
+		if err := writeGeneratedFile(fullPath, updated); err != nil {
+			return err
+		}
+		pterm.Success.Println("File updated: " + targetFile)
+	}
+	return nil
+}
+
+// insertContent returns original with content added on its own lines: at the end when
+// anchor is empty, otherwise after (or, with position "before", before) the first line
+// containing anchor. A missing anchor is an error.
+func insertContent(original, content, anchor, position string) (string, error) {
+	if !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	if anchor == "" {
+		if original != "" && !strings.HasSuffix(original, "\n") {
+			original += "\n"
+		}
+		return original + content, nil
+	}
+
+	lines := strings.SplitAfter(original, "\n")
+	for i, line := range lines {
+		if !strings.Contains(line, anchor) {
+			continue
+		}
+		if position == "before" {
+			return strings.Join(lines[:i], "") + content + strings.Join(lines[i:], ""), nil
+		}
+		if !strings.HasSuffix(line, "\n") {
+			lines[i] = line + "\n"
+		}
+		return strings.Join(lines[:i+1], "") + content + strings.Join(lines[i+1:], ""), nil
+	}
+	return "", fmt.Errorf("no line contains the anchor %q", anchor)
+}
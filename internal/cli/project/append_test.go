@@ -0,0 +1,127 @@
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInsertContent(t *testing.T) {
+	routes := "func routes() {\n\t// routes\n}\n"
+	cases := []struct {
+		name     string
+		original string
+		anchor   string
+		position string
+		want     string
+	}{
+		{name: "end", original: "a\nb", want: "a\nb\nnew\n"},
+		{name: "empty file", want: "new\n"},
+		{name: "after anchor", original: routes, anchor: "// routes", want: "func routes() {\n\t// routes\nnew\n}\n"},
+		{name: "before anchor", original: routes, anchor: "// routes", position: "before", want: "func routes() {\nnew\n\t// routes\n}\n"},
+		{name: "anchor on last line", original: "a\n// end", anchor: "// end", want: "a\n// end\nnew\n"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := insertContent(tc.original, "new", tc.anchor, tc.position)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+
+	_, err := insertContent(routes, "new", "// missing", "")
+	assert.ErrorContains(t, err, `"// missing"`)
+}
+
+func TestHandleAppendFile(t *testing.T) {
+	root := t.TempDir()
+	index := filepath.Join(root, "routes", "index.ts")
+	require.NoError(t, os.MkdirAll(filepath.Dir(index), 0755))
+	require.NoError(t, os.WriteFile(index, []byte("const routes = [\n  // magi:routes\n];\n"), 0644))
+
+	e := (&Executor{Cwd: root, CurrentParams: map[string]string{"name": "users"}}).WithAutoConfirm(true)
+	require.NoError(t, e.handleAppendFile(ActionStep{Tool: "append_file", Parameters: map[string]string{
+		"target":   "routes/index.ts",
+		"content":  "  {name}Route,",
+		"anchor":   "magi:routes",
+		"position": "before",
+	}}))
+	data, err := os.ReadFile(index)
+	require.NoError(t, err)
+	assert.Equal(t, "const routes = [\n  usersRoute,\n  // magi:routes\n];\n", string(data))
+
+	require.NoError(t, e.handleAppendFile(ActionStep{Tool: "append_file", Parameters: map[string]string{
+		"target":  "CHANGELOG.txt",
+		"content": "added {name}",
+	}}))
+	data, err = os.ReadFile(filepath.Join(root, "CHANGELOG.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "added users\n", string(data))
+
+	err = e.handleAppendFile(ActionStep{Tool: "append_file", Parameters: map[string]string{
+		"target":  "routes/index.ts",
+		"content": "x",
+		"anchor":  "missing marker",
+	}})
+	assert.ErrorContains(t, err, "missing marker")
+}
+
+func TestValidateConfigAppendFile(t *testing.T) {
+	config := &MagiConfig{Actions: []Action{{Name: "add_route", Steps: []ActionStep{
+		{Tool: "append_file"},
+		{Tool: "append_file", Parameters: map[string]string{"target": "index.ts", "position": "middle"}},
+		{Tool: "append_file", Parameters: map[string]string{"target": "index.ts", "position": "Before"}},
+	}}}}
+
+	issues := validateConfig(config)
+	require.Len(t, issues, 2)
+	assert.Contains(t, issues[0].Message, "step 1 is an append_file without a 'target' parameter")
+	assert.Contains(t, issues[1].Message, "step 2 has position 'middle'")
+}
+
+func TestAppendFileWithOutputDir(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "routes.txt"), []byte("home\n"), 0644))
+
+	e := (&Executor{Cwd: root}).WithAutoConfirm(true).WithOutputDir("preview")
+	step := ActionStep{Tool: "append_file", Parameters: map[string]string{"target": "routes.txt", "content": "users"}}
+	require.NoError(t, e.handleAppendFile(step))
+	require.NoError(t, e.handleAppendFile(ActionStep{Tool: "append_file", Parameters: map[string]string{"target": "routes.txt", "content": "orders"}}))
+
+	data, err := os.ReadFile(filepath.Join(root, "routes.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "home\n", string(data), "the project file is left alone")
+
+	data, err = os.ReadFile(filepath.Join(root, "preview", "routes.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "home\nusers\norders\n", string(data), "later steps build on the preview copy")
+}
+
+func TestOutputPathStaysInsideOutputDir(t *testing.T) {
+	root := t.TempDir()
+	e := (&Executor{Cwd: root}).WithOutputDir("preview")
+
+	path, err := e.outputPath("internal/api/handler.go")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(root, "preview", "internal", "api", "handler.go"), path)
+
+	for _, rel := range []string{"../src/main.go", "a/../../main.go", "/etc/hosts", "~/main.go"} {
+		_, err := e.outputPath(rel)
+		assert.Error(t, err, rel)
+	}
+
+	// An escaping append target is rejected before anything is read or written.
+	err = e.handleAppendFile(ActionStep{Tool: "append_file", Parameters: map[string]string{"target": "../main.go", "content": "x"}})
+	require.Error(t, err)
+	_, statErr := os.Stat(filepath.Join(root, "main.go"))
+	assert.True(t, os.IsNotExist(statErr))
+}
+
+func TestResolveOutputDir(t *testing.T) {
+	assert.Equal(t, "", resolveOutputDir("/repo", ""))
+	assert.Equal(t, filepath.Join("/repo", "preview"), resolveOutputDir("/repo", "preview"))
+	assert.Equal(t, "/tmp/preview", resolveOutputDir("/repo", "/tmp/preview"))
+}
@@ -13,11 +13,15 @@ func NewProjectCmd() *cobra.Command {
 It helps verify project structure, create new features, and manage architectural rules.
 
 Available subcommands:
-  init    Initialize project rules and configuration
-  create  Create new features/components
-  check   Check compliance with project rules
-  update  Update existing structures
-  redo    Re-analyze project structure
+  init     Initialize project rules and configuration
+  create   Create new features/components
+  check    Check compliance with project rules
+  update   Update existing structures
+  redo     Re-analyze project structure
+  list     List the actions defined in .magi.yaml
+  describe Show the parameters and steps of an action
+  validate Check the actions defined in .magi.yaml
+  add-action Define a new action interactively
 
 Usage:
   magi project [command]
@@ -25,7 +29,8 @@ Usage:
 Examples:
   magi project init
   magi project create slice --name my-feature
-  magi project check`,
+  magi project check
+  magi project describe create_slice`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return cmd.Help()
 		},
@@ -37,6 +42,9 @@ Examples:
 	cmd.AddCommand(NewUpdateCmd())
 	cmd.AddCommand(NewRedoCmd())
 	cmd.AddCommand(NewListCmd())
+	cmd.AddCommand(NewDescribeCmd())
+	cmd.AddCommand(NewValidateCmd())
+	cmd.AddCommand(NewAddActionCmd())
 
 	return cmd
 }
@@ -16,7 +16,7 @@ func TestNewProjectCmd(t *testing.T) {
 	assert.True(t, cmd.HasSubCommands())
 
 	// Check for expected subcommands
-	expectedParams := []string{"init", "exec", "check", "update", "redo", "list"}
+	expectedParams := []string{"init", "exec", "check", "update", "redo", "list", "describe", "validate"}
 	foundCount := 0
 	for _, sub := range cmd.Commands() {
 		for _, expected := range expectedParams {
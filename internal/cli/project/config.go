@@ -0,0 +1,199 @@
+package project
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const configFileName = ".magi.yaml"
+
+// knownStepTools lists the tools the Executor knows how to run.
+var knownStepTools = []string{"create_file", "edit_file", "append_file", "read_file", "search_replace", "run_command"}
+
+var snakeCasePattern = regexp.MustCompile(`^[a-z][a-z0-9]*(_[a-z0-9]+)*$`)
+
+// configPositions records the line of each action and step in the source file so
+// validation issues can point at the right place.
+type configPositions struct {
+	actions []int
+	steps   [][]int
+}
+
+func (p configPositions) actionLine(action int) int {
+	if action < len(p.actions) {
+		return p.actions[action]
+	}
+	return 0
+}
+
+func (p configPositions) stepLine(action, step int) int {
+	if action < len(p.steps) && step < len(p.steps[action]) {
+		return p.steps[action][step]
+	}
+	return p.actionLine(action)
+}
+
+// configIssue is a single problem found while validating .magi.yaml.
+type configIssue struct {
+	Line    int    `json:"line,omitempty"`
+	Message string `json:"message"`
+}
+
+func (i configIssue) String() string {
+	if i.Line > 0 {
+		return fmt.Sprintf("%s:%d: %s", configFileName, i.Line, i.Message)
+	}
+	return fmt.Sprintf("%s: %s", configFileName, i.Message)
+}
+
+// configValidationError reports every issue found in .magi.yaml at once.
+type configValidationError struct {
+	Issues []configIssue
+}
+
+func (e *configValidationError) Error() string {
+	lines := make([]string, 0, len(e.Issues)+1)
+	lines = append(lines, fmt.Sprintf("%s has %d problem(s):", configFileName, len(e.Issues)))
+	for _, issue := range e.Issues {
+		lines = append(lines, "  "+issue.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// loadMagiConfig reads and parses the .magi.yaml file found in dir. The returned error
+// wraps os.ErrNotExist when the file is missing so callers can point users to 'magi project init'.
+func loadMagiConfig(dir string) (*MagiConfig, error) {
+	data, err := os.ReadFile(filepath.Join(dir, configFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%s not found: %w", configFileName, os.ErrNotExist)
+		}
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+	return parseMagiConfig(data)
+}
+
+func parseMagiConfig(data []byte) (*MagiConfig, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("failed to parse config: %w", err)
+	}
+
+	var config MagiConfig
+	if len(root.Content) == 0 {
+		return &config, nil
+	}
+	if err := root.Decode(&config); err != nil {
+		return nil, fmt.Errorf("failed to parse config: %w", err)
+	}
+	config.positions = positionsFromNode(&root)
+	return &config, nil
+}
+
+func positionsFromNode(root *yaml.Node) configPositions {
+	var positions configPositions
+	actions := mappingValue(root.Content[0], "actions")
+	if actions == nil || actions.Kind != yaml.SequenceNode {
+		return positions
+	}
+	for _, action := range actions.Content {
+		positions.actions = append(positions.actions, action.Line)
+		var stepLines []int
+		if steps := mappingValue(action, "steps"); steps != nil && steps.Kind == yaml.SequenceNode {
+			for _, step := range steps.Content {
+				stepLines = append(stepLines, step.Line)
+			}
+		}
+		positions.steps = append(positions.steps, stepLines)
+	}
+	return positions
+}
+
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// validateConfig checks the action definitions without calling the LLM: names must be
+// unique snake_case, steps must use a known tool, run_command steps need a command and
+// append_file steps a target.
+func validateConfig(config *MagiConfig) []configIssue {
+	var issues []configIssue
+	seen := make(map[string]bool)
+
+	for i, action := range config.Actions {
+		line := config.positions.actionLine(i)
+		label := action.Name
+		switch {
+		case action.Name == "":
+			label = fmt.Sprintf("#%d", i+1)
+			issues = append(issues, configIssue{Line: line, Message: fmt.Sprintf("action %s has no name", label)})
+		case !snakeCasePattern.MatchString(action.Name):
+			issues = append(issues, configIssue{Line: line, Message: fmt.Sprintf("action '%s' must be snake_case (e.g. create_slice)", action.Name)})
+		case seen[action.Name]:
+			issues = append(issues, configIssue{Line: line, Message: fmt.Sprintf("action '%s' is defined more than once", action.Name)})
+		}
+		seen[action.Name] = true
+		if action.Name != "" {
+			label = "'" + action.Name + "'"
+		}
+
+		for j, step := range action.Steps {
+			stepLine := config.positions.stepLine(i, j)
+			switch {
+			case step.Tool == "":
+				issues = append(issues, configIssue{Line: stepLine, Message: fmt.Sprintf("action %s step %d has no tool", label, j+1)})
+			case !isKnownStepTool(step.Tool):
+				issues = append(issues, configIssue{Line: stepLine, Message: fmt.Sprintf("action %s step %d uses unknown tool '%s' (expected one of %s)", label, j+1, step.Tool, strings.Join(knownStepTools, ", "))})
+			case step.Tool == "run_command" && strings.TrimSpace(step.Parameters["command"]) == "":
+				issues = append(issues, configIssue{Line: stepLine, Message: fmt.Sprintf("action %s step %d is a run_command without a 'command' parameter", label, j+1)})
+			case step.Tool == "run_command" && !validInteractive(step.Parameters["interactive"]):
+				issues = append(issues, configIssue{Line: stepLine, Message: fmt.Sprintf("action %s step %d has interactive '%s' (expected true or false)", label, j+1, step.Parameters["interactive"])})
+			case step.Tool == "append_file" && strings.TrimSpace(step.Parameters["target"]) == "":
+				issues = append(issues, configIssue{Line: stepLine, Message: fmt.Sprintf("action %s step %d is an append_file without a 'target' parameter", label, j+1)})
+			case step.Tool == "append_file" && !validAppendPosition(step.Parameters["position"]):
+				issues = append(issues, configIssue{Line: stepLine, Message: fmt.Sprintf("action %s step %d has position '%s' (expected before or after)", label, j+1, step.Parameters["position"])})
+			}
+		}
+	}
+	return issues
+}
+
+func isKnownStepTool(tool string) bool {
+	for _, known := range knownStepTools {
+		if tool == known {
+			return true
+		}
+	}
+	return false
+}
+
+// validationError wraps issues in a configValidationError, or returns nil when there are none.
+func validationError(issues []configIssue) error {
+	if len(issues) == 0 {
+		return nil
+	}
+	return &configValidationError{Issues: issues}
+}
+
+// findAction returns the action called name, or nil when the config does not define it.
+func findAction(config *MagiConfig, name string) *Action {
+	for i := range config.Actions {
+		if config.Actions[i].Name == name {
+			return &config.Actions[i]
+		}
+	}
+	return nil
+}
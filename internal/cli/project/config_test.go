@@ -0,0 +1,71 @@
+package project
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateConfigReportsAllIssues(t *testing.T) {
+	data := []byte(`architecture: Vertical Slice
+actions:
+  - name: create-slice
+    steps:
+      - tool: create_file
+        instruction: Create the handler
+      - tool: generate_code
+        instruction: Generate code
+  - name: run_tests
+    steps:
+      - tool: run_command
+        instruction: go test ./...
+  - name: run_tests
+`)
+
+	config, err := parseMagiConfig(data)
+	require.NoError(t, err)
+
+	issues := validateConfig(config)
+	require.Len(t, issues, 4)
+	assert.Equal(t, 3, issues[0].Line)
+	assert.Contains(t, issues[0].Message, "must be snake_case")
+	assert.Equal(t, 7, issues[1].Line)
+	assert.Contains(t, issues[1].Message, "unknown tool 'generate_code'")
+	assert.Equal(t, 11, issues[2].Line)
+	assert.Contains(t, issues[2].Message, "without a 'command' parameter")
+	assert.Equal(t, 13, issues[3].Line)
+	assert.Contains(t, issues[3].Message, "defined more than once")
+
+	err = validationError(issues)
+	var validationErr *configValidationError
+	require.True(t, errors.As(err, &validationErr))
+	assert.Contains(t, err.Error(), ".magi.yaml:7: action 'create-slice' step 2")
+}
+
+func TestValidateConfigValid(t *testing.T) {
+	config, err := parseMagiConfig([]byte(describeTestConfig))
+	require.NoError(t, err)
+
+	assert.Empty(t, validateConfig(config))
+	assert.NoError(t, validationError(nil))
+}
+
+func TestValidateConfigWithoutPositions(t *testing.T) {
+	config := &MagiConfig{Actions: []Action{{Name: "Bad Name"}}}
+
+	issues := validateConfig(config)
+	require.Len(t, issues, 1)
+	assert.Equal(t, ".magi.yaml: action 'Bad Name' must be snake_case (e.g. create_slice)", issues[0].String())
+}
+
+func TestParseMagiConfigTypeErrors(t *testing.T) {
+	_, err := parseMagiConfig([]byte("actions: not-a-list\n"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "line 1")
+
+	config, err := parseMagiConfig(nil)
+	require.NoError(t, err)
+	assert.Empty(t, config.Actions)
+}
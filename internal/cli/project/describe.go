@@ -0,0 +1,105 @@
+package project
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+// NewDescribeCmd creates the describe command, which prints the full plan of an action.
+func NewDescribeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "describe <action>",
+		Short: "Show the parameters and steps of an action",
+		Long: `Shows everything an action defined in .magi.yaml will do before you run it: its description,
+the parameters it asks for, and each step in execution order with the tool it uses.
+
+Nothing is executed and no LLM calls are made.
+
+Usage:
+  magi project describe <action>
+
+Examples:
+  # Inspect the steps of the create_slice action
+  magi project describe create_slice`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cwd, err := os.Getwd()
+			if err != nil {
+				return fmt.Errorf("failed to get cwd: %w", err)
+			}
+
+			config, err := loadMagiConfig(cwd)
+			if errors.Is(err, os.ErrNotExist) {
+				pterm.Warning.Println(".magi.yaml not found. Run 'magi project init' first.")
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+
+			action := findAction(config, args[0])
+			if action == nil {
+				return fmt.Errorf("action '%s' not found (run 'magi project list' to see available actions)", args[0])
+			}
+
+			printActionPlan(action)
+			return nil
+		},
+	}
+	return cmd
+}
+
+func printActionPlan(action *Action) {
+	pterm.DefaultSection.Println(action.Name)
+	if action.Description != "" {
+		pterm.Println(action.Description)
+	}
+
+	pterm.DefaultSection.WithLevel(2).Println("Parameters")
+	if len(action.Parameters) == 0 {
+		pterm.Info.Println("This action takes no parameters.")
+	} else {
+		tableData := [][]string{{"Name", "Type", "Required", "Description"}}
+		for _, p := range action.Parameters {
+			required := "no"
+			if p.Required {
+				required = "yes"
+			}
+			tableData = append(tableData, []string{p.Name, p.Type, required, p.Description})
+		}
+		pterm.DefaultTable.WithHasHeader().WithData(tableData).Render()
+	}
+
+	pterm.DefaultSection.WithLevel(2).Println("Steps")
+	if len(action.Steps) == 0 {
+		pterm.Info.Println("No steps defined. 'magi project exec' will ask the LLM to plan the files to generate.")
+		return
+	}
+	for _, line := range formatActionSteps(action.Steps) {
+		pterm.Println(line)
+	}
+}
+
+// formatActionSteps renders each step as a numbered line followed by its static parameters.
+func formatActionSteps(steps []ActionStep) []string {
+	var lines []string
+	for i, step := range steps {
+		lines = append(lines, fmt.Sprintf("%d. [%s] %s", i+1, step.Tool, strings.TrimSpace(step.Instruction)))
+
+		keys := make([]string, 0, len(step.Parameters))
+		for k := range step.Parameters {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			lines = append(lines, fmt.Sprintf("     %s: %s", k, step.Parameters[k]))
+		}
+	}
+	return lines
+}
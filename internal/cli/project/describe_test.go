@@ -0,0 +1,83 @@
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const describeTestConfig = `actions:
+  - name: create_slice
+    description: Create a vertical slice
+    parameters:
+      - name: name
+        type: string
+        required: true
+    steps:
+      - tool: create_file
+        instruction: Create the handler
+        parameters:
+          path: internal/{{name}}/handler.go
+      - tool: run_command
+        instruction: Build the project
+        parameters:
+          command: go build ./...
+`
+
+func TestNewDescribeCmd(t *testing.T) {
+	cmd := NewDescribeCmd()
+	assert.Equal(t, "describe <action>", cmd.Use)
+	assert.NotEmpty(t, cmd.Short)
+	assert.NotEmpty(t, cmd.Long)
+	assert.NotNil(t, cmd.RunE)
+	assert.Error(t, cmd.Args(cmd, nil))
+}
+
+func TestLoadMagiConfig(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := loadMagiConfig(dir)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, configFileName), []byte(describeTestConfig), 0644))
+	config, err := loadMagiConfig(dir)
+	require.NoError(t, err)
+
+	action := findAction(config, "create_slice")
+	require.NotNil(t, action)
+	assert.Len(t, action.Steps, 2)
+	assert.Nil(t, findAction(config, "missing"))
+}
+
+func TestFormatActionSteps(t *testing.T) {
+	lines := formatActionSteps([]ActionStep{
+		{Tool: "create_file", Instruction: "Create the handler\n", Parameters: map[string]string{"path": "a.go", "mode": "0644"}},
+		{Tool: "run_command", Instruction: "go build ./..."},
+	})
+
+	assert.Equal(t, []string{
+		"1. [create_file] Create the handler",
+		"     mode: 0644",
+		"     path: a.go",
+		"2. [run_command] go build ./...",
+	}, lines)
+}
+
+func TestDescribeCmdRun(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, configFileName), []byte(describeTestConfig), 0644))
+	t.Chdir(dir)
+
+	cmd := NewDescribeCmd()
+	cmd.SetArgs([]string{"missing"})
+	err := cmd.Execute()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "action 'missing' not found")
+
+	cmd = NewDescribeCmd()
+	cmd.SetArgs([]string{"create_slice"})
+	assert.NoError(t, cmd.Execute())
+}
@@ -1,6 +1,7 @@
 package project
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -8,16 +9,70 @@ import (
 	"github.com/MagdielCAS/magi-cli/pkg/shared"
 	"github.com/pterm/pterm"
 	"github.com/spf13/cobra"
-	"gopkg.in/yaml.v3"
 )
 
 // NewExecCmd creates the exec command
 func NewExecCmd() *cobra.Command {
+	var yes bool
+	var allowCommands []string
+	var force bool
+	var outputDir string
+
 	cmd := &cobra.Command{
 		Use:   "exec [action]",
 		Short: "Execute a defined action",
-		Long:  `Executes a project action (e.g., create a slice, add a feature) defined in .magi.yaml.`,
-		Args:  cobra.MaximumNArgs(1),
+		Long: `Executes a project action (e.g., create a slice, add a feature) defined in .magi.yaml.
+
+The actions are validated before anything runs: names must be snake_case, steps must use one of
+create_file, edit_file, append_file, read_file, search_replace or run_command, run_command steps
+need a 'command' parameter and append_file steps a 'target'. Every problem is reported at once
+with its line in .magi.yaml.
+
+Step instructions and parameters are templates. ${NAME} is replaced with the environment
+variable NAME (${NAME:-default} supplies a fallback; unset variables become empty) and $$
+produces a literal $. Environment references are expanded first, then {param} is replaced
+with the value entered for the action parameter, so parameter values are never expanded.
+
+run_command steps run in the project root. Set the optional 'cwd' step parameter (for example
+"frontend" or "packages/{name}") to run the command in a subdirectory; it must exist and stay
+inside the project.
+
+read_file steps load their 'target' file as context: the following create_file, edit_file,
+append_file and search_replace steps get the content of every file read so far in their prompts.
+The target must stay inside the project, and secrets are redacted from the content first.
+
+append_file steps add to their 'target' file (creating it when missing) instead of rewriting it,
+for example to register a route in an index file. The 'content' parameter is inserted as is;
+without it the content is generated from the instruction. With an 'anchor' parameter the content
+goes after the first line containing that text ('position: before' puts it before the line).
+
+Commands time out after 10 minutes by default. Set a 'timeout' step parameter (e.g. "90s" or
+"300") or the project.command_timeout setting to change it; on failure the last lines of output
+are included in the error. Commands get the terminal's input, so prompting tools such as npm init
+work; steps with 'interactive: "true"' also ignore the timeout, and steps with
+'interactive: "false"' get no input and fail instead of waiting for it.
+
+Each file write and command asks for confirmation; pick "Yes to all remaining steps" or pass
+--yes to apply the rest of the action without asking. Commands that look destructive (rm, mv,
+git push, git reset, kubectl delete, ...) still ask unless they match an --allow-commands prefix.
+That includes commands wrapped in env, sudo, xargs, nice or timeout, the commands of a sh -c or
+bash -c payload and find with -delete or -exec rm; shells running a script always ask. Commands
+are split into arguments like a shell would, honoring quotes, but run without a shell.
+
+Actions without steps are planned by the LLM; the planned files are generated concurrently
+(project.generation_concurrency at a time, default 4, within the llm.* rate limits) and then
+written one by one in plan order.
+
+Generated files never silently replace existing ones: when the path exists, the diff between the
+current and the generated content is shown and that file is only overwritten after its own
+confirmation, which --yes does not give. Pass --force to overwrite without asking.
+
+Pass --output-dir to preview an action in a throwaway directory: every file the action creates,
+edits or appends to is written under that directory (relative to the project root) instead of
+the project. Edits start from the project file, or from the copy an earlier step wrote there.
+Paths that resolve outside the output directory (or the project without one) are rejected.
+run_command steps still run in the project.`,
+		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cwd, err := os.Getwd()
 			if err != nil {
@@ -25,19 +80,16 @@ func NewExecCmd() *cobra.Command {
 			}
 
 			// 1. Load Actions Logic
-			configPath := filepath.Join(cwd, ".magi.yaml")
-			if _, err := os.Stat(configPath); os.IsNotExist(err) {
+			config, err := loadMagiConfig(cwd)
+			if errors.Is(err, os.ErrNotExist) {
 				pterm.Warning.Println(".magi.yaml not found. Run 'magi project init' first.")
 				return nil
 			}
-
-			data, err := os.ReadFile(configPath)
 			if err != nil {
-				return fmt.Errorf("failed to read config: %w", err)
+				return err
 			}
-			var config MagiConfig
-			if err := yaml.Unmarshal(data, &config); err != nil {
-				return fmt.Errorf("failed to parse config: %w", err)
+			if err := validationError(validateConfig(config)); err != nil {
+				return err
 			}
 
 			if len(config.Actions) == 0 {
@@ -57,13 +109,7 @@ func NewExecCmd() *cobra.Command {
 				actionName, _ = pterm.DefaultInteractiveSelect.WithOptions(options).Show("Select action to perform")
 			}
 
-			var selectedAction *Action
-			for _, a := range config.Actions {
-				if a.Name == actionName {
-					selectedAction = &a
-					break
-				}
-			}
+			selectedAction := findAction(config, actionName)
 			if selectedAction == nil {
 				return fmt.Errorf("action '%s' not found", actionName)
 			}
@@ -93,7 +139,11 @@ func NewExecCmd() *cobra.Command {
 
 			// If action has defined steps, execute them
 			if len(selectedAction.Steps) > 0 {
-				executor := NewExecutor(runtime, cwd, architecture, projectType, *selectedAction, params)
+				executor := NewExecutor(runtime, cwd, architecture, projectType, *selectedAction, params).
+					WithAutoConfirm(yes).
+					WithAllowedCommands(allowCommands).
+					WithForce(force).
+					WithOutputDir(outputDir)
 				if err := executor.ExecuteSteps(selectedAction.Steps); err != nil {
 					return err
 				}
@@ -106,7 +156,7 @@ func NewExecCmd() *cobra.Command {
 
 			// ... Legacy Logic ...
 			agent = NewGeneratorAgent(runtime)
-			plan, err := agent.PlanGeneration(cwd, architecture, projectType, *selectedAction, params)
+			plan, err := agent.PlanGeneration(cwd, architecture, projectType, *selectedAction, params, nil)
 			if err != nil {
 				return fmt.Errorf("planning failed: %w", err)
 			}
@@ -117,44 +167,66 @@ func NewExecCmd() *cobra.Command {
 				pterm.Println(pterm.Green("  + ") + f.Path + pterm.Gray(" ("+f.Description+")"))
 			}
 
-			confirm, _ := pterm.DefaultInteractiveConfirm.Show("Proceed with generation?")
+			confirm := yes
+			if !confirm {
+				confirm, _ = shared.Confirm("Proceed with generation?", false)
+			}
 			if !confirm {
 				pterm.Info.Println("Aborted.")
 				return nil
 			}
 
-			// 6. Generate and Write
-			progressBar, _ := pterm.DefaultProgressbar.WithTotal(len(plan.Files)).Start()
-			for _, f := range plan.Files {
-				progressBar.UpdateTitle("Generating " + f.Path)
-				content, err := agent.GenerateContent(cwd, architecture, projectType, *selectedAction, params, f)
-				if err != nil {
-					pterm.Error.Printf("Failed to generate %s: %v\n", f.Path, err)
+			outputRoot := cwd
+			if outputDir != "" {
+				outputRoot = resolveOutputDir(cwd, outputDir)
+			}
+
+			// 6. Generate the files concurrently, then write them in plan order
+			progressBar, _ := pterm.DefaultProgressbar.WithTotal(len(plan.Files)).WithTitle("Generating files").Start()
+			results := generateFiles(plan.Files, generationConcurrency(), func(f GeneratedFile) (*FileContent, error) {
+				return agent.GenerateContent(cwd, architecture, projectType, *selectedAction, params, f, nil)
+			}, func(GeneratedFile) {
+				progressBar.Increment()
+			})
+			progressBar.Stop()
+
+			for _, result := range results {
+				f := result.File
+				if result.Err != nil {
+					pterm.Error.Printf("Failed to generate %s: %v\n", f.Path, result.Err)
 					continue
 				}
 
-				fullPath := filepath.Join(cwd, f.Path)
+				fullPath, err := resolveInside(outputRoot, f.Path)
+				if err != nil {
+					pterm.Error.Printf("Skipped %s: %v\n", f.Path, err)
+					continue
+				}
+				write, err := confirmOverwrite(fullPath, f.Path, result.Content.Content, force)
+				if err != nil {
+					pterm.Error.Println(err)
+					continue
+				}
+				if !write {
+					continue
+				}
 				if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
 					pterm.Error.Printf("Failed to create dir for %s: %v\n", f.Path, err)
 					continue
 				}
-
-				// Check overwrite
-				if _, err := os.Stat(fullPath); err == nil {
-					// In non-interactive mode or simply proceed for now as user confirmed plan.
-					// Ideally we verify individually but bulk confirm is standard for "create".
-				}
-
-				if err := os.WriteFile(fullPath, []byte(content.Content), 0644); err != nil {
+				if err := writeGeneratedFile(fullPath, result.Content.Content); err != nil {
 					pterm.Error.Printf("Failed to write %s: %v\n", f.Path, err)
 				}
-				progressBar.Increment()
 			}
-			progressBar.Stop()
 			pterm.Success.Println("Generation complete!")
 
 			return nil
 		},
 	}
+
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Apply file writes and run commands without asking (destructive commands still ask)")
+	cmd.Flags().StringVar(&outputDir, "output-dir", "", "Write generated and edited files under this directory instead of the project, to preview an action")
+	cmd.Flags().BoolVar(&force, "force", false, "Overwrite existing files with generated content without showing the diff and asking")
+	cmd.Flags().StringSliceVar(&allowCommands, "allow-commands", nil, "Command prefixes (e.g. \"git push\") that --yes may run without asking even if destructive")
 	return cmd
 }
@@ -1,16 +1,50 @@
 package project
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/MagdielCAS/magi-cli/pkg/shared"
 	"github.com/pterm/pterm"
+	"github.com/spf13/viper"
 )
 
+const (
+	commandTimeoutKey         = "project.command_timeout"
+	defaultCommandTimeout     = 10 * time.Minute
+	commandWaitDelay          = 5 * time.Second
+	commandOutputCaptureBytes = 64 * 1024
+	commandFailureOutputLines = 20
+	// maxReferenceFileBytes caps how much of a read_file result is passed to later prompts.
+	maxReferenceFileBytes = 32 * 1024
+)
+
+const (
+	confirmYes      = "Yes"
+	confirmYesToAll = "Yes to all remaining steps"
+	confirmNo       = "No"
+)
+
+// destructiveCommands always need an explicit confirmation, even in auto-confirm mode.
+var destructiveCommands = []string{
+	"rm", "rmdir", "mv", "dd", "shred", "mkfs", "sudo", "chmod", "chown",
+	"git push", "git reset", "git clean", "git checkout", "git rebase",
+	"docker rm", "docker rmi", "docker system prune", "docker volume rm",
+	"kubectl delete", "terraform destroy", "pulumi destroy", "npm publish",
+}
+
+var envNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
 // Executor handles the execution of action steps.
 type Executor struct {
 	Agent         *GeneratorAgent
@@ -20,6 +54,15 @@ type Executor struct {
 	ProjectType   string
 	CurrentAction Action
 	CurrentParams map[string]string
+
+	autoConfirm     bool
+	allowedCommands []string
+	// force overwrites existing files with generated content without asking.
+	force bool
+	// outputDir, when set, receives every written file instead of Cwd.
+	outputDir string
+	// references holds the files read by read_file steps, in read order, for later steps.
+	references []ReferenceFile
 }
 
 // NewExecutor creates a new Executor.
@@ -35,6 +78,68 @@ func NewExecutor(runtime *shared.RuntimeContext, cwd, arch, pType string, action
 	}
 }
 
+// WithAutoConfirm makes the executor apply file writes and run commands without asking.
+// Destructive commands still ask unless they match WithAllowedCommands.
+func (e *Executor) WithAutoConfirm(autoConfirm bool) *Executor {
+	e.autoConfirm = autoConfirm
+	return e
+}
+
+// WithAllowedCommands sets the command prefixes (e.g. "git push") that may run without
+// confirmation in auto-confirm mode even when they look destructive.
+func (e *Executor) WithAllowedCommands(commands []string) *Executor {
+	e.allowedCommands = commands
+	return e
+}
+
+// WithForce makes create_file steps overwrite existing files without showing the diff and
+// asking first.
+func (e *Executor) WithForce(force bool) *Executor {
+	e.force = force
+	return e
+}
+
+// WithOutputDir writes generated and edited files under dir instead of the project, to
+// preview an action. A relative dir is resolved against Cwd.
+func (e *Executor) WithOutputDir(dir string) *Executor {
+	e.outputDir = resolveOutputDir(e.Cwd, dir)
+	return e
+}
+
+// resolveOutputDir returns dir resolved against cwd, or "" when dir is empty.
+func resolveOutputDir(cwd, dir string) string {
+	if dir == "" || filepath.IsAbs(dir) {
+		return dir
+	}
+	return filepath.Join(cwd, dir)
+}
+
+// outputPath returns where the file at the project-relative path rel is written. Paths that
+// resolve outside the output directory (or the project without one) are rejected, so a
+// planned path such as "../src/main.go" cannot escape --output-dir.
+func (e *Executor) outputPath(rel string) (string, error) {
+	root := e.Cwd
+	if e.outputDir != "" {
+		root = e.outputDir
+	}
+	path, err := resolveInside(root, rel)
+	if err != nil {
+		return "", fmt.Errorf("file %w", err)
+	}
+	return path, nil
+}
+
+// sourcePath returns the file a step reads rel from: the copy in the output directory when
+// an earlier step wrote one, otherwise the project file.
+func (e *Executor) sourcePath(rel string) string {
+	if e.outputDir != "" {
+		if _, err := os.Stat(filepath.Join(e.outputDir, rel)); err == nil {
+			return filepath.Join(e.outputDir, rel)
+		}
+	}
+	return filepath.Join(e.Cwd, rel)
+}
+
 // ExecuteSteps runs the defined steps sequentially.
 func (e *Executor) ExecuteSteps(steps []ActionStep) error {
 	pterm.Info.Printf("Executing %d steps for action '%s'...\n", len(steps), e.CurrentAction.Name)
@@ -49,6 +154,8 @@ func (e *Executor) ExecuteSteps(steps []ActionStep) error {
 			err = e.handleCreateFile(step)
 		case "edit_file":
 			err = e.handleEditFile(step)
+		case "append_file":
+			err = e.handleAppendFile(step)
 		case "run_command":
 			err = e.handleRunCommand(step)
 		case "search_replace":
@@ -70,23 +177,33 @@ func (e *Executor) ExecuteSteps(steps []ActionStep) error {
 func (e *Executor) handleCreateFile(step ActionStep) error {
 	// Plan the file path and description based on instruction
 	stepAction := Action{Name: e.CurrentAction.Name, Description: step.Instruction}
-	plan, err := e.Agent.PlanGeneration(e.Cwd, e.Architecture, e.ProjectType, stepAction, e.CurrentParams)
+	plan, err := e.Agent.PlanGeneration(e.Cwd, e.Architecture, e.ProjectType, stepAction, e.CurrentParams, e.referenceFiles(""))
 	if err != nil {
 		return fmt.Errorf("failed to plan file creation: %w", err)
 	}
 
 	for _, f := range plan.Files {
 		pterm.Info.Printf("Proposed File: %s\n", f.Path)
-		if confirm, _ := pterm.DefaultInteractiveConfirm.Show("Generate this file?"); confirm {
-			content, err := e.Agent.GenerateContent(e.Cwd, e.Architecture, e.ProjectType, stepAction, e.CurrentParams, f)
+		fullPath, err := e.outputPath(f.Path)
+		if err != nil {
+			return err
+		}
+		if e.confirmStep("Generate this file?", true) {
+			content, err := e.Agent.GenerateContent(e.Cwd, e.Architecture, e.ProjectType, stepAction, e.CurrentParams, f, e.referenceFiles(""))
 			if err != nil {
 				return fmt.Errorf("failed generation: %w", err)
 			}
-			fullPath := filepath.Join(e.Cwd, f.Path)
+			write, err := confirmOverwrite(fullPath, f.Path, content.Content, e.force)
+			if err != nil {
+				return err
+			}
+			if !write {
+				continue
+			}
 			if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
 				return err
 			}
-			if err := os.WriteFile(fullPath, []byte(content.Content), 0644); err != nil {
+			if err := writeGeneratedFile(fullPath, content.Content); err != nil {
 				return err
 			}
 			pterm.Success.Println("File created: " + f.Path)
@@ -111,21 +228,27 @@ func (e *Executor) handleEditFile(step ActionStep) error {
 		}
 	}
 
-	fullPath := filepath.Join(e.Cwd, targetFile)
-	contentBytes, err := os.ReadFile(fullPath)
+	fullPath, err := e.outputPath(targetFile)
+	if err != nil {
+		return err
+	}
+	contentBytes, err := os.ReadFile(e.sourcePath(targetFile))
 	if err != nil {
 		return fmt.Errorf("failed to read target file '%s': %w", targetFile, err)
 	}
 
-	updated, err := e.Agent.UpdateContent(targetFile, string(contentBytes), step.Instruction, e.Architecture, e.ProjectType)
+	updated, err := e.Agent.UpdateContent(targetFile, string(contentBytes), step.Instruction, e.Architecture, e.ProjectType, e.referenceFiles(targetFile))
 	if err != nil {
 		return fmt.Errorf("failed to generate updates: %w", err)
 	}
 
 	// Show diff (simplified) or just confirm
 	pterm.Info.Println("Proposed changes generated.")
-	if confirm, _ := pterm.DefaultInteractiveConfirm.Show("Apply changes to " + targetFile + "?"); confirm {
-		if err := os.WriteFile(fullPath, []byte(updated.Content), 0644); err != nil {
+	if e.confirmStep("Apply changes to "+targetFile+"?", true) {
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+			return err
+		}
+		if err := writeGeneratedFile(fullPath, updated.Content); err != nil {
 			return err
 		}
 		pterm.Success.Println("File updated.")
@@ -141,30 +264,464 @@ func (e *Executor) handleRunCommand(step ActionStep) error {
 		cmdStr = e.resolveVariable(cmdParam)
 	}
 
+	dir, err := e.commandDir(step)
+	if err != nil {
+		return err
+	}
+
+	input, err := stepInput(step)
+	if err != nil {
+		return err
+	}
+	timeout, err := stepTimeout(step)
+	if err != nil {
+		return err
+	}
+
 	pterm.Info.Printf("Command: %s\n", cmdStr)
+	if dir != e.Cwd {
+		pterm.Info.Printf("Directory: %s\n", step.Parameters["cwd"])
+	}
+	if input == inputInteractive {
+		pterm.Warning.Println("Interactive command: the step timeout does not apply.")
+		timeout = 0
+	}
 
-	if confirm, _ := pterm.DefaultInteractiveConfirm.WithDefaultValue(false).Show("Run this command?"); !confirm {
+	if !e.confirmCommand(cmdStr) {
 		pterm.Info.Println("Skipped command execution.")
 		return nil
 	}
 
-	// Execute
-	parts := strings.Fields(cmdStr)
+	parts, err := shared.SplitCommandLine(cmdStr)
+	if err != nil {
+		return fmt.Errorf("invalid command '%s': %w", cmdStr, err)
+	}
 	if len(parts) == 0 {
 		return nil
 	}
+	return runCommand(parts, dir, timeout, input != inputNone)
+}
 
-	cmd := exec.Command(parts[0], parts[1:]...)
-	cmd.Dir = e.Cwd
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	cmd.Stdin = os.Stdin // Allow interactivity
+// confirmStep asks prompt with an extra option to apply every remaining step of the action.
+// Once that option is picked, or when auto-confirm is on, it returns true without asking.
+func (e *Executor) confirmStep(prompt string, defaultYes bool) bool {
+	if e.autoConfirm {
+		return true
+	}
 
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("command failed: %w", err)
+	defaultOption := confirmNo
+	if defaultYes {
+		defaultOption = confirmYes
 	}
-	pterm.Success.Println("Command executed successfully.")
-	return nil
+	choice, err := pterm.DefaultInteractiveSelect.
+		WithOptions([]string{confirmYes, confirmYesToAll, confirmNo}).
+		WithDefaultOption(defaultOption).
+		Show(prompt)
+	if err != nil {
+		return false
+	}
+
+	switch choice {
+	case confirmYesToAll:
+		e.autoConfirm = true
+		return true
+	case confirmYes:
+		return true
+	default:
+		return false
+	}
+}
+
+// confirmCommand asks before running cmdStr. In auto-confirm mode commands run directly unless
+// they look destructive and are not covered by the allowlist.
+func (e *Executor) confirmCommand(cmdStr string) bool {
+	if !e.autoConfirm {
+		return e.confirmStep("Run this command?", false)
+	}
+	if !isDestructiveCommand(cmdStr) || commandAllowed(cmdStr, e.allowedCommands) {
+		return true
+	}
+
+	pterm.Warning.Println("This command may be destructive and is not covered by --allow-commands.")
+	confirm, _ := shared.Confirm("Run this command?", false)
+	return confirm
+}
+
+// isDestructiveCommand reports whether cmdStr runs one of destructiveCommands. Wrappers
+// such as env, sudo, xargs or timeout are looked through, shell -c payloads are checked
+// command by command, and find with -delete or -exec rm counts as destructive. A command
+// line that cannot be parsed, or a shell or eval whose commands cannot be seen, also counts.
+func isDestructiveCommand(cmdStr string) bool {
+	words, err := shared.SplitCommandLine(cmdStr)
+	if err != nil {
+		return true
+	}
+	return destructiveWords(words, 0)
+}
+
+// maxShellNesting bounds how deep shell payloads such as sh -c "bash -c '...'" are unwrapped.
+const maxShellNesting = 4
+
+// commandWrappers run the command given in their arguments. The listed options take a value.
+var commandWrappers = map[string][]string{
+	"env":     {"-u", "--unset", "-C", "--chdir"},
+	"sudo":    {"-u", "-g", "-C", "-D", "-h", "-p", "-r", "-t", "-U"},
+	"doas":    {"-u", "-C"},
+	"xargs":   {"-I", "-n", "-L", "-P", "-d", "-E", "-s", "-a"},
+	"nice":    {"-n"},
+	"ionice":  {"-c", "-n", "-p"},
+	"nohup":   nil,
+	"time":    nil,
+	"command": nil,
+	"exec":    nil,
+	"stdbuf":  {"-i", "-o", "-e"},
+	"timeout": {"-s", "-k", "--signal", "--kill-after"},
+}
+
+var shellInterpreters = map[string]bool{"sh": true, "bash": true, "zsh": true, "dash": true, "ksh": true, "ash": true, "fish": true}
+
+// gitValueOptions are the git options before the subcommand that take a value.
+var gitValueOptions = map[string]bool{"-C": true, "-c": true, "--git-dir": true, "--work-tree": true, "--namespace": true}
+
+func destructiveWords(words []string, depth int) bool {
+	for {
+		words = skipAssignments(words)
+		if len(words) == 0 {
+			return false
+		}
+		words[0] = filepath.Base(words[0])
+		if matchesDestructive(words) {
+			return true
+		}
+		valueOptions, ok := commandWrappers[words[0]]
+		if !ok {
+			break
+		}
+		words = unwrapCommand(words, valueOptions)
+	}
+
+	switch name := words[0]; {
+	case shellInterpreters[name]:
+		payload, ok := shellPayload(words)
+		// Command substitutions can hide inside quoted words, so they are not inspected.
+		if !ok || depth >= maxShellNesting || strings.Contains(payload, "$(") || strings.Contains(payload, "`") {
+			return true
+		}
+		for _, segment := range shellSegments(payload) {
+			segmentWords, err := shared.SplitCommandLine(segment)
+			if err != nil || destructiveWords(segmentWords, depth+1) {
+				return true
+			}
+		}
+	case name == "find":
+		for i, arg := range words {
+			switch arg {
+			case "-delete":
+				return true
+			case "-exec", "-execdir", "-ok", "-okdir":
+				end := i + 1
+				for end < len(words) && words[end] != ";" && words[end] != "+" {
+					end++
+				}
+				if destructiveWords(append([]string(nil), words[i+1:end]...), depth) {
+					return true
+				}
+			}
+		}
+	case name == "git":
+		return matchesDestructive(stripGitOptions(words))
+	case name == "eval":
+		return true
+	}
+	return false
+}
+
+// matchesDestructive reports whether words start with one of destructiveCommands.
+func matchesDestructive(words []string) bool {
+	for _, prefix := range destructiveCommands {
+		if hasWordsPrefix(words, strings.Fields(prefix)) {
+			return true
+		}
+	}
+	return false
+}
+
+// skipAssignments drops leading NAME=value environment assignments.
+func skipAssignments(words []string) []string {
+	for len(words) > 0 {
+		name, _, found := strings.Cut(words[0], "=")
+		if !found || !envNamePattern.MatchString(name) {
+			break
+		}
+		words = words[1:]
+	}
+	return words
+}
+
+// unwrapCommand returns the command a wrapper such as sudo or xargs runs: its arguments
+// without the options (and their values) and, for timeout, the duration.
+func unwrapCommand(words []string, valueOptions []string) []string {
+	wrapper := words[0]
+	rest := words[1:]
+	for len(rest) > 0 && strings.HasPrefix(rest[0], "-") {
+		option := rest[0]
+		rest = rest[1:]
+		if option == "--" {
+			break
+		}
+		for _, withValue := range valueOptions {
+			if option == withValue && len(rest) > 0 {
+				rest = rest[1:]
+				break
+			}
+		}
+	}
+	if wrapper == "timeout" && len(rest) > 0 {
+		rest = rest[1:]
+	}
+	return append([]string(nil), rest...)
+}
+
+// shellPayload returns the command string of "sh -c <payload>". ok is false for shells
+// that read a script or stdin, whose commands cannot be inspected.
+func shellPayload(words []string) (payload string, ok bool) {
+	sawC := false
+	for _, arg := range words[1:] {
+		if strings.HasPrefix(arg, "-") && !strings.HasPrefix(arg, "--") {
+			if strings.Contains(arg, "c") {
+				sawC = true
+			}
+			continue
+		}
+		if sawC {
+			return arg, true
+		}
+		return "", false
+	}
+	return "", false
+}
+
+// shellSegments splits a shell payload into simple commands at unquoted operators and
+// grouping characters (;, &, |, newlines, parentheses, braces and backticks).
+func shellSegments(payload string) []string {
+	var segments []string
+	var current strings.Builder
+	var quote byte
+	for i := 0; i < len(payload); i++ {
+		c := payload[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			} else if c == '\\' && quote == '"' && i+1 < len(payload) {
+				current.WriteByte(c)
+				i++
+				c = payload[i]
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '\\' && i+1 < len(payload):
+			current.WriteByte(c)
+			i++
+			c = payload[i]
+		case strings.IndexByte(";&|\n(){}`", c) >= 0:
+			segments = append(segments, current.String())
+			current.Reset()
+			continue
+		}
+		current.WriteByte(c)
+	}
+	return append(segments, current.String())
+}
+
+// stripGitOptions drops the global options before the git subcommand, so "git -C dir push"
+// reads as "git push".
+func stripGitOptions(words []string) []string {
+	rest := words[1:]
+	for len(rest) > 0 && strings.HasPrefix(rest[0], "-") {
+		option := rest[0]
+		rest = rest[1:]
+		if gitValueOptions[option] && len(rest) > 0 {
+			rest = rest[1:]
+		}
+	}
+	return append([]string{"git"}, rest...)
+}
+
+// commandAllowed reports whether cmdStr starts with one of the allowed command prefixes.
+func commandAllowed(cmdStr string, allowed []string) bool {
+	for _, prefix := range allowed {
+		if strings.TrimSpace(prefix) != "" && hasCommandPrefix(cmdStr, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasCommandPrefix compares whole words, so "rm" matches "rm -rf dist" but not "rmtemp".
+func hasCommandPrefix(cmdStr, prefix string) bool {
+	return hasWordsPrefix(strings.Fields(cmdStr), strings.Fields(prefix))
+}
+
+func hasWordsPrefix(words, want []string) bool {
+	if len(want) == 0 || len(words) < len(want) {
+		return false
+	}
+	for i := range want {
+		if words[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Stdin modes of a run_command step, set with the "interactive" step parameter.
+const (
+	// inputDefault attaches the terminal's stdin, so prompting commands work, and keeps the timeout.
+	inputDefault = ""
+	// inputInteractive attaches stdin and drops the timeout, for commands the user drives.
+	inputInteractive = "true"
+	// inputNone gives the command no stdin, so it fails instead of waiting for input.
+	inputNone = "false"
+)
+
+// stepInput returns the stdin mode of a run_command step from its "interactive" parameter.
+func stepInput(step ActionStep) (string, error) {
+	mode := strings.ToLower(strings.TrimSpace(step.Parameters["interactive"]))
+	if !validInteractive(mode) {
+		return "", fmt.Errorf("invalid interactive value '%s': use true or false", step.Parameters["interactive"])
+	}
+	return mode, nil
+}
+
+func validInteractive(mode string) bool {
+	switch strings.ToLower(strings.TrimSpace(mode)) {
+	case inputDefault, inputInteractive, inputNone:
+		return true
+	}
+	return false
+}
+
+// stepTimeout returns the timeout of a run_command step: the "timeout" step parameter (a Go
+// duration such as "90s" or a number of seconds), then project.command_timeout, then
+// defaultCommandTimeout.
+func stepTimeout(step ActionStep) (time.Duration, error) {
+	if raw := strings.TrimSpace(step.Parameters["timeout"]); raw != "" {
+		if seconds, err := strconv.Atoi(raw); err == nil && seconds > 0 {
+			return time.Duration(seconds) * time.Second, nil
+		}
+		timeout, err := time.ParseDuration(raw)
+		if err != nil || timeout <= 0 {
+			return 0, fmt.Errorf("invalid timeout '%s': use a duration such as 90s or 5m", raw)
+		}
+		return timeout, nil
+	}
+	if timeout := viper.GetDuration(commandTimeoutKey); timeout > 0 {
+		return timeout, nil
+	}
+	return defaultCommandTimeout, nil
+}
+
+// runCommand runs args in dir, streaming stdout and stderr to the terminal. The command is
+// killed after timeout (0 disables it) and the end of both streams is captured so a failure
+// reports the last lines. stdin attaches the terminal's input.
+func runCommand(args []string, dir string, timeout time.Duration, stdin bool) error {
+	ctx := shared.BaseContext()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	stdoutTail := &tailWriter{max: commandOutputCaptureBytes}
+	stderrTail := &tailWriter{max: commandOutputCaptureBytes}
+
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Dir = dir
+	cmd.Stdout = io.MultiWriter(os.Stdout, stdoutTail)
+	cmd.Stderr = io.MultiWriter(os.Stderr, stderrTail)
+	cmd.WaitDelay = commandWaitDelay
+	if stdin {
+		cmd.Stdin = os.Stdin
+	}
+
+	err := cmd.Run()
+	if err == nil {
+		pterm.Success.Println("Command executed successfully.")
+		return nil
+	}
+
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		err = fmt.Errorf("command timed out after %s", timeout)
+	} else {
+		err = fmt.Errorf("command failed: %w", err)
+	}
+	if last := lastLines(string(stdoutTail.buf), commandFailureOutputLines); last != "" {
+		err = fmt.Errorf("%w\nlast output:\n%s", err, last)
+	}
+	if last := lastLines(string(stderrTail.buf), commandFailureOutputLines); last != "" {
+		err = fmt.Errorf("%w\nlast error output:\n%s", err, last)
+	}
+	return err
+}
+
+// tailWriter keeps the last max bytes written to it.
+type tailWriter struct {
+	max int
+	buf []byte
+}
+
+func (w *tailWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	if len(w.buf) > w.max {
+		w.buf = w.buf[len(w.buf)-w.max:]
+	}
+	return len(p), nil
+}
+
+// lastLines returns at most n trailing non-empty lines of s.
+func lastLines(s string, n int) string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// commandDir returns the directory a run_command step runs in: the project root, or the
+// optional "cwd" parameter resolved relative to it. The directory must exist and stay inside
+// the project.
+func (e *Executor) commandDir(step ActionStep) (string, error) {
+	rel := strings.TrimSpace(e.resolveVariable(step.Parameters["cwd"]))
+	if rel == "" {
+		return e.Cwd, nil
+	}
+	dir, err := resolveInside(e.Cwd, rel)
+	if err != nil {
+		return "", fmt.Errorf("cwd %w", err)
+	}
+
+	info, err := os.Stat(dir)
+	if err != nil {
+		return "", fmt.Errorf("cwd '%s' does not exist: %w", rel, err)
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("cwd '%s' is not a directory", rel)
+	}
+	return dir, nil
+}
+
+// resolveInside joins rel to root. It fails when rel is absolute, starts with ~ or resolves
+// outside root, so a step cannot reach files elsewhere on the machine.
+func resolveInside(root, rel string) (string, error) {
+	if filepath.IsAbs(rel) || strings.HasPrefix(rel, "~") {
+		return "", fmt.Errorf("'%s' must be a relative path", rel)
+	}
+	path := filepath.Join(root, rel)
+	if inside, err := filepath.Rel(root, path); err != nil || inside == ".." || strings.HasPrefix(inside, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("'%s' resolves outside %s", rel, root)
+	}
+	return path, nil
 }
 
 // handleSearchReplace handles simple search and replace or agentic replacement.
@@ -173,31 +730,132 @@ func (e *Executor) handleSearchReplace(step ActionStep) error {
 	return e.handleEditFile(step)
 }
 
-// handleReadFile reads a file and logs logic (mostly for context).
+// handleReadFile reads a file and keeps its content as context for the following steps. The
+// target must stay inside the project, and secrets are redacted before the content can reach
+// a prompt.
 func (e *Executor) handleReadFile(step ActionStep) error {
 	targetFile := e.resolveVariable(step.Parameters["target"])
 	if targetFile == "" {
 		return nil
 	}
-	fullPath := filepath.Join(e.Cwd, targetFile)
-	content, err := os.ReadFile(fullPath)
+	if _, err := resolveInside(e.Cwd, targetFile); err != nil {
+		return fmt.Errorf("read_file target %w", err)
+	}
+	content, err := os.ReadFile(e.sourcePath(targetFile))
 	if err != nil {
 		pterm.Error.Printf("Failed to read file %s: %v\n", targetFile, err)
 		return nil // Don't block flow for read error?
 	}
 	pterm.Info.Printf("Read %s (%d bytes)\n", targetFile, len(content))
+	if len(content) > maxReferenceFileBytes {
+		pterm.Warning.Printf("Only the first %d bytes of %s are passed to the next steps.\n", maxReferenceFileBytes, targetFile)
+	}
+
+	redactor, err := shared.RedactorFromConfig()
+	if err != nil {
+		return err
+	}
+	redacted, count := redactor.Redact(string(content))
+	if count > 0 {
+		pterm.Info.Printf("Redacted %d secret(s) from %s before passing it to the next steps.\n", count, targetFile)
+	}
+	e.addReference(targetFile, redacted)
 	return nil
 }
 
-// resolveVariable replaces {var} with values from CurrentParams.
+// addReference records the content of path for later steps. Reading the same file again
+// replaces the earlier content. Content over maxReferenceFileBytes is cut at a rune boundary.
+func (e *Executor) addReference(path, content string) {
+	if len(content) > maxReferenceFileBytes {
+		cut := maxReferenceFileBytes
+		for cut > 0 && !utf8.RuneStart(content[cut]) {
+			cut--
+		}
+		content = content[:cut] + "\n... (truncated)"
+	}
+	path = filepath.Clean(path)
+	for i := range e.references {
+		if e.references[i].Path == path {
+			e.references[i].Content = content
+			return
+		}
+	}
+	e.references = append(e.references, ReferenceFile{Path: path, Content: content})
+}
+
+// referenceFiles returns the files read so far, leaving out exclude because the prompt that
+// edits or generates it already carries it.
+func (e *Executor) referenceFiles(exclude string) []ReferenceFile {
+	if exclude != "" {
+		exclude = filepath.Clean(exclude)
+	}
+	var refs []ReferenceFile
+	for _, ref := range e.references {
+		if ref.Path != exclude {
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
+
+// resolveVariable expands a step template. Environment references are expanded first, so
+// ${NAME} always refers to the process environment and user-provided parameter values are
+// never expanded; then every {param} is replaced with the matching action parameter.
 func (e *Executor) resolveVariable(input string) string {
 	if input == "" {
 		return ""
 	}
-	output := input
+	output, missing := expandEnv(input, os.LookupEnv)
+	for _, name := range missing {
+		pterm.Warning.Printf("Environment variable %s is not set; using an empty value.\n", name)
+	}
 	for k, v := range e.CurrentParams {
 		key := fmt.Sprintf("{%s}", k)
 		output = strings.ReplaceAll(output, key, v)
 	}
 	return output
 }
+
+// expandEnv replaces ${NAME} and ${NAME:-default} with values from lookup and turns $$ into
+// a literal $. Unset variables without a default expand to an empty string and are returned
+// in missing. Anything else, including a bare $NAME, is left untouched.
+func expandEnv(input string, lookup func(string) (string, bool)) (string, []string) {
+	var out strings.Builder
+	var missing []string
+
+	for i := 0; i < len(input); i++ {
+		if input[i] != '$' || i+1 >= len(input) {
+			out.WriteByte(input[i])
+			continue
+		}
+		if input[i+1] == '$' {
+			out.WriteByte('$')
+			i++
+			continue
+		}
+		end := strings.IndexByte(input[i:], '}')
+		if input[i+1] != '{' || end < 0 {
+			out.WriteByte(input[i])
+			continue
+		}
+
+		expr := input[i+2 : i+end]
+		name, fallback, hasDefault := strings.Cut(expr, ":-")
+		if !envNamePattern.MatchString(name) {
+			out.WriteByte(input[i])
+			continue
+		}
+
+		value, ok := lookup(name)
+		switch {
+		case ok && (value != "" || !hasDefault):
+			out.WriteString(value)
+		case hasDefault:
+			out.WriteString(fallback)
+		default:
+			missing = append(missing, name)
+		}
+		i += end
+	}
+	return out.String(), missing
+}
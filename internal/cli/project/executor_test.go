@@ -0,0 +1,270 @@
+package project
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpandEnv(t *testing.T) {
+	env := map[string]string{"HOME": "/home/magi", "EMPTY": ""}
+	lookup := func(name string) (string, bool) {
+		v, ok := env[name]
+		return v, ok
+	}
+
+	tests := []struct {
+		name        string
+		input       string
+		want        string
+		wantMissing []string
+	}{
+		{"plain", "go test ./...", "go test ./...", nil},
+		{"braced", "ls ${HOME}/src", "ls /home/magi/src", nil},
+		{"missing", "echo ${CI_TOKEN}", "echo ", []string{"CI_TOKEN"}},
+		{"default for missing", "echo ${STAGE:-dev}", "echo dev", nil},
+		{"default for empty", "echo ${EMPTY:-fallback}", "echo fallback", nil},
+		{"empty without default", "echo [${EMPTY}]", "echo []", nil},
+		{"escaped dollar", "echo $${HOME} costs $$5", "echo ${HOME} costs $5", nil},
+		{"bare dollar untouched", "echo $HOME $", "echo $HOME $", nil},
+		{"unterminated", "echo ${HOME", "echo ${HOME", nil},
+		{"invalid name", "echo ${1abc}", "echo ${1abc}", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, missing := expandEnv(tt.input, lookup)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantMissing, missing)
+		})
+	}
+}
+
+func TestResolveVariableMixesParamsAndEnv(t *testing.T) {
+	t.Setenv("MAGI_TEST_REGISTRY", "ghcr.io/magi")
+	e := &Executor{CurrentParams: map[string]string{"name": "api", "tag": "${MAGI_TEST_REGISTRY}"}}
+
+	assert.Equal(t, "docker build -t ghcr.io/magi/api:latest services/api",
+		e.resolveVariable("docker build -t ${MAGI_TEST_REGISTRY}/{name}:latest services/{name}"))
+	// Parameter values are user input and are not expanded again.
+	assert.Equal(t, "echo ${MAGI_TEST_REGISTRY}", e.resolveVariable("echo {tag}"))
+	assert.Equal(t, "", e.resolveVariable(""))
+}
+
+func TestCommandDir(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "packages", "web"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "go.mod"), []byte("module x\n"), 0644))
+	e := &Executor{Cwd: root, CurrentParams: map[string]string{"pkg": "web"}}
+
+	dir, err := e.commandDir(ActionStep{})
+	require.NoError(t, err)
+	assert.Equal(t, root, dir)
+
+	dir, err = e.commandDir(ActionStep{Parameters: map[string]string{"cwd": "packages/{pkg}"}})
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(root, "packages", "web"), dir)
+
+	for _, cwd := range []string{"missing", "go.mod", "../outside", "/tmp"} {
+		_, err := e.commandDir(ActionStep{Parameters: map[string]string{"cwd": cwd}})
+		assert.Error(t, err, cwd)
+	}
+}
+
+func TestStepTimeout(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+
+	timeout, err := stepTimeout(ActionStep{})
+	require.NoError(t, err)
+	assert.Equal(t, defaultCommandTimeout, timeout)
+
+	viper.Set(commandTimeoutKey, "2m")
+	timeout, err = stepTimeout(ActionStep{})
+	require.NoError(t, err)
+	assert.Equal(t, 2*time.Minute, timeout)
+
+	timeout, err = stepTimeout(ActionStep{Parameters: map[string]string{"timeout": "30"}})
+	require.NoError(t, err)
+	assert.Equal(t, 30*time.Second, timeout)
+
+	timeout, err = stepTimeout(ActionStep{Parameters: map[string]string{"timeout": "1m30s"}})
+	require.NoError(t, err)
+	assert.Equal(t, 90*time.Second, timeout)
+
+	_, err = stepTimeout(ActionStep{Parameters: map[string]string{"timeout": "soon"}})
+	assert.Error(t, err)
+}
+
+func TestRunCommandTimeout(t *testing.T) {
+	if _, err := exec.LookPath("sleep"); err != nil {
+		t.Skip("sleep not available")
+	}
+
+	start := time.Now()
+	err := runCommand([]string{"sleep", "5"}, t.TempDir(), 100*time.Millisecond, false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "timed out after 100ms")
+	assert.Less(t, time.Since(start), 3*time.Second)
+}
+
+func TestRunCommandReportsLastOutputLines(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+
+	script := "for i in $(seq 1 30); do echo line $i; done; echo boom >&2; exit 3"
+	err := runCommand([]string{"sh", "-c", script}, t.TempDir(), time.Minute, false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "exit status 3")
+	assert.Contains(t, err.Error(), "line 30\nlast error output:\nboom")
+	assert.NotContains(t, err.Error(), "line 10\n")
+}
+
+func TestStepInput(t *testing.T) {
+	for value, want := range map[string]string{"": inputDefault, "true": inputInteractive, " False ": inputNone} {
+		got, err := stepInput(ActionStep{Parameters: map[string]string{"interactive": value}})
+		require.NoError(t, err, value)
+		assert.Equal(t, want, got, value)
+	}
+	_, err := stepInput(ActionStep{Parameters: map[string]string{"interactive": "yes"}})
+	assert.Error(t, err)
+}
+
+func TestTailWriterAndLastLines(t *testing.T) {
+	w := &tailWriter{max: 8}
+	_, _ = w.Write([]byte("abcdef"))
+	_, _ = w.Write([]byte("ghij"))
+	assert.Equal(t, "cdefghij", string(w.buf))
+
+	assert.Equal(t, "b\nc", lastLines("a\nb\nc\n", 2))
+	assert.Equal(t, "", lastLines("", 5))
+}
+
+func TestIsDestructiveCommand(t *testing.T) {
+	assert.True(t, isDestructiveCommand("rm -rf dist"))
+	assert.True(t, isDestructiveCommand("git push origin main"))
+	assert.False(t, isDestructiveCommand("git pull"))
+	assert.False(t, isDestructiveCommand("rmtemp"))
+	assert.False(t, isDestructiveCommand("go test ./..."))
+	assert.False(t, isDestructiveCommand(""))
+
+	for _, cmd := range []string{
+		`sh -c "rm -rf ."`,
+		`bash -lc 'npm ci && rm -rf dist'`,
+		`bash -c "echo ok; (cd web && git push --force)"`,
+		`sh -c 'echo "$(rm -rf .)"'`,
+		"bash scripts/cleanup.sh",
+		"env FOO=1 rm -rf dist",
+		"FOO=1 /bin/rm -rf dist",
+		"xargs -n 1 rm",
+		"sudo -u deploy ls",
+		"nice -n 10 timeout 30s rm -rf dist",
+		"find . -delete",
+		`find . -name "*.tmp" -exec rm {} ;`,
+		"git -C web push --force",
+		"git --git-dir=.git reset --hard",
+		`echo "unterminated`,
+	} {
+		assert.True(t, isDestructiveCommand(cmd), cmd)
+	}
+	for _, cmd := range []string{
+		`sh -c "go build ./... && go test ./..."`,
+		"env CGO_ENABLED=0 go build ./...",
+		`find . -name "*.go" -exec gofmt -l {} +`,
+		"git -C web pull",
+		"timeout 5m npm test",
+	} {
+		assert.False(t, isDestructiveCommand(cmd), cmd)
+	}
+}
+
+func TestConfirmCommandAutoConfirm(t *testing.T) {
+	e := (&Executor{}).WithAutoConfirm(true).WithAllowedCommands([]string{"git push", " "})
+
+	assert.True(t, e.confirmCommand("go build ./..."))
+	assert.True(t, e.confirmCommand("git push origin main"))
+	assert.True(t, e.confirmStep("Generate this file?", false))
+
+	assert.True(t, commandAllowed("git push --tags", e.allowedCommands))
+	assert.False(t, commandAllowed("git pushx", e.allowedCommands))
+	assert.False(t, commandAllowed("rm -rf dist", e.allowedCommands))
+}
+
+func TestReadFileKeepsReferences(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "internal", "api"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "internal", "api", "handler.go"), []byte("package api\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "go.mod"), []byte("module example\n"), 0644))
+
+	e := &Executor{Cwd: root, CurrentParams: map[string]string{"name": "api"}}
+	require.NoError(t, e.handleReadFile(ActionStep{Tool: "read_file", Parameters: map[string]string{"target": "internal/{name}/handler.go"}}))
+	require.NoError(t, e.handleReadFile(ActionStep{Tool: "read_file", Parameters: map[string]string{"target": "go.mod"}}))
+	// A missing file is reported but does not stop the action.
+	require.NoError(t, e.handleReadFile(ActionStep{Tool: "read_file", Parameters: map[string]string{"target": "missing.go"}}))
+
+	assert.Equal(t, []ReferenceFile{
+		{Path: "internal/api/handler.go", Content: "package api\n"},
+		{Path: "go.mod", Content: "module example\n"},
+	}, e.referenceFiles(""))
+	assert.Equal(t, []ReferenceFile{{Path: "go.mod", Content: "module example\n"}}, e.referenceFiles("./internal/api/handler.go"))
+
+	require.NoError(t, os.WriteFile(filepath.Join(root, "go.mod"), []byte("module changed\n"), 0644))
+	require.NoError(t, e.handleReadFile(ActionStep{Tool: "read_file", Parameters: map[string]string{"target": "go.mod"}}))
+	refs := e.referenceFiles("")
+	require.Len(t, refs, 2)
+	assert.Equal(t, "module changed\n", refs[1].Content)
+}
+
+func TestAddReferenceTruncates(t *testing.T) {
+	e := &Executor{}
+	e.addReference("big.txt", strings.Repeat("x", maxReferenceFileBytes+10))
+	refs := e.referenceFiles("")
+	require.Len(t, refs, 1)
+	assert.True(t, strings.HasSuffix(refs[0].Content, "(truncated)"))
+	assert.Less(t, len(refs[0].Content), maxReferenceFileBytes+100)
+}
+
+func TestAddReferenceTruncatesAtRuneBoundary(t *testing.T) {
+	e := &Executor{}
+	// "é" is two bytes, so the byte limit falls in the middle of one.
+	e.addReference("notes.txt", "x"+strings.Repeat("é", maxReferenceFileBytes))
+	refs := e.referenceFiles("")
+	require.Len(t, refs, 1)
+	assert.True(t, utf8.ValidString(refs[0].Content))
+}
+
+func TestReadFileStaysInsideProjectAndRedacts(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, ".env"), []byte("password=hunter2hunter2\n"), 0644))
+	e := &Executor{Cwd: root}
+
+	for _, target := range []string{"../secret.txt", "../../.ssh/id_rsa", "~/.aws/credentials", "/etc/passwd"} {
+		err := e.handleReadFile(ActionStep{Tool: "read_file", Parameters: map[string]string{"target": target}})
+		assert.Error(t, err, target)
+	}
+	assert.Empty(t, e.referenceFiles(""))
+
+	require.NoError(t, e.handleReadFile(ActionStep{Tool: "read_file", Parameters: map[string]string{"target": ".env"}}))
+	refs := e.referenceFiles("")
+	require.Len(t, refs, 1)
+	assert.NotContains(t, refs[0].Content, "hunter2")
+}
+
+func TestFormatReferenceFiles(t *testing.T) {
+	assert.Empty(t, formatReferenceFiles(nil))
+	section := formatReferenceFiles([]ReferenceFile{{Path: "go.mod", Content: "module example\n"}})
+	assert.Contains(t, section, "Reference files")
+	assert.Contains(t, section, "--- go.mod ---\nmodule example\n")
+}
@@ -0,0 +1,93 @@
+package project
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/MagdielCAS/magi-cli/pkg/shared"
+	"github.com/pterm/pterm"
+	"github.com/spf13/viper"
+)
+
+const (
+	// formatGeneratedKey turns off formatting generated and updated files when false.
+	formatGeneratedKey = "project.format_generated"
+	formatterTimeout   = 30 * time.Second
+)
+
+// prettierCommand formats web files in place.
+var prettierCommand = []string{"prettier", "--write"}
+
+// generatedFormatters maps a file extension to the command that formats a file in place;
+// the file path is appended as the last argument.
+var generatedFormatters = map[string][]string{
+	".go":   {"gofmt", "-w"},
+	".rs":   {"rustfmt"},
+	".py":   {"black", "--quiet"},
+	".js":   prettierCommand,
+	".jsx":  prettierCommand,
+	".mjs":  prettierCommand,
+	".cjs":  prettierCommand,
+	".ts":   prettierCommand,
+	".tsx":  prettierCommand,
+	".vue":  prettierCommand,
+	".css":  prettierCommand,
+	".scss": prettierCommand,
+	".html": prettierCommand,
+	".json": prettierCommand,
+}
+
+// formatGeneratedEnabled reports whether written files are formatted. It is on unless
+// project.format_generated is false.
+func formatGeneratedEnabled() bool {
+	if !viper.IsSet(formatGeneratedKey) {
+		return true
+	}
+	return viper.GetBool(formatGeneratedKey)
+}
+
+// writeGeneratedFile writes content produced by the generator agent to path and formats it.
+// A formatter failure only prints a warning: the unformatted content stays on disk.
+func writeGeneratedFile(path, content string) error {
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return err
+	}
+	if err := formatGeneratedFile(path); err != nil {
+		pterm.Warning.Printf("Kept %s unformatted: %v\n", path, err)
+	}
+	return nil
+}
+
+// formatGeneratedFile runs the formatter for the extension of path on it, from the file's
+// directory so project formatter settings apply. Files without a formatter, and formatters
+// that are not installed, are left alone.
+func formatGeneratedFile(path string) error {
+	if !formatGeneratedEnabled() {
+		return nil
+	}
+	formatter, ok := generatedFormatters[strings.ToLower(filepath.Ext(path))]
+	if !ok {
+		return nil
+	}
+	if _, err := exec.LookPath(formatter[0]); err != nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(shared.BaseContext(), formatterTimeout)
+	defer cancel()
+	args := append(append([]string{}, formatter[1:]...), path)
+	cmd := exec.CommandContext(ctx, formatter[0], args...)
+	cmd.Dir = filepath.Dir(path)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		if last := lastLines(string(output), commandFailureOutputLines); last != "" {
+			return fmt.Errorf("%s failed: %w\n%s", formatter[0], err, last)
+		}
+		return fmt.Errorf("%s failed: %w", formatter[0], err)
+	}
+	return nil
+}
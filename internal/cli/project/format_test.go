@@ -0,0 +1,54 @@
+package project
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteGeneratedFileFormatsGo(t *testing.T) {
+	if _, err := exec.LookPath("gofmt"); err != nil {
+		t.Skip("gofmt is not installed")
+	}
+	t.Cleanup(viper.Reset)
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "main.go")
+	require.NoError(t, writeGeneratedFile(path, "package main\nfunc main(){\nprintln( 1 )\n}\n"))
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "package main\n\nfunc main() {\n\tprintln(1)\n}\n", string(data))
+
+	broken := "package main\nfunc main() {\n"
+	require.NoError(t, writeGeneratedFile(path, broken))
+	data, err = os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, broken, string(data), "content that does not format must be kept")
+	assert.Error(t, formatGeneratedFile(path))
+
+	viper.Set(formatGeneratedKey, false)
+	unformatted := "package main\nfunc main(){}\n"
+	require.NoError(t, writeGeneratedFile(path, unformatted))
+	data, err = os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, unformatted, string(data))
+}
+
+func TestFormatGeneratedFileWithoutFormatter(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+
+	dir := t.TempDir()
+	for name, content := range map[string]string{"app.ts": "const a=1", "notes.txt": "x  y"} {
+		path := filepath.Join(dir, name)
+		require.NoError(t, writeGeneratedFile(path, content))
+		assert.NoError(t, formatGeneratedFile(path))
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, content, string(data))
+	}
+}
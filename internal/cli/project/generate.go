@@ -0,0 +1,61 @@
+package project
+
+import (
+	"sync"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	// generationConcurrencyKey bounds the file contents generated at once by 'magi project exec'.
+	generationConcurrencyKey     = "project.generation_concurrency"
+	defaultGenerationConcurrency = 4
+)
+
+// generationConcurrency returns project.generation_concurrency, or the default when it is
+// unset or not positive.
+func generationConcurrency() int {
+	if n := viper.GetInt(generationConcurrencyKey); n > 0 {
+		return n
+	}
+	return defaultGenerationConcurrency
+}
+
+// generatedFile is the outcome of generating one file of a plan.
+type generatedFile struct {
+	File    GeneratedFile
+	Content *FileContent
+	Err     error
+}
+
+// generateFiles runs generate for every file with at most concurrency calls in flight and
+// returns the outcomes in plan order, so the files can then be written one by one. done is
+// called after each file, never concurrently.
+func generateFiles(files []GeneratedFile, concurrency int, generate func(GeneratedFile) (*FileContent, error), done func(GeneratedFile)) []generatedFile {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	results := make([]generatedFile, len(files))
+	slots := make(chan struct{}, concurrency)
+	var doneMu sync.Mutex
+	var wg sync.WaitGroup
+
+	for i, file := range files {
+		wg.Add(1)
+		go func(i int, file GeneratedFile) {
+			defer wg.Done()
+			slots <- struct{}{}
+			content, err := generate(file)
+			<-slots
+
+			results[i] = generatedFile{File: file, Content: content, Err: err}
+			if done != nil {
+				doneMu.Lock()
+				done(file)
+				doneMu.Unlock()
+			}
+		}(i, file)
+	}
+	wg.Wait()
+	return results
+}
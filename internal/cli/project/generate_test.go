@@ -0,0 +1,61 @@
+package project
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateFilesBoundsConcurrencyAndKeepsOrder(t *testing.T) {
+	var files []GeneratedFile
+	for i := 0; i < 10; i++ {
+		files = append(files, GeneratedFile{Path: fmt.Sprintf("file%d.go", i)})
+	}
+
+	var running, peak atomic.Int32
+	done := 0
+	results := generateFiles(files, 3, func(f GeneratedFile) (*FileContent, error) {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			current := peak.Load()
+			if n <= current || peak.CompareAndSwap(current, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		if f.Path == "file4.go" {
+			return nil, errors.New("boom")
+		}
+		return &FileContent{Path: f.Path, Content: "package " + f.Path}, nil
+	}, func(GeneratedFile) { done++ })
+
+	assert.LessOrEqual(t, peak.Load(), int32(3))
+	assert.Equal(t, 10, done)
+	require.Len(t, results, 10)
+	for i, result := range results {
+		assert.Equal(t, files[i], result.File)
+		if i == 4 {
+			assert.EqualError(t, result.Err, "boom")
+			continue
+		}
+		require.NoError(t, result.Err)
+		assert.Equal(t, "package "+files[i].Path, result.Content.Content)
+	}
+}
+
+func TestGenerationConcurrency(t *testing.T) {
+	t.Cleanup(viper.Reset)
+
+	assert.Equal(t, defaultGenerationConcurrency, generationConcurrency())
+	viper.Set(generationConcurrencyKey, 8)
+	assert.Equal(t, 8, generationConcurrency())
+	viper.Set(generationConcurrencyKey, -1)
+	assert.Equal(t, defaultGenerationConcurrency, generationConcurrency())
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/MagdielCAS/magi-cli/pkg/shared"
 	"github.com/pterm/pterm"
@@ -16,26 +17,33 @@ func NewInitCmd() *cobra.Command {
 		Use:   "init",
 		Short: "Initialize project rules and configuration",
 		Long: `Analyzes the current project structure and creates/updates the .magi.yaml configuration
-and AGENTS.md rules file. Uses AI to detect architecture and suggest actions.`,
+and AGENTS.md rules file. Uses AI to detect architecture and suggest actions.
+
+Curated action templates for the detected architecture (for example create_slice and
+add_handler for vertical slice Go projects) are merged with the suggested actions. Pass
+--no-templates to keep only the AI suggestions.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			forceRules, _ := cmd.Flags().GetBool("force-rules")
+			noTemplates, _ := cmd.Flags().GetBool("no-templates")
 
 			// 1. Safety Confirm
-			confirm, _ := pterm.DefaultInteractiveConfirm.Show("This will analyze your project using LLM (consuming tokens) and may create/overwrite .magi.yaml. Proceed?")
+			confirm, _ := shared.Confirm("This will analyze your project using LLM (consuming tokens) and may create/overwrite .magi.yaml. Proceed?", false)
 			if !confirm {
 				pterm.Info.Println("Aborted by user.")
 				return nil
 			}
 
-			return RunAnalysisAndConfig(true, forceRules)
+			return RunAnalysisAndConfig(true, forceRules, !noTemplates)
 		},
 	}
 	cmd.Flags().Bool("force-rules", false, "Force creation/overwrite of AGENTS.md rules file")
+	cmd.Flags().Bool("no-templates", false, "Do not merge the built-in action templates into the suggested actions")
 	return cmd
 }
 
-// RunAnalysisAndConfig shared logic for init and redo.
-func RunAnalysisAndConfig(createRules bool, forceRules bool) error {
+// RunAnalysisAndConfig shared logic for init and redo. When useTemplates is set, the
+// built-in action templates matching the project are merged with the suggested actions.
+func RunAnalysisAndConfig(createRules bool, forceRules bool, useTemplates bool) error {
 	pterm.Info.Println("Initializing project analysis...")
 
 	cwd, err := os.Getwd()
@@ -61,19 +69,33 @@ func RunAnalysisAndConfig(createRules bool, forceRules bool) error {
 	// 2.5 Validation
 	validAgent := NewValidatorAgent(runtime)
 	spinnerVal, _ := pterm.DefaultSpinner.Start("Validating analysis results...")
-	analysis, err = validAgent.Validate(analysis)
+	validated, err := validAgent.Validate(analysis)
 	if err != nil {
+		// Proceed with the original analysis; validateConfig reports what still needs fixing.
 		spinnerVal.Warning("Validation incomplete: " + err.Error())
-		// Continue with original analysis instead of failing hard?
-		// Or fail? Let's log warning and proceed with potentially flawed analysis or original.
 	} else {
+		analysis = validated
 		spinnerVal.Success("Validation complete!")
 	}
 
+	// 2.6 Curated templates win over suggested actions with the same name.
+	var templateSets []string
+	if useTemplates {
+		templates, used, err := templateActions(cwd, analysis)
+		if err != nil {
+			return err
+		}
+		analysis.Actions = mergeActions(templates, analysis.Actions)
+		templateSets = used
+	}
+
 	// 3. Log Results
 	pterm.DefaultSection.Println("Project Analysis Result")
 	pterm.Info.Printf("Architecture: %s\n", analysis.Architecture)
 	pterm.Info.Printf("Project Type: %s\n", analysis.ProjectType)
+	if len(templateSets) > 0 {
+		pterm.Info.Printf("Action templates: %s\n", strings.Join(templateSets, ", "))
+	}
 	pterm.Info.Println("Identified Actions:")
 	for _, action := range analysis.Actions {
 		pterm.Println(pterm.Green("  - ") + action.Name + ": " + action.Description)
@@ -109,6 +131,13 @@ func RunAnalysisAndConfig(createRules bool, forceRules bool) error {
 		config.RulesPath = "AGENTS.md"
 	}
 
+	if issues := validateConfig(&config); len(issues) > 0 {
+		pterm.Warning.Printf("%s needs attention before its actions can run:\n", configFileName)
+		for _, issue := range issues {
+			pterm.Println("  " + issue.String())
+		}
+	}
+
 	// Save
 	data, err := yaml.Marshal(config)
 	if err != nil {
@@ -136,7 +165,7 @@ func RunAnalysisAndConfig(createRules bool, forceRules bool) error {
 				pterm.Info.Println("Forcing recreation of AGENTS.md...")
 			}
 		} else if !rulesExist {
-			createConfirm, _ := pterm.DefaultInteractiveConfirm.Show("Create default AGENTS.md?")
+			createConfirm, _ := shared.Confirm("Create default AGENTS.md?", false)
 			if createConfirm {
 				shouldCreate = true
 			}
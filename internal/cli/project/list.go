@@ -1,15 +1,15 @@
 package project
 
 import (
+	"errors"
 	"fmt"
 	"os"
-	"path/filepath"
 
 	"github.com/pterm/pterm"
 	"github.com/spf13/cobra"
-	"gopkg.in/yaml.v3"
 )
 
+// NewListCmd creates the list command, which prints the actions defined in .magi.yaml.
 func NewListCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "list",
@@ -21,20 +21,13 @@ func NewListCmd() *cobra.Command {
 				return fmt.Errorf("failed to get cwd: %w", err)
 			}
 
-			configPath := filepath.Join(cwd, ".magi.yaml")
-			if _, err := os.Stat(configPath); os.IsNotExist(err) {
+			config, err := loadMagiConfig(cwd)
+			if errors.Is(err, os.ErrNotExist) {
 				pterm.Warning.Println(".magi.yaml not found. Run 'magi project init' first.")
 				return nil
 			}
-
-			data, err := os.ReadFile(configPath)
 			if err != nil {
-				return fmt.Errorf("failed to read config: %w", err)
-			}
-
-			var config MagiConfig
-			if err := yaml.Unmarshal(data, &config); err != nil {
-				return fmt.Errorf("failed to parse config: %w", err)
+				return err
 			}
 
 			pterm.DefaultSection.Println("Available Actions")
@@ -61,6 +54,7 @@ func NewListCmd() *cobra.Command {
 			}
 
 			pterm.DefaultTable.WithHasHeader().WithData(tableData).Render()
+			pterm.Info.Println("Run 'magi project describe <action>' to see the steps an action runs.")
 			return nil
 		},
 	}
@@ -7,6 +7,9 @@ type MagiConfig struct {
 	Architecture string                 `mapstructure:"architecture" yaml:"architecture"`
 	ProjectType  string                 `mapstructure:"project_type" yaml:"project_type"`
 	Remaining    map[string]interface{} `mapstructure:",remain" yaml:",inline"`
+
+	// positions is filled by loadMagiConfig and only used to report validation issues.
+	positions configPositions
 }
 
 // Action defines a project-specific action (e.g., creating a slice, service, etc.).
@@ -0,0 +1,149 @@
+package project
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/MagdielCAS/magi-cli/pkg/shared"
+	"github.com/pterm/pterm"
+)
+
+const (
+	// diffContextLines is how many unchanged lines are shown around each change.
+	diffContextLines = 3
+	// maxDiffCells caps the size of the line table used to compute the diff.
+	maxDiffCells = 4_000_000
+)
+
+// confirmOverwrite decides whether generated may be written to fullPath. New files are
+// always written. An existing file is left alone when the content is unchanged; otherwise
+// the diff is shown and the file is only replaced after its own confirmation, which
+// --yes does not give, or with force.
+func confirmOverwrite(fullPath, displayPath, generated string, force bool) (bool, error) {
+	existing, err := os.ReadFile(fullPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return true, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read existing file '%s': %w", displayPath, err)
+	}
+	if string(existing) == generated {
+		pterm.Info.Printf("%s already has the generated content, leaving it unchanged.\n", displayPath)
+		return false, nil
+	}
+	if force {
+		pterm.Warning.Printf("Overwriting existing file %s (--force).\n", displayPath)
+		return true, nil
+	}
+
+	pterm.Warning.Printf("%s already exists. Changes the generated content would make:\n", displayPath)
+	printLineDiff(lineDiff(string(existing), generated))
+	confirm, _ := shared.Confirm("Overwrite "+displayPath+"?", false)
+	if !confirm {
+		pterm.Info.Printf("Kept the existing %s.\n", displayPath)
+	}
+	return confirm, nil
+}
+
+// printLineDiff prints the lines returned by lineDiff with removals in red and additions
+// in green.
+func printLineDiff(lines []string) {
+	for _, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "-"):
+			pterm.Println(pterm.Red(line))
+		case strings.HasPrefix(line, "+"):
+			pterm.Println(pterm.Green(line))
+		default:
+			pterm.Println(pterm.Gray(line))
+		}
+	}
+}
+
+// lineDiff compares two texts line by line and returns the changed lines prefixed with "-"
+// or "+", with diffContextLines unchanged lines (prefixed with " ") around them and "..."
+// for the unchanged runs in between. Texts too large to compare are summarized instead.
+func lineDiff(oldText, newText string) []string {
+	oldLines := splitLines(oldText)
+	newLines := splitLines(newText)
+	if (len(oldLines)+1)*(len(newLines)+1) > maxDiffCells {
+		return []string{fmt.Sprintf("... (%d lines would be replaced by %d lines; too large to compare)", len(oldLines), len(newLines))}
+	}
+
+	// common[i][j] is the length of the longest common subsequence of oldLines[i:] and newLines[j:].
+	common := make([][]int, len(oldLines)+1)
+	for i := range common {
+		common[i] = make([]int, len(newLines)+1)
+	}
+	for i := len(oldLines) - 1; i >= 0; i-- {
+		for j := len(newLines) - 1; j >= 0; j-- {
+			if oldLines[i] == newLines[j] {
+				common[i][j] = common[i+1][j+1] + 1
+			} else {
+				common[i][j] = max(common[i+1][j], common[i][j+1])
+			}
+		}
+	}
+
+	var all []string
+	i, j := 0, 0
+	for i < len(oldLines) || j < len(newLines) {
+		switch {
+		case i < len(oldLines) && j < len(newLines) && oldLines[i] == newLines[j]:
+			all = append(all, " "+oldLines[i])
+			i++
+			j++
+		case i < len(oldLines) && (j == len(newLines) || common[i+1][j] >= common[i][j+1]):
+			all = append(all, "-"+oldLines[i])
+			i++
+		default:
+			all = append(all, "+"+newLines[j])
+			j++
+		}
+	}
+	return withDiffContext(all, diffContextLines)
+}
+
+// withDiffContext keeps the changed lines of a full line diff and up to context unchanged
+// lines around each of them, replacing every other unchanged run with "...". It returns nil
+// when nothing changed.
+func withDiffContext(lines []string, context int) []string {
+	keep := make([]bool, len(lines))
+	changed := false
+	for i, line := range lines {
+		if strings.HasPrefix(line, " ") {
+			continue
+		}
+		changed = true
+		for k := max(0, i-context); k <= min(len(lines)-1, i+context); k++ {
+			keep[k] = true
+		}
+	}
+
+	if !changed {
+		return nil
+	}
+
+	var out []string
+	skipped := false
+	for i, line := range lines {
+		if keep[i] {
+			out = append(out, line)
+			skipped = false
+		} else if !skipped {
+			out = append(out, "...")
+			skipped = true
+		}
+	}
+	return out
+}
+
+// splitLines splits text into lines without their line endings.
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
@@ -0,0 +1,58 @@
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLineDiff(t *testing.T) {
+	oldText := "package api\n\nimport \"fmt\"\n\nfunc a() {}\n\nfunc b() {}\n\nfunc c() {}\n\nfunc d() {}\n"
+	newText := "package api\n\nimport \"fmt\"\n\nfunc a() {}\n\nfunc b() {}\n\nfunc c() {}\n\nfunc e() {}\n"
+
+	assert.Equal(t, []string{
+		"...",
+		" ",
+		" func c() {}",
+		" ",
+		"-func d() {}",
+		"+func e() {}",
+	}, lineDiff(oldText, newText))
+
+	assert.Empty(t, lineDiff(oldText, oldText))
+	assert.Equal(t, []string{"+a", "+b"}, lineDiff("", "a\nb\n"))
+}
+
+func TestLineDiffTooLarge(t *testing.T) {
+	big := strings.Repeat("x\n", 2100)
+	lines := lineDiff(big, big+"y\n")
+	require.Len(t, lines, 1)
+	assert.Contains(t, lines[0], "too large to compare")
+}
+
+func TestConfirmOverwrite(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "handler.go")
+
+	write, err := confirmOverwrite(path, "handler.go", "package api\n", false)
+	require.NoError(t, err)
+	assert.True(t, write, "new files are written")
+
+	require.NoError(t, os.WriteFile(path, []byte("package api\n"), 0644))
+	write, err = confirmOverwrite(path, "handler.go", "package api\n", true)
+	require.NoError(t, err)
+	assert.False(t, write, "unchanged content is not rewritten")
+
+	// Tests are not interactive, so the overwrite confirmation answers its default: no.
+	write, err = confirmOverwrite(path, "handler.go", "package users\n", false)
+	require.NoError(t, err)
+	assert.False(t, write)
+
+	write, err = confirmOverwrite(path, "handler.go", "package users\n", true)
+	require.NoError(t, err)
+	assert.True(t, write)
+}
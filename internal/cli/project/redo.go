@@ -1,6 +1,7 @@
 package project
 
 import (
+	"github.com/MagdielCAS/magi-cli/pkg/shared"
 	"github.com/pterm/pterm"
 	"github.com/spf13/cobra"
 )
@@ -10,21 +11,34 @@ func NewRedoCmd() *cobra.Command {
 		Use:   "redo",
 		Short: "Re-analyze project structure",
 		Long: `Re-runs the project analysis to identify new structures or actions.
-Updates .magi.yaml with findings.`,
+Updates .magi.yaml with findings and regenerates the AGENTS.md rules file.
+
+Use it to refresh the project rules after major structural changes. Pass --keep-rules to
+leave an existing AGENTS.md untouched, and --no-templates to skip the built-in action
+templates.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			forceRules, _ := cmd.Flags().GetBool("force-rules")
+			keepRules, _ := cmd.Flags().GetBool("keep-rules")
+			noTemplates, _ := cmd.Flags().GetBool("no-templates")
+
+			prompt := "This will re-analyze your project using LLM, update .magi.yaml, and regenerate AGENTS.md (overwriting it). Proceed?"
+			if keepRules {
+				prompt = "This will re-analyze your project using LLM and update .magi.yaml. Proceed?"
+			}
 
 			// Safety Confirm
-			confirm, _ := pterm.DefaultInteractiveConfirm.Show("This will re-analyze your project using LLM and update .magi.yaml. Proceed?")
+			confirm, _ := shared.Confirm(prompt, false)
 			if !confirm {
 				pterm.Info.Println("Aborted by user.")
 				return nil
 			}
 
 			// Reuse init logic
-			return RunAnalysisAndConfig(false, forceRules)
+			return RunAnalysisAndConfig(true, !keepRules, !noTemplates)
 		},
 	}
+	cmd.Flags().Bool("keep-rules", false, "Do not overwrite an existing AGENTS.md rules file")
+	cmd.Flags().Bool("no-templates", false, "Do not merge the built-in action templates into the suggested actions")
 	cmd.Flags().Bool("force-rules", false, "Force creation/overwrite of AGENTS.md rules file")
+	cmd.Flags().MarkDeprecated("force-rules", "AGENTS.md is now regenerated by default; use --keep-rules to opt out")
 	return cmd
 }
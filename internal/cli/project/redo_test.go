@@ -14,3 +14,12 @@ func TestNewRedoCmd(t *testing.T) {
 	assert.NotEmpty(t, cmd.Long)
 	assert.NotNil(t, cmd.RunE)
 }
+
+func TestNewRedoCmdFlags(t *testing.T) {
+	cmd := NewRedoCmd()
+	assert.NotNil(t, cmd.Flags().Lookup("keep-rules"))
+
+	forceRules := cmd.Flags().Lookup("force-rules")
+	assert.NotNil(t, forceRules)
+	assert.NotEmpty(t, forceRules.Deprecated)
+}
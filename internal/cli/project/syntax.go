@@ -0,0 +1,74 @@
+package project
+
+import (
+	"fmt"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"strings"
+
+	"github.com/MagdielCAS/magi-cli/pkg/llm"
+	"github.com/MagdielCAS/magi-cli/pkg/shared"
+	"github.com/pterm/pterm"
+)
+
+const fixGoSyntaxPrompt = `The Go file "%s" you produced does not parse:
+%v
+
+Fix only the syntax so the file parses, keeping its behavior and content otherwise unchanged.
+Return ONLY the corrected file content. No markdown code blocks, no explanations.
+
+%s`
+
+// parseGoSource reports the syntax errors of a Go source file, or nil when it parses.
+func parseGoSource(path, content string) error {
+	_, err := parser.ParseFile(token.NewFileSet(), path, content, parser.AllErrors)
+	return err
+}
+
+// ensureGoSyntax checks generated content for a .go path. When it does not parse, fix is
+// asked once for a corrected version, which is used if it parses. Otherwise the original
+// content is kept and a warning is printed, so the broken file is written but not silently.
+func ensureGoSyntax(path, content string, fix func(content string, parseErr error) (string, error)) string {
+	if !strings.EqualFold(filepath.Ext(path), ".go") {
+		return content
+	}
+	parseErr := parseGoSource(path, content)
+	if parseErr == nil {
+		return content
+	}
+
+	pterm.Info.Printf("Generated %s does not parse, asking the model to fix the syntax...\n", path)
+	fixed, err := fix(content, parseErr)
+	if err == nil {
+		if err = parseGoSource(path, fixed); err == nil {
+			return fixed
+		}
+	}
+	pterm.Warning.Printf("%s still has Go syntax errors and will be written as generated; it will not compile until fixed:\n%v\n", path, parseErr)
+	return content
+}
+
+// fixGoSyntax makes one corrective call asking the model to repair the syntax of content.
+func fixGoSyntax(service *llm.Service, path, content string, parseErr error) (string, error) {
+	resp, err := service.ChatCompletion(shared.BaseContext(), llm.ChatCompletionRequest{
+		Messages: []llm.ChatMessage{
+			{Role: "system", Content: "You are an expert Go developer who fixes syntax errors without changing behavior."},
+			{Role: "user", Content: fmt.Sprintf(fixGoSyntaxPrompt, path, parseErr, content)},
+		},
+		Temperature: 0.1,
+	})
+	if err != nil {
+		return "", err
+	}
+	return stripCodeFence(resp), nil
+}
+
+// stripCodeFence removes a markdown code block around generated file content.
+func stripCodeFence(resp string) string {
+	content := strings.TrimSpace(resp)
+	content = strings.TrimPrefix(content, "```go")
+	content = strings.TrimPrefix(content, "```")
+	content = strings.TrimSuffix(content, "```")
+	return content
+}
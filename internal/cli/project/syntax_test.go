@@ -0,0 +1,35 @@
+package project
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnsureGoSyntax(t *testing.T) {
+	valid := "package main\n\nfunc main() {}\n"
+	broken := "package main\n\nfunc main() {\n"
+
+	calls := 0
+	fix := func(content string, parseErr error) (string, error) {
+		calls++
+		require.Error(t, parseErr)
+		assert.Equal(t, broken, content)
+		return valid, nil
+	}
+
+	assert.Equal(t, valid, ensureGoSyntax("main.go", valid, fix))
+	assert.Equal(t, broken, ensureGoSyntax("main.ts", broken, fix), "only Go files are checked")
+	assert.Equal(t, 0, calls)
+
+	assert.Equal(t, valid, ensureGoSyntax("cmd/main.go", broken, fix))
+	assert.Equal(t, 1, calls)
+
+	stillBroken := func(string, error) (string, error) { return "package main\nfunc (", nil }
+	assert.Equal(t, broken, ensureGoSyntax("main.go", broken, stillBroken), "the original content is kept")
+
+	failing := func(string, error) (string, error) { return "", errors.New("provider down") }
+	assert.Equal(t, broken, ensureGoSyntax("main.go", broken, failing))
+}
@@ -76,7 +76,7 @@ Requires the file path as an argument.`,
 			}
 
 			agent := NewGeneratorAgent(runtime)
-			updatedFile, err := agent.UpdateContent(targetFile, string(content), instruction, architecture, projectType)
+			updatedFile, err := agent.UpdateContent(targetFile, string(content), instruction, architecture, projectType, nil)
 			if err != nil {
 				return fmt.Errorf("update failed: %w", err)
 			}
@@ -88,9 +88,9 @@ Requires the file path as an argument.`,
 			pterm.Info.Printf("File: %s\n", updatedFile.Path)
 			pterm.Info.Println("Content Length:", len(updatedFile.Content))
 
-			confirm, _ := pterm.DefaultInteractiveConfirm.Show("Apply changes?")
+			confirm, _ := shared.Confirm("Apply changes?", false)
 			if confirm {
-				if err := os.WriteFile(fullPath, []byte(updatedFile.Content), 0644); err != nil {
+				if err := writeGeneratedFile(fullPath, updatedFile.Content); err != nil {
 					return fmt.Errorf("failed to write file: %w", err)
 				}
 				pterm.Success.Println("File updated successfully.")
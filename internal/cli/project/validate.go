@@ -0,0 +1,163 @@
+package project
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/MagdielCAS/magi-cli/pkg/shared"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+type validateReport struct {
+	Valid  bool          `json:"valid"`
+	Fixed  bool          `json:"fixed,omitempty"`
+	Issues []configIssue `json:"issues"`
+}
+
+// NewValidateCmd creates the validate command, which lints the actions in .magi.yaml.
+func NewValidateCmd() *cobra.Command {
+	var fix bool
+
+	cmd := &cobra.Command{
+		Use:   "validate",
+		Short: "Check the actions defined in .magi.yaml",
+		Long: `Runs the same checks as 'magi project exec' against the current .magi.yaml and reports every
+problem with its line: action names must be unique snake_case, steps must use a known tool,
+run_command steps need a 'command' parameter and append_file steps a 'target'.
+
+The checks run locally and do not spend tokens. Pass --fix to send the actions and the reported
+issues to the ValidatorAgent, which asks the heavy model to correct them and rewrites .magi.yaml.
+
+Data handling:
+  - Without --fix nothing leaves your machine.
+  - With --fix the action definitions (names, descriptions, parameters, steps) are sent to the
+    configured LLM provider.
+
+Usage:
+  magi project validate [flags]
+
+Examples:
+  # Lint the action definitions
+  magi project validate
+
+  # Let the LLM fix the reported issues
+  magi project validate --fix`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cwd, err := os.Getwd()
+			if err != nil {
+				return fmt.Errorf("failed to get cwd: %w", err)
+			}
+			return runValidate(cwd, fix)
+		},
+	}
+
+	cmd.Flags().BoolVar(&fix, "fix", false, "Ask the LLM to fix the reported issues and rewrite .magi.yaml")
+	return cmd
+}
+
+func runValidate(dir string, fix bool) error {
+	config, err := loadMagiConfig(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		pterm.Warning.Println(".magi.yaml not found. Run 'magi project init' first.")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	issues := validateConfig(config)
+	report := validateReport{Valid: len(issues) == 0, Issues: issues}
+	if report.Issues == nil {
+		report.Issues = []configIssue{}
+	}
+
+	if len(issues) > 0 && fix {
+		fixed, err := fixConfig(config, issues)
+		if err != nil {
+			return err
+		}
+		if err := writeMagiConfig(dir, fixed); err != nil {
+			return err
+		}
+		report.Fixed = true
+		report.Issues = validateConfig(fixed)
+		report.Valid = len(report.Issues) == 0
+	}
+
+	if shared.IsJSONOutput() {
+		if err := shared.PrintJSON(report); err != nil {
+			return err
+		}
+	} else {
+		printValidateReport(report, len(config.Actions))
+	}
+
+	if !report.Valid {
+		return fmt.Errorf("%s has %d problem(s)", configFileName, len(report.Issues))
+	}
+	return nil
+}
+
+func printValidateReport(report validateReport, actions int) {
+	if report.Fixed {
+		pterm.Success.Printf("Updated %s with the ValidatorAgent fixes\n", configFileName)
+	}
+	if report.Valid {
+		pterm.Success.Printf("%s is valid (%d action(s))\n", configFileName, actions)
+		return
+	}
+
+	pterm.Error.Printf("%s has %d problem(s):\n", configFileName, len(report.Issues))
+	for _, issue := range report.Issues {
+		pterm.Println("  " + issue.String())
+	}
+	if !report.Fixed {
+		pterm.Info.Println("Run 'magi project validate --fix' to let the LLM correct them.")
+	}
+}
+
+// fixConfig sends the actions and the reported issues to the ValidatorAgent and returns a copy
+// of config with the corrected actions.
+func fixConfig(config *MagiConfig, issues []configIssue) (*MagiConfig, error) {
+	runtime, err := shared.BuildRuntimeContext()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load runtime context: %w", err)
+	}
+
+	messages := make([]string, 0, len(issues))
+	for _, issue := range issues {
+		messages = append(messages, issue.Message)
+	}
+
+	spinner, _ := pterm.DefaultSpinner.Start("Fixing action definitions...")
+	result, err := NewValidatorAgent(runtime).Fix(&AnalysisResult{
+		Architecture: config.Architecture,
+		ProjectType:  config.ProjectType,
+		Actions:      config.Actions,
+	}, messages)
+	if err != nil {
+		spinner.Fail("Fix failed: " + err.Error())
+		return nil, err
+	}
+	spinner.Success("Fix complete!")
+
+	fixed := *config
+	fixed.Actions = result.Actions
+	fixed.positions = configPositions{}
+	return &fixed, nil
+}
+
+func writeMagiConfig(dir string, config *MagiConfig) error {
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+	if err := shared.WriteFileAtomic(filepath.Join(dir, configFileName), data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", configFileName, err)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewValidateCmd(t *testing.T) {
+	cmd := NewValidateCmd()
+	assert.Equal(t, "validate", cmd.Use)
+	assert.NotEmpty(t, cmd.Short)
+	assert.NotEmpty(t, cmd.Long)
+	assert.NotNil(t, cmd.Flags().Lookup("fix"))
+}
+
+func TestRunValidate(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, runValidate(dir, false), "missing config only warns")
+
+	path := filepath.Join(dir, configFileName)
+	require.NoError(t, os.WriteFile(path, []byte(describeTestConfig), 0644))
+	assert.NoError(t, runValidate(dir, false))
+
+	require.NoError(t, os.WriteFile(path, []byte("actions:\n  - name: Bad\n    steps:\n      - tool: run_command\n"), 0644))
+	err := runValidate(dir, false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "2 problem(s)")
+}
+
+func TestWriteMagiConfigKeepsUnknownKeys(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, configFileName)
+	require.NoError(t, os.WriteFile(path, []byte("api:\n  model: gpt\nactions:\n  - name: old\n"), 0644))
+
+	config, err := loadMagiConfig(dir)
+	require.NoError(t, err)
+	config.Actions = []Action{{Name: "new_action"}}
+	require.NoError(t, writeMagiConfig(dir, config))
+
+	reloaded, err := loadMagiConfig(dir)
+	require.NoError(t, err)
+	assert.Equal(t, "new_action", reloaded.Actions[0].Name)
+	assert.Contains(t, reloaded.Remaining, "api")
+}
+
+func TestAnalysisIssues(t *testing.T) {
+	issues := analysisIssues(&AnalysisResult{Actions: []Action{{
+		Name:  "run_tests",
+		Steps: []ActionStep{{Tool: "run_command", Instruction: "go test ./..."}},
+	}}})
+	require.Len(t, issues, 1)
+	assert.Contains(t, issues[0], "without a 'command' parameter")
+}
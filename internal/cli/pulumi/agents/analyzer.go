@@ -3,8 +3,11 @@ package agents
 import (
 	"encoding/json"
 	"fmt"
+	"regexp"
+	"strconv"
 	"strings"
 
+	"github.com/MagdielCAS/magi-cli/internal/cli/pulumi/parsers"
 	"github.com/MagdielCAS/magi-cli/pkg/llm"
 	"github.com/MagdielCAS/magi-cli/pkg/shared"
 )
@@ -22,6 +25,7 @@ type ArchitectureAnalysis struct {
 	Security       SecurityRequirement   `json:"security"`
 	Monitoring     MonitoringRequirement `json:"monitoring"`
 	Estimated_Cost string                `json:"estimated_cost"`
+	CostDrivers    []string              `json:"cost_drivers"`
 }
 
 type ServiceRequirement struct {
@@ -45,10 +49,18 @@ type StorageRequirement struct {
 }
 
 type DatabaseRequirement struct {
-	Type   string `json:"type"`
-	Engine string `json:"engine"`
-	Size   string `json:"size"`
-	Backup bool   `json:"backup"`
+	Type   string             `json:"type"`
+	Engine string             `json:"engine"`
+	Size   string             `json:"size"`
+	Backup bool               `json:"backup"`
+	Tables []TableRequirement `json:"tables,omitempty"`
+}
+
+// TableRequirement is a table of a database, usually taken from an ER or class diagram.
+type TableRequirement struct {
+	Name       string   `json:"name"`
+	Columns    []string `json:"columns,omitempty"`
+	References []string `json:"references,omitempty"`
 }
 
 type FileStorageRequirement struct {
@@ -83,7 +95,7 @@ Your analysis should identify:
 4. Security requirements (IAM, encryption, VPC endpoints)
 5. Monitoring and logging requirements
 6. Service dependencies and relationships
-7. Estimated cost considerations
+7. Estimated cost considerations, including the resources most likely to dominate the monthly bill
 
 Use the MCP context to ensure recommendations follow current AWS and Pulumi best practices.
 
@@ -105,7 +117,7 @@ IMPORTANT: You must return the result as a valid JSON object with the following
     "cdn": false
   },
   "storage": {
-    "databases": [{"type": "rds", "engine": "postgres", "size": "db.t3.micro", "backup": true}],
+    "databases": [{"type": "rds", "engine": "postgres", "size": "db.t3.micro", "backup": true, "tables": [{"name": "orders", "columns": ["id", "customer_id"], "references": ["customers"]}]}],
     "file_storage": [{"type": "s3", "access_level": "private", "encryption": true}]
   },
   "security": {
@@ -119,8 +131,11 @@ IMPORTANT: You must return the result as a valid JSON object with the following
     "logging": true,
     "alerting": false
   },
-  "estimated_cost": "low/medium/high"
+  "estimated_cost": "low/medium/high",
+  "cost_drivers": ["NAT Gateway: hourly charge plus data processing per GB"]
 }
+When a data model is given, list its entities as the tables of the database that stores them.
+List at most five cost drivers, most expensive first, each as "resource: reason".
 Do not wrap the JSON in markdown code blocks. Return raw JSON only.`,
 		Personality: "Expert cloud architect with deep knowledge of AWS services, infrastructure patterns, and cost optimization. Skilled at translating business requirements into technical infrastructure specifications.",
 		Tools:       []string{"get_resource_details"},
@@ -190,6 +205,10 @@ func (a *ArchitectureAnalyzer) extractResourceTypes(input map[string]string) []s
 			resourceTypes = append(resourceTypes, service)
 		}
 	}
+	// An ER or class diagram describes tables, which live in a relational database.
+	if input["data_model"] != "" && !strings.Contains(text, "database") {
+		resourceTypes = append(resourceTypes, "rds")
+	}
 
 	return resourceTypes
 }
@@ -202,7 +221,10 @@ func (a *ArchitectureAnalyzer) buildArchitectureDescription(input map[string]str
 		parts = append(parts, fmt.Sprintf("Text Description: %s", text))
 	}
 
-	if mermaid := input["mermaid_content"]; mermaid != "" {
+	// A parsed ER or class diagram replaces the raw diagram text.
+	if dataModel := input["data_model"]; dataModel != "" {
+		parts = append(parts, dataModel)
+	} else if mermaid := input["mermaid_content"]; mermaid != "" {
 		parts = append(parts, fmt.Sprintf("Mermaid Diagram: %s", mermaid))
 	}
 
@@ -212,3 +234,123 @@ func (a *ArchitectureAnalyzer) buildArchitectureDescription(input map[string]str
 
 	return strings.Join(parts, "\n\n")
 }
+
+// relationalDatabaseTypes are the database types whose tables a data model describes.
+var relationalDatabaseTypes = []string{"rds", "aurora"}
+
+// ApplyDataModel makes sure the analysis stores the tables of model. When no database
+// lists tables yet, they are attached to the first relational database, or to a new
+// PostgreSQL RDS requirement when the analysis has none.
+func ApplyDataModel(analysis *ArchitectureAnalysis, model *parsers.DataModel) {
+	if analysis == nil || model == nil || len(model.Entities) == 0 {
+		return
+	}
+	for _, db := range analysis.Storage.Databases {
+		if len(db.Tables) > 0 {
+			return
+		}
+	}
+
+	tables := TablesFromDataModel(model)
+	for i, db := range analysis.Storage.Databases {
+		for _, relational := range relationalDatabaseTypes {
+			if strings.EqualFold(db.Type, relational) {
+				analysis.Storage.Databases[i].Tables = tables
+				return
+			}
+		}
+	}
+	analysis.Storage.Databases = append(analysis.Storage.Databases, DatabaseRequirement{
+		Type:   "rds",
+		Engine: "postgres",
+		Size:   "db.t3.micro",
+		Backup: true,
+		Tables: tables,
+	})
+}
+
+// TablesFromDataModel maps every entity of model to a table. A relationship makes the entity
+// on its "many" side reference the other one: the right entity unless the left side is the
+// many side, e.g. "}o--||" in an ER diagram or Order "*" --> "1" Customer in a class
+// diagram. Class inheritance and realization edges are not references and are skipped.
+func TablesFromDataModel(model *parsers.DataModel) []TableRequirement {
+	references := make(map[string][]string)
+	for _, relationship := range model.Relationships {
+		fromReferences, ok := referencingSide(relationship.Cardinality)
+		if !ok {
+			continue
+		}
+		from, to := relationship.From, relationship.To
+		if fromReferences {
+			from, to = to, from
+		}
+		references[to] = appendUnique(references[to], from)
+	}
+
+	tables := make([]TableRequirement, 0, len(model.Entities))
+	for _, entity := range model.Entities {
+		table := TableRequirement{Name: entity.Name, References: references[entity.Name]}
+		for _, attribute := range entity.Attributes {
+			table.Columns = append(table.Columns, attribute.Name)
+		}
+		tables = append(tables, table)
+	}
+	return tables
+}
+
+// classMultiplicityPattern splits a class relationship written as "left" arrow "right", the
+// form the parser uses when a multiplicity is given.
+var classMultiplicityPattern = regexp.MustCompile(`^"([^"]*)"\s+(\S+)\s+"([^"]*)"$`)
+
+// classInheritanceArrows are class diagram edges that map to no table reference.
+var classInheritanceArrows = map[string]bool{"<|--": true, "--|>": true, "<|..": true, "..|>": true}
+
+// referencingSide reports whether the left entity of a relationship references the right
+// one. ok is false for relationships that are not references, such as inheritance.
+func referencingSide(cardinality string) (fromReferences, ok bool) {
+	arrow, left, right := cardinality, "", ""
+	if m := classMultiplicityPattern.FindStringSubmatch(cardinality); m != nil {
+		left, arrow, right = m[1], m[2], m[3]
+	}
+	if classInheritanceArrows[arrow] {
+		return false, false
+	}
+	switch {
+	case strings.HasPrefix(arrow, "}"):
+		// ER diagram with the many side on the left, e.g. "}o--||".
+		return true, true
+	case isManyMultiplicity(left) != isManyMultiplicity(right):
+		return isManyMultiplicity(left), true
+	case arrow == "--*" || arrow == "--o":
+		// Composition or aggregation with the whole on the right.
+		return true, true
+	}
+	return false, true
+}
+
+// isManyMultiplicity reports whether a class multiplicity such as "*", "0..*", "1..*" or
+// "0..n" allows more than one instance.
+func isManyMultiplicity(multiplicity string) bool {
+	multiplicity = strings.ToLower(strings.TrimSpace(multiplicity))
+	if multiplicity == "" {
+		return false
+	}
+	upper := multiplicity
+	if i := strings.LastIndex(multiplicity, ".."); i >= 0 {
+		upper = multiplicity[i+2:]
+	}
+	if upper == "*" || upper == "n" || upper == "many" {
+		return true
+	}
+	n, err := strconv.Atoi(upper)
+	return err == nil && n > 1
+}
+
+func appendUnique(values []string, value string) []string {
+	for _, existing := range values {
+		if existing == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
@@ -0,0 +1,98 @@
+package agents
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/MagdielCAS/magi-cli/internal/cli/pulumi/parsers"
+)
+
+const sampleERDiagram = `erDiagram
+    CUSTOMER ||--o{ ORDER : places
+    CUSTOMER {
+        string id PK
+        string email
+    }
+    ORDER {
+        string id PK
+        string customer_id FK
+    }`
+
+func TestApplyDataModelAddsRDSWithTables(t *testing.T) {
+	model, ok := parsers.NewMermaidParser().ParseDataModel(sampleERDiagram)
+	if !ok {
+		t.Fatal("expected the ER diagram to parse")
+	}
+
+	analysis := &ArchitectureAnalysis{}
+	ApplyDataModel(analysis, model)
+
+	if len(analysis.Storage.Databases) != 1 {
+		t.Fatalf("expected one database, got %+v", analysis.Storage.Databases)
+	}
+	db := analysis.Storage.Databases[0]
+	if db.Type != "rds" || db.Engine != "postgres" {
+		t.Fatalf("expected a PostgreSQL RDS requirement, got %+v", db)
+	}
+	want := []TableRequirement{
+		{Name: "CUSTOMER", Columns: []string{"id", "email"}},
+		{Name: "ORDER", Columns: []string{"id", "customer_id"}, References: []string{"CUSTOMER"}},
+	}
+	if !reflect.DeepEqual(db.Tables, want) {
+		t.Fatalf("unexpected tables %+v", db.Tables)
+	}
+}
+
+func TestApplyDataModelUsesExistingDatabase(t *testing.T) {
+	model, _ := parsers.NewMermaidParser().ParseDataModel(sampleERDiagram)
+
+	analysis := &ArchitectureAnalysis{Storage: StorageRequirement{Databases: []DatabaseRequirement{
+		{Type: "dynamodb"},
+		{Type: "RDS", Engine: "mysql"},
+	}}}
+	ApplyDataModel(analysis, model)
+	if len(analysis.Storage.Databases) != 2 || len(analysis.Storage.Databases[1].Tables) != 2 || len(analysis.Storage.Databases[0].Tables) != 0 {
+		t.Fatalf("expected the tables on the existing RDS database, got %+v", analysis.Storage.Databases)
+	}
+
+	// Tables listed by the model are kept.
+	listed := &ArchitectureAnalysis{Storage: StorageRequirement{Databases: []DatabaseRequirement{
+		{Type: "rds", Tables: []TableRequirement{{Name: "customers"}}},
+	}}}
+	ApplyDataModel(listed, model)
+	if len(listed.Storage.Databases[0].Tables) != 1 {
+		t.Fatalf("expected the analysis tables to be kept, got %+v", listed.Storage.Databases[0].Tables)
+	}
+}
+
+func TestTablesFromClassDiagram(t *testing.T) {
+	model, ok := parsers.NewMermaidParser().ParseDataModel(`classDiagram
+    Order "*" --> "1" Customer : placed by
+    Customer "1" --> "0..*" Address
+    Invoice "1..*" --> "1" Order
+    Order *-- LineItem
+    Payment --o Order
+    Entity <|-- Customer
+    Payable <|.. Invoice`)
+	if !ok {
+		t.Fatal("expected the class diagram to parse")
+	}
+
+	references := make(map[string][]string)
+	for _, table := range TablesFromDataModel(model) {
+		references[table.Name] = table.References
+	}
+	want := map[string][]string{
+		"Order":    {"Customer"},
+		"Customer": nil,
+		"Address":  {"Customer"},
+		"Invoice":  {"Order"},
+		"LineItem": {"Order"},
+		"Payment":  {"Order"},
+		"Entity":   nil,
+		"Payable":  nil,
+	}
+	if !reflect.DeepEqual(references, want) {
+		t.Fatalf("unexpected references %v", references)
+	}
+}
@@ -105,9 +105,36 @@ func (g *PulumiGenerator) Generate(analysis *ArchitectureAnalysis, projectConfig
 		return nil, fmt.Errorf("failed to parse generated project: %w", err)
 	}
 
+	ensureReadme(project, analysis, projectConfig)
+
 	return project, nil
 }
 
+// ensureReadme adds a README.md with deployment instructions unless the model already
+// produced one.
+func ensureReadme(project *GeneratedProject, analysis *ArchitectureAnalysis, projectConfig map[string]string) {
+	if project.ProjectFiles == nil {
+		project.ProjectFiles = make(map[string]string)
+	}
+	for filename := range project.ProjectFiles {
+		if strings.EqualFold(filename, "README.md") {
+			return
+		}
+	}
+
+	var services []string
+	for _, service := range analysis.Services {
+		services = append(services, fmt.Sprintf("%s (%s)", service.Name, service.Type))
+	}
+
+	project.ProjectFiles["README.md"] = templates.GetReadmeTemplate(
+		projectConfig["project_name"],
+		projectConfig["aws_region"],
+		services,
+		project.Instructions,
+	)
+}
+
 // serializeAnalysis converts analysis to string for LLM processing
 func (g *PulumiGenerator) serializeAnalysis(analysis *ArchitectureAnalysis) string {
 	var parts []string
@@ -135,6 +162,16 @@ func (g *PulumiGenerator) serializeAnalysis(analysis *ArchitectureAnalysis) stri
 	parts = append(parts, "\n=== STORAGE ===")
 	for _, db := range analysis.Storage.Databases {
 		parts = append(parts, fmt.Sprintf("- Database: %s (%s), Size: %s, Backup: %t", db.Type, db.Engine, db.Size, db.Backup))
+		for _, table := range db.Tables {
+			line := fmt.Sprintf("  - Table %s", table.Name)
+			if len(table.Columns) > 0 {
+				line += fmt.Sprintf(", columns: %s", strings.Join(table.Columns, ", "))
+			}
+			if len(table.References) > 0 {
+				line += fmt.Sprintf(", references: %s", strings.Join(table.References, ", "))
+			}
+			parts = append(parts, line)
+		}
 	}
 	for _, storage := range analysis.Storage.FileStorage {
 		parts = append(parts, fmt.Sprintf("- File Storage: %s, Access: %s, Encryption: %t", storage.Type, storage.AccessLevel, storage.Encryption))
@@ -0,0 +1,30 @@
+package agents
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEnsureReadme(t *testing.T) {
+	analysis := &ArchitectureAnalysis{
+		Services: []ServiceRequirement{{Name: "api", Type: "lambda"}},
+	}
+	config := map[string]string{"project_name": "demo", "aws_region": "us-west-2"}
+
+	project := &GeneratedProject{ProjectFiles: map[string]string{"index.ts": "// code"}}
+	ensureReadme(project, analysis, config)
+
+	readme, ok := project.ProjectFiles["README.md"]
+	if !ok {
+		t.Fatal("expected README.md to be added")
+	}
+	if !strings.Contains(readme, "pulumi config set aws:region us-west-2") || !strings.Contains(readme, "api (lambda)") {
+		t.Fatalf("README missing project details:\n%s", readme)
+	}
+
+	existing := &GeneratedProject{ProjectFiles: map[string]string{"readme.md": "custom"}}
+	ensureReadme(existing, analysis, config)
+	if _, added := existing.ProjectFiles["README.md"]; added {
+		t.Fatal("expected existing README to be kept")
+	}
+}
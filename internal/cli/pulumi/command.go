@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/MagdielCAS/magi-cli/internal/cli/pulumi/agents"
 	"github.com/MagdielCAS/magi-cli/internal/cli/pulumi/parsers"
@@ -11,6 +12,7 @@ import (
 	"github.com/MagdielCAS/magi-cli/pkg/shared"
 	"github.com/pterm/pterm"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 type PulumiFlags struct {
@@ -23,6 +25,13 @@ type PulumiFlags struct {
 	AutoConfirm    bool
 	UseLocalMCP    bool
 	MCPServerURL   string
+	EstimateOnly   bool
+	StrictValidate bool
+
+	// regionFromFlag is set when --region was given, so the interactive mode does not ask.
+	regionFromFlag bool
+	// projectPrefix comes from pulumi.project_prefix.
+	projectPrefix string
 }
 
 func NewPulumiCommand() *cobra.Command {
@@ -47,7 +56,8 @@ FEATURES:
 
 INPUT OPTIONS:
 • Free text description via --text flag or interactive prompt
-• Mermaid architecture file via --mermaid flag
+• Mermaid architecture file via --mermaid flag (erDiagram and classDiagram
+  entities become the tables of a relational database)
 • Combined text + diagram for enhanced context
 
 OUTPUT:
@@ -66,35 +76,57 @@ EXAMPLES:
   # Interactive mode with custom output directory
   magi pulumi --output ./my-infrastructure
   
+  # Preview the implied infrastructure and cost band without generating anything
+  magi pulumi --text "Web app with RDS and S3" --estimate-only
+
+  # Ground validation in the TypeScript compiler and eslint (requires node tooling)
+  magi pulumi --text "Web app with RDS and S3" --strict-validate
+
   # Specify AWS region and project name
   magi pulumi --region us-west-2 --project my-app-infra
   
+  # Team defaults in .magi.yaml (flags still override them):
+  #   pulumi:
+  #     region: eu-west-1
+  #     project_prefix: acme
+  #     output_dir: ./infra
+
   # Use local MCP server
   magi pulumi --use-local-mcp --mcp-server http://localhost:3000
 
+  # List the tools exposed by the MCP server
+  magi pulumi mcp-tools
+
 The command uses MCP servers to access up-to-date Pulumi documentation and 
 AWS best practices, ensuring generated code follows current standards.`,
-		Run: func(cmd *cobra.Command, args []string) {
-			runPulumi(flags)
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := applyConfigDefaults(cmd.Flags(), flags); err != nil {
+				return err
+			}
+			return runPulumi(flags)
 		},
 	}
 
 	// Add flags
 	cmd.Flags().StringVarP(&flags.InputText, "text", "t", "", "Natural language description of infrastructure")
 	cmd.Flags().StringVarP(&flags.MermaidFile, "mermaid", "m", "", "Path to Mermaid architecture diagram file")
-	cmd.Flags().StringVarP(&flags.OutputDir, "output", "o", "./pulumi-infrastructure", "Output directory for generated project")
-	cmd.Flags().StringVarP(&flags.ProjectName, "project", "p", "", "Pulumi project name (auto-generated if not provided)")
-	cmd.Flags().StringVarP(&flags.AwsRegion, "region", "r", "us-east-1", "AWS region for resources")
+	cmd.Flags().StringVarP(&flags.OutputDir, "output", "o", defaultOutputDir, "Output directory for generated project (config: pulumi.output_dir)")
+	cmd.Flags().StringVarP(&flags.ProjectName, "project", "p", "", "Pulumi project name (auto-generated if not provided; prefixed with pulumi.project_prefix)")
+	cmd.Flags().StringVarP(&flags.AwsRegion, "region", "r", defaultRegion, "AWS region for resources (config: pulumi.region)")
 	cmd.Flags().BoolVar(&flags.SkipValidation, "skip-validation", false, "Skip infrastructure validation")
 	cmd.Flags().BoolVarP(&flags.AutoConfirm, "yes", "y", false, "Auto-confirm all prompts")
 	cmd.Flags().BoolVar(&flags.UseLocalMCP, "use-local-mcp", false, "Use local MCP server instead of default")
 	cmd.Flags().StringVar(&flags.MCPServerURL, "mcp-server", "", "Custom MCP server URL")
+	cmd.Flags().BoolVar(&flags.StrictValidate, "strict-validate", false, "Also type-check and lint the generated project with tsc and eslint when they are installed")
+	cmd.Flags().BoolVar(&flags.EstimateOnly, "estimate-only", false, "Only analyze the input and print the implied infrastructure and cost band, without generating files")
 
 	// Flag completions
 	cmd.RegisterFlagCompletionFunc("region", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		return []string{"us-east-1", "us-west-2", "eu-west-1", "ap-southeast-1"}, cobra.ShellCompDirectiveNoFileComp
 	})
 
+	cmd.AddCommand(newMCPToolsCmd())
+
 	cmd.RegisterFlagCompletionFunc("mermaid", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		return nil, cobra.ShellCompDirectiveFilterFileExt
 	})
@@ -102,46 +134,42 @@ AWS best practices, ensuring generated code follows current standards.`,
 	return cmd
 }
 
-func runPulumi(flags *PulumiFlags) {
+func runPulumi(flags *PulumiFlags) error {
 	// Validate inputs
 	if flags.InputText == "" && flags.MermaidFile == "" {
+		if !shared.IsInteractive() {
+			return shared.NonInteractiveError("--text or --mermaid")
+		}
 		if !collectInputInteractively(flags) {
-			pterm.Error.Println("No input provided. Operation cancelled.")
-			return
+			return fmt.Errorf("no input provided, operation cancelled")
 		}
 	}
 
 	// Initialize MCP client
 	mcpClient, err := initializeMCPClient(flags)
 	if err != nil {
-		pterm.Error.Printf("Failed to initialize MCP client: %v\n", err)
-		return
+		return fmt.Errorf("failed to initialize MCP client: %w", err)
 	}
 	defer mcpClient.Close()
 
+	if flags.EstimateOnly {
+		return estimateInfrastructure(flags, mcpClient)
+	}
+
 	// Generate infrastructure
 	if err := generateInfrastructure(flags, mcpClient); err != nil {
-		pterm.Error.Printf("Failed to generate infrastructure: %v\n", err)
-		return
+		return fmt.Errorf("failed to generate infrastructure: %w", err)
 	}
 
 	pterm.Success.Printf("Pulumi project generated successfully in: %s\n", flags.OutputDir)
+	return nil
 }
 
 func initializeMCPClient(flags *PulumiFlags) (*llm.MCPClient, error) {
-	serverURL := flags.MCPServerURL
-	if serverURL == "" {
-		if flags.UseLocalMCP {
-			serverURL = "http://localhost:3000" // Default local
-		} else if envURL := os.Getenv("MCP_SERVER_URL"); envURL != "" {
-			serverURL = envURL
-		} else {
-			// Use official Pulumi MCP server
-			serverURL = "https://mcp.ai.pulumi.com/mcp"
-		}
-	}
+	serverURL := resolveMCPServerURL(flags.MCPServerURL, flags.UseLocalMCP)
+	shared.SetStage("Connecting to the MCP server")
 
-	client := llm.NewMCPClient(serverURL)
+	client := newMCPClient(serverURL)
 	if err := client.Connect(); err != nil {
 		// Log warning but proceed, as agents should handle missing tools gracefully
 		pterm.Warning.Printf("Could not connect to MCP server at %s: %v. Continuing without MCP tools.\n", serverURL, err)
@@ -150,6 +178,37 @@ func initializeMCPClient(flags *PulumiFlags) (*llm.MCPClient, error) {
 	return client, nil
 }
 
+// resolveMCPServerURL picks the MCP server from the flag, the local default, the
+// MCP_SERVER_URL environment variable, or the official Pulumi server, in that order.
+func resolveMCPServerURL(serverURL string, useLocal bool) string {
+	if serverURL != "" {
+		return serverURL
+	}
+	if useLocal {
+		return "http://localhost:3000" // Default local
+	}
+	if envURL := os.Getenv("MCP_SERVER_URL"); envURL != "" {
+		return envURL
+	}
+	// Use official Pulumi MCP server
+	return "https://mcp.ai.pulumi.com/mcp"
+}
+
+// newMCPClient creates an MCP client configured with the pulumi.mcp.* settings.
+func newMCPClient(serverURL string) *llm.MCPClient {
+	return llm.NewMCPClient(serverURL).
+		WithTimeout(viper.GetDuration("pulumi.mcp.timeout")).
+		WithRetry(mcpMaxRetries(), viper.GetDuration("pulumi.mcp.retry_backoff"))
+}
+
+// mcpMaxRetries returns pulumi.mcp.max_retries, or -1 to keep the client default when unset.
+func mcpMaxRetries() int {
+	if !viper.IsSet("pulumi.mcp.max_retries") {
+		return -1
+	}
+	return viper.GetInt("pulumi.mcp.max_retries")
+}
+
 func generateInfrastructure(flags *PulumiFlags, mcpClient *llm.MCPClient) error {
 	// Build RuntimeContext
 	runtime, err := shared.BuildRuntimeContext()
@@ -158,6 +217,88 @@ func generateInfrastructure(flags *PulumiFlags, mcpClient *llm.MCPClient) error
 	}
 
 	// 1. Analyze Architecture
+	analysis, err := analyzeArchitecture(flags, mcpClient, runtime)
+	if err != nil {
+		return err
+	}
+
+	// Show summary of analysis
+	pterm.Info.Printf("Identified %d services, %d databases\n", len(analysis.Services), len(analysis.Storage.Databases))
+	printCostEstimate(analysis)
+
+	// 2. Generate Code
+	generator := agents.NewPulumiGenerator(mcpClient, runtime)
+
+	projectConfig := map[string]string{
+		"project_name":     resolveProjectName(flags.ProjectName, flags.projectPrefix),
+		"aws_region":       flags.AwsRegion,
+		"output_directory": flags.OutputDir,
+	}
+
+	pterm.Info.Println("Generating Pulumi project code...")
+	shared.SetStage("Generating the Pulumi project")
+	project, err := generator.Generate(analysis, projectConfig)
+	warnMCPLookupErrors(generator.LookupErrors())
+	if err != nil {
+		return fmt.Errorf("generation failed: %w", err)
+	}
+
+	// 3. Validate (if not skipped)
+	var validationResult *agents.ValidationResult
+	if !flags.SkipValidation {
+		pterm.Info.Println("Validating generated infrastructure...")
+		shared.SetStage("Validating the generated project")
+		validator := agents.NewInfrastructureValidator(mcpClient, runtime)
+		validationResult, err = validator.Validate(project)
+		warnMCPLookupErrors(validator.LookupErrors())
+		if err != nil {
+			pterm.Warning.Printf("Validation failed: %v\n", err)
+		}
+	}
+	if flags.StrictValidate {
+		pterm.Info.Println("Checking the generated project with tsc and eslint...")
+		shared.SetStage("Checking the generated project with tsc and eslint")
+		validationResult = strictValidate(project, validationResult)
+	}
+	if validationResult != nil {
+		if !validationResult.IsValid {
+			pterm.Warning.Println("Validation found issues:")
+			for _, issue := range validationResult.Issues {
+				pterm.Warning.Printf("- %s\n", issue)
+			}
+			for _, risk := range validationResult.SecurityRisks {
+				pterm.Error.Printf("- Security Risk: %s\n", risk)
+			}
+
+			if !flags.AutoConfirm {
+				confirm, _ := shared.Confirm("Do you want to proceed with writing files despite these issues?", false)
+				if !confirm {
+					return fmt.Errorf("operation cancelled by user due to validation issues")
+				}
+			}
+		} else {
+			pterm.Success.Println("Validation passed successfully.")
+		}
+	}
+
+	// 4. Write Files
+	shared.SetStage("Writing the project files")
+	if err := writeProjectFiles(project, flags.OutputDir); err != nil {
+		return fmt.Errorf("failed to write project files: %w", err)
+	}
+
+	return nil
+}
+
+// warnMCPLookupErrors reports the best-effort MCP lookups an agent had to go without.
+func warnMCPLookupErrors(errs []error) {
+	for _, err := range errs {
+		pterm.Warning.Printf("%v. Continuing without it.\n", err)
+	}
+}
+
+// analyzeArchitecture parses the text and Mermaid inputs and runs the architecture analyzer.
+func analyzeArchitecture(flags *PulumiFlags, mcpClient *llm.MCPClient, runtime *shared.RuntimeContext) (*agents.ArchitectureAnalysis, error) {
 	analyzer := agents.NewArchitectureAnalyzer(mcpClient, runtime)
 
 	input := map[string]string{
@@ -168,83 +309,162 @@ func generateInfrastructure(flags *PulumiFlags, mcpClient *llm.MCPClient) error
 		textParser := parsers.NewTextParser()
 		processedText, err := textParser.Process(flags.InputText)
 		if err != nil {
-			return fmt.Errorf("invalid text input: %w", err)
+			return nil, fmt.Errorf("invalid text input: %w", err)
 		}
 		input["text"] = processedText
 	}
 
+	var dataModel *parsers.DataModel
 	if flags.MermaidFile != "" {
 		mermaidParser := parsers.NewMermaidParser()
 		content, err := mermaidParser.ParseFile(flags.MermaidFile)
 		if err != nil {
-			return fmt.Errorf("failed to parse mermaid file: %w", err)
+			return nil, fmt.Errorf("failed to parse mermaid file: %w", err)
 		}
 		input["mermaid_content"] = content
+		if model, ok := mermaidParser.ParseDataModel(content); ok {
+			dataModel = model
+			input["data_model"] = model.Describe()
+			pterm.Info.Printf("Parsed %d entities and %d relationships from the %s.\n", len(model.Entities), len(model.Relationships), model.Kind)
+		}
 	}
 
 	pterm.Info.Println("Analyzing architecture requirements...")
+	shared.SetStage("Analyzing the architecture")
 	analysis, err := analyzer.Analyze(input)
+	warnMCPLookupErrors(analyzer.LookupErrors())
 	if err != nil {
-		return fmt.Errorf("analysis failed: %w", err)
+		return nil, fmt.Errorf("analysis failed: %w", err)
 	}
+	agents.ApplyDataModel(analysis, dataModel)
 
-	// Show summary of analysis
-	pterm.Info.Printf("Identified %d services, %d databases\n", len(analysis.Services), len(analysis.Storage.Databases))
-
-	// 2. Generate Code
-	generator := agents.NewPulumiGenerator(mcpClient, runtime)
+	return analysis, nil
+}
 
-	projectConfig := map[string]string{
-		"project_name":     flags.ProjectName,
-		"aws_region":       flags.AwsRegion,
-		"output_directory": flags.OutputDir,
+// estimateInfrastructure runs only the architecture analysis and prints what the input
+// implies with its cost band, without generating or writing the project.
+func estimateInfrastructure(flags *PulumiFlags, mcpClient *llm.MCPClient) error {
+	runtime, err := shared.BuildRuntimeContext()
+	if err != nil {
+		return fmt.Errorf("failed to build runtime context: %w", err)
+	}
+	analysis, err := analyzeArchitecture(flags, mcpClient, runtime)
+	if err != nil {
+		return err
 	}
 
-	if projectConfig["project_name"] == "" {
-		projectConfig["project_name"] = "pulumi-generated-project"
+	if shared.IsJSONOutput() {
+		return shared.PrintJSON(analysis)
 	}
+	pterm.DefaultSection.Println("Infrastructure estimate")
+	pterm.Println(formatAnalysisSummary(analysis))
+	printCostEstimate(analysis)
+	pterm.Info.Println("No files were written. Run without --estimate-only to generate the project.")
+	return nil
+}
 
-	pterm.Info.Println("Generating Pulumi project code...")
-	project, err := generator.Generate(analysis, projectConfig)
-	if err != nil {
-		return fmt.Errorf("generation failed: %w", err)
+// formatAnalysisSummary lists the services, networking, storage, security and monitoring
+// requirements of an analysis.
+func formatAnalysisSummary(analysis *agents.ArchitectureAnalysis) string {
+	var lines []string
+	section := func(title string, items []string) {
+		if len(items) == 0 {
+			items = []string{"none"}
+		}
+		lines = append(lines, title+":")
+		for _, item := range items {
+			lines = append(lines, "  - "+item)
+		}
 	}
 
-	// 3. Validate (if not skipped)
-	if !flags.SkipValidation {
-		pterm.Info.Println("Validating generated infrastructure...")
-		validator := agents.NewInfrastructureValidator(mcpClient, runtime)
-		validationResult, err := validator.Validate(project)
-		if err != nil {
-			pterm.Warning.Printf("Validation failed: %v\n", err)
-		} else {
-			if !validationResult.IsValid {
-				pterm.Warning.Println("Validation found issues:")
-				for _, issue := range validationResult.Issues {
-					pterm.Warning.Printf("- %s\n", issue)
-				}
-				for _, risk := range validationResult.SecurityRisks {
-					pterm.Error.Printf("- Security Risk: %s\n", risk)
-				}
+	var services []string
+	for _, service := range analysis.Services {
+		item := fmt.Sprintf("%s (%s)", service.Name, service.Type)
+		if service.Description != "" {
+			item += ": " + service.Description
+		}
+		services = append(services, item)
+	}
+	section("Services", services)
 
-				if !flags.AutoConfirm {
-					confirm, _ := pterm.DefaultInteractiveConfirm.Show("Do you want to proceed with writing files despite these issues?")
-					if !confirm {
-						return fmt.Errorf("operation cancelled by user due to validation issues")
-					}
-				}
-			} else {
-				pterm.Success.Println("Validation passed successfully.")
+	var networking []string
+	if analysis.Networking.VPC {
+		networking = append(networking, "VPC")
+	}
+	if len(analysis.Networking.Subnets) > 0 {
+		networking = append(networking, "Subnets: "+strings.Join(analysis.Networking.Subnets, ", "))
+	}
+	if analysis.Networking.LoadBalancer {
+		networking = append(networking, "Load balancer")
+	}
+	if analysis.Networking.CDN {
+		networking = append(networking, "CDN")
+	}
+	section("Networking", networking)
+
+	var storage []string
+	for _, db := range analysis.Storage.Databases {
+		item := fmt.Sprintf("Database: %s (%s, %s)", db.Type, db.Engine, db.Size)
+		if len(db.Tables) > 0 {
+			names := make([]string, 0, len(db.Tables))
+			for _, table := range db.Tables {
+				names = append(names, table.Name)
 			}
+			item += ", tables: " + strings.Join(names, ", ")
 		}
+		storage = append(storage, item)
+	}
+	for _, fs := range analysis.Storage.FileStorage {
+		storage = append(storage, fmt.Sprintf("File storage: %s (%s)", fs.Type, fs.AccessLevel))
 	}
+	section("Storage", storage)
 
-	// 4. Write Files
-	if err := writeProjectFiles(project, flags.OutputDir); err != nil {
-		return fmt.Errorf("failed to write project files: %w", err)
+	var security []string
+	if len(analysis.Security.IAMRoles) > 0 {
+		security = append(security, "IAM roles: "+strings.Join(analysis.Security.IAMRoles, ", "))
+	}
+	if analysis.Security.Encryption {
+		security = append(security, "Encryption")
+	}
+	if analysis.Security.VPCEndpoints {
+		security = append(security, "VPC endpoints")
 	}
+	if analysis.Security.WAF {
+		security = append(security, "WAF")
+	}
+	section("Security", security)
 
-	return nil
+	var monitoring []string
+	if analysis.Monitoring.CloudWatch {
+		monitoring = append(monitoring, "CloudWatch")
+	}
+	if analysis.Monitoring.Logging {
+		monitoring = append(monitoring, "Logging")
+	}
+	if analysis.Monitoring.Alerting {
+		monitoring = append(monitoring, "Alerting")
+	}
+	section("Monitoring", monitoring)
+
+	return strings.Join(lines, "\n")
+}
+
+// printCostEstimate shows the analyzer's advisory cost level and main cost drivers.
+func printCostEstimate(analysis *agents.ArchitectureAnalysis) {
+	level := strings.TrimSpace(analysis.Estimated_Cost)
+	if level == "" && len(analysis.CostDrivers) == 0 {
+		return
+	}
+	if level == "" {
+		level = "unknown"
+	}
+
+	pterm.Info.Printf("Estimated cost (AI estimate, not a quote): %s\n", level)
+	for _, driver := range analysis.CostDrivers {
+		if driver = strings.TrimSpace(driver); driver != "" {
+			pterm.Printf("  - %s\n", driver)
+		}
+	}
 }
 
 func writeProjectFiles(project *agents.GeneratedProject, outputDir string) error {
@@ -275,15 +495,19 @@ func collectInputInteractively(flags *PulumiFlags) bool {
 	flags.InputText = text
 
 	if flags.ProjectName == "" {
-		name, _ := pterm.DefaultInteractiveTextInput.Show("Project Name (default: pulumi-generated-project)")
+		name, _ := pterm.DefaultInteractiveTextInput.
+			WithDefaultValue(resolveProjectName("", flags.projectPrefix)).
+			Show("Project Name")
 		if name != "" {
 			flags.ProjectName = name
 		}
 	}
 
-	if flags.AwsRegion == "us-east-1" { // Default value
-		region, _ := pterm.DefaultInteractiveTextInput.Show("AWS Region (default: us-east-1)")
-		if region != "" {
+	if !flags.regionFromFlag {
+		region, _ := pterm.DefaultInteractiveTextInput.
+			WithDefaultValue(flags.AwsRegion).
+			Show("AWS Region")
+		if region = strings.TrimSpace(region); region != "" {
 			flags.AwsRegion = region
 		}
 	}
@@ -0,0 +1,41 @@
+package pulumi
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/MagdielCAS/magi-cli/internal/cli/pulumi/agents"
+)
+
+func TestFormatAnalysisSummary(t *testing.T) {
+	analysis := &agents.ArchitectureAnalysis{
+		Services:   []agents.ServiceRequirement{{Name: "api", Type: "lambda", Description: "REST API"}},
+		Networking: agents.NetworkRequirement{VPC: true, Subnets: []string{"public", "private"}},
+		Storage: agents.StorageRequirement{
+			Databases:   []agents.DatabaseRequirement{{Type: "rds", Engine: "postgres", Size: "small", Tables: []agents.TableRequirement{{Name: "users"}, {Name: "orders"}}}},
+			FileStorage: []agents.FileStorageRequirement{{Type: "s3", AccessLevel: "private"}},
+		},
+		Security: agents.SecurityRequirement{IAMRoles: []string{"api-role"}, WAF: true},
+	}
+
+	got := formatAnalysisSummary(analysis)
+	for _, want := range []string{
+		"Services:\n  - api (lambda): REST API",
+		"Networking:\n  - VPC\n  - Subnets: public, private",
+		"  - Database: rds (postgres, small), tables: users, orders",
+		"  - File storage: s3 (private)",
+		"Security:\n  - IAM roles: api-role\n  - WAF",
+		"Monitoring:\n  - none",
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("expected summary to contain %q, got:\n%s", want, got)
+		}
+	}
+}
+
+func TestEstimateOnlyFlag(t *testing.T) {
+	cmd := NewPulumiCommand()
+	if cmd.Flags().Lookup("estimate-only") == nil {
+		t.Fatal("expected the --estimate-only flag to be registered")
+	}
+}
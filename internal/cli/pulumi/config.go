@@ -0,0 +1,67 @@
+package pulumi
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/MagdielCAS/magi-cli/pkg/shared"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// Team defaults read from the pulumi: section of .magi.yaml or the global config.
+const (
+	regionKey        = "pulumi.region"
+	projectPrefixKey = "pulumi.project_prefix"
+	outputDirKey     = "pulumi.output_dir"
+	cloudKey         = "pulumi.cloud"
+
+	defaultRegion      = "us-east-1"
+	defaultOutputDir   = "./pulumi-infrastructure"
+	defaultProjectName = "pulumi-generated-project"
+)
+
+// supportedClouds lists the values pulumi.cloud accepts.
+var supportedClouds = []string{"aws"}
+
+// applyConfigDefaults fills the region and output directory from the configuration when
+// the matching flag was not given, and keeps the project prefix for resolveProjectName.
+// An unsupported pulumi.cloud is a configuration error.
+func applyConfigDefaults(fs *pflag.FlagSet, flags *PulumiFlags) error {
+	cloud := strings.ToLower(strings.TrimSpace(viper.GetString(cloudKey)))
+	if cloud != "" && !isSupportedCloud(cloud) {
+		return shared.ConfigError(fmt.Errorf("%s %q is not supported (supported: %s)", cloudKey, cloud, strings.Join(supportedClouds, ", ")))
+	}
+
+	flags.regionFromFlag = fs.Changed("region")
+	if region := strings.TrimSpace(viper.GetString(regionKey)); region != "" && !flags.regionFromFlag {
+		flags.AwsRegion = region
+	}
+	if dir := strings.TrimSpace(viper.GetString(outputDirKey)); dir != "" && !fs.Changed("output") {
+		flags.OutputDir = dir
+	}
+	flags.projectPrefix = strings.Trim(strings.TrimSpace(viper.GetString(projectPrefixKey)), "-")
+	return nil
+}
+
+func isSupportedCloud(cloud string) bool {
+	for _, supported := range supportedClouds {
+		if cloud == supported {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveProjectName returns the Pulumi project name: name, or the default when empty,
+// starting with "<prefix>-" when a project prefix is configured.
+func resolveProjectName(name, prefix string) string {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = defaultProjectName
+	}
+	if prefix == "" || strings.HasPrefix(name, prefix+"-") {
+		return name
+	}
+	return prefix + "-" + name
+}
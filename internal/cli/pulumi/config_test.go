@@ -0,0 +1,62 @@
+package pulumi
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestApplyConfigDefaults(t *testing.T) {
+	t.Cleanup(viper.Reset)
+	viper.Set(regionKey, "eu-west-1")
+	viper.Set(outputDirKey, "./infra")
+	viper.Set(projectPrefixKey, "acme-")
+
+	flags := &PulumiFlags{}
+	cmd := NewPulumiCommand()
+	flags.AwsRegion, _ = cmd.Flags().GetString("region")
+	flags.OutputDir, _ = cmd.Flags().GetString("output")
+	if err := applyConfigDefaults(cmd.Flags(), flags); err != nil {
+		t.Fatalf("applyConfigDefaults: %v", err)
+	}
+	if flags.AwsRegion != "eu-west-1" || flags.OutputDir != "./infra" || flags.projectPrefix != "acme" || flags.regionFromFlag {
+		t.Fatalf("expected the configured defaults, got %+v", flags)
+	}
+
+	cmd = NewPulumiCommand()
+	if err := cmd.Flags().Set("region", "us-west-2"); err != nil {
+		t.Fatalf("set region: %v", err)
+	}
+	flags = &PulumiFlags{AwsRegion: "us-west-2", OutputDir: defaultOutputDir}
+	if err := applyConfigDefaults(cmd.Flags(), flags); err != nil {
+		t.Fatalf("applyConfigDefaults: %v", err)
+	}
+	if flags.AwsRegion != "us-west-2" || !flags.regionFromFlag || flags.OutputDir != "./infra" {
+		t.Fatalf("expected --region to override the config, got %+v", flags)
+	}
+
+	viper.Set(cloudKey, "gcp")
+	if err := applyConfigDefaults(cmd.Flags(), &PulumiFlags{}); err == nil || !strings.Contains(err.Error(), "supported: aws") {
+		t.Fatalf("expected an unsupported cloud to be rejected, got %v", err)
+	}
+	viper.Set(cloudKey, "AWS")
+	if err := applyConfigDefaults(cmd.Flags(), &PulumiFlags{}); err != nil {
+		t.Fatalf("expected aws to be accepted, got %v", err)
+	}
+}
+
+func TestResolveProjectName(t *testing.T) {
+	cases := []struct{ name, prefix, want string }{
+		{"", "", defaultProjectName},
+		{"payments", "", "payments"},
+		{"payments", "acme", "acme-payments"},
+		{"acme-payments", "acme", "acme-payments"},
+		{"", "acme", "acme-" + defaultProjectName},
+	}
+	for _, tc := range cases {
+		if got := resolveProjectName(tc.name, tc.prefix); got != tc.want {
+			t.Fatalf("resolveProjectName(%q, %q) = %q, want %q", tc.name, tc.prefix, got, tc.want)
+		}
+	}
+}
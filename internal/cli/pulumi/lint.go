@@ -0,0 +1,201 @@
+package pulumi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/MagdielCAS/magi-cli/internal/cli/pulumi/agents"
+	"github.com/MagdielCAS/magi-cli/pkg/shared"
+	"github.com/pterm/pterm"
+)
+
+const lintTimeout = 2 * time.Minute
+
+// tscDiagnostic matches a diagnostic printed by tsc --pretty false, such as
+// "index.ts(3,7): error TS2322: Type 'number' is not assignable to type 'string'.".
+var tscDiagnostic = regexp.MustCompile(`^(.+)\((\d+),(\d+)\): error (TS\d+): (.*)$`)
+
+// tscMissingPackage matches the diagnostic tsc reports for an import of a package that is
+// not installed. The project is checked before npm install, so those are expected.
+var tscMissingPackage = regexp.MustCompile(`^Cannot find module '[^./][^']*'`)
+
+// strictValidate writes the project to a temporary directory, runs tsc --noEmit and eslint
+// on it and adds their diagnostics to result, which may be nil when the model validation
+// was skipped or failed. Tools that are not installed are skipped with a note.
+func strictValidate(project *agents.GeneratedProject, result *agents.ValidationResult) *agents.ValidationResult {
+	if result == nil {
+		result = &agents.ValidationResult{IsValid: true}
+	}
+
+	dir, err := os.MkdirTemp("", "magi-pulumi-lint-")
+	if err != nil {
+		pterm.Warning.Printf("Skipped strict validation: %v\n", err)
+		return result
+	}
+	defer os.RemoveAll(dir)
+	if err := writeProjectFiles(project, dir); err != nil {
+		pterm.Warning.Printf("Skipped strict validation: %v\n", err)
+		return result
+	}
+
+	linters := []struct {
+		name string
+		run  func(dir string) ([]string, error)
+	}{
+		{"tsc", lintTypeScript},
+		{"eslint", lintESLint},
+	}
+	var issues []string
+	for _, linter := range linters {
+		found, err := linter.run(dir)
+		if err != nil {
+			pterm.Warning.Printf("Skipped %s: %v\n", linter.name, err)
+			continue
+		}
+		issues = append(issues, found...)
+	}
+	if len(issues) > 0 {
+		result.IsValid = false
+		result.Issues = append(result.Issues, issues...)
+	}
+	return result
+}
+
+// lintTypeScript type-checks the project with tsc --noEmit. Imports of packages that are
+// not installed are ignored because the project has not been through npm install yet.
+func lintTypeScript(dir string) ([]string, error) {
+	if _, err := os.Stat(filepath.Join(dir, "tsconfig.json")); err != nil {
+		return nil, errors.New("the project has no tsconfig.json")
+	}
+	stdout, stderr, err := runLintTool(dir, "tsc", "--noEmit", "--pretty", "false", "-p", ".")
+	if err != nil {
+		return nil, err
+	}
+	issues, parsed := parseTSCOutput(stdout)
+	if output := stdout + "\n" + stderr; !parsed && strings.Contains(output, "error") {
+		return nil, fmt.Errorf("could not parse its errors:\n%s", lastLines(output, 10))
+	}
+	return issues, nil
+}
+
+// lintESLint runs eslint with the JSON formatter. A project without an eslint
+// configuration makes eslint fail, which is reported as a skip rather than an issue.
+func lintESLint(dir string) ([]string, error) {
+	stdout, stderr, err := runLintTool(dir, "eslint", "--format", "json", ".")
+	if err != nil {
+		return nil, err
+	}
+	issues, err := parseESLintOutput(stdout, dir)
+	if err != nil {
+		return nil, fmt.Errorf("no usable report (is an eslint config present?):\n%s", lastLines(stdout+"\n"+stderr, 3))
+	}
+	return issues, nil
+}
+
+// runLintTool runs name in dir and returns its stdout and stderr separately, so warnings
+// on stderr cannot corrupt a machine-readable report. Non-zero exit codes are expected
+// when diagnostics are found, so only a missing tool, a timeout or a failure to start is
+// an error.
+func runLintTool(dir, name string, args ...string) (stdout, stderr string, err error) {
+	if _, err := exec.LookPath(name); err != nil {
+		return "", "", errors.New("not installed")
+	}
+
+	ctx, cancel := context.WithTimeout(shared.BaseContext(), lintTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Dir = dir
+	var outBuf, errBuf bytes.Buffer
+	cmd.Stdout = &outBuf
+	cmd.Stderr = &errBuf
+	err = cmd.Run()
+	var exitErr *exec.ExitError
+	switch {
+	case ctx.Err() != nil:
+		return "", "", fmt.Errorf("timed out after %s", lintTimeout)
+	case err != nil && !errors.As(err, &exitErr):
+		return "", "", err
+	}
+	return outBuf.String(), errBuf.String(), nil
+}
+
+// parseTSCOutput turns tsc diagnostics into validation issues. parsed reports whether the
+// output held any diagnostic, including the ignored ones.
+func parseTSCOutput(output string) (issues []string, parsed bool) {
+	for _, line := range strings.Split(output, "\n") {
+		match := tscDiagnostic.FindStringSubmatch(strings.TrimSpace(line))
+		if match == nil {
+			continue
+		}
+		parsed = true
+		if tscMissingPackage.MatchString(match[5]) {
+			continue
+		}
+		issues = append(issues, fmt.Sprintf("tsc: %s:%s:%s %s %s", filepath.ToSlash(match[1]), match[2], match[3], match[4], match[5]))
+	}
+	return issues, parsed
+}
+
+// eslintFileResult is the part of an eslint --format json entry that magi reports.
+type eslintFileResult struct {
+	FilePath string `json:"filePath"`
+	Messages []struct {
+		RuleID   string `json:"ruleId"`
+		Severity int    `json:"severity"`
+		Message  string `json:"message"`
+		Line     int    `json:"line"`
+		Column   int    `json:"column"`
+	} `json:"messages"`
+}
+
+// parseESLintOutput turns eslint errors into validation issues, with paths relative to dir.
+// Warnings are left out. Output that is not the JSON report is an error.
+func parseESLintOutput(output, dir string) ([]string, error) {
+	var results []eslintFileResult
+	if err := json.Unmarshal([]byte(strings.TrimSpace(output)), &results); err != nil {
+		return nil, err
+	}
+
+	var issues []string
+	for _, file := range results {
+		path := file.FilePath
+		if rel, err := filepath.Rel(dir, path); err == nil && !strings.HasPrefix(rel, "..") {
+			path = rel
+		}
+		for _, msg := range file.Messages {
+			if msg.Severity < 2 {
+				continue
+			}
+			issue := fmt.Sprintf("eslint: %s:%d:%d %s", filepath.ToSlash(path), msg.Line, msg.Column, msg.Message)
+			if msg.RuleID != "" {
+				issue += " (" + msg.RuleID + ")"
+			}
+			issues = append(issues, issue)
+		}
+	}
+	return issues, nil
+}
+
+// lastLines returns the last n non-empty lines of output.
+func lastLines(output string, n int) string {
+	var lines []string
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+		}
+	}
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
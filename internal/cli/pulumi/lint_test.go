@@ -0,0 +1,101 @@
+package pulumi
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/MagdielCAS/magi-cli/internal/cli/pulumi/agents"
+)
+
+func TestParseTSCOutput(t *testing.T) {
+	output := `index.ts(1,22): error TS2307: Cannot find module '@pulumi/aws' or its corresponding type declarations.
+index.ts(3,7): error TS2322: Type 'number' is not assignable to type 'string'.
+src/db.ts(10,1): error TS2307: Cannot find module './missing' or its corresponding type declarations.
+`
+	issues, parsed := parseTSCOutput(output)
+	want := []string{
+		"tsc: index.ts:3:7 TS2322 Type 'number' is not assignable to type 'string'.",
+		"tsc: src/db.ts:10:1 TS2307 Cannot find module './missing' or its corresponding type declarations.",
+	}
+	if !parsed || !reflect.DeepEqual(issues, want) {
+		t.Fatalf("parseTSCOutput() = %v, %v; want %v", issues, parsed, want)
+	}
+
+	if issues, parsed := parseTSCOutput("index.ts(1,22): error TS2307: Cannot find module '@pulumi/pulumi'."); !parsed || len(issues) != 0 {
+		t.Fatalf("expected uninstalled packages to be ignored, got %v, %v", issues, parsed)
+	}
+	if _, parsed := parseTSCOutput("error TS5058: The specified path does not exist: 'x'."); parsed {
+		t.Fatal("expected output without file diagnostics to be reported as unparsed")
+	}
+}
+
+func TestParseESLintOutput(t *testing.T) {
+	dir := filepath.Join(string(filepath.Separator), "tmp", "project")
+	output := `[{"filePath":"` + filepath.ToSlash(filepath.Join(dir, "index.ts")) + `","messages":[
+		{"ruleId":"no-unused-vars","severity":2,"message":"'x' is defined but never used.","line":4,"column":7},
+		{"ruleId":"prefer-const","severity":1,"message":"Use const.","line":5,"column":5},
+		{"ruleId":null,"severity":2,"message":"Parsing error: Unexpected token","line":9,"column":1}
+	]}]`
+
+	issues, err := parseESLintOutput(output, dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{
+		"eslint: index.ts:4:7 'x' is defined but never used. (no-unused-vars)",
+		"eslint: index.ts:9:1 Parsing error: Unexpected token",
+	}
+	if !reflect.DeepEqual(issues, want) {
+		t.Fatalf("parseESLintOutput() = %v, want %v", issues, want)
+	}
+
+	if _, err := parseESLintOutput("Oops! Something went wrong! ESLint couldn't find an eslint.config.js file.", dir); err == nil {
+		t.Fatal("expected a non-JSON report to be an error")
+	}
+}
+
+func TestStrictValidate(t *testing.T) {
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("sh not available")
+	}
+	project := &agents.GeneratedProject{ProjectFiles: map[string]string{
+		"index.ts":      "const name: string = 1;\n",
+		"tsconfig.json": "{}\n",
+	}}
+
+	t.Setenv("PATH", t.TempDir())
+	result := strictValidate(project, nil)
+	if result == nil || !result.IsValid || len(result.Issues) != 0 {
+		t.Fatalf("expected missing tools to be skipped, got %+v", result)
+	}
+
+	bin := t.TempDir()
+	tsc := "#!" + sh + "\necho \"index.ts(1,7): error TS2322: Type 'number' is not assignable to type 'string'.\"\nexit 2\n"
+	if err := os.WriteFile(filepath.Join(bin, "tsc"), []byte(tsc), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin)
+
+	result = strictValidate(project, &agents.ValidationResult{IsValid: true, Issues: []string{"model issue"}})
+	want := []string{"model issue", "tsc: index.ts:1:7 TS2322 Type 'number' is not assignable to type 'string'."}
+	if result.IsValid || !reflect.DeepEqual(result.Issues, want) {
+		t.Fatalf("expected the tsc diagnostic to be added, got %+v", result)
+	}
+
+	// eslint warnings on stderr must not end up in the JSON report.
+	eslint := "#!" + sh + "\necho 'Warning: React version not specified' >&2\n" +
+		`echo '[{"filePath":"index.ts","messages":[{"ruleId":"no-undef","severity":2,"message":"'\''x'\'' is not defined.","line":2,"column":1}]}]'` +
+		"\nexit 1\n"
+	if err := os.WriteFile(filepath.Join(bin, "eslint"), []byte(eslint), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	result = strictValidate(project, nil)
+	want = append(want[1:], "eslint: index.ts:2:1 'x' is not defined. (no-undef)")
+	if result.IsValid || !reflect.DeepEqual(result.Issues, want) {
+		t.Fatalf("expected the eslint report to be parsed, got %+v", result)
+	}
+}
@@ -0,0 +1,87 @@
+package pulumi
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/MagdielCAS/magi-cli/pkg/llm"
+	"github.com/MagdielCAS/magi-cli/pkg/shared"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+func newMCPToolsCmd() *cobra.Command {
+	var serverURL string
+	var useLocal bool
+
+	cmd := &cobra.Command{
+		Use:   "mcp-tools",
+		Short: "List the tools exposed by the MCP server",
+		Long: `Connect to the MCP server used by 'magi pulumi' and list the tools it exposes.
+
+This is a debugging aid for MCP lookups that silently return nothing: it shows which
+tools the server actually advertises. The command exits with a non-zero status when
+the server cannot be reached, so it can be used in scripts.
+
+Data handling:
+  • Only the MCP initialize and tools/list requests are sent; no project data leaves your machine.
+
+Usage:
+  magi pulumi mcp-tools [flags]
+
+Examples:
+  # List tools from the default Pulumi MCP server
+  magi pulumi mcp-tools
+
+  # Inspect a local MCP server
+  magi pulumi mcp-tools --mcp-server http://localhost:3000`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runMCPTools(resolveMCPServerURL(serverURL, useLocal))
+		},
+	}
+
+	cmd.Flags().StringVar(&serverURL, "mcp-server", "", "Custom MCP server URL")
+	cmd.Flags().BoolVar(&useLocal, "use-local-mcp", false, "Use local MCP server instead of default")
+
+	return cmd
+}
+
+func runMCPTools(serverURL string) error {
+	client := newMCPClient(serverURL)
+	defer client.Close()
+
+	spinner, _ := pterm.DefaultSpinner.Start(fmt.Sprintf("Connecting to MCP server at %s...", serverURL))
+	if err := client.Connect(); err != nil {
+		spinner.Fail("Connection failed")
+		return fmt.Errorf("could not connect to MCP server at %s: %w", serverURL, err)
+	}
+	spinner.Success(fmt.Sprintf("Connected to %s", serverURL))
+
+	tools := sortedTools(client.Tools)
+	if shared.IsJSONOutput() {
+		return shared.PrintJSON(tools)
+	}
+
+	if len(tools) == 0 {
+		pterm.Warning.Println("The MCP server did not advertise any tools.")
+		return nil
+	}
+
+	tableData := pterm.TableData{{"Name", "Description"}}
+	for _, tool := range tools {
+		tableData = append(tableData, []string{tool.Name, tool.Description})
+	}
+	return pterm.DefaultTable.WithHasHeader().WithData(tableData).Render()
+}
+
+func sortedTools(tools map[string]llm.MCPTool) []llm.MCPTool {
+	sorted := make([]llm.MCPTool, 0, len(tools))
+	for _, tool := range tools {
+		sorted = append(sorted, tool)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted
+}
@@ -0,0 +1,234 @@
+package parsers
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// DataModel is the set of entities and relationships described by a Mermaid erDiagram or
+// classDiagram, which map to database tables and their references.
+type DataModel struct {
+	// Kind is "erDiagram" or "classDiagram".
+	Kind          string
+	Entities      []Entity
+	Relationships []Relationship
+}
+
+// Entity is an ER entity or a class.
+type Entity struct {
+	Name       string
+	Attributes []Attribute
+}
+
+// Attribute is a column of an entity.
+type Attribute struct {
+	Name string
+	Type string
+	// Keys lists the PK, FK and UK markers of an ER attribute.
+	Keys []string
+}
+
+// Relationship links two entities. Cardinality is the Mermaid notation as written, e.g.
+// "||--o{" or `"1" --> "*"`.
+type Relationship struct {
+	From        string
+	To          string
+	Cardinality string
+	Label       string
+}
+
+var (
+	erRelationshipPattern    = regexp.MustCompile(`^([\w-]+)\s+([|}o]{1,2}(?:--|\.\.)[|{o]{1,2})\s+([\w-]+)\s*:\s*(.*)$`)
+	erEntityStartPattern     = regexp.MustCompile(`^([\w-]+)(?:\s*\[[^\]]*\])?\s*\{\s*$`)
+	classRelationshipPattern = regexp.MustCompile(`^(\w+)\s*(?:"([^"]*)")?\s*(<\|--|--\|>|\*--|--\*|o--|--o|<--|-->|\.\.\|>|<\|\.\.|\.\.>|<\.\.|--|\.\.)\s*(?:"([^"]*)")?\s*(\w+)\s*(?::\s*(.*))?$`)
+	classStartPattern        = regexp.MustCompile(`^class\s+(\w+)(?:~[^~]*~)?\s*(\{)?\s*$`)
+	classMemberPattern       = regexp.MustCompile(`^(\w+)\s*:\s*(.+)$`)
+)
+
+// ParseDataModel extracts the entities and relationships of an erDiagram or classDiagram.
+// It returns false for other diagram types, so flowcharts keep being passed as text.
+func (p *MermaidParser) ParseDataModel(content string) (*DataModel, bool) {
+	lines := diagramLines(content)
+	if len(lines) == 0 {
+		return nil, false
+	}
+	switch {
+	case strings.HasPrefix(lines[0], "erDiagram"):
+		return parseERDiagram(lines[1:]), true
+	case strings.HasPrefix(lines[0], "classDiagram"):
+		return parseClassDiagram(lines[1:]), true
+	}
+	return nil, false
+}
+
+// diagramLines returns the trimmed, non-empty lines of a diagram without a surrounding
+// ```mermaid fence and without %% comments.
+func diagramLines(content string) []string {
+	var lines []string
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "%%") || strings.HasPrefix(line, "```") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func parseERDiagram(lines []string) *DataModel {
+	model := &DataModel{Kind: "erDiagram"}
+	var current *Entity
+	for _, line := range lines {
+		if current != nil {
+			if line == "}" {
+				current = nil
+				continue
+			}
+			if attribute, ok := parseERAttribute(line); ok {
+				current.Attributes = append(current.Attributes, attribute)
+			}
+			continue
+		}
+		if m := erEntityStartPattern.FindStringSubmatch(line); m != nil {
+			current = model.entity(m[1])
+			continue
+		}
+		if m := erRelationshipPattern.FindStringSubmatch(line); m != nil {
+			model.entity(m[1])
+			model.entity(m[3])
+			model.Relationships = append(model.Relationships, Relationship{
+				From:        m[1],
+				To:          m[3],
+				Cardinality: m[2],
+				Label:       strings.Trim(strings.TrimSpace(m[4]), `"`),
+			})
+		}
+	}
+	return model
+}
+
+// parseERAttribute parses "type name [PK, FK] ["comment"]".
+func parseERAttribute(line string) (Attribute, bool) {
+	if quote := strings.Index(line, `"`); quote != -1 {
+		line = strings.TrimSpace(line[:quote])
+	}
+	fields := strings.Fields(strings.ReplaceAll(line, ",", " "))
+	if len(fields) < 2 {
+		return Attribute{}, false
+	}
+	attribute := Attribute{Type: fields[0], Name: fields[1]}
+	for _, key := range fields[2:] {
+		switch key := strings.ToUpper(key); key {
+		case "PK", "FK", "UK":
+			attribute.Keys = append(attribute.Keys, key)
+		}
+	}
+	return attribute, true
+}
+
+func parseClassDiagram(lines []string) *DataModel {
+	model := &DataModel{Kind: "classDiagram"}
+	var current *Entity
+	for _, line := range lines {
+		if current != nil {
+			if line == "}" {
+				current = nil
+				continue
+			}
+			if attribute, ok := parseClassMember(line); ok {
+				current.Attributes = append(current.Attributes, attribute)
+			}
+			continue
+		}
+		if m := classStartPattern.FindStringSubmatch(line); m != nil {
+			entity := model.entity(m[1])
+			if m[2] != "" {
+				current = entity
+			}
+			continue
+		}
+		if m := classRelationshipPattern.FindStringSubmatch(line); m != nil {
+			model.entity(m[1])
+			model.entity(m[5])
+			cardinality := m[3]
+			if m[2] != "" || m[4] != "" {
+				cardinality = strings.TrimSpace(fmt.Sprintf("%q %s %q", m[2], m[3], m[4]))
+			}
+			model.Relationships = append(model.Relationships, Relationship{
+				From:        m[1],
+				To:          m[5],
+				Cardinality: cardinality,
+				Label:       strings.TrimSpace(m[6]),
+			})
+			continue
+		}
+		if m := classMemberPattern.FindStringSubmatch(line); m != nil {
+			if attribute, ok := parseClassMember(m[2]); ok {
+				entity := model.entity(m[1])
+				entity.Attributes = append(entity.Attributes, attribute)
+			}
+		}
+	}
+	return model
+}
+
+// parseClassMember parses a class attribute such as "+String id" or "-id: String". Methods
+// are skipped.
+func parseClassMember(line string) (Attribute, bool) {
+	line = strings.TrimLeft(strings.TrimSpace(line), "+-#~")
+	if line == "" || strings.Contains(line, "(") {
+		return Attribute{}, false
+	}
+	if name, typ, ok := strings.Cut(line, ":"); ok {
+		return Attribute{Name: strings.TrimSpace(name), Type: strings.TrimSpace(typ)}, true
+	}
+	fields := strings.Fields(line)
+	if len(fields) == 1 {
+		return Attribute{Name: fields[0]}, true
+	}
+	return Attribute{Type: fields[0], Name: fields[1]}, true
+}
+
+// entity returns the entity called name, adding it on first use.
+func (m *DataModel) entity(name string) *Entity {
+	for i := range m.Entities {
+		if m.Entities[i].Name == name {
+			return &m.Entities[i]
+		}
+	}
+	m.Entities = append(m.Entities, Entity{Name: name})
+	return &m.Entities[len(m.Entities)-1]
+}
+
+// Describe renders the data model as a structured list for the analyzer prompt.
+func (m *DataModel) Describe() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Data model (from a Mermaid %s):\n", m.Kind)
+	b.WriteString("Entities:\n")
+	for _, entity := range m.Entities {
+		columns := make([]string, 0, len(entity.Attributes))
+		for _, attribute := range entity.Attributes {
+			column := attribute.Name
+			if attribute.Type != "" {
+				column += " " + attribute.Type
+			}
+			if len(attribute.Keys) > 0 {
+				column += " " + strings.Join(attribute.Keys, ",")
+			}
+			columns = append(columns, column)
+		}
+		fmt.Fprintf(&b, "- %s: %s\n", entity.Name, strings.Join(columns, "; "))
+	}
+	if len(m.Relationships) > 0 {
+		b.WriteString("Relationships:\n")
+		for _, relationship := range m.Relationships {
+			fmt.Fprintf(&b, "- %s %s %s", relationship.From, relationship.Cardinality, relationship.To)
+			if relationship.Label != "" {
+				fmt.Fprintf(&b, " (%s)", relationship.Label)
+			}
+			b.WriteString("\n")
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
@@ -0,0 +1,93 @@
+package parsers
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const sampleERDiagram = "```mermaid\n" + `erDiagram
+    %% shop data model
+    CUSTOMER ||--o{ ORDER : places
+    ORDER ||--|{ LINE_ITEM : contains
+    CUSTOMER {
+        string id PK
+        string email UK "login"
+    }
+    ORDER {
+        string id PK
+        string customer_id FK
+        decimal total
+    }
+` + "```"
+
+func TestParseDataModelER(t *testing.T) {
+	model, ok := NewMermaidParser().ParseDataModel(sampleERDiagram)
+	if !ok {
+		t.Fatal("expected an erDiagram to be parsed")
+	}
+	if model.Kind != "erDiagram" {
+		t.Fatalf("unexpected kind %q", model.Kind)
+	}
+
+	var names []string
+	for _, entity := range model.Entities {
+		names = append(names, entity.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"CUSTOMER", "ORDER", "LINE_ITEM"}) {
+		t.Fatalf("unexpected entities %v", names)
+	}
+	wantCustomer := []Attribute{{Name: "id", Type: "string", Keys: []string{"PK"}}, {Name: "email", Type: "string", Keys: []string{"UK"}}}
+	if !reflect.DeepEqual(model.Entities[0].Attributes, wantCustomer) {
+		t.Fatalf("unexpected CUSTOMER attributes %+v", model.Entities[0].Attributes)
+	}
+	if len(model.Entities[1].Attributes) != 3 || model.Entities[1].Attributes[1].Keys[0] != "FK" {
+		t.Fatalf("unexpected ORDER attributes %+v", model.Entities[1].Attributes)
+	}
+	wantRelationship := Relationship{From: "CUSTOMER", To: "ORDER", Cardinality: "||--o{", Label: "places"}
+	if len(model.Relationships) != 2 || model.Relationships[0] != wantRelationship {
+		t.Fatalf("unexpected relationships %+v", model.Relationships)
+	}
+
+	description := model.Describe()
+	for _, want := range []string{"Mermaid erDiagram", "- ORDER: id string PK; customer_id string FK; total decimal", "- CUSTOMER ||--o{ ORDER (places)"} {
+		if !strings.Contains(description, want) {
+			t.Fatalf("expected the description to contain %q, got:\n%s", want, description)
+		}
+	}
+}
+
+func TestParseDataModelClass(t *testing.T) {
+	diagram := `classDiagram
+    class Customer {
+        +String id
+        -email: String
+        +placeOrder() Order
+    }
+    class Order
+    Order : +Decimal total
+    Customer "1" --> "*" Order : places
+    Entity <|-- Customer`
+
+	model, ok := NewMermaidParser().ParseDataModel(diagram)
+	if !ok {
+		t.Fatal("expected a classDiagram to be parsed")
+	}
+	wantCustomer := []Attribute{{Name: "id", Type: "String"}, {Name: "email", Type: "String"}}
+	if model.Entities[0].Name != "Customer" || !reflect.DeepEqual(model.Entities[0].Attributes, wantCustomer) {
+		t.Fatalf("unexpected Customer %+v", model.Entities[0])
+	}
+	if model.Entities[1].Name != "Order" || len(model.Entities[1].Attributes) != 1 || model.Entities[1].Attributes[0].Name != "total" {
+		t.Fatalf("unexpected Order %+v", model.Entities[1])
+	}
+	want := Relationship{From: "Customer", To: "Order", Cardinality: `"1" --> "*"`, Label: "places"}
+	if len(model.Relationships) != 2 || model.Relationships[0] != want {
+		t.Fatalf("unexpected relationships %+v", model.Relationships)
+	}
+}
+
+func TestParseDataModelIgnoresOtherDiagrams(t *testing.T) {
+	if _, ok := NewMermaidParser().ParseDataModel("graph TD\n  A-->B"); ok {
+		t.Fatal("expected flowcharts not to be parsed as a data model")
+	}
+}
@@ -1,6 +1,9 @@
 package templates
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 // GetPulumiYamlTemplate returns a default Pulumi.yaml template
 func GetPulumiYamlTemplate(projectName, description string) string {
@@ -48,3 +51,40 @@ func GetTsConfigTemplate() string {
 }
 `
 }
+
+// GetReadmeTemplate returns a README.md with deployment instructions for the generated project.
+// services lists the analyzed services and notes holds any extra instructions from the generator.
+func GetReadmeTemplate(projectName, region string, services []string, notes string) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# %s\n\nPulumi TypeScript project generated by magi-cli.\n\n", projectName)
+
+	if len(services) > 0 {
+		b.WriteString("## Services\n\n")
+		for _, service := range services {
+			fmt.Fprintf(&b, "- %s\n", service)
+		}
+		b.WriteString("\n")
+	}
+
+	fmt.Fprintf(&b, `## Deployment
+
+Prerequisites: the Pulumi CLI, Node.js and AWS credentials configured for the target account.
+
+`+"```bash"+`
+npm install
+pulumi stack init dev
+pulumi config set aws:region %s
+pulumi preview
+pulumi up
+`+"```"+`
+
+Run `+"`pulumi destroy`"+` to remove every resource created by this stack.
+`, region)
+
+	if notes = strings.TrimSpace(notes); notes != "" {
+		fmt.Fprintf(&b, "\n## Notes\n\n%s\n", notes)
+	}
+
+	return b.String()
+}
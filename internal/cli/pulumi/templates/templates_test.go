@@ -61,3 +61,12 @@ func TestGetAllTemplates(t *testing.T) {
 		t.Errorf("GetAllTemplates() missing s3_bucket")
 	}
 }
+
+func TestGetReadmeTemplate(t *testing.T) {
+	got := GetReadmeTemplate("test-project", "eu-west-1", []string{"api (lambda)"}, "Set the db password first.")
+	for _, want := range []string{"# test-project", "pulumi stack init", "pulumi config set aws:region eu-west-1", "pulumi up", "- api (lambda)", "Set the db password first."} {
+		if !strings.Contains(got, want) {
+			t.Errorf("GetReadmeTemplate() missing %q", want)
+		}
+	}
+}
@@ -1,17 +1,24 @@
 package ssh
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/pterm/pterm"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+
+	magicrypto "github.com/MagdielCAS/magi-cli/internal/cli/crypto"
+	"github.com/MagdielCAS/magi-cli/pkg/shared"
 )
 
 func addCmd() *cobra.Command {
@@ -22,25 +29,39 @@ func addCmd() *cobra.Command {
 
 This command will prompt you for:
 - Connection Alias (unique name)
-- SSH Key (select existing or add new)
+- SSH Key (select existing, add a path, or generate a new key)
 - Server IP
 - Username (default: ubuntu)
 - Port (default: 22)
+- Jump host (optional ProxyJump: a saved alias or [user@]host[:port])
+
+After the details are collected, magi runs a quick non-interactive ssh probe
+(BatchMode, 5 second timeout) to check connectivity and key authentication, and asks
+whether to save anyway if it fails. Use --no-test for hosts that need interactive auth.
+The probe accepts and records the host key on first contact (StrictHostKeyChecking=accept-new).
+
+Jump hosts authenticate with ssh-agent, the default ~/.ssh/id_* keys and ~/.ssh/config,
+because ssh -J does not pass -i to them. A saved alias with another key cannot be a hop.
 
 Usage:
-  magi ssh add
+  magi ssh add [--no-test]
 
 Examples:
   # Start the interactive add wizard
-  magi ssh add`,
+  magi ssh add
+
+  # Skip the connection test
+  magi ssh add --no-test`,
 		Run: func(cmd *cobra.Command, args []string) {
-			addConnection()
+			noTest, _ := cmd.Flags().GetBool("no-test")
+			addConnection(noTest)
 		},
 	}
+	cmd.Flags().Bool("no-test", false, "Save the connection without probing it first")
 	return cmd
 }
 
-func addConnection() {
+func addConnection(noTest bool) {
 	pterm.DefaultHeader.WithFullWidth().Println("SSH Connection Add")
 
 	// 1. Alias
@@ -51,7 +72,7 @@ func addConnection() {
 	}
 
 	// 2. SSH Key
-	keyPath, err := selectOrAddSSHKey()
+	keyPath, err := selectOrAddSSHKey(alias)
 	if err != nil {
 		pterm.Error.Println(err)
 		return
@@ -64,7 +85,13 @@ func addConnection() {
 		return
 	}
 
-	// 4. Save
+	// 4. Test
+	if !noTest && !confirmConnectionProbe(conn) {
+		pterm.Warning.Println("Connection not saved")
+		return
+	}
+
+	// 5. Save
 	if err := saveConnection(conn); err != nil {
 		pterm.Error.Printf("Failed to save connection: %v\n", err)
 		return
@@ -100,7 +127,7 @@ func promptForAlias() (string, error) {
 		}
 
 		if _, exists := existing[alias]; exists {
-			overwrite, _ := pterm.DefaultInteractiveConfirm.WithDefaultText(fmt.Sprintf("Alias '%s' already exists. Overwrite?", alias)).Show()
+			overwrite, _ := shared.Confirm(fmt.Sprintf("Alias '%s' already exists. Overwrite?", alias), false)
 			if !overwrite {
 				continue
 			}
@@ -110,9 +137,14 @@ func promptForAlias() (string, error) {
 	return alias, nil
 }
 
-func selectOrAddSSHKey() (string, error) {
+const (
+	optionGenerateKey = "Generate new key"
+	optionAddKeyPath  = "Add new key path"
+)
+
+func selectOrAddSSHKey(alias string) (string, error) {
 	keys := viper.GetStringSlice(ConfigSSHKeys)
-	options := append([]string{"Add new key path"}, keys...)
+	options := append([]string{optionGenerateKey, optionAddKeyPath}, keys...)
 
 	selection, err := pterm.DefaultInteractiveSelect.
 		WithDefaultText("Select SSH Key").
@@ -122,13 +154,63 @@ func selectOrAddSSHKey() (string, error) {
 		return "", err
 	}
 
-	if selection == "Add new key path" {
+	switch selection {
+	case optionGenerateKey:
+		return generateKeyForAlias(alias)
+	case optionAddKeyPath:
 		return promptForNewKey()
 	}
 
 	return selection, nil
 }
 
+// generateKeyForAlias creates ~/.ssh/magi_<alias> (Ed25519 by default), prints the public
+// key to install on the server, and registers the private key path.
+func generateKeyForAlias(alias string) (string, error) {
+	algorithm, err := pterm.DefaultInteractiveSelect.
+		WithDefaultText("Key algorithm").
+		WithOptions([]string{"ed25519", "ecdsa", "rsa"}).
+		WithDefaultOption("ed25519").
+		Show()
+	if err != nil {
+		return "", err
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve home directory: %w", err)
+	}
+	sshDir := filepath.Join(home, ".ssh")
+	if err := os.MkdirAll(sshDir, 0700); err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", sshDir, err)
+	}
+
+	path := generatedKeyPath(sshDir, alias)
+	if _, err := os.Stat(path); err == nil {
+		overwrite, _ := shared.Confirm(fmt.Sprintf("%s already exists. Overwrite?", path), false)
+		if !overwrite {
+			return "", fmt.Errorf("key generation cancelled: %s already exists", path)
+		}
+	}
+
+	authorizedKey, err := magicrypto.GenerateSSHKey(algorithm, path, "magi@"+alias)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate SSH key: %w", err)
+	}
+
+	pterm.Success.Printf("Private key generated at %s\n", path)
+	pterm.Info.Printf("Add this public key to ~/.ssh/authorized_keys on the server (also saved to %s.pub):\n", path)
+	pterm.Println(authorizedKey)
+
+	registerSSHKey(path)
+	return path, nil
+}
+
+// generatedKeyPath returns the private key path used for keys generated for alias.
+func generatedKeyPath(sshDir, alias string) string {
+	return filepath.Join(sshDir, "magi_"+alias)
+}
+
 func promptForNewKey() (string, error) {
 	for {
 		path, err := pterm.DefaultInteractiveTextInput.WithDefaultText("Enter absolute path to private key").Show()
@@ -152,24 +234,22 @@ func promptForNewKey() (string, error) {
 			continue
 		}
 
-		// Save new key to list if not exists
-		keys := viper.GetStringSlice(ConfigSSHKeys)
-		exists := false
-		for _, k := range keys {
-			if k == path {
-				exists = true
-				break
-			}
-		}
-		if !exists {
-			keys = append(keys, path)
-			viper.Set(ConfigSSHKeys, keys)
-		}
-
+		registerSSHKey(path)
 		return path, nil
 	}
 }
 
+// registerSSHKey adds path to the saved key list if it is not there yet.
+func registerSSHKey(path string) {
+	keys := viper.GetStringSlice(ConfigSSHKeys)
+	for _, k := range keys {
+		if k == path {
+			return
+		}
+	}
+	viper.Set(ConfigSSHKeys, append(keys, path))
+}
+
 func collectConnectionConfig(alias, keyPath string) (SSHConnection, error) {
 	// IP
 	var ip string
@@ -218,15 +298,124 @@ func collectConnectionConfig(alias, keyPath string) (SSHConnection, error) {
 		break
 	}
 
+	// Jump host
+	proxyJump, err := promptForProxyJump(alias)
+	if err != nil {
+		return SSHConnection{}, err
+	}
+
 	return SSHConnection{
-		Alias:    alias,
-		KeyPath:  keyPath,
-		IP:       ip,
-		Username: username,
-		Port:     port,
+		Alias:     alias,
+		KeyPath:   keyPath,
+		IP:        ip,
+		Username:  username,
+		Port:      port,
+		ProxyJump: proxyJump,
 	}, nil
 }
 
+// promptForProxyJump asks for optional jump hosts until the answer is empty or valid.
+func promptForProxyJump(alias string) (string, error) {
+	connections, err := loadConnections()
+	if err != nil {
+		return "", err
+	}
+
+	for {
+		spec, err := pterm.DefaultInteractiveTextInput.
+			WithDefaultText("Jump host (optional; saved alias or [user@]host[:port], comma-separated for several hops)").
+			Show()
+		if err != nil {
+			return "", err
+		}
+
+		spec = strings.TrimSpace(spec)
+		if _, err := resolveProxyJump(spec, alias, connections); err != nil {
+			pterm.Warning.Println(err)
+			continue
+		}
+		return spec, nil
+	}
+}
+
+// probeTimeout bounds the whole connection test, including the ssh ConnectTimeout.
+const probeTimeout = 15 * time.Second
+
+// confirmConnectionProbe tests conn and reports the result. It returns whether the
+// connection should be saved: always on success, and on failure only if the user agrees.
+func confirmConnectionProbe(conn SSHConnection) bool {
+	connections, err := loadConnections()
+	if err != nil {
+		pterm.Warning.Printf("Skipping connection test: %v\n", err)
+		return true
+	}
+	jump, err := resolveProxyJump(conn.ProxyJump, conn.Alias, connections)
+	if err != nil {
+		pterm.Warning.Printf("Skipping connection test: %v\n", err)
+		return true
+	}
+
+	spinner, _ := pterm.DefaultSpinner.Start(fmt.Sprintf("Testing connection to %s@%s:%d...", conn.Username, conn.IP, conn.Port))
+	output, err := runConnectionProbe(conn, jump)
+	if err == nil {
+		spinner.Success("Connection and key authentication succeeded")
+		return true
+	}
+	spinner.Fail(describeProbeFailure(output, err))
+
+	save, _ := shared.Confirm("Save the connection anyway?", false)
+	return save
+}
+
+// probeArgs builds a non-interactive ssh invocation that only checks that conn can log in.
+func probeArgs(conn SSHConnection, jump string) []string {
+	args := []string{
+		"-o", "BatchMode=yes",
+		"-o", "ConnectTimeout=5",
+		"-o", "StrictHostKeyChecking=accept-new",
+	}
+	args = append(args, sshBaseArgs(conn, jump)...)
+	return append(args, fmt.Sprintf("%s@%s", conn.Username, conn.IP), "exit")
+}
+
+func runConnectionProbe(conn SSHConnection, jump string) (string, error) {
+	ctx, cancel := context.WithTimeout(shared.BaseContext(), probeTimeout)
+	defer cancel()
+
+	output, err := exec.CommandContext(ctx, "ssh", probeArgs(conn, jump)...).CombinedOutput()
+	if ctx.Err() != nil {
+		return string(output), ctx.Err()
+	}
+	return string(output), err
+}
+
+// describeProbeFailure turns the ssh output into a short explanation of what went wrong.
+func describeProbeFailure(output string, err error) string {
+	lower := strings.ToLower(output)
+	switch {
+	case errors.Is(err, exec.ErrNotFound):
+		return "Connection test skipped: ssh is not installed"
+	case errors.Is(err, context.Canceled):
+		return "Connection test cancelled"
+	case errors.Is(err, context.DeadlineExceeded), strings.Contains(lower, "timed out"):
+		return "Connection test failed: the server did not answer (check the IP, port, and firewall)"
+	case strings.Contains(lower, "connection refused"):
+		return "Connection test failed: connection refused (check the port and that sshd is running)"
+	case strings.Contains(lower, "permission denied"):
+		return "Connection test failed: authentication rejected (check the username and that the public key is authorized)"
+	case strings.Contains(lower, "host key verification failed"), strings.Contains(lower, "remote host identification has changed"):
+		return "Connection test failed: host key verification failed (the server key differs from known_hosts)"
+	case strings.Contains(lower, "no route to host"), strings.Contains(lower, "network is unreachable"):
+		return "Connection test failed: host unreachable"
+	}
+
+	detail := strings.TrimSpace(output)
+	if detail == "" {
+		detail = err.Error()
+	}
+	return fmt.Sprintf("Connection test failed: %s", detail)
+}
+
 func saveConnection(conn SSHConnection) error {
 	var connMap map[string]SSHConnection
 	if err := viper.UnmarshalKey(ConfigSSHConnections, &connMap); err != nil {
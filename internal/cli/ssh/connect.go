@@ -111,14 +111,21 @@ func getConnection(alias string) (SSHConnection, error) {
 }
 
 func executeSSHConnection(conn SSHConnection) error {
-	// Build SSH command
-	// ssh -i keyPath user@ip -p port
-	args := []string{
-		"-i", conn.KeyPath,
-		"-p", fmt.Sprintf("%d", conn.Port),
-		fmt.Sprintf("%s@%s", conn.Username, conn.IP),
+	jump := ""
+	if conn.ProxyJump != "" {
+		connections, err := loadConnections()
+		if err != nil {
+			return err
+		}
+		if jump, err = resolveProxyJump(conn.ProxyJump, conn.Alias, connections); err != nil {
+			return err
+		}
 	}
 
+	// Build SSH command
+	// ssh -i keyPath -p port [-J jump] user@ip
+	args := append(sshBaseArgs(conn, jump), fmt.Sprintf("%s@%s", conn.Username, conn.IP))
+
 	cmd := exec.Command("ssh", args...)
 	cmd.Stdin = os.Stdin
 	cmd.Stdout = os.Stdout
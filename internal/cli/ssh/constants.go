@@ -26,6 +26,7 @@ var (
 	ErrEmptyAlias       = errors.New("alias cannot be empty")
 	ErrInvalidAlias     = errors.New("alias contains invalid characters")
 	ErrConnectionFailed = errors.New("failed to establish SSH connection")
+	ErrInvalidProxyJump = errors.New("invalid jump host")
 )
 
 // SSHConnection represents a saved SSH connection configuration
@@ -35,4 +36,7 @@ type SSHConnection struct {
 	IP       string `mapstructure:"ip" json:"ip"`
 	Username string `mapstructure:"username" json:"username"`
 	Port     int    `mapstructure:"port" json:"port"`
+	// ProxyJump is an optional comma-separated list of jump hosts, each a saved alias or
+	// [user@]host[:port]; it is passed to ssh as -J.
+	ProxyJump string `mapstructure:"proxy_jump" json:"proxy_jump,omitempty"`
 }
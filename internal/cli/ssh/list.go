@@ -21,6 +21,7 @@ The table includes:
 - Username
 - Port
 - Key Path
+- Jump host (ProxyJump)
 
 Usage:
   magi ssh list
@@ -49,7 +50,7 @@ func listConnections() {
 
 	// Prepare table data
 	data := [][]string{
-		{"Alias", "IP", "User", "Port", "Key Path"},
+		{"Alias", "IP", "User", "Port", "Key Path", "Jump"},
 	}
 
 	var aliases []string
@@ -66,6 +67,7 @@ func listConnections() {
 			conn.Username,
 			strconv.Itoa(conn.Port),
 			conn.KeyPath,
+			conn.ProxyJump,
 		})
 	}
 
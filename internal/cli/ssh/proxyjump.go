@@ -0,0 +1,107 @@
+package ssh
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// jumpHostPattern matches [user@]host[:port] where host is a name, an IPv4 address, or a
+// bracketed IPv6 address.
+var jumpHostPattern = regexp.MustCompile(`^(?:[a-zA-Z0-9._-]+@)?(\[[0-9a-fA-F:.]+\]|[a-zA-Z0-9.-]+)(?::(\d{1,5}))?$`)
+
+// defaultIdentities are the key files in ~/.ssh that ssh tries without -i.
+var defaultIdentities = []string{"id_rsa", "id_ecdsa", "id_ecdsa_sk", "id_ed25519", "id_ed25519_sk", "id_dsa"}
+
+// resolveProxyJump validates spec and returns the value for ssh -J. Each comma-separated
+// hop is either a saved connection alias, expanded to user@ip:port, or a [user@]host[:port]
+// spec used as is. self is the alias being configured and may not be used as a hop.
+//
+// ssh -J does not pass -i to the jump hosts, which only authenticate with the default
+// identities, ssh-agent keys and ~/.ssh/config. Aliases saved with another key are
+// rejected rather than expanded to a hop that would ignore it.
+func resolveProxyJump(spec, self string, connections map[string]SSHConnection) (string, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return "", nil
+	}
+
+	var hops []string
+	for _, hop := range strings.Split(spec, ",") {
+		hop = strings.TrimSpace(hop)
+		if hop == "" {
+			return "", fmt.Errorf("%w: empty hop in %q", ErrInvalidProxyJump, spec)
+		}
+		if hop == self {
+			return "", fmt.Errorf("%w: connection %q cannot jump through itself", ErrInvalidProxyJump, self)
+		}
+		if conn, ok := connections[hop]; ok {
+			if conn.ProxyJump != "" {
+				return "", fmt.Errorf("%w: jump alias %q has its own jump host; list every hop instead", ErrInvalidProxyJump, hop)
+			}
+			if conn.KeyPath != "" && !isDefaultIdentity(conn.KeyPath) {
+				return "", fmt.Errorf("%w: jump alias %q uses the key %s, which ssh -J does not pass to jump hosts; set it as IdentityFile for %s in ~/.ssh/config (or add it to ssh-agent) and give the hop as %s@%s:%d",
+					ErrInvalidProxyJump, hop, conn.KeyPath, conn.IP, conn.Username, conn.IP, conn.Port)
+			}
+			hops = append(hops, fmt.Sprintf("%s@%s:%d", conn.Username, conn.IP, conn.Port))
+			continue
+		}
+
+		match := jumpHostPattern.FindStringSubmatch(hop)
+		if match == nil {
+			return "", fmt.Errorf("%w: %q is neither a saved alias nor [user@]host[:port]", ErrInvalidProxyJump, hop)
+		}
+		if match[2] != "" {
+			if port, err := strconv.Atoi(match[2]); err != nil || port < MinPort || port > MaxPort {
+				return "", fmt.Errorf("%w: port in %q must be between %d and %d", ErrInvalidProxyJump, hop, MinPort, MaxPort)
+			}
+		}
+		hops = append(hops, hop)
+	}
+	return strings.Join(hops, ","), nil
+}
+
+// isDefaultIdentity reports whether keyPath is one of the defaultIdentities.
+func isDefaultIdentity(keyPath string) bool {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return false
+	}
+	if rest, ok := strings.CutPrefix(keyPath, "~/"); ok {
+		keyPath = filepath.Join(home, rest)
+	}
+	keyPath = filepath.Clean(keyPath)
+	for _, name := range defaultIdentities {
+		if keyPath == filepath.Join(home, ".ssh", name) {
+			return true
+		}
+	}
+	return false
+}
+
+// sshBaseArgs returns the identity, port, and jump options shared by connect and the
+// connection test.
+func sshBaseArgs(conn SSHConnection, jump string) []string {
+	args := []string{
+		"-i", conn.KeyPath,
+		"-p", strconv.Itoa(conn.Port),
+	}
+	if jump != "" {
+		args = append(args, "-J", jump)
+	}
+	return args
+}
+
+// loadConnections returns the saved connections keyed by alias.
+func loadConnections() (map[string]SSHConnection, error) {
+	var connMap map[string]SSHConnection
+	if err := viper.UnmarshalKey(ConfigSSHConnections, &connMap); err != nil {
+		return nil, fmt.Errorf("failed to load connections: %w", err)
+	}
+	return connMap, nil
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"sort"
 
+	"github.com/MagdielCAS/magi-cli/pkg/shared"
 	"github.com/pterm/pterm"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -74,9 +75,7 @@ func removeConnection(alias string) {
 		return
 	}
 
-	confirm, _ := pterm.DefaultInteractiveConfirm.
-		WithDefaultText(fmt.Sprintf("Are you sure you want to remove '%s'?", alias)).
-		Show()
+	confirm, _ := shared.Confirm(fmt.Sprintf("Are you sure you want to remove '%s'?", alias), false)
 
 	if !confirm {
 		pterm.Info.Println("Operation cancelled")
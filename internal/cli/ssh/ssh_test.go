@@ -1,7 +1,12 @@
 package ssh
 
 import (
+	"context"
+	"errors"
 	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/spf13/viper"
@@ -68,3 +73,105 @@ func TestGetConnection_EmptyConfig(t *testing.T) {
 	_, err := getConnection("any")
 	assert.Error(t, err)
 }
+
+func TestRegisterSSHKey(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+
+	registerSSHKey("/keys/a")
+	registerSSHKey("/keys/b")
+	registerSSHKey("/keys/a")
+
+	assert.Equal(t, []string{"/keys/a", "/keys/b"}, viper.GetStringSlice(ConfigSSHKeys))
+}
+
+func TestGeneratedKeyPath(t *testing.T) {
+	assert.Equal(t, filepath.Join("/home/u/.ssh", "magi_prod-db"), generatedKeyPath("/home/u/.ssh", "prod-db"))
+}
+
+func TestProbeArgs(t *testing.T) {
+	args := probeArgs(SSHConnection{KeyPath: "/keys/id", IP: "10.0.0.1", Username: "deploy", Port: 2222}, "")
+	assert.Equal(t, []string{
+		"-o", "BatchMode=yes",
+		"-o", "ConnectTimeout=5",
+		"-o", "StrictHostKeyChecking=accept-new",
+		"-i", "/keys/id",
+		"-p", "2222",
+		"deploy@10.0.0.1",
+		"exit",
+	}, args)
+
+	args = probeArgs(SSHConnection{KeyPath: "/keys/id", IP: "10.0.0.1", Username: "deploy", Port: 22}, "ops@bastion:2200")
+	assert.Contains(t, strings.Join(args, " "), "-p 22 -J ops@bastion:2200 deploy@10.0.0.1")
+}
+
+func TestResolveProxyJump(t *testing.T) {
+	connections := map[string]SSHConnection{
+		"bastion": {Alias: "bastion", IP: "203.0.113.10", Username: "ops", Port: 2200},
+		"inner":   {Alias: "inner", IP: "10.0.0.2", Username: "ubuntu", Port: 22, ProxyJump: "bastion"},
+	}
+
+	tests := []struct {
+		spec    string
+		want    string
+		wantErr bool
+	}{
+		{spec: "", want: ""},
+		{spec: "bastion", want: "ops@203.0.113.10:2200"},
+		{spec: "jump.example.com", want: "jump.example.com"},
+		{spec: "admin@10.1.1.1:2222, bastion", want: "admin@10.1.1.1:2222,ops@203.0.113.10:2200"},
+		{spec: "admin@[2001:db8::1]:22", want: "admin@[2001:db8::1]:22"},
+		{spec: "self", wantErr: true},
+		{spec: "inner", wantErr: true},
+		{spec: "host:70000", wantErr: true},
+		{spec: "bad host", wantErr: true},
+		{spec: "bastion,", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := resolveProxyJump(tt.spec, "self", connections)
+		if tt.wantErr {
+			assert.ErrorIs(t, err, ErrInvalidProxyJump, tt.spec)
+			continue
+		}
+		assert.NoError(t, err, tt.spec)
+		assert.Equal(t, tt.want, got, tt.spec)
+	}
+}
+
+func TestResolveProxyJumpRejectsAliasKeys(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	connections := map[string]SSHConnection{
+		"bastion": {Alias: "bastion", IP: "203.0.113.10", Username: "ops", Port: 2200, KeyPath: "/keys/bastion"},
+		"default": {Alias: "default", IP: "203.0.113.11", Username: "ops", Port: 22, KeyPath: filepath.Join(home, ".ssh", "id_ed25519")},
+		"tilde":   {Alias: "tilde", IP: "203.0.113.12", Username: "ops", Port: 22, KeyPath: "~/.ssh/id_rsa"},
+	}
+
+	_, err := resolveProxyJump("bastion", "self", connections)
+	assert.ErrorIs(t, err, ErrInvalidProxyJump)
+	assert.Contains(t, err.Error(), "/keys/bastion")
+
+	got, err := resolveProxyJump("default,tilde", "self", connections)
+	assert.NoError(t, err)
+	assert.Equal(t, "ops@203.0.113.11:22,ops@203.0.113.12:22", got)
+}
+
+func TestDescribeProbeFailure(t *testing.T) {
+	failed := errors.New("exit status 255")
+	tests := []struct {
+		output string
+		err    error
+		want   string
+	}{
+		{"ssh: connect to host 10.0.0.1 port 22: Connection refused", failed, "connection refused"},
+		{"deploy@10.0.0.1: Permission denied (publickey).", failed, "authentication rejected"},
+		{"ssh: connect to host 10.0.0.1 port 22: Connection timed out", failed, "did not answer"},
+		{"", context.DeadlineExceeded, "did not answer"},
+		{"", exec.ErrNotFound, "ssh is not installed"},
+		{"", context.Canceled, "cancelled"},
+		{"something odd", failed, "something odd"},
+	}
+	for _, tt := range tests {
+		assert.Contains(t, describeProbeFailure(tt.output, tt.err), tt.want)
+	}
+}
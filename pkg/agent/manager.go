@@ -12,7 +12,11 @@ package agent
 
 import (
 	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
 	"sync"
+	"time"
 )
 
 // AgentInstance interface for extensibility
@@ -25,6 +29,13 @@ type AgentInstance interface {
 // AgentPool to handle agent execution
 type AgentPool struct {
 	agents map[string]AgentInstance
+
+	progress   []ProgressFunc
+	progressMu sync.Mutex
+	logger     *slog.Logger
+
+	// maxConcurrency caps how many agents execute at once; 0 means unlimited.
+	maxConcurrency int
 }
 
 // NewAgentPool initializes a new AgentPool
@@ -39,7 +50,69 @@ func (am *AgentPool) WithAgent(agent AgentInstance) {
 	am.agents[agent.Name()] = agent
 }
 
-// ExecuteAgents runs all agents, respecting dependencies
+// WithProgress registers a callback that is told when each agent starts, finishes, fails
+// or is skipped, so commands can show which agents are still running. Each call adds a
+// callback; they run in the order they were registered. A nil fn is ignored.
+func (am *AgentPool) WithProgress(fn ProgressFunc) {
+	if fn != nil {
+		am.progress = append(am.progress, fn)
+	}
+}
+
+// WithLogger writes every progress event, with the run time of each agent, to logger.
+// Nothing is logged by default.
+func (am *AgentPool) WithLogger(logger *slog.Logger) {
+	am.logger = logger
+}
+
+// WithMaxConcurrency limits ExecuteAgents to n agents running at the same time. Agents
+// waiting for their dependencies do not hold a slot. n <= 0 removes the limit (the default).
+func (am *AgentPool) WithMaxConcurrency(n int) {
+	if n < 0 {
+		n = 0
+	}
+	am.maxConcurrency = n
+}
+
+func (am *AgentPool) emit(event Event) {
+	logEvent(am.logger, event)
+	if len(am.progress) == 0 {
+		return
+	}
+	am.progressMu.Lock()
+	defer am.progressMu.Unlock()
+	for _, fn := range am.progress {
+		fn(event)
+	}
+}
+
+// ValidateDependencies checks that every dependency declared by a registered agent is
+// either another registered agent or a key of initialInput. Call it before ExecuteAgents
+// to fail fast on misspelled dependency names; all problems are reported at once.
+func (am *AgentPool) ValidateDependencies(initialInput map[string]string) error {
+	var problems []string
+	for name, agent := range am.agents {
+		for _, dep := range agent.WaitForResults() {
+			if _, isAgent := am.agents[dep]; isAgent {
+				continue
+			}
+			if _, isInput := initialInput[dep]; isInput {
+				continue
+			}
+			problems = append(problems, fmt.Sprintf("agent %q depends on %q, which is neither a registered agent nor an initial input", name, dep))
+		}
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+
+	sort.Strings(problems)
+	return fmt.Errorf("invalid agent dependencies:\n  %s", strings.Join(problems, "\n  "))
+}
+
+// ExecuteAgents runs all agents, respecting dependencies.
+// When an agent fails, the results of the agents that succeeded are still returned
+// together with the first error so callers can degrade gracefully.
 func (am *AgentPool) ExecuteAgents(initialInput map[string]string) (map[string]string, error) {
 	errors := make(chan error, len(am.agents))
 	results := make(map[string]string)
@@ -52,6 +125,12 @@ func (am *AgentPool) ExecuteAgents(initialInput map[string]string) (map[string]s
 		doneChannels[name] = make(chan struct{})
 	}
 
+	// slots is a semaphore that bounds the running agents when a limit is set
+	var slots chan struct{}
+	if am.maxConcurrency > 0 {
+		slots = make(chan struct{}, am.maxConcurrency)
+	}
+
 	// Launch agents
 	for name, agent := range am.agents {
 		wg.Add(1)
@@ -78,7 +157,9 @@ func (am *AgentPool) ExecuteAgents(initialInput map[string]string) (map[string]s
 
 					if !ok {
 						// Agent failed or didn't produce output
-						errors <- fmt.Errorf("dependency %q failed or produced no output for agent %q", dep, name)
+						err := fmt.Errorf("dependency %q failed or produced no output for agent %q", dep, name)
+						am.emit(Event{Agent: name, Kind: EventSkipped, Err: err})
+						errors <- err
 						// Close channel to prevent deadlocks in dependents (though we are returning)
 						close(doneChannels[name])
 						return
@@ -87,7 +168,9 @@ func (am *AgentPool) ExecuteAgents(initialInput map[string]string) (map[string]s
 				} else {
 					// Not an agent, must be in initialInput
 					if _, ok := initialInput[dep]; !ok {
-						errors <- fmt.Errorf("dependency %q not found (not an agent and not in initial input) for agent %q", dep, name)
+						err := fmt.Errorf("dependency %q not found (not an agent and not in initial input) for agent %q", dep, name)
+						am.emit(Event{Agent: name, Kind: EventSkipped, Err: err})
+						errors <- err
 						close(doneChannels[name])
 						return
 					}
@@ -95,9 +178,19 @@ func (am *AgentPool) ExecuteAgents(initialInput map[string]string) (map[string]s
 				}
 			}
 
+			// Wait for a free slot only once the dependencies are done, so waiting agents
+			// cannot starve the ones they depend on
+			if slots != nil {
+				slots <- struct{}{}
+				defer func() { <-slots }()
+			}
+
 			// Execute agent actions
+			am.emit(Event{Agent: name, Kind: EventStarted})
+			started := time.Now()
 			result, err := agent.Execute(dependencyInputs)
 			if err != nil {
+				am.emit(Event{Agent: name, Kind: EventFailed, Elapsed: time.Since(started), Err: err})
 				errors <- fmt.Errorf("error in agent %s: %v", name, err)
 				// We still close the channel so dependents don't hang, but they might get partial data
 				close(doneChannels[name])
@@ -108,6 +201,7 @@ func (am *AgentPool) ExecuteAgents(initialInput map[string]string) (map[string]s
 			resultsMu.Lock()
 			results[name] = result
 			resultsMu.Unlock()
+			am.emit(Event{Agent: name, Kind: EventFinished, Elapsed: time.Since(started)})
 
 			// Signal completion
 			close(doneChannels[name])
@@ -120,7 +214,7 @@ func (am *AgentPool) ExecuteAgents(initialInput map[string]string) (map[string]s
 
 	// Check for errors
 	if len(errors) > 0 {
-		return nil, <-errors
+		return results, <-errors
 	}
 
 	return results, nil
@@ -1,9 +1,14 @@
 package agent
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
+	"log/slog"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 type mockAgent struct {
@@ -112,6 +117,30 @@ func TestAgentPool_ExecuteAgents(t *testing.T) {
 		// We expect an error.
 	})
 
+	t.Run("partial results on failure", func(t *testing.T) {
+		pool := NewAgentPool()
+
+		pool.WithAgent(&mockAgent{name: "agent1"})
+		pool.WithAgent(&mockAgent{
+			name:         "agent2",
+			dependencies: []string{"agent1"},
+			executeFunc: func(input map[string]string) (string, error) {
+				return "", errors.New("agent2 failed")
+			},
+		})
+
+		results, err := pool.ExecuteAgents(nil)
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+		if results["agent1"] != "done" {
+			t.Errorf("expected agent1 result to be kept, got '%s'", results["agent1"])
+		}
+		if _, ok := results["agent2"]; ok {
+			t.Error("expected no result for the failed agent")
+		}
+	})
+
 	t.Run("initial input dependency", func(t *testing.T) {
 		pool := NewAgentPool()
 
@@ -138,3 +167,130 @@ func TestAgentPool_ExecuteAgents(t *testing.T) {
 		}
 	})
 }
+
+func TestAgentPool_ValidateDependencies(t *testing.T) {
+	pool := NewAgentPool()
+	pool.WithAgent(&mockAgent{name: "extractor", dependencies: []string{"diff"}})
+	pool.WithAgent(&mockAgent{name: "writer", dependencies: []string{"extractor"}})
+
+	if err := pool.ValidateDependencies(map[string]string{"diff": "x"}); err != nil {
+		t.Fatalf("expected valid dependencies, got %v", err)
+	}
+
+	pool.WithAgent(&mockAgent{name: "sql", dependencies: []string{"writter"}})
+	err := pool.ValidateDependencies(nil)
+	if err == nil {
+		t.Fatal("expected validation error")
+	}
+	for _, want := range []string{`"sql" depends on "writter"`, `"extractor" depends on "diff"`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected %q in error, got %v", want, err)
+		}
+	}
+}
+
+func TestAgentPool_Progress(t *testing.T) {
+	pool := NewAgentPool()
+	pool.WithAgent(&mockAgent{name: "extractor"})
+	pool.WithAgent(&mockAgent{
+		name:         "translator",
+		dependencies: []string{"extractor"},
+		executeFunc: func(map[string]string) (string, error) {
+			return "", errors.New("quota exceeded")
+		},
+	})
+	pool.WithAgent(&mockAgent{name: "sql", dependencies: []string{"translator"}})
+
+	var events []string
+	pool.WithProgress(func(e Event) {
+		entry := e.Agent + ":" + string(e.Kind)
+		if e.Err != nil {
+			entry += "!"
+		}
+		events = append(events, entry)
+	})
+	pool.WithProgress(nil)
+	var counted int
+	pool.WithProgress(func(Event) { counted++ })
+
+	if _, err := pool.ExecuteAgents(nil); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	want := []string{
+		"extractor:started",
+		"extractor:finished",
+		"translator:started",
+		"translator:failed!",
+		"sql:skipped!",
+	}
+	if strings.Join(events, ",") != strings.Join(want, ",") {
+		t.Fatalf("expected events %v, got %v", want, events)
+	}
+	if counted != len(want) {
+		t.Fatalf("expected every callback to get all %d events, the second got %d", len(want), counted)
+	}
+}
+
+func TestAgentPool_WithMaxConcurrency(t *testing.T) {
+	var running, peak atomic.Int32
+	track := func(map[string]string) (string, error) {
+		current := running.Add(1)
+		for {
+			old := peak.Load()
+			if current <= old || peak.CompareAndSwap(old, current) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		running.Add(-1)
+		return "done", nil
+	}
+
+	pool := NewAgentPool()
+	pool.WithMaxConcurrency(1)
+	for _, name := range []string{"a", "b", "c", "d"} {
+		pool.WithAgent(&mockAgent{name: name, executeFunc: track})
+	}
+	pool.WithAgent(&mockAgent{name: "e", dependencies: []string{"a", "b"}, executeFunc: track})
+
+	results, err := pool.ExecuteAgents(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 5 {
+		t.Fatalf("expected 5 results, got %d", len(results))
+	}
+	if got := peak.Load(); got != 1 {
+		t.Fatalf("expected agents to run one at a time, peak concurrency was %d", got)
+	}
+
+	running.Store(0)
+	peak.Store(0)
+	pool.WithMaxConcurrency(0)
+	if _, err := pool.ExecuteAgents(nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := peak.Load(); got < 2 {
+		t.Fatalf("expected independent agents to run in parallel without a limit, peak was %d", got)
+	}
+}
+
+func TestAgentPool_LogsEvents(t *testing.T) {
+	var buf bytes.Buffer
+	pool := NewAgentPool()
+	pool.WithLogger(slog.New(slog.NewJSONHandler(&buf, nil)))
+	pool.WithAgent(&mockAgent{name: "writer"})
+	pool.WithAgent(&mockAgent{name: "broken", executeFunc: func(map[string]string) (string, error) {
+		return "", errors.New("boom")
+	}})
+	_, _ = pool.ExecuteAgents(map[string]string{})
+
+	log := buf.String()
+	if !strings.Contains(log, `"msg":"agent finished","agent":"writer","state":"finished","duration_ms":`) {
+		t.Fatalf("expected the finished agent with its timing, got %s", log)
+	}
+	if !strings.Contains(log, `"level":"WARN","msg":"agent failed","agent":"broken"`) || !strings.Contains(log, `"error":"boom"`) {
+		t.Fatalf("expected the failed agent with its error, got %s", log)
+	}
+}
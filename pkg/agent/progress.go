@@ -0,0 +1,53 @@
+package agent
+
+import (
+	"log/slog"
+	"time"
+)
+
+// EventKind tells what happened to an agent during ExecuteAgents.
+type EventKind string
+
+const (
+	// EventStarted is sent once the dependencies are available and the agent starts.
+	EventStarted EventKind = "started"
+	// EventFinished is sent when the agent returned a result.
+	EventFinished EventKind = "finished"
+	// EventFailed is sent when the agent returned an error.
+	EventFailed EventKind = "failed"
+	// EventSkipped is sent when the agent never ran because a dependency is missing.
+	EventSkipped EventKind = "skipped"
+)
+
+// Event describes a progress update for one agent.
+type Event struct {
+	Agent string
+	Kind  EventKind
+	// Elapsed is the run time of the agent for EventFinished and EventFailed.
+	Elapsed time.Duration
+	// Err is set for EventFailed and EventSkipped.
+	Err error
+}
+
+// ProgressFunc receives the progress events of an AgentPool. Calls are serialized, so the
+// callback does not need its own locking.
+type ProgressFunc func(Event)
+
+// logEvent records an agent event with its timing in logger. A nil logger discards it.
+func logEvent(logger *slog.Logger, event Event) {
+	if logger == nil {
+		return
+	}
+	attrs := []any{"agent", event.Agent, "state", string(event.Kind)}
+	if event.Elapsed > 0 {
+		attrs = append(attrs, "duration_ms", event.Elapsed.Milliseconds())
+	}
+	switch event.Kind {
+	case EventFailed, EventSkipped:
+		logger.Warn("agent "+string(event.Kind), append(attrs, "error", event.Err)...)
+	case EventStarted:
+		logger.Debug("agent "+string(event.Kind), attrs...)
+	default:
+		logger.Info("agent "+string(event.Kind), attrs...)
+	}
+}
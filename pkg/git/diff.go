@@ -0,0 +1,288 @@
+package git
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// FileDiff is one file of a unified diff.
+type FileDiff struct {
+	// OldPath and NewPath are the paths before and after the change without the a/ and b/
+	// prefixes. OldPath is empty for added files and NewPath for deleted ones.
+	OldPath string
+	NewPath string
+	// Header holds the lines before the first hunk (diff --git, index, mode, rename and
+	// ---/+++ lines, and the data of binary patches) as they appeared in the diff.
+	Header []string
+	Hunks  []Hunk
+	// Binary is set when git reported the file as binary instead of showing hunks.
+	Binary bool
+}
+
+// Hunk is one @@ section of a FileDiff.
+type Hunk struct {
+	OldStart, OldLines int
+	NewStart, NewLines int
+	// Section is the text git prints after the closing @@, usually the enclosing function.
+	Section string
+	// Lines are the hunk lines with their " ", "+", "-" or "\" prefix.
+	Lines []string
+}
+
+var hunkRangePattern = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@(.*)$`)
+
+// Path returns the path of the file after the change, or before it for deleted files.
+func (f FileDiff) Path() string {
+	if f.NewPath != "" {
+		return f.NewPath
+	}
+	return f.OldPath
+}
+
+// IsNew reports whether the diff adds the file.
+func (f FileDiff) IsNew() bool {
+	return f.OldPath == "" && f.NewPath != ""
+}
+
+// IsDeleted reports whether the diff deletes the file.
+func (f FileDiff) IsDeleted() bool {
+	return f.NewPath == "" && f.OldPath != ""
+}
+
+// IsRename reports whether the file was moved or copied.
+func (f FileDiff) IsRename() bool {
+	return f.OldPath != "" && f.NewPath != "" && f.OldPath != f.NewPath
+}
+
+// WithHunks returns a copy of f keeping only the hunks at indexes, in diff order. The new
+// side line numbers are recomputed so the result still applies with git apply.
+func (f FileDiff) WithHunks(indexes ...int) FileDiff {
+	keep := make(map[int]bool, len(indexes))
+	for _, i := range indexes {
+		keep[i] = true
+	}
+
+	subset := f
+	subset.Header = append([]string(nil), f.Header...)
+	subset.Hunks = nil
+	delta := 0
+	for i, hunk := range f.Hunks {
+		if !keep[i] {
+			continue
+		}
+		hunk.Lines = append([]string(nil), hunk.Lines...)
+		switch {
+		case hunk.OldLines == 0:
+			// Pure additions are placed after OldStart.
+			hunk.NewStart = hunk.OldStart + 1 + delta
+		case hunk.NewLines == 0:
+			// Pure removals point at the line before the removed block.
+			hunk.NewStart = hunk.OldStart - 1 + delta
+		default:
+			hunk.NewStart = hunk.OldStart + delta
+		}
+		delta += hunk.NewLines - hunk.OldLines
+		subset.Hunks = append(subset.Hunks, hunk)
+	}
+	return subset
+}
+
+// Header returns the @@ line of the hunk. Counts of one are omitted, as git does.
+func (h Hunk) Header() string {
+	return fmt.Sprintf("@@ -%s +%s @@%s", hunkRange(h.OldStart, h.OldLines), hunkRange(h.NewStart, h.NewLines), h.Section)
+}
+
+func hunkRange(start, lines int) string {
+	if lines == 1 {
+		return strconv.Itoa(start)
+	}
+	return fmt.Sprintf("%d,%d", start, lines)
+}
+
+// ParseUnifiedDiff splits the output of git diff (or any unified diff) into files and
+// hunks. Lines before the first file, such as a commit message, are ignored. An error is
+// returned for malformed hunk headers and for hunks whose body does not match the line
+// counts of their header.
+func ParseUnifiedDiff(diff string) ([]FileDiff, error) {
+	if strings.TrimSpace(diff) == "" {
+		return nil, nil
+	}
+
+	var files []FileDiff
+	var file *FileDiff
+	var hunk *Hunk
+	oldLeft, newLeft := 0, 0
+	sawOldPath := false
+
+	startFile := func(line string) {
+		files = append(files, FileDiff{Header: []string{line}})
+		file = &files[len(files)-1]
+		hunk = nil
+		sawOldPath = false
+	}
+
+	lines := strings.Split(strings.TrimSuffix(diff, "\n"), "\n")
+	for i, line := range lines {
+		if hunk != nil && (oldLeft > 0 || newLeft > 0) {
+			switch {
+			case line == "":
+				// Some tools strip the leading space of empty context lines.
+				line = " "
+				oldLeft--
+				newLeft--
+			case line[0] == ' ':
+				oldLeft--
+				newLeft--
+			case line[0] == '-':
+				oldLeft--
+			case line[0] == '+':
+				newLeft--
+			case line[0] == '\\':
+			default:
+				return nil, fmt.Errorf("line %d: hunk %q of %s is shorter than its header", i+1, hunk.Header(), file.Path())
+			}
+			if oldLeft < 0 || newLeft < 0 {
+				return nil, fmt.Errorf("line %d: hunk %q of %s is longer than its header", i+1, hunk.Header(), file.Path())
+			}
+			hunk.Lines = append(hunk.Lines, line)
+			continue
+		}
+
+		switch {
+		case hunk != nil && strings.HasPrefix(line, `\`):
+			// "\ No newline at end of file" after the last line of the hunk.
+			hunk.Lines = append(hunk.Lines, line)
+		case strings.HasPrefix(line, "diff --git "):
+			startFile(line)
+			file.OldPath, file.NewPath = gitHeaderPaths(strings.TrimPrefix(line, "diff --git "))
+		case strings.HasPrefix(line, "--- ") && (file == nil || sawOldPath || len(file.Hunks) > 0):
+			// A plain unified diff without diff --git headers.
+			startFile(line)
+			file.OldPath = diffPath(strings.TrimPrefix(line, "--- "), "a/")
+			sawOldPath = true
+		case file == nil:
+		case strings.HasPrefix(line, "@@"):
+			match := hunkRangePattern.FindStringSubmatch(line)
+			if match == nil {
+				return nil, fmt.Errorf("line %d: malformed hunk header %q", i+1, line)
+			}
+			file.Hunks = append(file.Hunks, Hunk{
+				OldStart: atoi(match[1], 0),
+				OldLines: atoi(match[2], 1),
+				NewStart: atoi(match[3], 0),
+				NewLines: atoi(match[4], 1),
+				Section:  match[5],
+			})
+			hunk = &file.Hunks[len(file.Hunks)-1]
+			oldLeft, newLeft = hunk.OldLines, hunk.NewLines
+		case len(file.Hunks) > 0:
+			// Trailing text after the last hunk, such as a format-patch signature.
+		default:
+			file.Header = append(file.Header, line)
+			parseHeaderLine(file, line)
+			if strings.HasPrefix(line, "--- ") {
+				sawOldPath = true
+			}
+		}
+	}
+
+	if hunk != nil && (oldLeft > 0 || newLeft > 0) {
+		return nil, fmt.Errorf("hunk %q of %s is truncated", hunk.Header(), file.Path())
+	}
+	return files, nil
+}
+
+// parseHeaderLine updates the paths and the binary flag of file from one extended header line.
+func parseHeaderLine(file *FileDiff, line string) {
+	switch {
+	case strings.HasPrefix(line, "--- "):
+		file.OldPath = diffPath(strings.TrimPrefix(line, "--- "), "a/")
+	case strings.HasPrefix(line, "+++ "):
+		file.NewPath = diffPath(strings.TrimPrefix(line, "+++ "), "b/")
+	case strings.HasPrefix(line, "new file mode"):
+		file.OldPath = ""
+	case strings.HasPrefix(line, "deleted file mode"):
+		file.NewPath = ""
+	case strings.HasPrefix(line, "rename from "), strings.HasPrefix(line, "copy from "):
+		file.OldPath = unquotePath(line[strings.Index(line, " from ")+len(" from "):])
+	case strings.HasPrefix(line, "rename to "), strings.HasPrefix(line, "copy to "):
+		file.NewPath = unquotePath(line[strings.Index(line, " to ")+len(" to "):])
+	case strings.HasPrefix(line, "Binary files "), line == "GIT binary patch":
+		file.Binary = true
+	}
+}
+
+// gitHeaderPaths reads the paths of a "diff --git a/<old> b/<new>" line. They are only a
+// fallback: the ---/+++ and rename lines are authoritative when present.
+func gitHeaderPaths(paths string) (string, string) {
+	if strings.HasPrefix(paths, `"`) {
+		if old, err := strconv.QuotedPrefix(paths); err == nil {
+			return diffPath(old, "a/"), diffPath(strings.TrimSpace(paths[len(old):]), "b/")
+		}
+	}
+	// Without renames both paths are equal, which resolves spaces in file names.
+	if half := (len(paths) - 1) / 2; len(paths)%2 == 1 && strings.HasPrefix(paths, "a/") &&
+		paths[half] == ' ' && strings.TrimPrefix(paths[:half], "a/") == strings.TrimPrefix(paths[half+1:], "b/") {
+		return diffPath(paths[:half], "a/"), diffPath(paths[half+1:], "b/")
+	}
+	if idx := strings.LastIndex(paths, " b/"); idx != -1 {
+		return diffPath(paths[:idx], "a/"), diffPath(paths[idx+1:], "b/")
+	}
+	return "", ""
+}
+
+// diffPath normalizes the path of a ---/+++ line: the timestamp some tools append after a
+// tab is dropped, quoted paths are unquoted, and /dev/null becomes "".
+func diffPath(raw, prefix string) string {
+	if idx := strings.Index(raw, "\t"); idx != -1 {
+		raw = raw[:idx]
+	}
+	path := unquotePath(strings.TrimSpace(raw))
+	if path == "/dev/null" {
+		return ""
+	}
+	return strings.TrimPrefix(path, prefix)
+}
+
+func unquotePath(path string) string {
+	if strings.HasPrefix(path, `"`) {
+		if unquoted, err := strconv.Unquote(path); err == nil {
+			return unquoted
+		}
+	}
+	return path
+}
+
+func atoi(value string, fallback int) int {
+	if value == "" {
+		return fallback
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return fallback
+	}
+	return n
+}
+
+// FormatUnifiedDiff serializes files back into a unified diff, for example a subset of
+// the files returned by ParseUnifiedDiff or files narrowed with FileDiff.WithHunks.
+func FormatUnifiedDiff(files []FileDiff) string {
+	var b strings.Builder
+	for _, file := range files {
+		for _, line := range file.Header {
+			b.WriteString(line)
+			b.WriteByte('\n')
+		}
+		for _, hunk := range file.Hunks {
+			b.WriteString(hunk.Header())
+			b.WriteByte('\n')
+			for _, line := range hunk.Lines {
+				b.WriteString(line)
+				b.WriteByte('\n')
+			}
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,236 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const sampleDiff = `diff --git a/pkg/app.go b/pkg/app.go
+index 1111111..2222222 100644
+--- a/pkg/app.go
++++ b/pkg/app.go
+@@ -1,4 +1,5 @@ package app
+ package app
++// Version of the app.
+
+ func main() {
+ }
+@@ -10 +11,0 @@ func helper() {
+-	return
+diff --git a/new.txt b/new.txt
+new file mode 100644
+index 0000000..3333333
+--- /dev/null
++++ b/new.txt
+@@ -0,0 +1,2 @@
++first
++-- second
+\ No newline at end of file
+diff --git a/old.txt b/old.txt
+deleted file mode 100644
+index 4444444..0000000
+--- a/old.txt
++++ /dev/null
+@@ -1 +0,0 @@
+-gone
+diff --git a/docs/a b.md b/docs/a b.md
+similarity index 90%
+rename from docs/a b.md
+rename to docs/c d.md
+index 5555555..6666666 100644
+diff --git a/logo.png b/logo.png
+index 7777777..8888888 100644
+Binary files a/logo.png and b/logo.png differ
+`
+
+func TestParseUnifiedDiff(t *testing.T) {
+	files, err := ParseUnifiedDiff(sampleDiff)
+	if err != nil {
+		t.Fatalf("ParseUnifiedDiff returned error: %v", err)
+	}
+	if len(files) != 5 {
+		t.Fatalf("expected 5 files, got %d", len(files))
+	}
+
+	app := files[0]
+	if app.OldPath != "pkg/app.go" || app.NewPath != "pkg/app.go" || app.Binary || len(app.Hunks) != 2 {
+		t.Fatalf("unexpected modified file: %+v", app)
+	}
+	first := app.Hunks[0]
+	if first.OldStart != 1 || first.OldLines != 4 || first.NewStart != 1 || first.NewLines != 5 || first.Section != " package app" {
+		t.Fatalf("unexpected first hunk: %+v", first)
+	}
+	if len(first.Lines) != 5 || first.Lines[2] != " " {
+		t.Fatalf("expected the stripped empty context line to be restored, got %q", first.Lines)
+	}
+	second := app.Hunks[1]
+	if second.OldStart != 10 || second.OldLines != 1 || second.NewStart != 11 || second.NewLines != 0 {
+		t.Fatalf("unexpected second hunk: %+v", second)
+	}
+
+	added := files[1]
+	if !added.IsNew() || added.Path() != "new.txt" || len(added.Hunks) != 1 {
+		t.Fatalf("unexpected added file: %+v", added)
+	}
+	if lines := added.Hunks[0].Lines; len(lines) != 3 || lines[1] != "+-- second" || !strings.HasPrefix(lines[2], `\`) {
+		t.Fatalf("unexpected added lines: %q", lines)
+	}
+
+	deleted := files[2]
+	if !deleted.IsDeleted() || deleted.Path() != "old.txt" {
+		t.Fatalf("unexpected deleted file: %+v", deleted)
+	}
+
+	renamed := files[3]
+	if !renamed.IsRename() || renamed.OldPath != "docs/a b.md" || renamed.NewPath != "docs/c d.md" || len(renamed.Hunks) != 0 {
+		t.Fatalf("unexpected renamed file: %+v", renamed)
+	}
+
+	binary := files[4]
+	if !binary.Binary || binary.Path() != "logo.png" || len(binary.Hunks) != 0 {
+		t.Fatalf("unexpected binary file: %+v", binary)
+	}
+}
+
+func TestParseUnifiedDiffRoundTrip(t *testing.T) {
+	files, err := ParseUnifiedDiff(sampleDiff)
+	if err != nil {
+		t.Fatalf("ParseUnifiedDiff returned error: %v", err)
+	}
+	// The only difference is the space restored on the empty context line.
+	want := strings.Replace(sampleDiff, "+// Version of the app.\n\n", "+// Version of the app.\n \n", 1)
+	if got := FormatUnifiedDiff(files); got != want {
+		t.Fatalf("round trip mismatch:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestParseUnifiedDiffPlainAndQuoted(t *testing.T) {
+	plain := "Subject: a patch\n\n" +
+		"--- a/one.txt\t2024-01-01 10:00:00\n+++ b/one.txt\t2024-01-02 10:00:00\n@@ -1 +1 @@\n-a\n+b\n" +
+		"--- a/two.txt\n+++ b/two.txt\n@@ -1 +1 @@\n-c\n+d\n" +
+		"-- \n2.40.0\n"
+	files, err := ParseUnifiedDiff(plain)
+	if err != nil {
+		t.Fatalf("ParseUnifiedDiff returned error: %v", err)
+	}
+	if len(files) != 2 || files[0].Path() != "one.txt" || files[1].Path() != "two.txt" {
+		t.Fatalf("unexpected plain diff files: %+v", files)
+	}
+	if len(files[1].Hunks[0].Lines) != 2 {
+		t.Fatalf("expected the signature to be ignored, got %q", files[1].Hunks[0].Lines)
+	}
+
+	quoted := "diff --git \"a/sp\\303\\244ce.txt\" \"b/sp\\303\\244ce.txt\"\nindex 1..2 100644\nBinary files \"a/sp\\303\\244ce.txt\" and \"b/sp\\303\\244ce.txt\" differ\n"
+	files, err = ParseUnifiedDiff(quoted)
+	if err != nil {
+		t.Fatalf("ParseUnifiedDiff returned error: %v", err)
+	}
+	if len(files) != 1 || files[0].Path() != "späce.txt" || !files[0].Binary {
+		t.Fatalf("unexpected quoted file: %+v", files)
+	}
+
+	if files, err := ParseUnifiedDiff("  \n"); err != nil || files != nil {
+		t.Fatalf("expected no files for an empty diff, got %v (err=%v)", files, err)
+	}
+}
+
+func TestParseUnifiedDiffErrors(t *testing.T) {
+	header := "diff --git a/x b/x\n--- a/x\n+++ b/x\n"
+	cases := map[string]string{
+		"malformed header": header + "@@ -1,a +1 @@\n x\n",
+		"short hunk":       header + "@@ -1,3 +1,3 @@\n x\ndiff --git a/y b/y\n",
+		"long hunk":        header + "@@ -1 +1 @@\n-x\n-y\n",
+		"truncated hunk":   header + "@@ -1,2 +1,2 @@\n x\n",
+	}
+	for name, diff := range cases {
+		if _, err := ParseUnifiedDiff(diff); err == nil {
+			t.Fatalf("expected the %s case to fail", name)
+		}
+	}
+}
+
+func TestWithHunksRecomputesLineNumbers(t *testing.T) {
+	file := FileDiff{
+		OldPath: "x", NewPath: "x",
+		Hunks: []Hunk{
+			{OldStart: 2, OldLines: 0, NewStart: 3, NewLines: 2, Lines: []string{"+a", "+b"}},
+			{OldStart: 10, OldLines: 2, NewStart: 12, NewLines: 1, Lines: []string{" c", "-d"}},
+			{OldStart: 20, OldLines: 1, NewStart: 21, NewLines: 0, Lines: []string{"-e"}},
+			{OldStart: 30, OldLines: 1, NewStart: 30, NewLines: 1, Lines: []string{"-f", "+g"}},
+		},
+	}
+
+	subset := file.WithHunks(3, 0, 2)
+	var starts []string
+	for _, hunk := range subset.Hunks {
+		starts = append(starts, fmt.Sprint(hunk.NewStart))
+	}
+	if got := strings.Join(starts, ","); got != "3,21,31" {
+		t.Fatalf("expected new starts 3,21,31, got %s", got)
+	}
+	if file.Hunks[3].NewStart != 30 {
+		t.Fatal("expected WithHunks to leave the original file unchanged")
+	}
+}
+
+func TestWithHunksAppliesToGit(t *testing.T) {
+	repo := initTestRepo(t)
+	withGitEnv(t, repo)
+
+	var original []string
+	for i := 1; i <= 30; i++ {
+		original = append(original, fmt.Sprintf("line %d", i))
+	}
+	path := filepath.Join(repo, "list.txt")
+	if err := os.WriteFile(path, []byte(strings.Join(original, "\n")+"\n"), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	runGitCmd(t, repo, "add", "list.txt")
+	runGitCmd(t, repo, "commit", "-m", "list")
+
+	changed := append([]string(nil), original...)
+	changed[1] = "line 2 changed"
+	changed = append(changed[:15], append([]string{"inserted a", "inserted b"}, changed[15:]...)...)
+	changed[len(changed)-2] = "line 29 changed"
+	if err := os.WriteFile(path, []byte(strings.Join(changed, "\n")+"\n"), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+
+	diff, err := Diff(context.Background(), false)
+	if err != nil {
+		t.Fatalf("Diff returned error: %v", err)
+	}
+	files, err := ParseUnifiedDiff(diff)
+	if err != nil {
+		t.Fatalf("ParseUnifiedDiff returned error: %v", err)
+	}
+	if len(files) != 1 || len(files[0].Hunks) != 3 {
+		t.Fatalf("expected one file with three hunks, got %+v", files)
+	}
+	if got := FormatUnifiedDiff(files); got != diff {
+		t.Fatalf("round trip mismatch:\n%s\nwant:\n%s", got, diff)
+	}
+
+	patch := filepath.Join(t.TempDir(), "subset.patch")
+	if err := os.WriteFile(patch, []byte(FormatUnifiedDiff([]FileDiff{files[0].WithHunks(0, 2)})), 0o644); err != nil {
+		t.Fatalf("write patch: %v", err)
+	}
+	cmd := exec.Command("git", "apply", "--cached", patch)
+	cmd.Dir = repo
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git apply failed: %v\n%s", err, output)
+	}
+
+	staged, err := RunGit(context.Background(), "diff", "--cached")
+	if err != nil {
+		t.Fatalf("git diff --cached: %v", err)
+	}
+	if !strings.Contains(staged, "+line 2 changed") || !strings.Contains(staged, "+line 29 changed") || strings.Contains(staged, "inserted a") {
+		t.Fatalf("expected only the first and last hunks to be staged, got:\n%s", staged)
+	}
+}
@@ -83,6 +83,32 @@ func LogGitFailure(err error) {
 	}
 }
 
+// ErrWhitespaceOnly is returned by Diff when every change disappears once whitespace is ignored.
+var ErrWhitespaceOnly = errors.New("the diff only contains whitespace changes; nothing substantive to review")
+
+// Diff runs git diff with args. With ignoreWhitespace the diff is taken with
+// --ignore-all-space, and ErrWhitespaceOnly is returned when that leaves nothing although
+// the regular diff is not empty.
+func Diff(ctx context.Context, ignoreWhitespace bool, args ...string) (string, error) {
+	if !ignoreWhitespace {
+		return RunGit(ctx, append([]string{"diff"}, args...)...)
+	}
+
+	diff, err := RunGit(ctx, append([]string{"diff", "--ignore-all-space"}, args...)...)
+	if err != nil || strings.TrimSpace(diff) != "" {
+		return diff, err
+	}
+
+	full, err := RunGit(ctx, append([]string{"diff"}, args...)...)
+	if err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(full) != "" {
+		return "", ErrWhitespaceOnly
+	}
+	return "", nil
+}
+
 func EnsureGitRepo(ctx context.Context) error {
 	_, err := RunGit(ctx, "rev-parse", "--is-inside-work-tree")
 	if err != nil {
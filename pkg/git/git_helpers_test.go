@@ -2,6 +2,7 @@ package git
 
 import (
 	"context"
+	"errors"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -68,6 +69,41 @@ func TestGitHooksDirReturnsCleanPath(t *testing.T) {
 	}
 }
 
+func TestDiffIgnoreWhitespace(t *testing.T) {
+	repo := initTestRepo(t)
+	withGitEnv(t, repo)
+	ctx := context.Background()
+
+	readme := filepath.Join(repo, "README.md")
+	if err := os.WriteFile(readme, []byte("#   test repo  \n"), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+
+	diff, err := Diff(ctx, false)
+	if err != nil || strings.TrimSpace(diff) == "" {
+		t.Fatalf("expected a whitespace diff, got %q (err=%v)", diff, err)
+	}
+	if _, err := Diff(ctx, true); !errors.Is(err, ErrWhitespaceOnly) {
+		t.Fatalf("expected ErrWhitespaceOnly, got %v", err)
+	}
+
+	if err := os.WriteFile(readme, []byte("#   test repo  \nmore\n"), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	diff, err = Diff(ctx, true)
+	if err != nil {
+		t.Fatalf("Diff returned error: %v", err)
+	}
+	if !strings.Contains(diff, "+more") {
+		t.Fatalf("expected the substantive change in the diff, got %q", diff)
+	}
+
+	runGitCmd(t, repo, "checkout", "--", "README.md")
+	if diff, err := Diff(ctx, true); err != nil || diff != "" {
+		t.Fatalf("expected an empty diff, got %q (err=%v)", diff, err)
+	}
+}
+
 func initTestRepo(t *testing.T) string {
 	t.Helper()
 	dir := t.TempDir()
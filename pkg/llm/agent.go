@@ -1,7 +1,6 @@
 package llm
 
 import (
-	"context"
 	"fmt"
 	"strings"
 
@@ -61,5 +60,5 @@ func (a *Agent) Analyze(input map[string]string) (string, error) {
 		{Role: "user", Content: userPrompt},
 	}
 
-	return service.ChatCompletion(context.Background(), req)
+	return service.ChatCompletion(shared.BaseContext(), req)
 }
@@ -12,6 +12,8 @@ type MCPAgent struct {
 	Agent
 	MCPClient *MCPClient
 	Tools     []string // Available MCP tools for this agent
+
+	lookupErrors []error
 }
 
 // MCPAgentConfig configuration for MCP-enabled agents
@@ -47,6 +49,8 @@ func NewMCPAgent(config MCPAgentConfig, mcpClient *MCPClient, runtime *shared.Ru
 
 // AnalyzeWithMCP performs analysis using both LLM and MCP tools
 func (a *MCPAgent) AnalyzeWithMCP(input map[string]string) (string, error) {
+	a.lookupErrors = nil
+
 	// Gather MCP context
 	mcpContext, err := a.gatherMCPContext(input)
 	if err != nil {
@@ -68,6 +72,11 @@ func (a *MCPAgent) AnalyzeWithMCP(input map[string]string) (string, error) {
 func (a *MCPAgent) gatherMCPContext(input map[string]string) (string, error) {
 	var contextParts []string
 
+	// MCP is best-effort: without a connected server there is nothing to look up.
+	if a.MCPClient == nil || len(a.MCPClient.Tools) == 0 {
+		return "", nil
+	}
+
 	for _, toolName := range a.Tools {
 		if toolName == "get_resource_details" {
 			if resourceTypes, exists := input["resource_types"]; exists {
@@ -95,9 +104,12 @@ func (a *MCPAgent) gatherMCPContext(input map[string]string) (string, error) {
 					resourceType = strings.TrimSpace(strings.ToLower(resourceType))
 					if token, ok := tokenMap[resourceType]; ok {
 						details, err := a.MCPClient.GetResourceDetails(token)
-						if err == nil {
-							contextParts = append(contextParts, fmt.Sprintf("Pulumi Resource Details for %s (%s):\n%s", resourceType, token, details))
+						if err != nil {
+							shared.Logger().Warn("mcp lookup failed", "token", token, "error", err)
+							a.lookupErrors = append(a.lookupErrors, fmt.Errorf("MCP lookup for %s failed: %w", token, err))
+							continue
 						}
+						contextParts = append(contextParts, fmt.Sprintf("Pulumi Resource Details for %s (%s):\n%s", resourceType, token, details))
 					}
 				}
 			}
@@ -107,6 +119,12 @@ func (a *MCPAgent) gatherMCPContext(input map[string]string) (string, error) {
 	return strings.Join(contextParts, "\n\n"), nil
 }
 
+// LookupErrors returns the MCP lookups that failed during the last AnalyzeWithMCP call.
+// The analysis went on without them, so callers may only want to warn about them.
+func (a *MCPAgent) LookupErrors() []error {
+	return a.lookupErrors
+}
+
 // SetAPIKey sets the API key for the underlying agent
 func (a *MCPAgent) SetAPIKey(apiKey string) {
 	a.Agent.CompletionRequest.ApiKey = apiKey
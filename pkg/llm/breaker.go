@@ -0,0 +1,93 @@
+/**
+ * Copyright © 2025 Magdiel Campelo <github.com/MagdielCAS/magi-cli>
+ * This file is part of the magi-cli
+**/
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/openai/openai-go/v3"
+	"github.com/spf13/viper"
+)
+
+const (
+	// CircuitBreakerThresholdKey sets how many consecutive provider failures, counted across
+	// every Service in the process, make the remaining requests fail fast. Zero disables it.
+	CircuitBreakerThresholdKey = "llm.circuit_breaker_threshold"
+
+	defaultCircuitBreakerThreshold = 5
+)
+
+// ErrProviderUnavailable is returned once the circuit breaker has tripped.
+var ErrProviderUnavailable = errors.New("provider appears unavailable")
+
+// sharedCircuitBreaker spans the whole command invocation so agents running in parallel
+// stop retrying a provider that is down.
+var sharedCircuitBreaker = &circuitBreaker{}
+
+// circuitBreaker counts consecutive provider failures. Once the threshold is reached it stays
+// open for the rest of the process; any response from the provider resets the count first.
+type circuitBreaker struct {
+	mu       sync.Mutex
+	failures int
+	open     bool
+	lastErr  error
+}
+
+func circuitBreakerThreshold() int {
+	if !viper.IsSet(CircuitBreakerThresholdKey) {
+		return defaultCircuitBreakerThreshold
+	}
+	return viper.GetInt(CircuitBreakerThresholdKey)
+}
+
+// allow returns ErrProviderUnavailable when the breaker is open. A non-positive threshold
+// disables the breaker.
+func (b *circuitBreaker) allow(threshold int) error {
+	if threshold <= 0 {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.open {
+		return nil
+	}
+	return fmt.Errorf("%w: %d consecutive requests failed, skipping the remaining AI calls for this run (last error: %v)", ErrProviderUnavailable, b.failures, b.lastErr)
+}
+
+// record updates the breaker with the outcome of a request.
+func (b *circuitBreaker) record(err error, threshold int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !isProviderFailure(err) {
+		if !errors.Is(err, context.Canceled) {
+			b.failures = 0
+		}
+		return
+	}
+
+	b.failures++
+	b.lastErr = err
+	if threshold > 0 && b.failures >= threshold {
+		b.open = true
+	}
+}
+
+// isProviderFailure reports whether err suggests the provider itself is unavailable:
+// network errors, timeouts, rate limiting and 5xx responses. Other API errors such as an
+// invalid request prove the provider is up, and a cancelled context is the user's choice.
+func isProviderFailure(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	var apiErr *openai.Error
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode == 429 || apiErr.StatusCode >= 500
+	}
+	return true
+}
@@ -0,0 +1,106 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/MagdielCAS/magi-cli/pkg/shared"
+	"github.com/openai/openai-go/v3"
+	"github.com/spf13/viper"
+)
+
+func TestCircuitBreakerTripsAfterConsecutiveFailures(t *testing.T) {
+	breaker := &circuitBreaker{}
+	unavailable := &openai.Error{StatusCode: http.StatusServiceUnavailable}
+
+	breaker.record(unavailable, 3)
+	breaker.record(unavailable, 3)
+	breaker.record(nil, 3)
+	breaker.record(unavailable, 3)
+	breaker.record(unavailable, 3)
+	if err := breaker.allow(3); err != nil {
+		t.Fatalf("expected the success to reset the count, got %v", err)
+	}
+
+	breaker.record(errors.New("dial tcp: connection refused"), 3)
+	err := breaker.allow(3)
+	if !errors.Is(err, ErrProviderUnavailable) {
+		t.Fatalf("expected ErrProviderUnavailable, got %v", err)
+	}
+
+	breaker.record(nil, 3)
+	if err := breaker.allow(3); err == nil {
+		t.Fatal("expected the breaker to stay open for the rest of the run")
+	}
+	if err := breaker.allow(0); err != nil {
+		t.Fatalf("expected a zero threshold to disable the breaker, got %v", err)
+	}
+}
+
+func TestIsProviderFailure(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"cancelled", context.Canceled, false},
+		{"deadline", context.DeadlineExceeded, true},
+		{"network", errors.New("connection reset"), true},
+		{"rate limited", &openai.Error{StatusCode: http.StatusTooManyRequests}, true},
+		{"server error", &openai.Error{StatusCode: http.StatusBadGateway}, true},
+		{"bad request", &openai.Error{StatusCode: http.StatusBadRequest}, false},
+	}
+
+	for _, tt := range tests {
+		if got := isProviderFailure(tt.err); got != tt.want {
+			t.Fatalf("%s: isProviderFailure = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCircuitBreakerThreshold(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+
+	if got := circuitBreakerThreshold(); got != defaultCircuitBreakerThreshold {
+		t.Fatalf("expected default threshold, got %d", got)
+	}
+	viper.Set(CircuitBreakerThresholdKey, 0)
+	if got := circuitBreakerThreshold(); got != 0 {
+		t.Fatalf("expected 0 to disable the breaker, got %d", got)
+	}
+}
+
+func TestServiceChatCompletionFailsFastWhenBreakerOpen(t *testing.T) {
+	previous := sharedCircuitBreaker
+	sharedCircuitBreaker = &circuitBreaker{open: true, failures: 5, lastErr: errors.New("503")}
+	t.Cleanup(func() { sharedCircuitBreaker = previous })
+
+	called := false
+	rt := &shared.RuntimeContext{
+		Provider:   "openai",
+		APIKey:     "key",
+		HeavyModel: "gpt-4",
+		HTTPClient: &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			called = true
+			return nil, errors.New("unexpected request")
+		})},
+	}
+	service, err := NewServiceBuilder(rt).UseHeavyModel().Build()
+	if err != nil {
+		t.Fatalf("unexpected error building service: %v", err)
+	}
+
+	_, err = service.ChatCompletion(context.Background(), ChatCompletionRequest{
+		Messages: []ChatMessage{{Role: "user", Content: "hi"}},
+	})
+	if !errors.Is(err, ErrProviderUnavailable) {
+		t.Fatalf("expected ErrProviderUnavailable, got %v", err)
+	}
+	if called {
+		t.Fatal("expected no request while the breaker is open")
+	}
+}
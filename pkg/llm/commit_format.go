@@ -0,0 +1,110 @@
+/**
+ * Copyright © 2025 Magdiel Campelo <github.com/MagdielCAS/magi-cli>
+ * This file is part of the magi-cli
+**/
+package llm
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	// CommitFormatKey configures how commit messages are rendered and validated.
+	CommitFormatKey = "commit.format"
+	// DefaultCommitFormat renders "<type>(<scope>): <gitmoji> <description>".
+	DefaultCommitFormat = "{{.Type}}({{.Scope}}): {{.Gitmoji}} {{.Description}}"
+)
+
+// CommitFields are the parts of a commit message that commit.format can reference.
+type CommitFields struct {
+	Type        string
+	Scope       string
+	Gitmoji     string
+	Description string
+}
+
+var commitFormatPlaceholder = regexp.MustCompile(`\{\{\s*\.(\w+)\s*\}\}`)
+
+// commitFieldPatterns are the expressions used to recognise each field when parsing.
+var commitFieldPatterns = map[string]string{
+	"Type":        `([a-z]+)`,
+	"Scope":       `([^\s()\[\]]+)`,
+	"Gitmoji":     `(\S+)`,
+	"Description": `(.+)`,
+}
+
+// CommitFormat returns the configured commit.format, or DefaultCommitFormat.
+func CommitFormat() string {
+	if format := strings.TrimSpace(viper.GetString(CommitFormatKey)); format != "" {
+		return format
+	}
+	return DefaultCommitFormat
+}
+
+// FormatCommitMessage renders fields with the format template.
+func FormatCommitMessage(format string, fields CommitFields) (string, error) {
+	tmpl, err := template.New("commit_format").Option("missingkey=error").Parse(format)
+	if err != nil {
+		return "", fmt.Errorf("invalid %s %q: %w", CommitFormatKey, format, err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, fields); err != nil {
+		return "", fmt.Errorf("invalid %s %q: %w", CommitFormatKey, format, err)
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
+// ParseCommitMessage extracts the fields of message according to format. Only the
+// {{.Type}}, {{.Scope}}, {{.Gitmoji}} and {{.Description}} placeholders are supported.
+// The second return value lists the fields the format contains.
+func ParseCommitMessage(format, message string) (CommitFields, []string, error) {
+	var fields CommitFields
+	var names []string
+	var pattern strings.Builder
+	pattern.WriteString("^")
+
+	last := 0
+	for _, loc := range commitFormatPlaceholder.FindAllStringSubmatchIndex(format, -1) {
+		name := format[loc[2]:loc[3]]
+		fieldPattern, ok := commitFieldPatterns[name]
+		if !ok {
+			return fields, nil, fmt.Errorf("unsupported field %q in %s", name, CommitFormatKey)
+		}
+		pattern.WriteString(regexp.QuoteMeta(format[last:loc[0]]))
+		pattern.WriteString(fieldPattern)
+		names = append(names, name)
+		last = loc[1]
+	}
+	pattern.WriteString(regexp.QuoteMeta(format[last:]))
+	pattern.WriteString("$")
+
+	re, err := regexp.Compile(pattern.String())
+	if err != nil {
+		return fields, nil, fmt.Errorf("invalid %s %q: %w", CommitFormatKey, format, err)
+	}
+	match := re.FindStringSubmatch(message)
+	if match == nil {
+		return fields, names, fmt.Errorf("message does not match the commit format %q", format)
+	}
+
+	for i, name := range names {
+		value := strings.TrimSpace(match[i+1])
+		switch name {
+		case "Type":
+			fields.Type = value
+		case "Scope":
+			fields.Scope = value
+		case "Gitmoji":
+			fields.Gitmoji = value
+		case "Description":
+			fields.Description = value
+		}
+	}
+	return fields, names, nil
+}
@@ -0,0 +1,52 @@
+package llm
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCommitFormatRoundTrip(t *testing.T) {
+	fields := CommitFields{Type: "feat", Scope: "cli", Gitmoji: "✨", Description: "add format option"}
+
+	tests := []struct {
+		format  string
+		message string
+		parsed  CommitFields
+	}{
+		{DefaultCommitFormat, "feat(cli): ✨ add format option", fields},
+		{"[{{.Type}}] {{.Description}}", "[feat] add format option", CommitFields{Type: "feat", Description: "add format option"}},
+		{"{{.Type}}: {{.Description}}", "feat: add format option", CommitFields{Type: "feat", Description: "add format option"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			message, err := FormatCommitMessage(tt.format, fields)
+			if err != nil {
+				t.Fatalf("FormatCommitMessage failed: %v", err)
+			}
+			if message != tt.message {
+				t.Fatalf("FormatCommitMessage() = %q, want %q", message, tt.message)
+			}
+
+			parsed, _, err := ParseCommitMessage(tt.format, message)
+			if err != nil {
+				t.Fatalf("ParseCommitMessage failed: %v", err)
+			}
+			if !reflect.DeepEqual(parsed, tt.parsed) {
+				t.Fatalf("ParseCommitMessage() = %+v, want %+v", parsed, tt.parsed)
+			}
+		})
+	}
+}
+
+func TestCommitFormatErrors(t *testing.T) {
+	if _, err := FormatCommitMessage("{{.Ticket}} {{.Description}}", CommitFields{}); err == nil {
+		t.Fatal("expected error for unknown field when rendering")
+	}
+	if _, _, err := ParseCommitMessage("{{.Ticket}} {{.Description}}", "ABC-1 fix"); err == nil {
+		t.Fatal("expected error for unknown field when parsing")
+	}
+	if _, _, err := ParseCommitMessage(DefaultCommitFormat, "feat: add"); err == nil {
+		t.Fatal("expected error when the message does not match the format")
+	}
+}
@@ -10,11 +10,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"sync"
 	"text/template"
 
 	openai "github.com/openai/openai-go/v3"
 	openaiShared "github.com/openai/openai-go/v3/shared"
-	"github.com/tiktoken-go/tokenizer"
 
 	"github.com/MagdielCAS/magi-cli/pkg/shared"
 )
@@ -81,60 +81,107 @@ var (
 	fixCommitPromptTemplate = template.Must(template.New("fix_commit_prompt").Parse(fixCommitUserPrompt))
 )
 
-// GenerateCommitMessage requests an AI-generated conventional commit message for the supplied diff.
-func GenerateCommitMessage(ctx context.Context, runtime *shared.RuntimeContext, diff string) (string, error) {
+// candidateTemperature is used for every candidate after the first so they differ.
+const candidateTemperature = 0.8
+
+// GenerateCommitMessage requests an AI-generated conventional commit message for the supplied
+// diff from the model tier given by variant.
+func GenerateCommitMessage(ctx context.Context, runtime *shared.RuntimeContext, diff string, variant ModelVariant) (string, error) {
+	candidates, err := GenerateCommitCandidates(ctx, runtime, diff, variant, 1)
+	if err != nil {
+		return "", err
+	}
+	return candidates[0], nil
+}
+
+// GenerateCommitCandidates requests n commit messages for the diff in parallel. The first
+// one uses temperature 0 like GenerateCommitMessage and the others a higher temperature so
+// they read differently. Duplicates and failed requests are dropped; an error is returned
+// only when no candidate could be generated.
+func GenerateCommitCandidates(ctx context.Context, runtime *shared.RuntimeContext, diff string, variant ModelVariant, n int) ([]string, error) {
 	if strings.TrimSpace(diff) == "" {
-		return "", fmt.Errorf("diff cannot be empty")
+		return nil, fmt.Errorf("diff cannot be empty")
 	}
-	if runtime == nil {
-		return "", fmt.Errorf("runtime context is required")
+	if n < 1 {
+		n = 1
 	}
-	if runtime.LightModel == "" {
-		return "", fmt.Errorf("api.heavy_model must be configured")
+	builder, model, err := commitServiceBuilder(runtime, variant)
+	if err != nil {
+		return nil, err
 	}
 
 	prompt, err := renderCommitPrompt(diff)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	service, err := NewServiceBuilder(runtime).UseLightModel().Build()
+	service, err := builder.Build()
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	maxTokens := 100.0
-	enc, err := tokenizer.Get(tokenizer.Cl100kBase)
-	if err != nil {
-		maxTokens = 2048
+	count := EstimateTokens(model, commitSystemPrompt+prompt)
+	// commit msg length + an estimative of prompt tokens + 10% error margin
+	maxTokens := 500 + float64(count)*1.1
+	// Hard cap to prevent excessive costs/abuse
+	if maxTokens > 4096 {
+		maxTokens = 4096
 	}
-	if enc != nil {
-		count, err := enc.Count(fmt.Sprintf("%s%s", commitSystemPrompt, prompt))
-		if err != nil {
-			count = 2048
-		}
-		// commit msg length + an estimative of prompt tokens + 10% error margin
-		maxTokens = 500 + float64(count)*1.1
-		// Hard cap to prevent excessive costs/abuse
-		if maxTokens > 4096 {
-			maxTokens = 4096
+
+	messages := make([]string, n)
+	errs := make([]error, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		temperature := 0.0
+		if i > 0 {
+			temperature = candidateTemperature
 		}
+		wg.Add(1)
+		go func(i int, temperature float64) {
+			defer wg.Done()
+			response, err := service.ChatCompletion(ctx, ChatCompletionRequest{
+				Messages: []ChatMessage{
+					{Role: "system", Content: commitSystemPrompt},
+					{Role: "user", Content: prompt},
+				},
+				Temperature:    temperature,
+				MaxTokens:      maxTokens,
+				ResponseFormat: CommitSchema,
+			})
+			if err == nil {
+				messages[i], err = parseCommitMessage(response)
+			}
+			errs[i] = err
+		}(i, temperature)
 	}
+	wg.Wait()
 
-	message, err := service.ChatCompletion(ctx, ChatCompletionRequest{
-		Messages: []ChatMessage{
-			{Role: "system", Content: commitSystemPrompt},
-			{Role: "user", Content: prompt},
-		},
-		Temperature:    0.0,
-		MaxTokens:      maxTokens,
-		ResponseFormat: CommitSchema,
-	})
-	if err != nil {
-		return "", err
+	seen := make(map[string]bool, n)
+	var candidates []string
+	for i, message := range messages {
+		if errs[i] != nil || seen[message] {
+			continue
+		}
+		seen[message] = true
+		candidates = append(candidates, message)
 	}
+	if len(candidates) == 0 {
+		return nil, errs[0]
+	}
+	return candidates, nil
+}
 
-	return parseCommitMessage(message)
+// commitServiceBuilder returns a builder for variant and its model, which must be configured.
+func commitServiceBuilder(runtime *shared.RuntimeContext, variant ModelVariant) (*ServiceBuilder, string, error) {
+	if runtime == nil {
+		return nil, "", fmt.Errorf("runtime context is required")
+	}
+	builder := NewServiceBuilder(runtime).UseVariant(variant)
+	model, _ := builder.resolveVariantConfig()
+	if model == "" {
+		return nil, "", fmt.Errorf("%s must be configured", variantModelKey(variant))
+	}
+	return builder, model, nil
 }
 
 func renderCommitPrompt(diff string) (string, error) {
@@ -149,16 +196,15 @@ func renderCommitPrompt(diff string) (string, error) {
 	return buf.String(), nil
 }
 
-// FixCommitMessage reparses the diff with guidance about the validation failure and returns a corrected message.
-func FixCommitMessage(ctx context.Context, runtime *shared.RuntimeContext, diff, previousMessage string, validationErr error) (string, error) {
+// FixCommitMessage reparses the diff with guidance about the validation failure and returns a
+// corrected message from the model tier given by variant.
+func FixCommitMessage(ctx context.Context, runtime *shared.RuntimeContext, diff, previousMessage string, validationErr error, variant ModelVariant) (string, error) {
 	if strings.TrimSpace(diff) == "" {
 		return "", fmt.Errorf("diff cannot be empty")
 	}
-	if runtime == nil {
-		return "", fmt.Errorf("runtime context is required")
-	}
-	if runtime.LightModel == "" {
-		return "", fmt.Errorf("api.heavy_model must be configured")
+	builder, _, err := commitServiceBuilder(runtime, variant)
+	if err != nil {
+		return "", err
 	}
 
 	prompt, err := renderFixCommitPrompt(diff, previousMessage, validationErr)
@@ -166,7 +212,7 @@ func FixCommitMessage(ctx context.Context, runtime *shared.RuntimeContext, diff,
 		return "", err
 	}
 
-	service, err := NewServiceBuilder(runtime).UseLightModel().Build()
+	service, err := builder.Build()
 	if err != nil {
 		return "", err
 	}
@@ -226,6 +272,10 @@ func parseCommitMessage(jsonStr string) (string, error) {
 		return "", fmt.Errorf("failed to parse commit message JSON: %w", err)
 	}
 
-	// Format: <type>(<scope>): <gitmoji> <description>
-	return fmt.Sprintf("%s(%s): %s %s", result.Type, result.Scope, result.Gitmoji, result.Description), nil
+	return FormatCommitMessage(CommitFormat(), CommitFields{
+		Type:        result.Type,
+		Scope:       result.Scope,
+		Gitmoji:     result.Gitmoji,
+		Description: result.Description,
+	})
 }
@@ -3,6 +3,8 @@ package llm
 import (
 	"strings"
 	"testing"
+
+	"github.com/MagdielCAS/magi-cli/pkg/shared"
 )
 
 func TestRenderCommitPrompt(t *testing.T) {
@@ -20,3 +22,42 @@ func TestRenderCommitPrompt(t *testing.T) {
 		t.Fatalf("prompt is missing commit rules")
 	}
 }
+
+func TestCommitServiceBuilder(t *testing.T) {
+	runtime := &shared.RuntimeContext{LightModel: "light-model"}
+
+	if _, model, err := commitServiceBuilder(runtime, ModelVariantLight); err != nil || model != "light-model" {
+		t.Fatalf("expected the light model, got %q (err=%v)", model, err)
+	}
+	_, _, err := commitServiceBuilder(runtime, ModelVariantHeavy)
+	if err == nil || !strings.Contains(err.Error(), "api.heavy_model") {
+		t.Fatalf("expected an error naming api.heavy_model, got %v", err)
+	}
+	if _, _, err := commitServiceBuilder(nil, ModelVariantLight); err == nil {
+		t.Fatal("expected a nil runtime context to fail")
+	}
+}
+
+func TestParseCommitSplit(t *testing.T) {
+	groups, err := parseCommitSplit(`{"groups":[
+		{"files":["pkg/a.go","pkg/a_test.go"],"type":"feat","scope":"api","gitmoji":"✨","description":"add endpoint"},
+		{"files":["README.md"],"type":"docs","scope":"readme","gitmoji":"📚","description":"document endpoint"}
+	]}`)
+	if err != nil {
+		t.Fatalf("parseCommitSplit returned error: %v", err)
+	}
+	if len(groups) != 2 {
+		t.Fatalf("expected 2 groups, got %d", len(groups))
+	}
+	if groups[0].Message != "feat(api): ✨ add endpoint" || len(groups[0].Files) != 2 {
+		t.Fatalf("unexpected first group %+v", groups[0])
+	}
+
+	prompt, err := renderCommitSplitPrompt("+hello", []string{"pkg/a.go"})
+	if err != nil {
+		t.Fatalf("renderCommitSplitPrompt returned error: %v", err)
+	}
+	if !strings.Contains(prompt, "- pkg/a.go") || !strings.Contains(prompt, "+hello") {
+		t.Fatalf("prompt is missing files or diff:\n%s", prompt)
+	}
+}
@@ -0,0 +1,168 @@
+/**
+ * Copyright © 2025 Magdiel Campelo <github.com/MagdielCAS/magi-cli>
+ * This file is part of the magi-cli
+**/
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/template"
+
+	openai "github.com/openai/openai-go/v3"
+	openaiShared "github.com/openai/openai-go/v3/shared"
+
+	"github.com/MagdielCAS/magi-cli/pkg/shared"
+)
+
+var (
+	CommitSplitSchema = &openai.ChatCompletionNewParamsResponseFormatUnion{
+		OfJSONSchema: &openaiShared.ResponseFormatJSONSchemaParam{
+			JSONSchema: openaiShared.ResponseFormatJSONSchemaJSONSchemaParam{
+				Name:        "commit_split",
+				Description: openai.String("A series of conventional commits, each covering a subset of the changed files"),
+				Schema: interface{}(map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"groups": map[string]interface{}{
+							"type": "array",
+							"items": map[string]interface{}{
+								"type": "object",
+								"properties": map[string]interface{}{
+									"files":       map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+									"type":        map[string]interface{}{"type": "string", "enum": []string{"feat", "fix", "docs", "style", "refactor", "perf", "test", "build", "ci", "chore", "revert"}},
+									"scope":       map[string]interface{}{"type": "string"},
+									"gitmoji":     map[string]interface{}{"type": "string"},
+									"description": map[string]interface{}{"type": "string"},
+								},
+								"required":             []string{"files", "type", "scope", "gitmoji", "description"},
+								"additionalProperties": false,
+							},
+						},
+					},
+					"required":             []string{"groups"},
+					"additionalProperties": false,
+				}),
+				Strict: openai.Bool(true),
+			},
+		},
+	}
+)
+
+const commitSplitUserPrompt = `Split the following changes into a short series of logical commits that are easy to review.
+
+Rules:
+1. Every changed file must appear in exactly one group. Only use paths from the list below.
+2. Order the groups so that each commit builds on the previous ones.
+3. Prefer fewer groups; only split changes that are genuinely unrelated.
+4. Type must be one of: feat, fix, docs, style, refactor, perf, test, build, ci, chore, revert
+5. Scope must be a short, meaningful noun (e.g., cli, api, docs)
+6. Description must be a short summary of the change in present tense (e.g., add, fix, update). Do not capitalize. Do not end with a period.
+7. Gitmoji must be one appropriate unicode emoji from: ✨, 🐛, 📚, 🎨, ♻️, ⚡️, ✅, 🔧, 👷, 🔨, ⏪️.
+
+Changed files:
+{{range .Files}}- {{.}}
+{{end}}
+Git diff to analyze:
+` + "```diff\n{{.Diff}}\n```"
+
+var commitSplitPromptTemplate = template.Must(template.New("commit_split_prompt").Parse(commitSplitUserPrompt))
+
+// CommitGroup is one proposed commit: the files it contains and its formatted message.
+type CommitGroup struct {
+	Files   []string `json:"files"`
+	Message string   `json:"message"`
+}
+
+// ProposeCommitSplit asks the model tier given by variant to group the changed files into a
+// series of conventional commits.
+func ProposeCommitSplit(ctx context.Context, runtime *shared.RuntimeContext, diff string, files []string, variant ModelVariant) ([]CommitGroup, error) {
+	if strings.TrimSpace(diff) == "" {
+		return nil, fmt.Errorf("diff cannot be empty")
+	}
+	builder, model, err := commitServiceBuilder(runtime, variant)
+	if err != nil {
+		return nil, err
+	}
+
+	prompt, err := renderCommitSplitPrompt(diff, files)
+	if err != nil {
+		return nil, err
+	}
+
+	service, err := builder.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	count := EstimateTokens(model, commitSystemPrompt+prompt)
+	// Each group lists its files, so budget for them on top of the messages.
+	maxTokens := 1000 + float64(count)*0.2
+	if maxTokens > 4096 {
+		maxTokens = 4096
+	}
+
+	response, err := service.ChatCompletion(ctx, ChatCompletionRequest{
+		Messages: []ChatMessage{
+			{Role: "system", Content: commitSystemPrompt},
+			{Role: "user", Content: prompt},
+		},
+		Temperature:    0.0,
+		MaxTokens:      maxTokens,
+		ResponseFormat: CommitSplitSchema,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return parseCommitSplit(response)
+}
+
+func renderCommitSplitPrompt(diff string, files []string) (string, error) {
+	var buf bytes.Buffer
+	if err := commitSplitPromptTemplate.Execute(&buf, struct {
+		Diff  string
+		Files []string
+	}{
+		Diff:  diff,
+		Files: files,
+	}); err != nil {
+		return "", fmt.Errorf("failed to render commit split prompt: %w", err)
+	}
+	return buf.String(), nil
+}
+
+func parseCommitSplit(jsonStr string) ([]CommitGroup, error) {
+	var result struct {
+		Groups []struct {
+			Files       []string `json:"files"`
+			Type        string   `json:"type"`
+			Scope       string   `json:"scope"`
+			Gitmoji     string   `json:"gitmoji"`
+			Description string   `json:"description"`
+		} `json:"groups"`
+	}
+
+	if err := json.Unmarshal([]byte(jsonStr), &result); err != nil {
+		return nil, fmt.Errorf("failed to parse commit split JSON: %w", err)
+	}
+
+	format := CommitFormat()
+	groups := make([]CommitGroup, 0, len(result.Groups))
+	for _, g := range result.Groups {
+		message, err := FormatCommitMessage(format, CommitFields{
+			Type:        g.Type,
+			Scope:       g.Scope,
+			Gitmoji:     g.Gitmoji,
+			Description: g.Description,
+		})
+		if err != nil {
+			return nil, err
+		}
+		groups = append(groups, CommitGroup{Files: g.Files, Message: message})
+	}
+	return groups, nil
+}
@@ -0,0 +1,87 @@
+package llm
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	openai "github.com/openai/openai-go/v3"
+	openaiShared "github.com/openai/openai-go/v3/shared"
+)
+
+// JSON modes accepted by api.json_mode and api.<tier>.json_mode.
+const (
+	// JSONModeSchema sends the strict JSON schema as response_format (default).
+	JSONModeSchema = "schema"
+	// JSONModeObject sends {"type": "json_object"} and describes the schema in the prompt.
+	JSONModeObject = "json_object"
+	// JSONModeNone sends no response_format and relies on the prompt alone.
+	JSONModeNone = "none"
+)
+
+var jsonModes = []string{JSONModeSchema, JSONModeObject, JSONModeNone}
+
+// normalizeJSONMode validates a configured JSON mode. An empty value means JSONModeSchema.
+func normalizeJSONMode(mode string) (string, error) {
+	mode = strings.ToLower(strings.TrimSpace(mode))
+	if mode == "" {
+		return JSONModeSchema, nil
+	}
+	for _, valid := range jsonModes {
+		if mode == valid {
+			return mode, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported json_mode %q (valid modes: %s)", mode, strings.Join(jsonModes, ", "))
+}
+
+// applyJSONMode adapts a structured-output request to mode. In the looser modes the JSON
+// schema is removed from response_format and described in a system message instead, placed
+// after the leading system messages so the model still knows the expected shape.
+func applyJSONMode(mode string, messages []ChatMessage, format *openai.ChatCompletionNewParamsResponseFormatUnion) ([]ChatMessage, *openai.ChatCompletionNewParamsResponseFormatUnion) {
+	if format == nil || format.OfJSONSchema == nil || mode == JSONModeSchema {
+		return messages, format
+	}
+
+	var looser *openai.ChatCompletionNewParamsResponseFormatUnion
+	if mode == JSONModeObject {
+		looser = &openai.ChatCompletionNewParamsResponseFormatUnion{
+			OfJSONObject: &openaiShared.ResponseFormatJSONObjectParam{},
+		}
+	}
+
+	schema, err := json.Marshal(format.OfJSONSchema.JSONSchema.Schema)
+	if err != nil {
+		return messages, looser
+	}
+	instruction := ChatMessage{
+		Role:    "system",
+		Content: fmt.Sprintf("Respond with a single JSON object, without markdown fences or any other text, that matches this JSON schema:\n%s", schema),
+	}
+
+	at := 0
+	for at < len(messages) && isSystemRole(messages[at].Role) {
+		at++
+	}
+	adapted := make([]ChatMessage, 0, len(messages)+1)
+	adapted = append(adapted, messages[:at]...)
+	adapted = append(adapted, instruction)
+	adapted = append(adapted, messages[at:]...)
+	return adapted, looser
+}
+
+// stripJSONFence removes a markdown code fence around a JSON answer, which models tend to
+// add when the response format is not enforced.
+func stripJSONFence(content string) string {
+	trimmed := strings.TrimSpace(content)
+	if !strings.HasPrefix(trimmed, "```") || !strings.HasSuffix(trimmed, "```") || len(trimmed) < 6 {
+		return content
+	}
+	body := strings.TrimSuffix(trimmed, "```")
+	if newline := strings.Index(body, "\n"); newline != -1 {
+		body = body[newline+1:]
+	} else {
+		return content
+	}
+	return strings.TrimSpace(body)
+}
@@ -0,0 +1,122 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/MagdielCAS/magi-cli/pkg/shared"
+)
+
+func TestNormalizeJSONMode(t *testing.T) {
+	cases := map[string]string{"": JSONModeSchema, " Schema ": JSONModeSchema, "json_object": JSONModeObject, "NONE": JSONModeNone}
+	for input, want := range cases {
+		got, err := normalizeJSONMode(input)
+		if err != nil || got != want {
+			t.Fatalf("normalizeJSONMode(%q) = %q, %v; want %q", input, got, err, want)
+		}
+	}
+	if _, err := normalizeJSONMode("xml"); err == nil || !strings.Contains(err.Error(), "schema, json_object, none") {
+		t.Fatalf("expected an error listing the valid modes, got %v", err)
+	}
+}
+
+func TestApplyJSONMode(t *testing.T) {
+	messages := []ChatMessage{
+		{Role: "system", Content: "You write commits."},
+		{Role: "user", Content: "diff"},
+	}
+
+	got, format := applyJSONMode(JSONModeSchema, messages, CommitSchema)
+	if format != CommitSchema || len(got) != 2 {
+		t.Fatalf("expected schema mode to leave the request untouched, got %v %v", got, format)
+	}
+
+	got, format = applyJSONMode(JSONModeObject, messages, CommitSchema)
+	if format == nil || format.OfJSONObject == nil || format.OfJSONSchema != nil {
+		t.Fatalf("expected a json_object response format, got %+v", format)
+	}
+	if len(got) != 3 || got[1].Role != "system" || !strings.Contains(got[1].Content, `"description"`) || got[2].Content != "diff" {
+		t.Fatalf("expected the schema instruction after the system prompt, got %+v", got)
+	}
+	if len(messages) != 2 {
+		t.Fatal("expected the original messages to be left unchanged")
+	}
+
+	got, format = applyJSONMode(JSONModeNone, messages[1:], CommitSchema)
+	if format != nil {
+		t.Fatalf("expected no response format, got %+v", format)
+	}
+	if len(got) != 2 || got[0].Role != "system" {
+		t.Fatalf("expected the schema instruction first without a system prompt, got %+v", got)
+	}
+
+	got, format = applyJSONMode(JSONModeNone, messages, nil)
+	if format != nil || len(got) != 2 {
+		t.Fatalf("expected requests without a response format to be untouched, got %v %v", got, format)
+	}
+}
+
+func TestStripJSONFence(t *testing.T) {
+	cases := map[string]string{
+		"```json\n{\"a\":1}\n```": `{"a":1}`,
+		"```\n{\"a\":1}```":       `{"a":1}`,
+		`{"a":1}`:                 `{"a":1}`,
+		"```{\"a\":1}```":         "```{\"a\":1}```",
+	}
+	for input, want := range cases {
+		if got := stripJSONFence(input); got != want {
+			t.Fatalf("stripJSONFence(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestServiceChatCompletionJSONObjectMode(t *testing.T) {
+	var body map[string]any
+	rt := &shared.RuntimeContext{
+		Provider:      "groq",
+		APIKey:        "key",
+		HeavyModel:    "model",
+		HeavyEndpoint: shared.ModelEndpoint{JSONMode: JSONModeObject},
+		HTTPClient: &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+				t.Fatalf("decode request: %v", err)
+			}
+			response := strings.Replace(successfulChatCompletionResponse, `"content": "ok"`, `"content": "`+"```json\\n{}\\n```"+`"`, 1)
+			resp := &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(response)),
+				Header:     make(http.Header),
+			}
+			resp.Header.Set("Content-Type", "application/json")
+			return resp, nil
+		})},
+	}
+
+	service, err := NewServiceBuilder(rt).Build()
+	if err != nil {
+		t.Fatalf("unexpected error building service: %v", err)
+	}
+	resp, err := service.ChatCompletion(context.Background(), ChatCompletionRequest{
+		Messages:       []ChatMessage{{Role: "user", Content: "diff"}},
+		ResponseFormat: CommitSchema,
+	})
+	if err != nil {
+		t.Fatalf("completion failed: %v", err)
+	}
+	if resp != "{}" {
+		t.Fatalf("expected the fence to be stripped, got %q", resp)
+	}
+	format, _ := body["response_format"].(map[string]any)
+	if format["type"] != "json_object" {
+		t.Fatalf("expected a json_object response format, got %v", body["response_format"])
+	}
+
+	rt.HeavyEndpoint.JSONMode = "strict"
+	if _, err := NewServiceBuilder(rt).Build(); err == nil {
+		t.Fatal("expected an invalid json_mode to fail the build")
+	}
+}
@@ -2,20 +2,40 @@ package llm
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"sync"
 	"time"
+
+	"github.com/MagdielCAS/magi-cli/pkg/shared"
 )
 
 // MCPClient represents a Model Context Protocol client
 type MCPClient struct {
-	ServerURL  string
-	HTTPClient *http.Client
-	SessionID  string
-	Tools      map[string]MCPTool
+	ServerURL    string
+	HTTPClient   *http.Client
+	SessionID    string
+	Tools        map[string]MCPTool
+	AllowedHosts []string
+	// MaxRetries is the number of extra attempts made on connection errors and 5xx responses.
+	MaxRetries int
+	// RetryBackoff is the delay before the first retry; it doubles on every attempt.
+	RetryBackoff time.Duration
+
+	// resourceCache keeps GetResourceDetails results for the lifetime of the client so
+	// agents in the same run do not fetch the same token twice.
+	resourceMu    sync.Mutex
+	resourceCache map[string]string
 }
 
+const (
+	defaultMCPTimeout      = 60 * time.Second
+	defaultMCPMaxRetries   = 2
+	defaultMCPRetryBackoff = time.Second
+)
+
 // MCPTool represents an available MCP tool
 type MCPTool struct {
 	Name        string                 `json:"name"`
@@ -46,14 +66,41 @@ func NewMCPClient(serverURL string) *MCPClient {
 	return &MCPClient{
 		ServerURL: serverURL,
 		HTTPClient: &http.Client{
-			Timeout: 60 * time.Second,
+			Timeout: defaultMCPTimeout,
 		},
-		Tools: make(map[string]MCPTool),
+		Tools:         make(map[string]MCPTool),
+		AllowedHosts:  shared.AllowedHosts(),
+		MaxRetries:    defaultMCPMaxRetries,
+		RetryBackoff:  defaultMCPRetryBackoff,
+		resourceCache: make(map[string]string),
 	}
 }
 
+// WithTimeout sets the per-call timeout. Non-positive values keep the current timeout.
+func (c *MCPClient) WithTimeout(timeout time.Duration) *MCPClient {
+	if timeout > 0 {
+		c.HTTPClient.Timeout = timeout
+	}
+	return c
+}
+
+// WithRetry configures how many times failed calls are retried and the initial backoff.
+func (c *MCPClient) WithRetry(maxRetries int, backoff time.Duration) *MCPClient {
+	if maxRetries >= 0 {
+		c.MaxRetries = maxRetries
+	}
+	if backoff > 0 {
+		c.RetryBackoff = backoff
+	}
+	return c
+}
+
 // Connect establishes connection to MCP server and initializes session
 func (c *MCPClient) Connect() error {
+	if err := shared.CheckHostAllowed(c.ServerURL, c.AllowedHosts); err != nil {
+		return fmt.Errorf("MCP server rejected: %w", err)
+	}
+
 	// Initialize session
 	req := MCPRequest{
 		Method: "initialize",
@@ -143,8 +190,32 @@ func (c *MCPClient) CallTool(toolName string, arguments map[string]interface{})
 	return resp.Result, nil
 }
 
-// GetResourceDetails retrieves detailed information about a Pulumi Registry resource
+// GetResourceDetails retrieves detailed information about a Pulumi Registry resource.
+// Successful lookups are cached per token for the lifetime of the client.
 func (c *MCPClient) GetResourceDetails(token string) (string, error) {
+	c.resourceMu.Lock()
+	details, ok := c.resourceCache[token]
+	c.resourceMu.Unlock()
+	if ok {
+		return details, nil
+	}
+
+	details, err := c.fetchResourceDetails(token)
+	if err != nil {
+		return "", err
+	}
+
+	c.resourceMu.Lock()
+	if c.resourceCache == nil {
+		c.resourceCache = make(map[string]string)
+	}
+	c.resourceCache[token] = details
+	c.resourceMu.Unlock()
+
+	return details, nil
+}
+
+func (c *MCPClient) fetchResourceDetails(token string) (string, error) {
 	result, err := c.CallTool("get-resource", map[string]interface{}{
 		"token": token,
 	})
@@ -166,32 +237,66 @@ func (c *MCPClient) GetResourceDetails(token string) (string, error) {
 	return string(jsonBytes), nil
 }
 
-// sendRequest sends an HTTP request to the MCP server
+// sendRequest sends an HTTP request to the MCP server, retrying connection errors and
+// 5xx responses with exponential backoff. Requests and retries stop when the command
+// context (shared.BaseContext) is cancelled.
 func (c *MCPClient) sendRequest(req MCPRequest) (*MCPResponse, error) {
 	jsonData, err := json.Marshal(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	httpReq, err := http.NewRequest("POST", c.ServerURL, bytes.NewBuffer(jsonData))
+	ctx := shared.BaseContext()
+	backoff := c.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		resp, retryable, err := c.doRequest(ctx, jsonData)
+		if err == nil {
+			return resp, nil
+		}
+		if !retryable || attempt >= c.MaxRetries || ctx.Err() != nil {
+			if attempt > 0 {
+				err = fmt.Errorf("%w (after %d attempts)", err, attempt+1)
+			}
+			return nil, withRequestID(err)
+		}
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return nil, withRequestID(fmt.Errorf("%w (after %d attempts)", err, attempt+1))
+		}
+		backoff *= 2
+	}
+}
+
+// doRequest performs a single HTTP round trip and reports whether a failure is retryable.
+func (c *MCPClient) doRequest(ctx context.Context, body []byte) (*MCPResponse, bool, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.ServerURL, bytes.NewReader(body))
 	if err != nil {
-		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+		return nil, false, fmt.Errorf("failed to create HTTP request: %w", err)
 	}
 
 	httpReq.Header.Set("Content-Type", "application/json")
+	if requestIDEnabled() {
+		httpReq.Header.Set(RequestIDHeader, RequestID())
+	}
 
 	httpResp, err := c.HTTPClient.Do(httpReq)
 	if err != nil {
-		return nil, fmt.Errorf("failed to send HTTP request: %w", err)
+		return nil, true, fmt.Errorf("failed to send HTTP request: %w", err)
 	}
 	defer httpResp.Body.Close()
 
+	if httpResp.StatusCode >= http.StatusInternalServerError {
+		return nil, true, fmt.Errorf("MCP server returned status %d", httpResp.StatusCode)
+	}
+
 	var mcpResp MCPResponse
 	if err := json.NewDecoder(httpResp.Body).Decode(&mcpResp); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+		return nil, false, fmt.Errorf("failed to decode response: %w", err)
 	}
 
-	return &mcpResp, nil
+	return &mcpResp, false, nil
 }
 
 // Close closes the MCP client connection
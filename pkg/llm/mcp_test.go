@@ -0,0 +1,139 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/MagdielCAS/magi-cli/pkg/shared"
+)
+
+func TestMCPClientRetriesServerErrors(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"result":{"tools":[]}}`))
+	}))
+	defer server.Close()
+
+	client := NewMCPClient(server.URL).WithRetry(2, time.Millisecond)
+	if _, err := client.sendRequest(MCPRequest{Method: "tools/list"}); err != nil {
+		t.Fatalf("expected request to succeed after retries, got %v", err)
+	}
+	if got := atomic.LoadInt32(&calls); got != 3 {
+		t.Fatalf("expected 3 attempts, got %d", got)
+	}
+}
+
+func TestMCPClientGivesUpAfterMaxRetries(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	client := NewMCPClient(server.URL).WithRetry(1, time.Millisecond).WithTimeout(time.Second)
+	if _, err := client.sendRequest(MCPRequest{Method: "tools/list"}); err == nil {
+		t.Fatal("expected error after exhausting retries")
+	}
+	if got := atomic.LoadInt32(&calls); got != 2 {
+		t.Fatalf("expected 2 attempts, got %d", got)
+	}
+	if client.HTTPClient.Timeout != time.Second {
+		t.Fatalf("expected timeout to be applied, got %v", client.HTTPClient.Timeout)
+	}
+}
+
+func TestMCPClientStopsWhenBaseContextIsCancelled(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	shared.SetBaseContext(ctx)
+	t.Cleanup(func() { shared.SetBaseContext(nil) })
+
+	client := NewMCPClient(server.URL).WithRetry(3, time.Hour)
+	if _, err := client.sendRequest(MCPRequest{Method: "tools/list"}); err == nil || !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the cancellation error, got %v", err)
+	}
+	if got := atomic.LoadInt32(&calls); got != 0 {
+		t.Fatalf("expected no request to be sent, got %d", got)
+	}
+}
+
+func TestMCPClientDoesNotRetryClientErrors(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`not json`))
+	}))
+	defer server.Close()
+
+	client := NewMCPClient(server.URL).WithRetry(3, time.Millisecond)
+	if _, err := client.sendRequest(MCPRequest{Method: "tools/list"}); err == nil {
+		t.Fatal("expected decode error")
+	}
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Fatalf("expected a single attempt, got %d", got)
+	}
+}
+
+func TestMCPClientCachesResourceDetails(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"result":"bucket docs"}`))
+	}))
+	defer server.Close()
+
+	client := NewMCPClient(server.URL)
+	client.Tools["get-resource"] = MCPTool{Name: "get-resource"}
+
+	for i := 0; i < 2; i++ {
+		details, err := client.GetResourceDetails("aws:s3/bucket:Bucket")
+		if err != nil {
+			t.Fatalf("GetResourceDetails failed: %v", err)
+		}
+		if details != "bucket docs" {
+			t.Fatalf("unexpected details %q", details)
+		}
+	}
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Fatalf("expected a single MCP call, got %d", got)
+	}
+}
+
+func TestMCPAgentReportsFailedLookups(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	client := NewMCPClient(server.URL).WithRetry(0, time.Millisecond)
+	client.Tools["get-resource"] = MCPTool{Name: "get-resource"}
+	agent := NewMCPAgent(MCPAgentConfig{Tools: []string{"get_resource_details"}}, client, nil)
+
+	mcpContext, err := agent.gatherMCPContext(map[string]string{"resource_types": "s3"})
+	if err != nil || mcpContext != "" {
+		t.Fatalf("expected the lookup to be skipped, got %q, %v", mcpContext, err)
+	}
+	if errs := agent.LookupErrors(); len(errs) != 1 {
+		t.Fatalf("expected one failed lookup, got %v", errs)
+	}
+}
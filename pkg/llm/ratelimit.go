@@ -0,0 +1,130 @@
+/**
+ * Copyright © 2025 Magdiel Campelo <github.com/MagdielCAS/magi-cli>
+ * This file is part of the magi-cli
+**/
+package llm
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	// RequestsPerMinuteKey caps the chat completion requests sent per minute across every
+	// Service in the process. Zero or unset disables the limit.
+	RequestsPerMinuteKey = "llm.requests_per_minute"
+	// MaxConcurrentRequestsKey caps the chat completion requests in flight at once across
+	// every Service in the process. Zero or unset disables the limit.
+	MaxConcurrentRequestsKey = "llm.max_concurrent_requests"
+)
+
+// Package-level limiters so concurrent agents and batches share one budget.
+var (
+	sharedRateLimiter        = &rateLimiter{now: time.Now}
+	sharedConcurrencyLimiter = &concurrencyLimiter{}
+)
+
+// acquireRequestSlot blocks until the configured rate and concurrency limits allow another
+// request, or ctx is done. The returned release func must be called when the request ends.
+func acquireRequestSlot(ctx context.Context) (func(), error) {
+	release, err := sharedConcurrencyLimiter.acquire(ctx, viper.GetInt(MaxConcurrentRequestsKey))
+	if err != nil {
+		return nil, err
+	}
+	if err := sharedRateLimiter.wait(ctx, viper.GetInt(RequestsPerMinuteKey)); err != nil {
+		release()
+		return nil, err
+	}
+	return release, nil
+}
+
+// rateLimiter is a token bucket holding up to one minute of requests. It refills
+// continuously at perMinute/60 tokens per second.
+type rateLimiter struct {
+	mu        sync.Mutex
+	perMinute int
+	tokens    float64
+	last      time.Time
+	now       func() time.Time
+}
+
+// wait takes a token, sleeping until one is available. A non-positive perMinute disables
+// the limit. Changing perMinute between calls resets the bucket.
+func (l *rateLimiter) wait(ctx context.Context, perMinute int) error {
+	if perMinute <= 0 {
+		return nil
+	}
+
+	for {
+		delay := l.reserve(perMinute)
+		if delay <= 0 {
+			return nil
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// reserve takes a token and returns zero, or returns how long to wait for the next one.
+func (l *rateLimiter) reserve(perMinute int) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if l.perMinute != perMinute {
+		l.perMinute = perMinute
+		l.tokens = float64(perMinute)
+		l.last = now
+	}
+
+	perSecond := float64(perMinute) / 60
+	l.tokens += now.Sub(l.last).Seconds() * perSecond
+	if capacity := float64(perMinute); l.tokens > capacity {
+		l.tokens = capacity
+	}
+	l.last = now
+
+	if l.tokens >= 1 {
+		l.tokens--
+		return 0
+	}
+	return time.Duration((1 - l.tokens) / perSecond * float64(time.Second))
+}
+
+// concurrencyLimiter bounds the requests in flight. Changing the limit starts a new set of
+// slots; requests holding slots of the previous limit release them as usual.
+type concurrencyLimiter struct {
+	mu    sync.Mutex
+	limit int
+	slots chan struct{}
+}
+
+func (c *concurrencyLimiter) acquire(ctx context.Context, limit int) (func(), error) {
+	if limit <= 0 {
+		return func() {}, nil
+	}
+
+	c.mu.Lock()
+	if c.limit != limit {
+		c.limit = limit
+		c.slots = make(chan struct{}, limit)
+	}
+	slots := c.slots
+	c.mu.Unlock()
+
+	select {
+	case slots <- struct{}{}:
+		return func() { <-slots }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
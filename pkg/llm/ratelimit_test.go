@@ -0,0 +1,66 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterReserve(t *testing.T) {
+	now := time.Unix(0, 0)
+	limiter := &rateLimiter{now: func() time.Time { return now }}
+
+	for i := 0; i < 2; i++ {
+		if delay := limiter.reserve(2); delay != 0 {
+			t.Fatalf("request %d: expected a token from the full bucket, got delay %v", i, delay)
+		}
+	}
+	if delay := limiter.reserve(2); delay != 30*time.Second {
+		t.Fatalf("expected 30s until the next token, got %v", delay)
+	}
+
+	now = now.Add(30 * time.Second)
+	if delay := limiter.reserve(2); delay != 0 {
+		t.Fatalf("expected refilled token, got delay %v", delay)
+	}
+}
+
+func TestRateLimiterWaitRespectsContext(t *testing.T) {
+	limiter := &rateLimiter{now: time.Now}
+	if err := limiter.wait(context.Background(), 1); err != nil {
+		t.Fatalf("first wait should not block: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := limiter.wait(ctx, 1); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+
+	if err := limiter.wait(context.Background(), 0); err != nil {
+		t.Fatalf("disabled limiter should not block: %v", err)
+	}
+}
+
+func TestConcurrencyLimiter(t *testing.T) {
+	limiter := &concurrencyLimiter{}
+
+	release, err := limiter.acquire(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("acquire failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := limiter.acquire(ctx, 1); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected second acquire to block until the deadline, got %v", err)
+	}
+
+	release()
+	release, err = limiter.acquire(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("acquire after release failed: %v", err)
+	}
+	release()
+}
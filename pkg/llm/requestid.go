@@ -0,0 +1,49 @@
+package llm
+
+import (
+	"crypto/rand"
+	"fmt"
+	"sync"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	// RequestIDHeader carries the request ID of the magi invocation on every LLM and MCP request.
+	RequestIDHeader = "X-Magi-Request-Id"
+	// RequestIDKey turns the request ID header off when set to false, for strict environments.
+	RequestIDKey = "llm.request_id_header"
+)
+
+// RequestID returns the ID shared by every LLM and MCP request of this magi invocation, so
+// proxy and provider logs can be matched with the error magi reports.
+var RequestID = sync.OnceValue(newRequestID)
+
+// newRequestID returns a random version 4 UUID.
+func newRequestID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "00000000-0000-4000-8000-000000000000"
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// requestIDEnabled reports whether the request ID header is sent. It is on unless
+// llm.request_id_header is false.
+func requestIDEnabled() bool {
+	if !viper.IsSet(RequestIDKey) {
+		return true
+	}
+	return viper.GetBool(RequestIDKey)
+}
+
+// withRequestID adds the request ID to err when the header is sent, so the failing request
+// can be found in the logs of the provider or proxy.
+func withRequestID(err error) error {
+	if err == nil || !requestIDEnabled() {
+		return err
+	}
+	return fmt.Errorf("%w (request id %s)", err, RequestID())
+}
@@ -0,0 +1,108 @@
+package llm
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+
+	"github.com/MagdielCAS/magi-cli/pkg/shared"
+)
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+func TestRequestID(t *testing.T) {
+	id := RequestID()
+	if !uuidPattern.MatchString(id) {
+		t.Fatalf("expected a version 4 UUID, got %q", id)
+	}
+	if RequestID() != id {
+		t.Fatal("expected the request ID to be stable within the process")
+	}
+	if newRequestID() == id {
+		t.Fatal("expected new request IDs to differ")
+	}
+}
+
+func TestServiceSendsRequestID(t *testing.T) {
+	t.Cleanup(viper.Reset)
+
+	var header string
+	rt := &shared.RuntimeContext{
+		Provider:   "openai",
+		APIKey:     "key",
+		HeavyModel: "gpt-4",
+		HTTPClient: &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			header = req.Header.Get(RequestIDHeader)
+			resp := &http.Response{
+				StatusCode: http.StatusBadRequest,
+				Body:       io.NopCloser(strings.NewReader(`{"error":{"message":"bad request"}}`)),
+				Header:     make(http.Header),
+			}
+			resp.Header.Set("Content-Type", "application/json")
+			return resp, nil
+		})},
+	}
+	request := ChatCompletionRequest{Messages: []ChatMessage{{Role: "user", Content: "hi"}}}
+
+	service, err := NewServiceBuilder(rt).Build()
+	if err != nil {
+		t.Fatalf("unexpected error building service: %v", err)
+	}
+	_, err = service.ChatCompletion(context.Background(), request)
+	if err == nil {
+		t.Fatal("expected the request to fail")
+	}
+	if header != RequestID() {
+		t.Fatalf("expected header %s, got %q", RequestID(), header)
+	}
+	if !strings.Contains(err.Error(), "request id "+RequestID()) {
+		t.Fatalf("expected the error to name the request ID, got %v", err)
+	}
+
+	viper.Set(RequestIDKey, false)
+	service, err = NewServiceBuilder(rt).Build()
+	if err != nil {
+		t.Fatalf("unexpected error building service: %v", err)
+	}
+	_, err = service.ChatCompletion(context.Background(), request)
+	if header != "" {
+		t.Fatalf("expected no request ID header when disabled, got %q", header)
+	}
+	if err == nil || strings.Contains(err.Error(), "request id") {
+		t.Fatalf("expected an error without the request ID, got %v", err)
+	}
+}
+
+func TestMCPClientSendsRequestID(t *testing.T) {
+	t.Cleanup(viper.Reset)
+
+	var header string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Get(RequestIDHeader)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	client := NewMCPClient(server.URL).WithRetry(0, 0)
+	_, err := client.sendRequest(MCPRequest{Method: "tools/list"})
+	if err == nil || !strings.Contains(err.Error(), "request id "+RequestID()) {
+		t.Fatalf("expected the error to name the request ID, got %v", err)
+	}
+	if header != RequestID() {
+		t.Fatalf("expected header %s, got %q", RequestID(), header)
+	}
+
+	viper.Set(RequestIDKey, false)
+	if _, err := client.sendRequest(MCPRequest{Method: "tools/list"}); err == nil {
+		t.Fatal("expected the request to fail")
+	}
+	if header != "" {
+		t.Fatalf("expected no request ID header when disabled, got %q", header)
+	}
+}
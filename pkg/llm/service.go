@@ -8,15 +8,35 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"net/url"
 	"strings"
+	"time"
 
 	openai "github.com/openai/openai-go/v3"
 	"github.com/openai/openai-go/v3/option"
 	openaiShared "github.com/openai/openai-go/v3/shared"
+	"github.com/spf13/viper"
 
 	"github.com/MagdielCAS/magi-cli/pkg/shared"
 )
 
+// MergeSystemMessagesKey controls whether consecutive system and developer messages are
+// sent as a single system message.
+const MergeSystemMessagesKey = "llm.merge_system_messages"
+
+// LenientRolesKey makes unknown chat message roles fall back to user instead of failing.
+const LenientRolesKey = "llm.lenient_roles"
+
+const (
+	// OpenRouterRefererKey and OpenRouterTitleKey override the attribution headers sent to
+	// OpenRouter. An empty value omits the header.
+	OpenRouterRefererKey = "llm.openrouter.referer"
+	OpenRouterTitleKey   = "llm.openrouter.title"
+
+	defaultOpenRouterReferer = "https://github.com/MagdielCAS/magi-cli"
+	defaultOpenRouterTitle   = "magi-cli"
+)
+
 // ModelVariant defines the logical model buckets (light/heavy/fallback) available to commands.
 type ModelVariant int
 
@@ -62,6 +82,12 @@ func (b *ServiceBuilder) UseFallbackModel() *ServiceBuilder {
 	return b
 }
 
+// UseVariant selects the model tier given as a ModelVariant.
+func (b *ServiceBuilder) UseVariant(variant ModelVariant) *ServiceBuilder {
+	b.variant = variant
+	return b
+}
+
 // WithModel overrides the model identifier entirely.
 func (b *ServiceBuilder) WithModel(model string) *ServiceBuilder {
 	b.customModel = strings.TrimSpace(model)
@@ -89,7 +115,7 @@ func (b *ServiceBuilder) WithHTTPClient(client *http.Client) *ServiceBuilder {
 // Build resolves the requested configuration and returns a ready-to-use LLM service.
 func (b *ServiceBuilder) Build() (*Service, error) {
 	if b.runtime == nil {
-		return nil, fmt.Errorf("runtime context is required")
+		return nil, shared.ConfigError(fmt.Errorf("runtime context is required"))
 	}
 
 	model, endpoint := b.resolveVariantConfig()
@@ -97,17 +123,20 @@ func (b *ServiceBuilder) Build() (*Service, error) {
 		model = b.customModel
 	}
 	if model == "" {
-		return nil, fmt.Errorf("model is not configured for the selected variant")
+		return nil, shared.ConfigError(fmt.Errorf("model is not configured for the selected variant"))
 	}
 
 	apiKey := firstNonEmpty(b.apiKeyOverride, endpoint.APIKey, b.runtime.APIKey)
 	if apiKey == "" {
-		return nil, fmt.Errorf("api key is not configured")
+		return nil, shared.ConfigError(fmt.Errorf("api key is not configured"))
 	}
 
-	baseURL := firstNonEmpty(b.baseURLOverride, endpoint.BaseURL, b.runtime.BaseURL, providerDefaultBaseURL(b.runtime.Provider))
+	baseURL := firstNonEmpty(b.baseURLOverride, endpoint.BaseURL, b.runtime.BaseURL, providerDefaultBaseURL(firstNonEmpty(endpoint.Provider, b.runtime.Provider)))
 	if baseURL == "" {
-		return nil, fmt.Errorf("base URL is not configured")
+		return nil, shared.ConfigError(fmt.Errorf("base URL is not configured"))
+	}
+	if err := shared.CheckHostAllowed(baseURL, b.runtime.AllowedHosts); err != nil {
+		return nil, shared.ConfigError(err)
 	}
 
 	httpClient := b.httpClient
@@ -119,24 +148,51 @@ func (b *ServiceBuilder) Build() (*Service, error) {
 	}
 
 	trimmedBaseURL := strings.TrimRight(baseURL, "/")
-	client := openai.NewClient(
+	provider := firstNonEmpty(endpoint.Provider, b.runtime.Provider)
+	jsonMode, err := normalizeJSONMode(endpoint.JSONMode)
+	if err != nil {
+		return nil, shared.ConfigError(err)
+	}
+	opts := []option.RequestOption{
 		option.WithAPIKey(apiKey),
 		option.WithBaseURL(trimmedBaseURL),
 		option.WithHTTPClient(httpClient),
-		option.WithHeaderAdd("HTTP-Referer", "https://github.com/MagdielCAS/magi-cli"),
-		option.WithHeaderAdd("X-Title", "Magi CLI"),
-		option.WithJSONSet("provider.zdr", true),
-	)
+	}
+	openRouter := isOpenRouter(provider, trimmedBaseURL)
+	if openRouter {
+		// Zero data retention routing is an OpenRouter body field; strict providers reject it.
+		opts = append(opts, option.WithJSONSet("provider.zdr", true))
+		opts = append(opts, openRouterHeaders()...)
+	}
+	if requestIDEnabled() {
+		opts = append(opts, option.WithHeader(RequestIDHeader, RequestID()))
+	}
+	client := openai.NewClient(opts...)
 
 	return &Service{
-		provider: b.runtime.Provider,
-		model:    model,
-		apiKey:   apiKey,
-		baseURL:  trimmedBaseURL,
-		client:   client,
+		provider:            b.runtime.Provider,
+		model:               model,
+		apiKey:              apiKey,
+		baseURL:             trimmedBaseURL,
+		client:              client,
+		mergeSystemMessages: shouldMergeSystemMessages(provider),
+		jsonMode:            jsonMode,
+		openRouter:          openRouter,
 	}, nil
 }
 
+// variantModelKey is the configuration key of the model of variant.
+func variantModelKey(variant ModelVariant) string {
+	switch variant {
+	case ModelVariantLight:
+		return "api.light_model"
+	case ModelVariantFallback:
+		return "api.fallback_model"
+	default:
+		return "api.heavy_model"
+	}
+}
+
 func (b *ServiceBuilder) resolveVariantConfig() (string, shared.ModelEndpoint) {
 	switch b.variant {
 	case ModelVariantLight:
@@ -155,6 +211,12 @@ type Service struct {
 	apiKey   string
 	baseURL  string
 	client   openai.Client
+	// mergeSystemMessages joins consecutive system/developer messages before sending.
+	mergeSystemMessages bool
+	// jsonMode is how requests with a JSON schema response format are sent.
+	jsonMode string
+	// openRouter enables the OpenRouter-only request fields, such as provider.zdr.
+	openRouter bool
 }
 
 // ChatMessage represents a message in a chat completion request.
@@ -174,15 +236,60 @@ type ChatCompletionRequest struct {
 	ResponseFormat   *openai.ChatCompletionNewParamsResponseFormatUnion
 }
 
+// ChatCompletionResult is a single chat completion response.
+type ChatCompletionResult struct {
+	// Content is the assistant message, which may be empty.
+	Content string
+	// FinishReason is why the model stopped, e.g. "stop" or "length" when MaxTokens was hit.
+	FinishReason string
+	// CompletionTokens is the number of tokens the provider reports for the answer.
+	CompletionTokens int64
+}
+
 // ChatCompletion sends a chat completion request and returns the assistant response text.
+// A structured-output request whose answer is cut off by the token limit is retried once
+// with a larger limit; if it still does not fit the error wraps ErrResponseTruncated.
 func (s *Service) ChatCompletion(ctx context.Context, req ChatCompletionRequest) (string, error) {
+	result, err := s.Complete(ctx, req)
+	if err != nil {
+		return "", err
+	}
+
+	if result.FinishReason == FinishReasonLength && req.ResponseFormat != nil {
+		limit := truncationRetryLimit(req.MaxTokens, result.CompletionTokens)
+		if limit == 0 {
+			return "", truncatedError(req.MaxTokens, false)
+		}
+		retry := req
+		retry.MaxTokens = limit
+		if result, err = s.Complete(ctx, retry); err != nil {
+			return "", err
+		}
+		if result.FinishReason == FinishReasonLength {
+			return "", truncatedError(limit, true)
+		}
+	}
+
+	if result.Content == "" {
+		return "", shared.ProviderError(withRequestID(fmt.Errorf("provider response did not contain a message")))
+	}
+	return result.Content, nil
+}
+
+// Complete sends a single chat completion request, without retrying truncated answers, and
+// returns the response with its finish reason.
+func (s *Service) Complete(ctx context.Context, req ChatCompletionRequest) (*ChatCompletionResult, error) {
 	if len(req.Messages) == 0 {
-		return "", fmt.Errorf("at least one message is required")
+		return nil, fmt.Errorf("at least one message is required")
 	}
 
-	messages, err := buildMessageParams(req.Messages)
+	chatMessages, responseFormat := applyJSONMode(s.jsonMode, req.Messages, req.ResponseFormat)
+	if s.mergeSystemMessages {
+		chatMessages = mergeConsecutiveSystemMessages(chatMessages)
+	}
+	messages, err := buildMessageParams(chatMessages, viper.GetBool(LenientRolesKey))
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	temperature := req.Temperature
@@ -195,11 +302,13 @@ func (s *Service) ChatCompletion(ctx context.Context, req ChatCompletionRequest)
 		Messages: messages,
 	}
 
-	params.SetExtraFields(map[string]any{
-		"provider": map[string]any{
-			"zdr": true,
-		},
-	})
+	if s.openRouter {
+		params.SetExtraFields(map[string]any{
+			"provider": map[string]any{
+				"zdr": true,
+			},
+		})
+	}
 
 	if req.MaxTokens > 0 {
 		params.MaxCompletionTokens = openai.Int(int64(req.MaxTokens))
@@ -215,26 +324,88 @@ func (s *Service) ChatCompletion(ctx context.Context, req ChatCompletionRequest)
 	if req.PresencePenalty != 0 {
 		params.PresencePenalty = openai.Float(req.PresencePenalty)
 	}
-	if req.ResponseFormat != nil {
-		params.ResponseFormat = *req.ResponseFormat
+	if responseFormat != nil {
+		params.ResponseFormat = *responseFormat
+	}
+
+	threshold := circuitBreakerThreshold()
+	if err := sharedCircuitBreaker.allow(threshold); err != nil {
+		return nil, shared.ProviderError(err)
 	}
 
+	release, err := acquireRequestSlot(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("waiting for the request rate limit: %w", err)
+	}
+	defer release()
+
+	started := time.Now()
 	resp, err := s.client.Chat.Completions.New(ctx, params)
+	sharedCircuitBreaker.record(err, threshold)
+	logAttrs := []any{"provider", s.provider, "model", s.model, "duration_ms", time.Since(started).Milliseconds()}
 	if err != nil {
-		return "", fmt.Errorf("chat completion request failed: %w", err)
+		err = shared.ProviderError(withRequestID(fmt.Errorf("chat completion request failed: %w", err)))
+		shared.Logger().Error("llm call failed", append(logAttrs, "error", err)...)
+		return nil, err
 	}
 
-	if len(resp.Choices) == 0 || resp.Choices[0].Message.Content == "" {
-		return "", fmt.Errorf("provider response did not contain a message")
+	if len(resp.Choices) == 0 {
+		return nil, shared.ProviderError(withRequestID(fmt.Errorf("provider response did not contain a message")))
+	}
+	shared.Logger().Info("llm call",
+		append(logAttrs,
+			"prompt_tokens", resp.Usage.PromptTokens,
+			"completion_tokens", resp.Usage.CompletionTokens,
+			"total_tokens", resp.Usage.TotalTokens,
+			"finish_reason", resp.Choices[0].FinishReason)...)
+
+	content := resp.Choices[0].Message.Content
+	if req.ResponseFormat != nil && s.jsonMode != JSONModeSchema {
+		content = stripJSONFence(content)
 	}
+	return &ChatCompletionResult{
+		Content:          content,
+		FinishReason:     resp.Choices[0].FinishReason,
+		CompletionTokens: resp.Usage.CompletionTokens,
+	}, nil
+}
+
+// validMessageRoles lists the roles accepted by buildMessageParams.
+var validMessageRoles = []string{"system", "developer", "user", "assistant"}
 
-	return resp.Choices[0].Message.Content, nil
+// messageRoleAliases maps common alternative role names to the chat roles.
+var messageRoleAliases = map[string]string{
+	"ai":    "assistant",
+	"bot":   "assistant",
+	"model": "assistant",
+	"human": "user",
+}
+
+// normalizeMessageRole lowercases role and resolves aliases. Unknown roles are an error
+// listing the valid roles, or are coerced to user when lenient is set.
+func normalizeMessageRole(role string, lenient bool) (string, error) {
+	normalized := strings.ToLower(strings.TrimSpace(role))
+	if alias, ok := messageRoleAliases[normalized]; ok {
+		return alias, nil
+	}
+	for _, valid := range validMessageRoles {
+		if normalized == valid {
+			return normalized, nil
+		}
+	}
+	if lenient {
+		return "user", nil
+	}
+	return "", fmt.Errorf("unsupported message role %q (valid roles: %s; set %s to treat unknown roles as user)", role, strings.Join(validMessageRoles, ", "), LenientRolesKey)
 }
 
-func buildMessageParams(messages []ChatMessage) ([]openai.ChatCompletionMessageParamUnion, error) {
+func buildMessageParams(messages []ChatMessage, lenient bool) ([]openai.ChatCompletionMessageParamUnion, error) {
 	results := make([]openai.ChatCompletionMessageParamUnion, 0, len(messages))
 	for _, msg := range messages {
-		role := strings.ToLower(strings.TrimSpace(msg.Role))
+		role, err := normalizeMessageRole(msg.Role, lenient)
+		if err != nil {
+			return nil, err
+		}
 		var param openai.ChatCompletionMessageParamUnion
 		switch role {
 		case "system":
@@ -247,21 +418,111 @@ func buildMessageParams(messages []ChatMessage) ([]openai.ChatCompletionMessageP
 			// Developer role is not supported by every OpenAI-compatible API.
 			// Treat it as a system instruction to remain compatible.
 			param = openai.SystemMessage(msg.Content)
-		default:
-			return nil, fmt.Errorf("unsupported message role %q", msg.Role)
 		}
 		results = append(results, param)
 	}
 	return results, nil
 }
 
+// shouldMergeSystemMessages applies llm.merge_system_messages, which defaults to true for
+// every provider except openai because some compatible endpoints reject more than one
+// system message.
+func shouldMergeSystemMessages(provider string) bool {
+	if viper.IsSet(MergeSystemMessagesKey) {
+		return viper.GetBool(MergeSystemMessagesKey)
+	}
+	return !strings.EqualFold(strings.TrimSpace(provider), "openai")
+}
+
+// mergeConsecutiveSystemMessages joins each run of system and developer messages into a
+// single system message, keeping their order and every other message untouched.
+func mergeConsecutiveSystemMessages(messages []ChatMessage) []ChatMessage {
+	merged := make([]ChatMessage, 0, len(messages))
+	for _, msg := range messages {
+		if !isSystemRole(msg.Role) {
+			merged = append(merged, msg)
+			continue
+		}
+		if last := len(merged) - 1; last >= 0 && isSystemRole(merged[last].Role) {
+			merged[last].Content += "\n\n" + msg.Content
+			continue
+		}
+		merged = append(merged, ChatMessage{Role: "system", Content: msg.Content})
+	}
+	return merged
+}
+
+func isSystemRole(role string) bool {
+	role = strings.ToLower(strings.TrimSpace(role))
+	return role == "system" || role == "developer"
+}
+
+// isOpenRouter reports whether requests go to OpenRouter, either because the provider is
+// openrouter or because the base URL points at openrouter.ai.
+func isOpenRouter(provider, baseURL string) bool {
+	if strings.EqualFold(strings.TrimSpace(provider), "openrouter") {
+		return true
+	}
+	parsed, err := url.Parse(baseURL)
+	if err != nil {
+		return false
+	}
+	host := strings.ToLower(parsed.Hostname())
+	return host == "openrouter.ai" || strings.HasSuffix(host, ".openrouter.ai")
+}
+
+// openRouterHeaders returns the HTTP-Referer and X-Title attribution headers OpenRouter
+// expects; some free-tier models reject requests without them.
+func openRouterHeaders() []option.RequestOption {
+	var opts []option.RequestOption
+	for _, header := range []struct{ name, key, fallback string }{
+		{"HTTP-Referer", OpenRouterRefererKey, defaultOpenRouterReferer},
+		{"X-Title", OpenRouterTitleKey, defaultOpenRouterTitle},
+	} {
+		value := header.fallback
+		if viper.IsSet(header.key) {
+			value = strings.TrimSpace(viper.GetString(header.key))
+		}
+		if value != "" {
+			opts = append(opts, option.WithHeader(header.name, value))
+		}
+	}
+	return opts
+}
+
+// knownProviders are the OpenAI-compatible providers with a default base URL, in the order
+// the setup wizard lists them. Any other provider, such as custom, needs api.base_url.
+var knownProviders = []struct {
+	name    string
+	baseURL string
+}{
+	{"openai", "https://api.openai.com/v1"},
+	{"openrouter", "https://openrouter.ai/api/v1"},
+	{"groq", "https://api.groq.com/openai/v1"},
+	{"together", "https://api.together.xyz/v1"},
+	{"deepseek", "https://api.deepseek.com/v1"},
+	{"mistral", "https://api.mistral.ai/v1"},
+	{"ollama", "http://localhost:11434/v1"},
+}
+
+// KnownProviders returns the provider slugs that work without a base URL.
+func KnownProviders() []string {
+	names := make([]string, 0, len(knownProviders))
+	for _, provider := range knownProviders {
+		names = append(names, provider.name)
+	}
+	return names
+}
+
+// providerDefaultBaseURL returns the base URL of a known provider, or "" for any other one.
 func providerDefaultBaseURL(provider string) string {
-	switch strings.ToLower(strings.TrimSpace(provider)) {
-	case "openai":
-		return "https://api.openai.com/v1"
-	default:
-		return ""
+	provider = strings.ToLower(strings.TrimSpace(provider))
+	for _, known := range knownProviders {
+		if known.name == provider {
+			return known.baseURL
+		}
 	}
+	return ""
 }
 
 func firstNonEmpty(values ...string) string {
@@ -2,11 +2,17 @@ package llm
 
 import (
 	"context"
+	"encoding/json"
 	"io"
 	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 
+	"github.com/spf13/viper"
+
 	"github.com/MagdielCAS/magi-cli/pkg/shared"
 )
 
@@ -45,6 +51,65 @@ func TestServiceBuilderUsesVariantConfig(t *testing.T) {
 	}
 }
 
+func TestServiceBuilderUsesProviderDefaultBaseURL(t *testing.T) {
+	rt := &shared.RuntimeContext{
+		Provider:   "OpenRouter",
+		APIKey:     "key",
+		HeavyModel: "model",
+		LightModel: "model",
+		LightEndpoint: shared.ModelEndpoint{
+			Provider: "groq",
+		},
+		HTTPClient: shared.DefaultHTTPClient(),
+	}
+
+	service, err := NewServiceBuilder(rt).UseHeavyModel().Build()
+	if err != nil {
+		t.Fatalf("unexpected error building service: %v", err)
+	}
+	if service.baseURL != "https://openrouter.ai/api/v1" {
+		t.Fatalf("expected the openrouter base URL, got %s", service.baseURL)
+	}
+
+	service, err = NewServiceBuilder(rt).UseLightModel().Build()
+	if err != nil {
+		t.Fatalf("unexpected error building service: %v", err)
+	}
+	if service.baseURL != "https://api.groq.com/openai/v1" {
+		t.Fatalf("expected the tier provider base URL, got %s", service.baseURL)
+	}
+
+	rt.Provider = "custom"
+	if _, err := NewServiceBuilder(rt).UseHeavyModel().Build(); err == nil {
+		t.Fatal("expected the custom provider to require a base URL")
+	}
+
+	for _, provider := range KnownProviders() {
+		if providerDefaultBaseURL(provider) == "" {
+			t.Fatalf("expected a base URL for %s", provider)
+		}
+	}
+}
+
+func TestServiceBuilderRejectsDisallowedHost(t *testing.T) {
+	rt := &shared.RuntimeContext{
+		Provider:     "openai",
+		APIKey:       "key",
+		BaseURL:      "https://unapproved.example.com/v1",
+		HeavyModel:   "gpt-4",
+		AllowedHosts: []string{"api.openai.com"},
+	}
+
+	if _, err := NewServiceBuilder(rt).Build(); err == nil || !strings.Contains(err.Error(), "security.allowed_hosts") {
+		t.Fatalf("expected allowed hosts error, got %v", err)
+	}
+
+	rt.BaseURL = "https://api.openai.com/v1"
+	if _, err := NewServiceBuilder(rt).Build(); err != nil {
+		t.Fatalf("expected allowed host to build, got %v", err)
+	}
+}
+
 func TestServiceChatCompletion(t *testing.T) {
 	var path string
 	testClient := &http.Client{
@@ -93,6 +158,185 @@ func TestServiceChatCompletion(t *testing.T) {
 	}
 }
 
+func TestServiceLogsChatCompletion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "magi.log")
+	shared.SetLogFormat(shared.LogFormatJSON)
+	shared.SetLogFile(path)
+	t.Cleanup(func() {
+		shared.SetLogFormat("")
+		shared.SetLogFile("")
+		_, _ = shared.StartLogging()
+	})
+	closeLog, err := shared.StartLogging()
+	if err != nil {
+		t.Fatalf("StartLogging failed: %v", err)
+	}
+
+	rt := &shared.RuntimeContext{
+		Provider:   "openai",
+		APIKey:     "key",
+		HeavyModel: "gpt-4",
+		HTTPClient: &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			resp := &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(successfulChatCompletionResponse)),
+				Header:     make(http.Header),
+			}
+			resp.Header.Set("Content-Type", "application/json")
+			return resp, nil
+		})},
+	}
+	service, err := NewServiceBuilder(rt).Build()
+	if err != nil {
+		t.Fatalf("unexpected error building service: %v", err)
+	}
+	if _, err := service.ChatCompletion(context.Background(), ChatCompletionRequest{
+		Messages: []ChatMessage{{Role: "user", Content: "hi"}},
+	}); err != nil {
+		t.Fatalf("completion failed: %v", err)
+	}
+	if err := closeLog(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var record map[string]any
+	if err := json.Unmarshal(data, &record); err != nil {
+		t.Fatalf("expected one JSON record, got %q: %v", data, err)
+	}
+	if record["msg"] != "llm call" || record["model"] != "gpt-4" || record["prompt_tokens"] != float64(1) || record["completion_tokens"] != float64(1) || record["finish_reason"] != "stop" {
+		t.Fatalf("unexpected llm call record: %v", record)
+	}
+}
+
+func TestMergeConsecutiveSystemMessages(t *testing.T) {
+	got := mergeConsecutiveSystemMessages([]ChatMessage{
+		{Role: "system", Content: "rules"},
+		{Role: "developer", Content: "format"},
+		{Role: "user", Content: "hi"},
+		{Role: "assistant", Content: "hello"},
+		{Role: "developer", Content: "retry"},
+	})
+	want := []ChatMessage{
+		{Role: "system", Content: "rules\n\nformat"},
+		{Role: "user", Content: "hi"},
+		{Role: "assistant", Content: "hello"},
+		{Role: "system", Content: "retry"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("mergeConsecutiveSystemMessages() = %+v, want %+v", got, want)
+	}
+}
+
+func TestNormalizeMessageRole(t *testing.T) {
+	tests := []struct {
+		role    string
+		lenient bool
+		want    string
+		wantErr bool
+	}{
+		{role: "User", want: "user"},
+		{role: " developer ", want: "developer"},
+		{role: "AI", want: "assistant"},
+		{role: "bot", want: "assistant"},
+		{role: "model", want: "assistant"},
+		{role: "human", want: "user"},
+		{role: "tool", wantErr: true},
+		{role: "tool", lenient: true, want: "user"},
+	}
+
+	for _, tt := range tests {
+		got, err := normalizeMessageRole(tt.role, tt.lenient)
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("normalizeMessageRole(%q, %v) error = %v, wantErr %v", tt.role, tt.lenient, err, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Fatalf("normalizeMessageRole(%q, %v) = %q, want %q", tt.role, tt.lenient, got, tt.want)
+		}
+	}
+
+	if _, err := normalizeMessageRole("tool", false); err == nil || !strings.Contains(err.Error(), "system, developer, user, assistant") {
+		t.Fatalf("expected error listing valid roles, got %v", err)
+	}
+}
+
+func TestServiceMergeSystemMessagesPolicy(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider string
+		setting  any
+		wantMsgs int
+	}{
+		{"merged by default for other providers", "openrouter", nil, 2},
+		{"kept separate by default for openai", "openai", nil, 3},
+		{"config forces merge for openai", "openai", true, 2},
+		{"config disables merge", "openrouter", false, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Reset()
+			t.Cleanup(viper.Reset)
+			if tt.setting != nil {
+				viper.Set(MergeSystemMessagesKey, tt.setting)
+			}
+
+			var sent struct {
+				Messages []struct {
+					Role    string `json:"role"`
+					Content string `json:"content"`
+				} `json:"messages"`
+			}
+			client := &http.Client{
+				Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+					if err := json.NewDecoder(req.Body).Decode(&sent); err != nil {
+						t.Fatalf("failed to decode request: %v", err)
+					}
+					resp := &http.Response{
+						StatusCode: http.StatusOK,
+						Body:       io.NopCloser(strings.NewReader(successfulChatCompletionResponse)),
+						Header:     make(http.Header),
+					}
+					resp.Header.Set("Content-Type", "application/json")
+					return resp, nil
+				}),
+			}
+			rt := &shared.RuntimeContext{
+				Provider:   tt.provider,
+				APIKey:     "key",
+				BaseURL:    "https://example.com",
+				HeavyModel: "model",
+				HTTPClient: client,
+			}
+
+			service, err := NewServiceBuilder(rt).Build()
+			if err != nil {
+				t.Fatalf("unexpected error building service: %v", err)
+			}
+			_, err = service.ChatCompletion(context.Background(), ChatCompletionRequest{
+				Messages: []ChatMessage{
+					{Role: "system", Content: "rules"},
+					{Role: "developer", Content: "format"},
+					{Role: "user", Content: "hi"},
+				},
+			})
+			if err != nil {
+				t.Fatalf("completion failed: %v", err)
+			}
+
+			if len(sent.Messages) != tt.wantMsgs {
+				t.Fatalf("expected %d messages, got %+v", tt.wantMsgs, sent.Messages)
+			}
+			if tt.wantMsgs == 2 && (sent.Messages[0].Role != "system" || sent.Messages[0].Content != "rules\n\nformat" || sent.Messages[1].Role != "user") {
+				t.Fatalf("unexpected merged messages %+v", sent.Messages)
+			}
+		})
+	}
+}
+
 const successfulChatCompletionResponse = `{
   "id": "chatcmpl-test",
   "object": "chat.completion",
@@ -144,3 +388,74 @@ type roundTripFunc func(*http.Request) (*http.Response, error)
 func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
 	return f(req)
 }
+
+func TestServiceSendsOpenRouterHeadersOnlyToOpenRouter(t *testing.T) {
+	t.Cleanup(viper.Reset)
+
+	var headers http.Header
+	var body map[string]any
+	client := &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		headers = req.Header.Clone()
+		body = nil
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			t.Errorf("failed to decode request body: %v", err)
+		}
+		resp := &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(successfulChatCompletionResponse)),
+			Header:     make(http.Header),
+		}
+		resp.Header.Set("Content-Type", "application/json")
+		return resp, nil
+	})}
+	send := func(provider, baseURL string) {
+		t.Helper()
+		rt := &shared.RuntimeContext{Provider: provider, BaseURL: baseURL, APIKey: "key", HeavyModel: "model", HTTPClient: client}
+		service, err := NewServiceBuilder(rt).Build()
+		if err != nil {
+			t.Fatalf("unexpected error building service: %v", err)
+		}
+		if _, err := service.ChatCompletion(context.Background(), ChatCompletionRequest{
+			Messages: []ChatMessage{{Role: "user", Content: "hi"}},
+		}); err != nil {
+			t.Fatalf("completion failed: %v", err)
+		}
+	}
+
+	send("openrouter", "")
+	if got := headers.Get("HTTP-Referer"); got != "https://github.com/MagdielCAS/magi-cli" {
+		t.Fatalf("unexpected HTTP-Referer %q", got)
+	}
+	if got := headers.Get("X-Title"); got != "magi-cli" {
+		t.Fatalf("unexpected X-Title %q", got)
+	}
+	if provider, ok := body["provider"].(map[string]any); !ok || provider["zdr"] != true {
+		t.Fatalf("expected provider.zdr in the OpenRouter request body, got %v", body["provider"])
+	}
+
+	// A custom provider pointing at OpenRouter gets them too.
+	send("custom", "https://openrouter.ai/api/v1")
+	if headers.Get("X-Title") != "magi-cli" {
+		t.Fatalf("expected the OpenRouter headers for an openrouter.ai base URL, got %v", headers)
+	}
+
+	for _, provider := range []string{"openai", "groq", "mistral"} {
+		send(provider, "")
+		if headers.Get("HTTP-Referer") != "" || headers.Get("X-Title") != "" {
+			t.Fatalf("expected no OpenRouter headers for %s, got %v", provider, headers)
+		}
+		if _, ok := body["provider"]; ok {
+			t.Fatalf("expected no provider field in the %s request body, got %v", provider, body["provider"])
+		}
+	}
+
+	viper.Set(OpenRouterRefererKey, "https://example.com/app")
+	viper.Set(OpenRouterTitleKey, "")
+	send("openrouter", "")
+	if got := headers.Get("HTTP-Referer"); got != "https://example.com/app" {
+		t.Fatalf("expected the configured HTTP-Referer, got %q", got)
+	}
+	if _, ok := headers["X-Title"]; ok {
+		t.Fatalf("expected an empty title to omit X-Title, got %v", headers)
+	}
+}
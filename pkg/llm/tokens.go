@@ -0,0 +1,84 @@
+/**
+ * Copyright © 2025 Magdiel Campelo <github.com/MagdielCAS/magi-cli>
+ * This file is part of the magi-cli
+**/
+package llm
+
+import (
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/tiktoken-go/tokenizer"
+)
+
+// charsPerToken is the rough ratio used when no tokenizer is known for a model.
+const charsPerToken = 4
+
+var (
+	codecs   = map[tokenizer.Encoding]tokenizer.Codec{}
+	codecsMu sync.Mutex
+)
+
+// EstimateTokens returns an approximate token count for text as seen by model.
+// OpenAI model families use their tiktoken encoding (o200k_base or cl100k_base);
+// any other model falls back to a characters/4 heuristic.
+func EstimateTokens(model, text string) int {
+	if text == "" {
+		return 0
+	}
+
+	if encoding, ok := encodingForModel(model); ok {
+		if codec := loadCodec(encoding); codec != nil {
+			if count, err := codec.Count(text); err == nil {
+				return count
+			}
+		}
+	}
+
+	return (utf8.RuneCountInString(text) + charsPerToken - 1) / charsPerToken
+}
+
+// encodingForModel maps a model identifier (optionally prefixed with a provider, as
+// used by OpenRouter, e.g. "openai/gpt-4o") to its tokenizer encoding.
+func encodingForModel(model string) (tokenizer.Encoding, bool) {
+	name := strings.ToLower(strings.TrimSpace(model))
+	if idx := strings.LastIndex(name, "/"); idx != -1 {
+		name = name[idx+1:]
+	}
+
+	switch {
+	case strings.HasPrefix(name, "gpt-4o"),
+		strings.HasPrefix(name, "gpt-4.1"),
+		strings.HasPrefix(name, "gpt-4.5"),
+		strings.HasPrefix(name, "gpt-5"),
+		strings.HasPrefix(name, "chatgpt-4o"),
+		strings.HasPrefix(name, "o1"),
+		strings.HasPrefix(name, "o3"),
+		strings.HasPrefix(name, "o4"):
+		return tokenizer.O200kBase, true
+	case strings.HasPrefix(name, "gpt-4"),
+		strings.HasPrefix(name, "gpt-3.5"),
+		strings.HasPrefix(name, "gpt-35"),
+		strings.HasPrefix(name, "text-embedding-3"),
+		strings.HasPrefix(name, "text-embedding-ada-002"):
+		return tokenizer.Cl100kBase, true
+	default:
+		return "", false
+	}
+}
+
+func loadCodec(encoding tokenizer.Encoding) tokenizer.Codec {
+	codecsMu.Lock()
+	defer codecsMu.Unlock()
+
+	if codec, ok := codecs[encoding]; ok {
+		return codec
+	}
+	codec, err := tokenizer.Get(encoding)
+	if err != nil {
+		return nil
+	}
+	codecs[encoding] = codec
+	return codec
+}
@@ -0,0 +1,53 @@
+package llm
+
+import (
+	"testing"
+
+	"github.com/tiktoken-go/tokenizer"
+)
+
+func TestEstimateTokens(t *testing.T) {
+	tests := []struct {
+		name  string
+		model string
+		text  string
+		want  int
+	}{
+		{"empty", "gpt-4", "", 0},
+		{"cl100k", "gpt-4", "hello world", 2},
+		{"o200k", "gpt-4o-mini", "hello world", 2},
+		{"provider prefix", "openai/gpt-4o", "hello world", 2},
+		{"heuristic exact", "claude-3-5-sonnet", "abcdefgh", 2},
+		{"heuristic rounds up", "llama3", "abcdefghi", 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EstimateTokens(tt.model, tt.text); got != tt.want {
+				t.Fatalf("EstimateTokens(%q, %q) = %d, want %d", tt.model, tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEncodingForModel(t *testing.T) {
+	tests := []struct {
+		model string
+		want  tokenizer.Encoding
+		ok    bool
+	}{
+		{"gpt-3.5-turbo", tokenizer.Cl100kBase, true},
+		{"gpt-4-turbo", tokenizer.Cl100kBase, true},
+		{"gpt-4.1", tokenizer.O200kBase, true},
+		{"o3-mini", tokenizer.O200kBase, true},
+		{"GPT-5", tokenizer.O200kBase, true},
+		{"mistral-large", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := encodingForModel(tt.model)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("encodingForModel(%q) = %q, %v; want %q, %v", tt.model, got, ok, tt.want, tt.ok)
+		}
+	}
+}
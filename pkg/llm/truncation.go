@@ -0,0 +1,36 @@
+package llm
+
+import (
+	"errors"
+	"fmt"
+)
+
+// FinishReasonLength is the finish reason of an answer cut off by the token limit.
+const FinishReasonLength = "length"
+
+// ErrResponseTruncated is wrapped by the ChatCompletion error when a structured-output
+// answer is still cut off by the token limit after the retry.
+var ErrResponseTruncated = errors.New("the response was cut off by the max token limit")
+
+// truncationRetryLimit returns the MaxTokens for retrying a truncated answer: twice the
+// requested limit, or twice the tokens the provider produced when no limit was requested.
+// It returns 0 when neither is known.
+func truncationRetryLimit(maxTokens float64, completionTokens int64) float64 {
+	if maxTokens > 0 {
+		return maxTokens * 2
+	}
+	return float64(completionTokens * 2)
+}
+
+// truncatedError explains a truncated structured-output answer, pointing at the two ways
+// out: a larger token limit or a smaller input.
+func truncatedError(limit float64, retried bool) error {
+	attempt := ""
+	if retried {
+		attempt = " even after retrying with a larger limit"
+	}
+	if limit > 0 {
+		return withRequestID(fmt.Errorf("%w (%d tokens)%s; raise the max tokens setting of the command or split the input into smaller chunks", ErrResponseTruncated, int64(limit), attempt))
+	}
+	return withRequestID(fmt.Errorf("%w%s; raise the max tokens setting of the command or split the input into smaller chunks", ErrResponseTruncated, attempt))
+}
@@ -0,0 +1,94 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/MagdielCAS/magi-cli/pkg/shared"
+)
+
+func TestTruncationRetryLimit(t *testing.T) {
+	if got := truncationRetryLimit(1000, 1000); got != 2000 {
+		t.Fatalf("expected the requested limit to double, got %v", got)
+	}
+	if got := truncationRetryLimit(0, 4096); got != 8192 {
+		t.Fatalf("expected twice the produced tokens without a requested limit, got %v", got)
+	}
+	if got := truncationRetryLimit(0, 0); got != 0 {
+		t.Fatalf("expected no retry limit when nothing is known, got %v", got)
+	}
+}
+
+// truncatingService returns a service whose provider answers with the given finish reasons
+// in turn and records the max_tokens of each request.
+func truncatingService(t *testing.T, finishReasons ...string) (*Service, *[]float64) {
+	t.Helper()
+	var limits []float64
+	rt := &shared.RuntimeContext{
+		Provider:   "openai",
+		APIKey:     "key",
+		HeavyModel: "gpt-4",
+		HTTPClient: &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			var body map[string]any
+			if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+				t.Fatalf("decode request: %v", err)
+			}
+			limit, _ := body["max_tokens"].(float64)
+			limits = append(limits, limit)
+			reason := finishReasons[min(len(limits), len(finishReasons))-1]
+			response := strings.Replace(successfulChatCompletionResponse, `"finish_reason": "stop"`, `"finish_reason": "`+reason+`"`, 1)
+			resp := &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(response)),
+				Header:     make(http.Header),
+			}
+			resp.Header.Set("Content-Type", "application/json")
+			return resp, nil
+		})},
+	}
+	service, err := NewServiceBuilder(rt).Build()
+	if err != nil {
+		t.Fatalf("unexpected error building service: %v", err)
+	}
+	return service, &limits
+}
+
+func TestChatCompletionRetriesTruncatedJSON(t *testing.T) {
+	request := ChatCompletionRequest{
+		Messages:       []ChatMessage{{Role: "user", Content: "diff"}},
+		MaxTokens:      500,
+		ResponseFormat: CommitSchema,
+	}
+
+	service, limits := truncatingService(t, FinishReasonLength, "stop")
+	resp, err := service.ChatCompletion(context.Background(), request)
+	if err != nil || resp != "ok" {
+		t.Fatalf("expected the retry to succeed, got %q, %v", resp, err)
+	}
+	if len(*limits) != 2 || (*limits)[0] != 500 || (*limits)[1] != 1000 {
+		t.Fatalf("expected a retry with twice the limit, got %v", *limits)
+	}
+
+	service, limits = truncatingService(t, FinishReasonLength)
+	_, err = service.ChatCompletion(context.Background(), request)
+	if !errors.Is(err, ErrResponseTruncated) || !strings.Contains(err.Error(), "1000 tokens") || !strings.Contains(err.Error(), "smaller chunks") {
+		t.Fatalf("expected a truncation error naming the limit, got %v", err)
+	}
+	if len(*limits) != 2 {
+		t.Fatalf("expected a single retry, got %d requests", len(*limits))
+	}
+
+	service, limits = truncatingService(t, FinishReasonLength)
+	request.ResponseFormat = nil
+	if resp, err := service.ChatCompletion(context.Background(), request); err != nil || resp != "ok" {
+		t.Fatalf("expected plain text answers to be returned as is, got %q, %v", resp, err)
+	}
+	if len(*limits) != 1 {
+		t.Fatalf("expected no retry for plain text requests, got %d requests", len(*limits))
+	}
+}
@@ -0,0 +1,20 @@
+package shared
+
+import "context"
+
+var baseContext context.Context
+
+// SetBaseContext sets the context that long-running work (LLM requests, external commands)
+// derives from. The root command installs one that is cancelled when the user presses Ctrl-C.
+func SetBaseContext(ctx context.Context) {
+	baseContext = ctx
+}
+
+// BaseContext returns the context set through SetBaseContext, falling back to
+// context.Background when none was set (for example in tests).
+func BaseContext() context.Context {
+	if baseContext == nil {
+		return context.Background()
+	}
+	return baseContext
+}
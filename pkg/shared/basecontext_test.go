@@ -0,0 +1,22 @@
+package shared
+
+import (
+	"context"
+	"testing"
+)
+
+func TestBaseContext(t *testing.T) {
+	t.Cleanup(func() { SetBaseContext(nil) })
+
+	if BaseContext() == nil {
+		t.Fatal("expected a background context when none is set")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	SetBaseContext(ctx)
+	cancel()
+
+	if BaseContext().Err() == nil {
+		t.Fatal("expected the installed context to be returned")
+	}
+}
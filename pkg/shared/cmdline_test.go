@@ -0,0 +1,36 @@
+package shared
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitCommandLine(t *testing.T) {
+	tests := []struct {
+		line string
+		want []string
+	}{
+		{"go test ./...", []string{"go", "test", "./..."}},
+		{`  agent  --prompt "a b"  `, []string{"agent", "--prompt", "a b"}},
+		{`sh -c 'rm -rf "$HOME"'`, []string{"sh", "-c", `rm -rf "$HOME"`}},
+		{`echo "say \"hi\"" it\'s`, []string{"echo", `say "hi"`, "it's"}},
+		{`find . -exec rm {} \;`, []string{"find", ".", "-exec", "rm", "{}", ";"}},
+		{`--flag="" x`, []string{"--flag=", "x"}},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		got, err := SplitCommandLine(tt.line)
+		if err != nil {
+			t.Fatalf("SplitCommandLine(%q) returned error: %v", tt.line, err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Fatalf("SplitCommandLine(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+
+	for _, line := range []string{`echo "open`, `echo 'open`} {
+		if _, err := SplitCommandLine(line); err == nil {
+			t.Fatalf("expected an error for %q", line)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package shared
+
+import (
+	"strings"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/viper"
+)
+
+// ConfirmDefaultKey sets the preselected answer of every yes/no confirmation ("yes" or "no").
+// When empty, each prompt keeps its own default.
+const ConfirmDefaultKey = "ui.confirm_default"
+
+var confirmDefaultOverride *bool
+
+// SetConfirmDefault overrides ui.confirm_default for the current process. The root command
+// calls it when --assume-yes or --assume-no is passed.
+func SetConfirmDefault(answer bool) {
+	confirmDefaultOverride = &answer
+}
+
+// ConfirmDefault returns the answer preselected in confirmation prompts: the --assume-yes or
+// --assume-no override, then ui.confirm_default, then fallback.
+func ConfirmDefault(fallback bool) bool {
+	if confirmDefaultOverride != nil {
+		return *confirmDefaultOverride
+	}
+	switch strings.ToLower(strings.TrimSpace(viper.GetString(ConfirmDefaultKey))) {
+	case "yes", "y", "true":
+		return true
+	case "no", "n", "false":
+		return false
+	}
+	return fallback
+}
+
+// Confirm asks a yes/no question. defaultYes is the prompt's own default, which
+// ConfirmDefault may override. Commands should use it instead of
+// pterm.DefaultInteractiveConfirm so the configured default applies everywhere.
+// When the session is not interactive the default is returned without prompting.
+func Confirm(prompt string, defaultYes bool) (bool, error) {
+	answer := ConfirmDefault(defaultYes)
+	if !IsInteractive() {
+		label := "no"
+		if answer {
+			label = "yes"
+		}
+		pterm.Warning.Printf("Non-interactive session, answering %q to: %s\n", label, prompt)
+		return answer, nil
+	}
+
+	return pterm.DefaultInteractiveConfirm.
+		WithDefaultValue(answer).
+		Show(prompt)
+}
@@ -0,0 +1,31 @@
+package shared
+
+import (
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestConfirmDefault(t *testing.T) {
+	t.Cleanup(viper.Reset)
+	t.Cleanup(func() { confirmDefaultOverride = nil })
+
+	if !ConfirmDefault(true) || ConfirmDefault(false) {
+		t.Fatal("expected the prompt's own default when nothing is configured")
+	}
+
+	viper.Set(ConfirmDefaultKey, "no")
+	if ConfirmDefault(true) {
+		t.Fatal("expected ui.confirm_default=no to override a yes default")
+	}
+
+	viper.Set(ConfirmDefaultKey, "Yes")
+	if !ConfirmDefault(false) {
+		t.Fatal("expected ui.confirm_default=yes to override a no default")
+	}
+
+	SetConfirmDefault(false)
+	if ConfirmDefault(true) {
+		t.Fatal("expected --assume-no to win over ui.confirm_default")
+	}
+}
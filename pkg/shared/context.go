@@ -26,6 +26,7 @@ type RuntimeContext struct {
 	HTTPClient       *http.Client
 	AnalysisTimeout  time.Duration
 	WriterTimeout    time.Duration
+	AllowedHosts     []string
 }
 
 // ModelEndpoint describes the credentials and endpoint overrides for a specific model class.
@@ -33,6 +34,8 @@ type ModelEndpoint struct {
 	APIKey   string
 	BaseURL  string
 	Provider string
+	// JSONMode is how structured responses are requested: schema, json_object or none.
+	JSONMode string
 }
 
 var (
@@ -60,7 +63,7 @@ func DefaultHTTPClient() *http.Client {
 func BuildRuntimeContext() (*RuntimeContext, error) {
 	apiKey := strings.TrimSpace(viper.GetString("api.key"))
 	if apiKey == "" {
-		return nil, fmt.Errorf("missing api.key in configuration")
+		return nil, ConfigError(fmt.Errorf("missing api.key in configuration"))
 	}
 
 	provider := strings.TrimSpace(viper.GetString("api.provider"))
@@ -69,6 +72,7 @@ func BuildRuntimeContext() (*RuntimeContext, error) {
 	}
 
 	globalBaseURL := strings.TrimSpace(viper.GetString("api.base_url"))
+	globalJSONMode := strings.TrimSpace(viper.GetString("api.json_mode"))
 
 	ctx := &RuntimeContext{
 		Provider:   provider,
@@ -81,20 +85,24 @@ func BuildRuntimeContext() (*RuntimeContext, error) {
 			APIKey:   fallbackString(strings.TrimSpace(viper.GetString("api.light.api_key")), apiKey),
 			BaseURL:  fallbackString(strings.TrimSpace(viper.GetString("api.light.base_url")), globalBaseURL),
 			Provider: fallbackString(strings.TrimSpace(viper.GetString("api.light.provider")), provider),
+			JSONMode: fallbackString(strings.TrimSpace(viper.GetString("api.light.json_mode")), globalJSONMode),
 		},
 		HeavyEndpoint: ModelEndpoint{
 			APIKey:   fallbackString(strings.TrimSpace(viper.GetString("api.heavy.api_key")), apiKey),
 			BaseURL:  fallbackString(strings.TrimSpace(viper.GetString("api.heavy.base_url")), globalBaseURL),
 			Provider: fallbackString(strings.TrimSpace(viper.GetString("api.heavy.provider")), provider),
+			JSONMode: fallbackString(strings.TrimSpace(viper.GetString("api.heavy.json_mode")), globalJSONMode),
 		},
 		FallbackEndpoint: ModelEndpoint{
 			APIKey:   fallbackString(strings.TrimSpace(viper.GetString("api.fallback.api_key")), apiKey),
 			BaseURL:  fallbackString(strings.TrimSpace(viper.GetString("api.fallback.base_url")), globalBaseURL),
 			Provider: fallbackString(strings.TrimSpace(viper.GetString("api.fallback.provider")), provider),
+			JSONMode: fallbackString(strings.TrimSpace(viper.GetString("api.fallback.json_mode")), globalJSONMode),
 		},
 		HTTPClient:      DefaultHTTPClient(),
 		AnalysisTimeout: getDurationOrDefault("agent.analysis.timeout", 5*time.Minute),
 		WriterTimeout:   getDurationOrDefault("agent.writer.timeout", 5*time.Minute),
+		AllowedHosts:    AllowedHosts(),
 	}
 
 	return ctx, nil
@@ -105,16 +113,16 @@ func BuildRuntimeContext() (*RuntimeContext, error) {
 func (rc *RuntimeContext) RedactedCopy() RuntimeContext {
 	clone := *rc
 	if clone.APIKey != "" {
-		clone.APIKey = "***REDACTED***"
+		clone.APIKey = RedactedValue
 	}
 	if clone.LightEndpoint.APIKey != "" {
-		clone.LightEndpoint.APIKey = "***REDACTED***"
+		clone.LightEndpoint.APIKey = RedactedValue
 	}
 	if clone.HeavyEndpoint.APIKey != "" {
-		clone.HeavyEndpoint.APIKey = "***REDACTED***"
+		clone.HeavyEndpoint.APIKey = RedactedValue
 	}
 	if clone.FallbackEndpoint.APIKey != "" {
-		clone.FallbackEndpoint.APIKey = "***REDACTED***"
+		clone.FallbackEndpoint.APIKey = RedactedValue
 	}
 
 	return clone
@@ -0,0 +1,63 @@
+package shared
+
+import "errors"
+
+// Process exit codes returned by magi. Scripts and CI jobs can rely on these values.
+const (
+	// ExitCodeOK means the command completed successfully.
+	ExitCodeOK = 0
+	// ExitCodeError is used for every failure without a more specific code.
+	ExitCodeError = 1
+	// ExitCodeConfig means the configuration is missing or invalid (for example no api.key).
+	ExitCodeConfig = 2
+	// ExitCodeProvider means the LLM provider or the network could not serve a request.
+	ExitCodeProvider = 3
+	// ExitCodeTimeout means the command ran longer than --timeout.
+	ExitCodeTimeout = 124
+	// ExitCodeInterrupted means the user interrupted the command with Ctrl-C.
+	ExitCodeInterrupted = 130
+)
+
+// exitCodeError attaches an exit code to an error while keeping it unwrappable.
+type exitCodeError struct {
+	code int
+	err  error
+}
+
+func (e *exitCodeError) Error() string {
+	return e.err.Error()
+}
+
+func (e *exitCodeError) Unwrap() error {
+	return e.err
+}
+
+// ConfigError marks err as a configuration problem so the process exits with ExitCodeConfig.
+func ConfigError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &exitCodeError{code: ExitCodeConfig, err: err}
+}
+
+// ProviderError marks err as a provider or network failure so the process exits with
+// ExitCodeProvider.
+func ProviderError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &exitCodeError{code: ExitCodeProvider, err: err}
+}
+
+// ExitCode returns the process exit code for err. The outermost marked error wins, and
+// unmarked errors map to ExitCodeError.
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitCodeOK
+	}
+	var coded *exitCodeError
+	if errors.As(err, &coded) {
+		return coded.code
+	}
+	return ExitCodeError
+}
@@ -0,0 +1,47 @@
+package shared
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestExitCode(t *testing.T) {
+	base := errors.New("boom")
+
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"nil", nil, ExitCodeOK},
+		{"plain", base, ExitCodeError},
+		{"config", ConfigError(base), ExitCodeConfig},
+		{"provider", ProviderError(base), ExitCodeProvider},
+		{"wrapped config", fmt.Errorf("failed to build runtime context: %w", ConfigError(base)), ExitCodeConfig},
+		{"outermost wins", ProviderError(fmt.Errorf("retry: %w", ConfigError(base))), ExitCodeProvider},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExitCode(tt.err); got != tt.want {
+				t.Fatalf("ExitCode(%v) = %d, want %d", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarkedErrorsKeepMessageAndCause(t *testing.T) {
+	base := errors.New("missing api.key in configuration")
+	err := ConfigError(base)
+
+	if err.Error() != base.Error() {
+		t.Fatalf("expected message %q, got %q", base.Error(), err.Error())
+	}
+	if !errors.Is(err, base) {
+		t.Fatal("expected marked error to unwrap to its cause")
+	}
+	if ConfigError(nil) != nil || ProviderError(nil) != nil {
+		t.Fatal("expected nil errors to stay nil")
+	}
+}
@@ -0,0 +1,52 @@
+package shared
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// AllowedHosts returns the host globs configured in security.allowed_hosts.
+// An empty list means every host is allowed.
+func AllowedHosts() []string {
+	var hosts []string
+	for _, host := range viper.GetStringSlice("security.allowed_hosts") {
+		if host = strings.TrimSpace(host); host != "" {
+			hosts = append(hosts, host)
+		}
+	}
+	return hosts
+}
+
+// CheckHostAllowed returns an error when the host of rawURL does not match any of the
+// allowed host globs (e.g. "api.openai.com" or "*.example.com"). An empty allow list
+// permits every host.
+func CheckHostAllowed(rawURL string, allowed []string) error {
+	if len(allowed) == 0 {
+		return nil
+	}
+
+	parsed, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return fmt.Errorf("invalid URL %q: %w", rawURL, err)
+	}
+	host := strings.ToLower(parsed.Hostname())
+	if host == "" {
+		return fmt.Errorf("URL %q has no host", rawURL)
+	}
+
+	for _, pattern := range allowed {
+		matched, err := path.Match(strings.ToLower(pattern), host)
+		if err != nil {
+			return fmt.Errorf("invalid pattern %q in security.allowed_hosts: %w", pattern, err)
+		}
+		if matched {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("host %q is not in security.allowed_hosts (%s); add it with 'magi config set' if this endpoint is approved", host, strings.Join(allowed, ", "))
+}
@@ -0,0 +1,29 @@
+package shared
+
+import "testing"
+
+func TestCheckHostAllowed(t *testing.T) {
+	tests := []struct {
+		name    string
+		url     string
+		allowed []string
+		wantErr bool
+	}{
+		{"empty list allows all", "https://anything.example.com/v1", nil, false},
+		{"exact match", "https://api.openai.com/v1", []string{"api.openai.com"}, false},
+		{"glob match", "https://gateway.corp.example.com:8443/v1", []string{"*.corp.example.com"}, false},
+		{"case insensitive", "https://API.OpenAI.com/v1", []string{"api.openai.com"}, false},
+		{"not allowed", "https://openrouter.ai/api/v1", []string{"api.openai.com", "*.corp.example.com"}, true},
+		{"glob does not match apex", "https://corp.example.com", []string{"*.corp.example.com"}, true},
+		{"invalid pattern", "https://api.openai.com", []string{"[api"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckHostAllowed(tt.url, tt.allowed)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CheckHostAllowed(%q, %v) error = %v, wantErr %v", tt.url, tt.allowed, err, tt.wantErr)
+			}
+		})
+	}
+}
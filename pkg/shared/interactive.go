@@ -0,0 +1,30 @@
+package shared
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"golang.org/x/term"
+)
+
+// ErrNonInteractive is returned when a command needs an answer that only a prompt can
+// provide, but magi is not attached to a terminal (CI jobs, pipes).
+var ErrNonInteractive = errors.New("not running in an interactive terminal")
+
+// isTerminal is replaced in tests.
+var isTerminal = func(f *os.File) bool {
+	return term.IsTerminal(int(f.Fd()))
+}
+
+// IsInteractive reports whether prompts can be shown: stdin must be a terminal, and so must
+// stdout or stderr (JSON mode renders prompts on stderr).
+func IsInteractive() bool {
+	return isTerminal(os.Stdin) && (isTerminal(os.Stdout) || isTerminal(os.Stderr))
+}
+
+// NonInteractiveError tells the user which flag replaces a prompt when the session is not
+// interactive, for example NonInteractiveError("--yes").
+func NonInteractiveError(flag string) error {
+	return fmt.Errorf("%w: this command requires %s in non-interactive mode", ErrNonInteractive, flag)
+}
@@ -0,0 +1,68 @@
+package shared
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func setTerminal(t *testing.T, terminals ...*os.File) {
+	t.Helper()
+	original := isTerminal
+	t.Cleanup(func() { isTerminal = original })
+	isTerminal = func(f *os.File) bool {
+		for _, terminal := range terminals {
+			if f == terminal {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func TestIsInteractive(t *testing.T) {
+	setTerminal(t, os.Stdin, os.Stdout)
+	if !IsInteractive() {
+		t.Fatal("expected a terminal session to be interactive")
+	}
+
+	setTerminal(t, os.Stdin, os.Stderr)
+	if !IsInteractive() {
+		t.Fatal("expected stdin and stderr terminals to be interactive (JSON mode)")
+	}
+
+	setTerminal(t, os.Stdout, os.Stderr)
+	if IsInteractive() {
+		t.Fatal("expected piped stdin to be non-interactive")
+	}
+}
+
+func TestConfirmNonInteractiveUsesDefault(t *testing.T) {
+	setTerminal(t)
+	t.Cleanup(viper.Reset)
+	t.Cleanup(func() { confirmDefaultOverride = nil })
+
+	answer, err := Confirm("Proceed?", true)
+	if err != nil || !answer {
+		t.Fatalf("expected the prompt default without prompting, got %v, %v", answer, err)
+	}
+
+	SetConfirmDefault(false)
+	answer, err = Confirm("Proceed?", true)
+	if err != nil || answer {
+		t.Fatalf("expected --assume-no to be used, got %v, %v", answer, err)
+	}
+}
+
+func TestNonInteractiveError(t *testing.T) {
+	err := NonInteractiveError("--yes")
+	if !errors.Is(err, ErrNonInteractive) {
+		t.Fatalf("expected ErrNonInteractive, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "requires --yes in non-interactive mode") {
+		t.Fatalf("expected the flag to be named, got %q", err.Error())
+	}
+}
@@ -0,0 +1,104 @@
+package shared
+
+import (
+	"embed"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+// LanguageKey selects the language of magi's own prompts and messages. The MAGI_LANG
+// environment variable takes precedence.
+const LanguageKey = "ui.language"
+
+const (
+	languageEnv     = "MAGI_LANG"
+	defaultLanguage = "en"
+)
+
+//go:embed locales/*.yaml
+var localeFiles embed.FS
+
+var (
+	catalogMu sync.Mutex
+	catalogs  = map[string]map[string]string{}
+)
+
+// CurrentLanguage returns the normalized UI language (e.g. "pt-br") from MAGI_LANG or
+// ui.language, defaulting to English.
+func CurrentLanguage() string {
+	lang := strings.TrimSpace(os.Getenv(languageEnv))
+	if lang == "" {
+		lang = strings.TrimSpace(viper.GetString(LanguageKey))
+	}
+	if lang == "" {
+		return defaultLanguage
+	}
+	lang, _, _ = strings.Cut(lang, ".")
+	return strings.ReplaceAll(strings.ToLower(lang), "_", "-")
+}
+
+// T returns the message for key in the current language, formatted with args. Keys missing
+// from the language catalog fall back to English, then to the key itself.
+func T(key string, args ...any) string {
+	message := lookupMessage(CurrentLanguage(), key)
+	if len(args) == 0 {
+		return message
+	}
+	return fmt.Sprintf(message, args...)
+}
+
+// TOptions translates a list of option keys for an interactive select.
+func TOptions(keys []string) []string {
+	labels := make([]string, len(keys))
+	for i, key := range keys {
+		labels[i] = T(key)
+	}
+	return labels
+}
+
+// OptionKey maps a label picked from TOptions(keys) back to its key, or returns "" when
+// label is not one of the options.
+func OptionKey(keys []string, label string) string {
+	for _, key := range keys {
+		if T(key) == label {
+			return key
+		}
+	}
+	return ""
+}
+
+func lookupMessage(lang, key string) string {
+	candidates := []string{lang}
+	if base, _, found := strings.Cut(lang, "-"); found {
+		candidates = append(candidates, base)
+	}
+	candidates = append(candidates, defaultLanguage)
+
+	for _, candidate := range candidates {
+		if message, ok := loadCatalog(candidate)[key]; ok {
+			return message
+		}
+	}
+	return key
+}
+
+// loadCatalog reads locales/<lang>.yaml once. Unknown languages yield an empty catalog.
+func loadCatalog(lang string) map[string]string {
+	catalogMu.Lock()
+	defer catalogMu.Unlock()
+
+	if catalog, ok := catalogs[lang]; ok {
+		return catalog
+	}
+	catalog := map[string]string{}
+	if data, err := localeFiles.ReadFile("locales/" + lang + ".yaml"); err == nil {
+		_ = yaml.Unmarshal(data, &catalog)
+	}
+	catalogs[lang] = catalog
+	return catalog
+}
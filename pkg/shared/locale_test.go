@@ -0,0 +1,77 @@
+package shared
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestCurrentLanguage(t *testing.T) {
+	t.Cleanup(viper.Reset)
+	t.Setenv(languageEnv, "")
+
+	if got := CurrentLanguage(); got != "en" {
+		t.Fatalf("expected English by default, got %s", got)
+	}
+
+	viper.Set(LanguageKey, "pt")
+	if got := CurrentLanguage(); got != "pt" {
+		t.Fatalf("expected ui.language to be used, got %s", got)
+	}
+
+	t.Setenv(languageEnv, "pt_BR.UTF-8")
+	if got := CurrentLanguage(); got != "pt-br" {
+		t.Fatalf("expected MAGI_LANG to win and be normalized, got %s", got)
+	}
+}
+
+func TestTFallsBack(t *testing.T) {
+	t.Setenv(languageEnv, "pt-BR")
+	if got := T("setup.completed"); got != "Configuração concluída com sucesso!" {
+		t.Fatalf("expected the base language catalog, got %q", got)
+	}
+	if got := T("setup.api_key_failed", "boom"); got != "Falha ao obter a chave de API: boom" {
+		t.Fatalf("expected formatted message, got %q", got)
+	}
+
+	t.Setenv(languageEnv, "xx")
+	if got := T("setup.completed"); got != "Setup completed successfully!" {
+		t.Fatalf("expected the English fallback, got %q", got)
+	}
+	if got := T("missing.key"); got != "missing.key" {
+		t.Fatalf("expected the key for unknown messages, got %q", got)
+	}
+}
+
+func TestOptionKey(t *testing.T) {
+	t.Setenv(languageEnv, "pt")
+	keys := []string{"pr.action.submit", "pr.action.cancel"}
+
+	labels := TOptions(keys)
+	if labels[1] != "Cancelar" {
+		t.Fatalf("unexpected labels %v", labels)
+	}
+	if got := OptionKey(keys, "Cancelar"); got != "pr.action.cancel" {
+		t.Fatalf("expected the key of the picked label, got %q", got)
+	}
+	if got := OptionKey(keys, "Other"); got != "" {
+		t.Fatalf("expected no key for an unknown label, got %q", got)
+	}
+}
+
+func TestLocaleCatalogsHaveEnglishKeys(t *testing.T) {
+	english := loadCatalog(defaultLanguage)
+	entries, err := localeFiles.ReadDir("locales")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		lang := strings.TrimSuffix(entry.Name(), ".yaml")
+		for key := range loadCatalog(lang) {
+			if _, ok := english[key]; !ok {
+				t.Fatalf("%s defines %q, which is missing from the English catalog", entry.Name(), key)
+			}
+		}
+	}
+}
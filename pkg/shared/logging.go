@@ -0,0 +1,182 @@
+package shared
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/viper"
+)
+
+// Structured logging settings. The log is separate from the human output: it goes to
+// stderr or log.file, never to stdout.
+const (
+	// LogFormatKey selects the structured log format: json, text or off (default).
+	// The MAGI_LOG environment variable takes precedence.
+	LogFormatKey = "log.format"
+	// LogFileKey appends the structured log to a file instead of stderr.
+	LogFileKey = "log.file"
+
+	logFormatEnv = "MAGI_LOG"
+)
+
+// Structured log formats.
+const (
+	LogFormatJSON = "json"
+	LogFormatText = "text"
+	LogFormatOff  = "off"
+)
+
+var logFormats = []string{LogFormatJSON, LogFormatText, LogFormatOff}
+
+// sensitiveLogKeys are attribute names whose values are never logged, also when they end
+// an attribute name (as in "openai_api_key").
+var sensitiveLogKeys = []string{"key", "token", "secret", "password", "passphrase", "authorization"}
+
+var (
+	loggerMu          sync.RWMutex
+	logger            = slog.New(slog.DiscardHandler)
+	logFormatOverride string
+	logFileOverride   string
+)
+
+// Logger returns the structured logger. It discards every record unless logging was
+// started with a format other than off.
+func Logger() *slog.Logger {
+	loggerMu.RLock()
+	defer loggerMu.RUnlock()
+	return logger
+}
+
+// SetLogFormat overrides log.format and MAGI_LOG for the current process. The root command
+// calls it when --log-format is passed.
+func SetLogFormat(format string) {
+	logFormatOverride = format
+}
+
+// SetLogFile overrides log.file for the current process. The root command calls it when
+// --log-file is passed.
+func SetLogFile(path string) {
+	logFileOverride = path
+}
+
+// LogFormat returns the normalized structured log format from --log-format, MAGI_LOG or
+// log.format, in that order. An empty value means off.
+func LogFormat() (string, error) {
+	format := logFormatOverride
+	if format == "" {
+		format = os.Getenv(logFormatEnv)
+	}
+	if format == "" {
+		format = viper.GetString(LogFormatKey)
+	}
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format == "" {
+		return LogFormatOff, nil
+	}
+	for _, valid := range logFormats {
+		if format == valid {
+			return format, nil
+		}
+	}
+	return "", ConfigError(fmt.Errorf("unsupported log format %q (valid formats: %s)", format, strings.Join(logFormats, ", ")))
+}
+
+// StartLogging points Logger at stderr or the configured log file in the configured format.
+// Attribute values that look like credentials, and attributes with a sensitive name, are
+// redacted. The returned function closes the log file.
+func StartLogging() (func() error, error) {
+	format, err := LogFormat()
+	if err != nil {
+		return nil, err
+	}
+	if format == LogFormatOff {
+		setLogger(slog.New(slog.DiscardHandler))
+		return func() error { return nil }, nil
+	}
+
+	var w io.Writer = os.Stderr
+	closeLog := func() error { return nil }
+	path := logFileOverride
+	if path == "" {
+		path = viper.GetString(LogFileKey)
+	}
+	if path = strings.TrimSpace(path); path != "" {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create the log directory: %w", err)
+		}
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open the log file: %w", err)
+		}
+		w = file
+		closeLog = file.Close
+	}
+
+	redactor, err := RedactorFromConfig()
+	if err != nil {
+		return nil, ConfigError(err)
+	}
+	setLogger(slog.New(newLogHandler(w, format, redactor)))
+	return closeLog, nil
+}
+
+func setLogger(l *slog.Logger) {
+	loggerMu.Lock()
+	defer loggerMu.Unlock()
+	logger = l
+}
+
+// newLogHandler returns a handler for format that redacts secrets. --debug lowers the
+// level to include debug records.
+func newLogHandler(w io.Writer, format string, redactor *Redactor) slog.Handler {
+	level := slog.LevelInfo
+	if pterm.PrintDebugMessages {
+		level = slog.LevelDebug
+	}
+	opts := &slog.HandlerOptions{
+		Level: level,
+		ReplaceAttr: func(groups []string, attr slog.Attr) slog.Attr {
+			return redactLogAttr(attr, redactor)
+		},
+	}
+	if format == LogFormatText {
+		return slog.NewTextHandler(w, opts)
+	}
+	return slog.NewJSONHandler(w, opts)
+}
+
+// redactLogAttr hides the value of attributes with a sensitive name and scrubs credentials
+// from string and error values.
+func redactLogAttr(attr slog.Attr, redactor *Redactor) slog.Attr {
+	if isSensitiveLogKey(attr.Key) {
+		return slog.String(attr.Key, RedactedValue)
+	}
+	value := attr.Value.Resolve()
+	switch value.Kind() {
+	case slog.KindString:
+		redacted, _ := redactor.Redact(value.String())
+		return slog.String(attr.Key, redacted)
+	case slog.KindAny:
+		if err, ok := value.Any().(error); ok && err != nil {
+			redacted, _ := redactor.Redact(err.Error())
+			return slog.String(attr.Key, redacted)
+		}
+	}
+	return attr
+}
+
+func isSensitiveLogKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, name := range sensitiveLogKeys {
+		if key == name || strings.HasSuffix(key, "_"+name) || strings.HasSuffix(key, "-"+name) {
+			return true
+		}
+	}
+	return false
+}
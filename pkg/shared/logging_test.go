@@ -0,0 +1,112 @@
+package shared
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestLogFormat(t *testing.T) {
+	t.Cleanup(func() {
+		viper.Reset()
+		SetLogFormat("")
+	})
+	t.Setenv(logFormatEnv, "")
+
+	if got, err := LogFormat(); err != nil || got != LogFormatOff {
+		t.Fatalf("expected logging to be off by default, got %q, %v", got, err)
+	}
+
+	viper.Set(LogFormatKey, "text")
+	t.Setenv(logFormatEnv, " JSON ")
+	if got, _ := LogFormat(); got != LogFormatJSON {
+		t.Fatalf("expected MAGI_LOG to win over log.format, got %q", got)
+	}
+
+	SetLogFormat("text")
+	if got, _ := LogFormat(); got != LogFormatText {
+		t.Fatalf("expected the flag override to win, got %q", got)
+	}
+
+	SetLogFormat("xml")
+	if _, err := LogFormat(); err == nil || ExitCode(err) != ExitCodeConfig || !strings.Contains(err.Error(), "json, text, off") {
+		t.Fatalf("expected a configuration error listing the formats, got %v", err)
+	}
+}
+
+func TestLogHandlerRedactsSecrets(t *testing.T) {
+	redactor, err := NewRedactor(DefaultRedactionPatterns)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	logger := slog.New(newLogHandler(&buf, LogFormatJSON, redactor))
+
+	key := "sk-" + strings.Repeat("a", 24)
+	logger.Info("llm call with "+key,
+		"api_key", "plain-value",
+		"completion_tokens", 12,
+		"error", errors.New("request failed for "+key),
+		"note", "password="+strings.Repeat("b", 10))
+
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("expected one JSON record, got %q: %v", buf.String(), err)
+	}
+	if strings.Contains(buf.String(), key) || strings.Contains(buf.String(), "plain-value") || strings.Contains(buf.String(), strings.Repeat("b", 10)) {
+		t.Fatalf("expected secrets to be redacted, got %s", buf.String())
+	}
+	if record["api_key"] != RedactedValue || record["completion_tokens"] != float64(12) {
+		t.Fatalf("expected only the sensitive attribute to be hidden, got %v", record)
+	}
+	if record["error"] != "request failed for "+RedactedValue {
+		t.Fatalf("expected the error to be redacted, got %v", record["error"])
+	}
+}
+
+func TestStartLoggingToFile(t *testing.T) {
+	t.Cleanup(func() {
+		viper.Reset()
+		SetLogFormat("")
+		SetLogFile("")
+		_, _ = StartLogging()
+	})
+	t.Setenv(logFormatEnv, "")
+
+	path := filepath.Join(t.TempDir(), "logs", "magi.log")
+	SetLogFormat(LogFormatJSON)
+	SetLogFile(path)
+	closeLog, err := StartLogging()
+	if err != nil {
+		t.Fatalf("StartLogging failed: %v", err)
+	}
+	Logger().Info("command started", "command", "magi pr")
+	Logger().Debug("hidden without --debug")
+	if err := closeLog(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 1 || !strings.Contains(lines[0], `"msg":"command started"`) || !strings.Contains(lines[0], `"command":"magi pr"`) {
+		t.Fatalf("unexpected log file contents: %s", data)
+	}
+
+	SetLogFormat(LogFormatOff)
+	if _, err := StartLogging(); err != nil {
+		t.Fatal(err)
+	}
+	if Logger().Enabled(t.Context(), slog.LevelError) {
+		t.Fatal("expected logging off to discard every record")
+	}
+}
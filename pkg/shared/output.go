@@ -0,0 +1,85 @@
+package shared
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/viper"
+)
+
+// OutputMode controls how commands present their results.
+type OutputMode string
+
+const (
+	// OutputText prints the regular pterm-styled human output.
+	OutputText OutputMode = "text"
+	// OutputJSON sends human output to stderr and structured results to stdout.
+	OutputJSON OutputMode = "json"
+	// OutputQuiet suppresses info, success and spinner output; warnings and errors go to stderr.
+	OutputQuiet OutputMode = "quiet"
+)
+
+var outputModeOverride OutputMode
+
+// SetOutputMode overrides the configured output mode for the current process. The root
+// command calls it when --quiet or --json is passed.
+func SetOutputMode(mode OutputMode) {
+	outputModeOverride = mode
+}
+
+// CurrentOutputMode returns the mode selected through SetOutputMode, falling back to the
+// output.format configuration.
+func CurrentOutputMode() OutputMode {
+	if outputModeOverride != "" {
+		return outputModeOverride
+	}
+	if strings.EqualFold(strings.TrimSpace(viper.GetString("output.format")), "json") {
+		return OutputJSON
+	}
+	return OutputText
+}
+
+// IsJSONOutput reports whether commands should emit structured results.
+func IsJSONOutput() bool {
+	return CurrentOutputMode() == OutputJSON
+}
+
+// ConfigureOutput points the shared pterm printers at the writers required by mode so
+// every command behaves consistently without checking the mode itself.
+func ConfigureOutput(mode OutputMode) {
+	switch mode {
+	case OutputQuiet:
+		pterm.Info.Writer = io.Discard
+		pterm.Success.Writer = io.Discard
+		pterm.Description.Writer = io.Discard
+		pterm.DefaultSection.Writer = io.Discard
+		pterm.DefaultHeader.Writer = io.Discard
+		pterm.DefaultSpinner.Writer = io.Discard
+		pterm.DefaultProgressbar.Writer = io.Discard
+		pterm.Warning.Writer = os.Stderr
+		pterm.Error.Writer = os.Stderr
+		pterm.Fatal.Writer = os.Stderr
+	case OutputJSON:
+		// Keep stdout reserved for the structured result.
+		pterm.SetDefaultOutput(os.Stderr)
+	}
+}
+
+// PrintJSON writes v to stdout as indented JSON.
+func PrintJSON(v any) error {
+	return WriteJSON(os.Stdout, v)
+}
+
+// WriteJSON writes v to w as indented JSON.
+func WriteJSON(w io.Writer, v any) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		return fmt.Errorf("failed to encode JSON output: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package shared
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestCurrentOutputMode(t *testing.T) {
+	t.Cleanup(func() {
+		viper.Reset()
+		SetOutputMode("")
+	})
+
+	if got := CurrentOutputMode(); got != OutputText {
+		t.Fatalf("expected text mode by default, got %s", got)
+	}
+
+	viper.Set("output.format", "json")
+	if got := CurrentOutputMode(); got != OutputJSON {
+		t.Fatalf("expected output.format to select json mode, got %s", got)
+	}
+
+	SetOutputMode(OutputQuiet)
+	if got := CurrentOutputMode(); got != OutputQuiet {
+		t.Fatalf("expected flag override to win, got %s", got)
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteJSON(&buf, map[string]string{"message": "feat: add"}); err != nil {
+		t.Fatalf("WriteJSON failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), `"message": "feat: add"`) {
+		t.Fatalf("unexpected JSON output: %s", buf.String())
+	}
+}
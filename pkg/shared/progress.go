@@ -0,0 +1,109 @@
+package shared
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/viper"
+)
+
+// ProgressKey toggles the live per-agent progress list of multi-agent commands (default true).
+// When disabled, those commands show a single spinner instead.
+const ProgressKey = "ui.progress"
+
+var progressOverride *bool
+
+// SetProgress overrides ui.progress for the current process. The root command calls it when
+// --progress or --no-progress is passed.
+func SetProgress(enabled bool) {
+	progressOverride = &enabled
+}
+
+// ProgressEnabled reports whether the live progress list should be shown. --raw, the quiet
+// and JSON output modes, and a stdout that is not a terminal always disable it.
+func ProgressEnabled() bool {
+	if pterm.RawOutput || CurrentOutputMode() != OutputText || !isTerminal(os.Stdout) {
+		return false
+	}
+	if progressOverride != nil {
+		return *progressOverride
+	}
+	if viper.IsSet(ProgressKey) {
+		return viper.GetBool(ProgressKey)
+	}
+	return true
+}
+
+// AgentProgress renders one line per agent with its current state while a multi-agent
+// workflow runs. Agents are listed in the order they first report.
+type AgentProgress struct {
+	title string
+	area  *pterm.AreaPrinter
+	order []string
+	lines map[string]string
+}
+
+// StartAgentProgress starts a live progress list under title.
+func StartAgentProgress(title string) *AgentProgress {
+	p := &AgentProgress{title: title, lines: make(map[string]string)}
+	p.area, _ = pterm.DefaultArea.Start()
+	p.render()
+	return p
+}
+
+// Update records the state of an agent: "started", "finished", "failed" or "skipped".
+// elapsed and err are shown when set.
+func (p *AgentProgress) Update(agent, state string, elapsed time.Duration, err error) {
+	if _, ok := p.lines[agent]; !ok {
+		p.order = append(p.order, agent)
+	}
+	p.lines[agent] = progressLine(agent, state, elapsed, err)
+	p.render()
+}
+
+// Stop leaves the final state of every agent on screen.
+func (p *AgentProgress) Stop() {
+	if p.area != nil {
+		_ = p.area.Stop()
+	}
+}
+
+func (p *AgentProgress) render() {
+	if p.area == nil {
+		return
+	}
+	var b strings.Builder
+	b.WriteString(p.title)
+	for _, agent := range p.order {
+		b.WriteString("\n")
+		b.WriteString(p.lines[agent])
+	}
+	p.area.Update(b.String())
+}
+
+func progressLine(agent, state string, elapsed time.Duration, err error) string {
+	var line string
+	switch state {
+	case "started":
+		line = fmt.Sprintf("  %s %s running...", pterm.Cyan("●"), agent)
+	case "finished":
+		line = fmt.Sprintf("  %s %s", pterm.Green("✓"), agent)
+	case "failed":
+		line = fmt.Sprintf("  %s %s failed", pterm.Red("✗"), agent)
+	case "skipped":
+		line = fmt.Sprintf("  %s %s skipped", pterm.Yellow("-"), agent)
+	default:
+		line = fmt.Sprintf("    %s %s", agent, state)
+	}
+	if elapsed > 0 {
+		line += pterm.Gray(fmt.Sprintf(" (%s)", elapsed.Round(100*time.Millisecond)))
+	}
+	if err != nil {
+		message, _, _ := strings.Cut(err.Error(), "\n")
+		line += pterm.Gray(": " + message)
+	}
+	return line
+}
@@ -0,0 +1,59 @@
+package shared
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/viper"
+)
+
+func TestProgressEnabled(t *testing.T) {
+	t.Cleanup(viper.Reset)
+	t.Cleanup(func() { progressOverride = nil })
+	original := isTerminal
+	t.Cleanup(func() { isTerminal = original })
+	isTerminal = func(*os.File) bool { return true }
+
+	if !ProgressEnabled() {
+		t.Fatal("expected progress to be enabled by default on a terminal")
+	}
+
+	viper.Set(ProgressKey, false)
+	if ProgressEnabled() {
+		t.Fatal("expected ui.progress=false to disable progress")
+	}
+
+	SetProgress(true)
+	if !ProgressEnabled() {
+		t.Fatal("expected --progress to win over ui.progress")
+	}
+
+	pterm.RawOutput = true
+	t.Cleanup(func() { pterm.RawOutput = false })
+	if ProgressEnabled() {
+		t.Fatal("expected --raw to disable progress")
+	}
+	pterm.RawOutput = false
+
+	isTerminal = func(*os.File) bool { return false }
+	if ProgressEnabled() {
+		t.Fatal("expected progress to be disabled when stdout is not a terminal")
+	}
+}
+
+func TestProgressLine(t *testing.T) {
+	pterm.DisableColor()
+	t.Cleanup(pterm.EnableColor)
+
+	if got := progressLine("AnalysisAgent", "started", 0, nil); !strings.Contains(got, "AnalysisAgent running") {
+		t.Fatalf("unexpected started line %q", got)
+	}
+	got := progressLine("WriterAgent", "failed", 1500*time.Millisecond, errors.New("invalid JSON\nraw: {"))
+	if !strings.Contains(got, "WriterAgent failed (1.5s): invalid JSON") || strings.Contains(got, "raw") {
+		t.Fatalf("unexpected failed line %q", got)
+	}
+}
@@ -0,0 +1,84 @@
+package shared
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/spf13/viper"
+)
+
+// RedactionPatternsKey holds extra regular expressions whose matches are scrubbed from
+// diffs and context before they are sent to an AI provider.
+const RedactionPatternsKey = "security.redaction_patterns"
+
+// DefaultRedactionPatterns match common credential formats. When a pattern has a
+// capture group, only the first group is redacted so the surrounding text (for example
+// the variable name) stays readable for the model.
+var DefaultRedactionPatterns = []string{
+	// AWS access key IDs
+	`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`,
+	// GitHub tokens
+	`\bgh[pousr]_[A-Za-z0-9]{36,}\b`,
+	`\bgithub_pat_[A-Za-z0-9_]{22,}\b`,
+	// OpenAI / Anthropic style API keys
+	`\bsk-[A-Za-z0-9_-]{20,}`,
+	// Slack tokens
+	`\bxox[abprs]-[A-Za-z0-9-]{10,}`,
+	// Google API keys
+	`\bAIza[0-9A-Za-z_-]{35}\b`,
+	// PEM private keys
+	`-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?-----END [A-Z ]*PRIVATE KEY-----`,
+	// password = "...", api_key: ..., token=...
+	`(?i)(?:password|passwd|secret|api[_-]?key|access[_-]?token|auth[_-]?token)["']?\s*[:=]\s*["']?([^\s"'` + "`" + `,;]{6,})`,
+}
+
+// Redactor replaces secrets in text with RedactedValue.
+type Redactor struct {
+	patterns []*regexp.Regexp
+}
+
+// NewRedactor compiles patterns into a Redactor.
+func NewRedactor(patterns []string) (*Redactor, error) {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction pattern %q: %w", pattern, err)
+		}
+		compiled = append(compiled, re)
+	}
+	return &Redactor{patterns: compiled}, nil
+}
+
+// RedactorFromConfig returns a Redactor using DefaultRedactionPatterns plus the patterns
+// configured under security.redaction_patterns.
+func RedactorFromConfig() (*Redactor, error) {
+	patterns := append([]string{}, DefaultRedactionPatterns...)
+	patterns = append(patterns, viper.GetStringSlice(RedactionPatternsKey)...)
+	return NewRedactor(patterns)
+}
+
+// Redact returns text with every match replaced by RedactedValue and the number of
+// redactions made.
+func (r *Redactor) Redact(text string) (string, int) {
+	if r == nil || text == "" {
+		return text, 0
+	}
+
+	count := 0
+	for _, re := range r.patterns {
+		text = re.ReplaceAllStringFunc(text, func(match string) string {
+			if re.NumSubexp() == 0 {
+				count++
+				return RedactedValue
+			}
+			loc := re.FindStringSubmatchIndex(match)
+			if loc == nil || loc[2] < 0 || match[loc[2]:loc[3]] == RedactedValue {
+				return match
+			}
+			count++
+			return match[:loc[2]] + RedactedValue + match[loc[3]:]
+		})
+	}
+	return text, count
+}
@@ -0,0 +1,148 @@
+package shared
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// RedactedValue replaces secrets whenever configuration or runtime values are shared.
+const RedactedValue = "***REDACTED***"
+
+// SensitiveConfigKeys lists the configuration keys that hold credentials.
+var SensitiveConfigKeys = []string{
+	"api.key",
+	"api.light.api_key",
+	"api.heavy.api_key",
+	"api.fallback.api_key",
+	"i18n.deepl.api_key",
+}
+
+const (
+	encryptedSecretPrefix = "enc:v1:"
+	secretSaltSize        = 16
+	secretKeyIterations   = 600000
+)
+
+// TransformSecrets calls fn for every non-empty sensitive key found in a nested settings
+// map (as returned by viper.AllSettings) and stores the result. Returning an empty string
+// removes the key from the map.
+func TransformSecrets(settings map[string]any, fn func(key, value string) (string, error)) error {
+	for _, key := range SensitiveConfigKeys {
+		parts := strings.Split(key, ".")
+		parent := settings
+		for _, part := range parts[:len(parts)-1] {
+			next, ok := parent[part].(map[string]any)
+			if !ok {
+				parent = nil
+				break
+			}
+			parent = next
+		}
+		if parent == nil {
+			continue
+		}
+
+		leaf := parts[len(parts)-1]
+		value, ok := parent[leaf].(string)
+		if !ok || strings.TrimSpace(value) == "" {
+			continue
+		}
+
+		updated, err := fn(key, value)
+		if err != nil {
+			return fmt.Errorf("failed to process %s: %w", key, err)
+		}
+		if updated == "" {
+			delete(parent, leaf)
+			continue
+		}
+		parent[leaf] = updated
+	}
+	return nil
+}
+
+// IsEncryptedSecret reports whether value was produced by EncryptSecret.
+func IsEncryptedSecret(value string) bool {
+	return strings.HasPrefix(value, encryptedSecretPrefix)
+}
+
+// EncryptSecret encrypts plaintext with a key derived from passphrase (PBKDF2-SHA256 + AES-GCM).
+func EncryptSecret(plaintext, passphrase string) (string, error) {
+	if passphrase == "" {
+		return "", fmt.Errorf("passphrase is required")
+	}
+
+	salt := make([]byte, secretSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	gcm, err := secretCipher(passphrase, salt)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	payload := append(salt, nonce...)
+	payload = gcm.Seal(payload, nonce, []byte(plaintext), nil)
+	return encryptedSecretPrefix + base64.StdEncoding.EncodeToString(payload), nil
+}
+
+// DecryptSecret reverses EncryptSecret. A wrong passphrase results in an error.
+func DecryptSecret(value, passphrase string) (string, error) {
+	if !IsEncryptedSecret(value) {
+		return "", fmt.Errorf("value is not an encrypted secret")
+	}
+
+	payload, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, encryptedSecretPrefix))
+	if err != nil {
+		return "", fmt.Errorf("failed to decode secret: %w", err)
+	}
+	if len(payload) < secretSaltSize {
+		return "", fmt.Errorf("encrypted secret is truncated")
+	}
+
+	salt := payload[:secretSaltSize]
+	gcm, err := secretCipher(passphrase, salt)
+	if err != nil {
+		return "", err
+	}
+
+	rest := payload[secretSaltSize:]
+	if len(rest) < gcm.NonceSize() {
+		return "", fmt.Errorf("encrypted secret is truncated")
+	}
+	nonce, ciphertext := rest[:gcm.NonceSize()], rest[gcm.NonceSize():]
+
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt secret (wrong passphrase?)")
+	}
+	return string(plaintext), nil
+}
+
+func secretCipher(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := pbkdf2.Key(sha256.New, passphrase, salt, secretKeyIterations, 32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive key: %w", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return gcm, nil
+}
@@ -0,0 +1,64 @@
+package shared
+
+import "testing"
+
+func TestEncryptDecryptSecret(t *testing.T) {
+	encrypted, err := EncryptSecret("sk-test", "passphrase")
+	if err != nil {
+		t.Fatalf("EncryptSecret failed: %v", err)
+	}
+	if !IsEncryptedSecret(encrypted) {
+		t.Fatalf("expected encrypted prefix, got %s", encrypted)
+	}
+
+	plain, err := DecryptSecret(encrypted, "passphrase")
+	if err != nil {
+		t.Fatalf("DecryptSecret failed: %v", err)
+	}
+	if plain != "sk-test" {
+		t.Fatalf("expected sk-test, got %s", plain)
+	}
+
+	if _, err := DecryptSecret(encrypted, "wrong"); err == nil {
+		t.Fatal("expected error with wrong passphrase")
+	}
+}
+
+func TestTransformSecrets(t *testing.T) {
+	settings := map[string]any{
+		"api": map[string]any{
+			"key":         "sk-global",
+			"heavy_model": "gpt-4",
+			"heavy": map[string]any{
+				"api_key": "sk-heavy",
+			},
+			"light": map[string]any{
+				"api_key": "",
+			},
+		},
+	}
+
+	err := TransformSecrets(settings, func(key, value string) (string, error) {
+		if key == "api.heavy.api_key" {
+			return "", nil
+		}
+		return RedactedValue, nil
+	})
+	if err != nil {
+		t.Fatalf("TransformSecrets failed: %v", err)
+	}
+
+	api := settings["api"].(map[string]any)
+	if api["key"] != RedactedValue {
+		t.Fatalf("expected api.key to be redacted, got %v", api["key"])
+	}
+	if api["heavy_model"] != "gpt-4" {
+		t.Fatalf("expected non-secret values untouched, got %v", api["heavy_model"])
+	}
+	if _, ok := api["heavy"].(map[string]any)["api_key"]; ok {
+		t.Fatal("expected api.heavy.api_key to be removed")
+	}
+	if api["light"].(map[string]any)["api_key"] != "" {
+		t.Fatal("expected empty secrets to be skipped")
+	}
+}
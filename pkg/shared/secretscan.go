@@ -0,0 +1,143 @@
+package shared
+
+import (
+	"fmt"
+	"math"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pterm/pterm"
+)
+
+// SecretFinding points at an added diff line that looks like it contains a secret.
+type SecretFinding struct {
+	File   string `json:"file"`
+	Line   int    `json:"line"`
+	Reason string `json:"reason"`
+}
+
+type secretRule struct {
+	reason  string
+	pattern *regexp.Regexp
+}
+
+var secretRules = []secretRule{
+	{"AWS access key", regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{"private key", regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----`)},
+	{"GitHub token", regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})\b`)},
+	{"API key", regexp.MustCompile(`\bsk-[A-Za-z0-9_-]{20,}`)},
+	{"Slack token", regexp.MustCompile(`\bxox[abprs]-[A-Za-z0-9-]{10,}`)},
+	{"Google API key", regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}\b`)},
+}
+
+var (
+	diffHunkHeaderPattern = regexp.MustCompile(`^@@ -\d+(?:,\d+)? \+(\d+)(?:,\d+)? @@`)
+	envAssignmentPattern  = regexp.MustCompile(`^\s*(?:export\s+)?([A-Za-z_][A-Za-z0-9_]*)\s*=\s*["']?([^"'\s#]+)`)
+	sensitiveNamePattern  = regexp.MustCompile(`(?i)(password|passwd|secret|token|api_?key|private_?key|credential)`)
+	entropyCandidate      = regexp.MustCompile(`[A-Za-z0-9+/_\-=]{24,}`)
+)
+
+// secretScanSkippedFiles contain hashes and checksums that look random by design.
+var secretScanSkippedFiles = map[string]bool{
+	"go.sum":            true,
+	"package-lock.json": true,
+	"yarn.lock":         true,
+	"pnpm-lock.yaml":    true,
+	"Cargo.lock":        true,
+	"poetry.lock":       true,
+	"composer.lock":     true,
+}
+
+// highEntropyThreshold is above the 4.0 bits/char ceiling of hex digests, so commit
+// SHAs and checksums are not reported.
+const highEntropyThreshold = 4.3
+
+// ScanDiffForSecrets inspects the lines added by a unified diff and reports the ones that
+// match known credential formats, sensitive .env assignments or high-entropy strings.
+func ScanDiffForSecrets(diff string) []SecretFinding {
+	var findings []SecretFinding
+	file := ""
+	line := 0
+
+	for _, raw := range strings.Split(diff, "\n") {
+		switch {
+		case strings.HasPrefix(raw, "diff --git "):
+			file = ""
+		case strings.HasPrefix(raw, "+++ "):
+			file = strings.TrimPrefix(strings.TrimPrefix(raw, "+++ "), "b/")
+		case strings.HasPrefix(raw, "@@"):
+			if match := diffHunkHeaderPattern.FindStringSubmatch(raw); match != nil {
+				line, _ = strconv.Atoi(match[1])
+			}
+		case strings.HasPrefix(raw, "+"):
+			if reason := secretReason(file, raw[1:]); reason != "" {
+				findings = append(findings, SecretFinding{File: file, Line: line, Reason: reason})
+			}
+			line++
+		case strings.HasPrefix(raw, " "):
+			line++
+		}
+	}
+	return findings
+}
+
+func secretReason(file, content string) string {
+	if file == "/dev/null" || secretScanSkippedFiles[path.Base(file)] {
+		return ""
+	}
+
+	for _, rule := range secretRules {
+		if rule.pattern.MatchString(content) {
+			return rule.reason
+		}
+	}
+
+	if strings.HasPrefix(path.Base(file), ".env") {
+		if match := envAssignmentPattern.FindStringSubmatch(content); match != nil && sensitiveNamePattern.MatchString(match[1]) {
+			return fmt.Sprintf(".env assignment to %s", match[1])
+		}
+	}
+
+	for _, candidate := range entropyCandidate.FindAllString(content, -1) {
+		if shannonEntropy(candidate) >= highEntropyThreshold {
+			return "high-entropy string"
+		}
+	}
+	return ""
+}
+
+func shannonEntropy(s string) float64 {
+	counts := make(map[rune]int)
+	total := 0
+	for _, r := range s {
+		counts[r]++
+		total++
+	}
+	var entropy float64
+	for _, count := range counts {
+		p := float64(count) / float64(total)
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
+
+// ConfirmSecretFindings lists the findings and asks whether to continue. It returns true
+// when there is nothing to report.
+func ConfirmSecretFindings(findings []SecretFinding) (bool, error) {
+	if len(findings) == 0 {
+		return true, nil
+	}
+
+	pterm.Warning.Printf("The diff appears to add %d secret(s):\n", len(findings))
+	for _, finding := range findings {
+		pterm.Printf("  %s:%d  %s\n", finding.File, finding.Line, finding.Reason)
+	}
+
+	proceed, err := Confirm("Continue anyway? (use --no-secrets-check to skip this check)", false)
+	if err != nil {
+		return false, fmt.Errorf("confirmation prompt failed: %w", err)
+	}
+	return proceed, nil
+}
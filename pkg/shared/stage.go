@@ -0,0 +1,101 @@
+package shared
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+var stage struct {
+	sync.Mutex
+	name   string
+	agents map[string]int
+}
+
+// SetStage records the step a long-running command is in, such as "Reviewing the diff",
+// so a timeout can report where the command stopped.
+func SetStage(name string) {
+	stage.Lock()
+	defer stage.Unlock()
+	stage.name = name
+}
+
+// AgentStarted records that an AI agent is running, for the same report.
+func AgentStarted(name string) {
+	stage.Lock()
+	defer stage.Unlock()
+	if stage.agents == nil {
+		stage.agents = make(map[string]int)
+	}
+	stage.agents[name]++
+}
+
+// AgentStopped records that an AI agent started with AgentStarted is done.
+func AgentStopped(name string) {
+	stage.Lock()
+	defer stage.Unlock()
+	if stage.agents[name] <= 1 {
+		delete(stage.agents, name)
+		return
+	}
+	stage.agents[name]--
+}
+
+// TrackAgent updates the running agents from an agent progress state: "started" calls
+// AgentStarted, "finished" and "failed" call AgentStopped. Other states are ignored.
+func TrackAgent(name, state string) {
+	switch state {
+	case "started":
+		AgentStarted(name)
+	case "finished", "failed":
+		AgentStopped(name)
+	}
+}
+
+// CurrentStage describes the active stage and running agents, or returns "" when nothing
+// was recorded.
+func CurrentStage() string {
+	stage.Lock()
+	defer stage.Unlock()
+
+	agents := make([]string, 0, len(stage.agents))
+	for name := range stage.agents {
+		agents = append(agents, name)
+	}
+	sort.Strings(agents)
+
+	description := stage.name
+	if len(agents) > 0 {
+		running := "running agents: " + strings.Join(agents, ", ")
+		if description == "" {
+			return running
+		}
+		description += " (" + running + ")"
+	}
+	return description
+}
+
+// TimeoutError is the cause of the command context when --timeout expires. Stage is the
+// stage that was active at that moment.
+type TimeoutError struct {
+	Timeout time.Duration
+	Stage   string
+}
+
+func (e *TimeoutError) Error() string {
+	if e.Stage == "" {
+		return fmt.Sprintf("command timed out after %s", e.Timeout)
+	}
+	return fmt.Sprintf("command timed out after %s while: %s", e.Timeout, e.Stage)
+}
+
+// StartTimeout calls cancel with a *TimeoutError once timeout has elapsed. The returned
+// function stops the timer.
+func StartTimeout(timeout time.Duration, cancel func(cause error)) (stop func() bool) {
+	timer := time.AfterFunc(timeout, func() {
+		cancel(&TimeoutError{Timeout: timeout, Stage: CurrentStage()})
+	})
+	return timer.Stop
+}
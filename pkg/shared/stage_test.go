@@ -0,0 +1,74 @@
+package shared
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCurrentStage(t *testing.T) {
+	t.Cleanup(func() { SetStage("") })
+
+	if got := CurrentStage(); got != "" {
+		t.Fatalf("expected no stage, got %q", got)
+	}
+
+	AgentStarted("WriterAgent")
+	AgentStarted("AnalysisAgent")
+	if got := CurrentStage(); got != "running agents: AnalysisAgent, WriterAgent" {
+		t.Fatalf("unexpected stage %q", got)
+	}
+
+	SetStage("Reviewing the diff")
+	AgentStopped("WriterAgent")
+	if got := CurrentStage(); got != "Reviewing the diff (running agents: AnalysisAgent)" {
+		t.Fatalf("unexpected stage %q", got)
+	}
+
+	AgentStopped("AnalysisAgent")
+	if got := CurrentStage(); got != "Reviewing the diff" {
+		t.Fatalf("unexpected stage %q", got)
+	}
+
+	TrackAgent("LinterAgent", "started")
+	TrackAgent("LinterAgent", "skipped")
+	if got := CurrentStage(); got != "Reviewing the diff (running agents: LinterAgent)" {
+		t.Fatalf("unexpected stage %q", got)
+	}
+	TrackAgent("LinterAgent", "failed")
+	if got := CurrentStage(); got != "Reviewing the diff" {
+		t.Fatalf("unexpected stage %q", got)
+	}
+}
+
+func TestStartTimeout(t *testing.T) {
+	t.Cleanup(func() { SetStage("") })
+	SetStage("Generating the Pulumi project")
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	defer cancel(nil)
+	StartTimeout(10*time.Millisecond, cancel)
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("expected the timeout to cancel the context")
+	}
+	var timeoutErr *TimeoutError
+	if !errors.As(context.Cause(ctx), &timeoutErr) {
+		t.Fatalf("expected a TimeoutError cause, got %v", context.Cause(ctx))
+	}
+	if got := timeoutErr.Error(); got != "command timed out after 10ms while: Generating the Pulumi project" {
+		t.Fatalf("unexpected message %q", got)
+	}
+
+	ctx, cancel = context.WithCancelCause(context.Background())
+	defer cancel(nil)
+	if stop := StartTimeout(time.Hour, cancel); !stop() {
+		t.Fatal("expected the timer to be stopped")
+	}
+	if ctx.Err() != nil {
+		t.Fatal("expected a stopped timeout to leave the context alone")
+	}
+}